@@ -0,0 +1,161 @@
+// Package baseline implements suppression of known findings, to make it possible to gradually
+// adopt kube-score in projects with existing issues.
+package baseline
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// DateFormat is the format of the expiry dates in the baseline
+const DateFormat = "2006-01-02"
+
+type Baseline struct {
+	Suppressions []Suppression `yaml:"suppressions"`
+}
+
+// Suppression is a known finding, that should not be reported
+type Suppression struct {
+	// Object is the key of the object in the scorecard (Kind/APIVersion/Namespace/Name)
+	Object string `yaml:"object"`
+	Check  string `yaml:"check"`
+	Path   string `yaml:"path,omitempty"`
+
+	// Expires is an optional date (YYYY-MM-DD), after which the finding will be reported again
+	Expires string `yaml:"expires,omitempty"`
+}
+
+// New creates a baseline that suppresses all current WARNING and CRITICAL findings in the scorecard
+func New(scoreCard *scorecard.Scorecard) Baseline {
+	var b Baseline
+
+	for key, o := range *scoreCard {
+		for _, c := range o.Checks {
+			if c.Skipped || c.Grade > scorecard.GradeWarning {
+				continue
+			}
+
+			if len(c.Comments) == 0 {
+				b.Suppressions = append(b.Suppressions, Suppression{Object: key, Check: c.Check.ID})
+			}
+
+			for _, comment := range c.Comments {
+				b.Suppressions = append(b.Suppressions, Suppression{Object: key, Check: c.Check.ID, Path: comment.Path})
+			}
+		}
+	}
+
+	sort.Slice(b.Suppressions, func(i, j int) bool {
+		x, y := b.Suppressions[i], b.Suppressions[j]
+		if x.Object != y.Object {
+			return x.Object < y.Object
+		}
+		if x.Check != y.Check {
+			return x.Check < y.Check
+		}
+		return x.Path < y.Path
+	})
+
+	b.Suppressions = dedup(b.Suppressions)
+
+	return b
+}
+
+func dedup(in []Suppression) []Suppression {
+	var res []Suppression
+	for i, s := range in {
+		if i > 0 && s == in[i-1] {
+			continue
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+func Parse(r io.Reader) (Baseline, error) {
+	var b Baseline
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return b, err
+	}
+
+	if err := yaml.Unmarshal(data, &b); err != nil {
+		return b, fmt.Errorf("invalid baseline: %w", err)
+	}
+
+	for _, s := range b.Suppressions {
+		if s.Expires == "" {
+			continue
+		}
+		if _, err := time.Parse(DateFormat, s.Expires); err != nil {
+			return b, fmt.Errorf("invalid expiry date %q for %s in %s, expected format YYYY-MM-DD", s.Expires, s.Check, s.Object)
+		}
+	}
+
+	return b, nil
+}
+
+func (b Baseline) Write(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(b); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// Apply removes all findings from the scorecard that are suppressed by the baseline, and have not expired.
+// Checks where all findings are suppressed are marked as skipped.
+func (b Baseline) Apply(scoreCard *scorecard.Scorecard, now time.Time) {
+	active := make(map[Suppression]struct{})
+	for _, s := range b.Suppressions {
+		if s.Expires != "" {
+			expires, err := time.Parse(DateFormat, s.Expires)
+			if err != nil || !now.Before(expires.AddDate(0, 0, 1)) {
+				continue
+			}
+		}
+		active[Suppression{Object: s.Object, Check: scorecard.CheckID(s.Check), Path: s.Path}] = struct{}{}
+	}
+
+	isSuppressed := func(object, check, path string) bool {
+		_, ok := active[Suppression{Object: object, Check: check, Path: path}]
+		return ok
+	}
+
+	for key, o := range *scoreCard {
+		for i, c := range o.Checks {
+			if c.Skipped || c.Grade > scorecard.GradeWarning {
+				continue
+			}
+
+			var remaining []scorecard.TestScoreComment
+			for _, comment := range c.Comments {
+				if !isSuppressed(key, c.Check.ID, comment.Path) {
+					remaining = append(remaining, comment)
+				}
+			}
+
+			allSuppressed := len(remaining) == 0
+			if len(c.Comments) == 0 {
+				allSuppressed = isSuppressed(key, c.Check.ID, "")
+			}
+
+			if allSuppressed {
+				o.Checks[i].Skipped = true
+				o.Checks[i].Comments = []scorecard.TestScoreComment{{Summary: fmt.Sprintf("Skipped because %s is suppressed by the baseline", c.Check.ID)}}
+				continue
+			}
+
+			o.Checks[i].Comments = remaining
+		}
+	}
+}
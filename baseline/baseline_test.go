@@ -0,0 +1,115 @@
+package baseline
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func getTestCard() *scorecard.Scorecard {
+	return &scorecard.Scorecard{
+		"Deployment/apps/v1/foofoo/foo": &scorecard.ScoredObject{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "foofoo"},
+			Checks: []scorecard.TestScore{
+				{
+					Check: domain.Check{ID: "container-resources"},
+					Grade: scorecard.GradeCritical,
+					Comments: []scorecard.TestScoreComment{
+						{Path: "app", Summary: "CPU limit is not set"},
+						{Path: "sidecar", Summary: "CPU limit is not set"},
+					},
+				},
+				{
+					Check: domain.Check{ID: "deployment-has-poddisruptionbudget"},
+					Grade: scorecard.GradeCritical,
+					Comments: []scorecard.TestScoreComment{
+						{Summary: "No matching PodDisruptionBudget was found"},
+					},
+				},
+				{
+					Check: domain.Check{ID: "pod-probes"},
+					Grade: scorecard.GradeAllOK,
+				},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	b := New(getTestCard())
+	assert.Equal(t, []Suppression{
+		{Object: "Deployment/apps/v1/foofoo/foo", Check: "container-resources", Path: "app"},
+		{Object: "Deployment/apps/v1/foofoo/foo", Check: "container-resources", Path: "sidecar"},
+		{Object: "Deployment/apps/v1/foofoo/foo", Check: "deployment-has-poddisruptionbudget"},
+	}, b.Suppressions)
+}
+
+func TestWriteAndParse(t *testing.T) {
+	b := New(getTestCard())
+	b.Suppressions[0].Expires = "2030-01-01"
+
+	var buf bytes.Buffer
+	assert.Nil(t, b.Write(&buf))
+
+	parsed, err := Parse(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, b, parsed)
+}
+
+func TestParseInvalidExpiry(t *testing.T) {
+	_, err := Parse(bytes.NewBufferString(`suppressions:
+- object: Deployment/apps/v1/foofoo/foo
+  check: container-resources
+  expires: tomorrow
+`))
+	assert.NotNil(t, err)
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	b := Baseline{Suppressions: []Suppression{
+		{Object: "Deployment/apps/v1/foofoo/foo", Check: "container-resources", Path: "app"},
+		{Object: "Deployment/apps/v1/foofoo/foo", Check: "container-resources", Path: "sidecar", Expires: "2025-05-31"},
+		{Object: "Deployment/apps/v1/foofoo/foo", Check: "deployment-has-poddisruptionbudget", Expires: "2025-06-01"},
+	}}
+
+	sc := getTestCard()
+	b.Apply(sc, now)
+	checks := (*sc)["Deployment/apps/v1/foofoo/foo"].Checks
+
+	// The suppression for "sidecar" has expired
+	assert.False(t, checks[0].Skipped)
+	assert.Equal(t, scorecard.GradeCritical, checks[0].Grade)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "sidecar", Summary: "CPU limit is not set"}}, checks[0].Comments)
+
+	// Expires at the end of the day
+	assert.True(t, checks[1].Skipped)
+
+	assert.False(t, checks[2].Skipped)
+	assert.True(t, sc.AnyBelowOrEqualToGrade(scorecard.GradeCritical))
+}
+
+func TestApplyRenamedCheck(t *testing.T) {
+	sc := &scorecard.Scorecard{
+		"Deployment/apps/v1/foofoo/foo": &scorecard.ScoredObject{
+			Checks: []scorecard.TestScore{{
+				Check: domain.Check{ID: "deployment-has-pod-spread"},
+				Grade: scorecard.GradeWarning,
+			}},
+		},
+	}
+
+	// Baselines that were written before the check was renamed still suppress its findings
+	Baseline{Suppressions: []Suppression{
+		{Object: "Deployment/apps/v1/foofoo/foo", Check: "deployment-has-host-podantiaffinity"},
+	}}.Apply(sc, time.Now())
+	assert.True(t, (*sc)["Deployment/apps/v1/foofoo/foo"].Checks[0].Skipped)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/internal/logger"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/podsecurity"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// checkFlags are the flags that configure the checks, they are shared by all actions that score objects
+type checkFlags struct {
+	ignoreContainerCpuLimit       *bool
+	ignoreContainerMemoryLimit    *bool
+	optionalTests                 *[]string
+	allOptionalTests              *bool
+	ignoreTests                   *[]string
+	disableIgnoreChecksAnnotation *bool
+	requiredDroppedCapabilities   *[]string
+	allowedHostPaths              *[]string
+	allowedImageRegistries        *[]string
+	maxMemoryLimitRatio           *float64
+	requiredLabels                *[]string
+	podSecurityStandard           *string
+	readWriteOnceStorageClasses   *[]string
+	allowedPriorityClasses        *[]string
+	kubernetesVersion             *string
+	pluginFiles                   *[]string
+	pluginRuntime                 *string
+	profiles                      *[]string
+	ignoreKinds                   *[]string
+	onlyKinds                     *[]string
+	unknownKinds                  *string
+	parallelism                   *int
+}
+
+func registerCheckFlags(fs *flag.FlagSet) *checkFlags {
+	return &checkFlags{
+		ignoreContainerCpuLimit:       fs.Bool("ignore-container-cpu-limit", false, "Disables the requirement of setting a container CPU limit"),
+		ignoreContainerMemoryLimit:    fs.Bool("ignore-container-memory-limit", false, "Disables the requirement of setting a container memory limit"),
+		optionalTests:                 fs.StringSlice("enable-optional-test", []string{}, "Enable an optional test, can be set multiple times"),
+		allOptionalTests:              fs.Bool("enable-all-optional-tests", false, "Enable all optional tests, including the optional tests that are added in new versions of kube-score"),
+		ignoreTests:                   fs.StringSlice("ignore-test", []string{}, "Disable a test, can be set multiple times"),
+		disableIgnoreChecksAnnotation: fs.Bool("disable-ignore-checks-annotations", false, "Set to true to disable the effect of the 'kube-score/ignore' and 'kube-score/downgrade' annotations"),
+		requiredDroppedCapabilities:   fs.StringSlice("required-dropped-capabilities", []string{"ALL"}, "Capabilities that all containers must drop, can be set multiple times"),
+		allowedHostPaths:              fs.StringSlice("allowed-host-path", []string{}, "Allow pods to mount this path, and all paths below it, as a hostPath volume, can be set multiple times"),
+		allowedImageRegistries:        fs.StringSlice("allowed-image-registry", []string{}, "Allow images to be pulled from this registry, used by the container-image-registry check, can be set multiple times"),
+		maxMemoryLimitRatio:           fs.Float64("max-memory-limit-ratio", 2, "The highest allowed ratio between the memory limit and the memory request of a container, used by the container-memory-limit-ratio check"),
+		requiredLabels:                fs.StringSlice("required-label", []string{}, "A label that all workloads must have, used by the workload-required-labels check, can be set multiple times. By default the app.kubernetes.io/name, instance, version and part-of labels are required"),
+		podSecurityStandard:           fs.String("pod-security-standard", "", "Require all pods to satisfy this Pod Security Standard. Set to 'privileged', 'baseline' or 'restricted'. By default, no standard is required"),
+		allowedPriorityClasses:        fs.StringSlice("allowed-priority-class", []string{}, "Allow pods to use this PriorityClass, used by the pod-priority-class check, can be set multiple times. By default, all PriorityClasses are allowed"),
+		readWriteOnceStorageClasses:   fs.StringSlice("read-write-once-storage-class", []string{}, "A StorageClass that does not support the ReadWriteMany access mode, used by the persistentvolumeclaim-readwritemany check, can be set multiple times. By default the default StorageClasses of GKE, EKS and AKS are used"),
+		kubernetesVersion:             fs.String("kubernetes-version", "v1.18", "Setting the kubernetes-version will affect the checks ran against the manifests. Set this to the version of Kubernetes that you're using in production for the best results."),
+		pluginFiles:                   fs.StringSlice("plugin", []string{}, "Load checks from a WebAssembly (WASI) plugin, can be set multiple times"),
+		pluginRuntime:                 fs.String("plugin-runtime", "wasmtime", "The WebAssembly runtime that is used to run the plugins, must support '<runtime> run module.wasm args...'"),
+		ignoreKinds:                   fs.StringSlice("ignore-kind", []string{}, "Do not score objects of this kind, such as CronJob, can be set multiple times"),
+		onlyKinds:                     fs.StringSlice("only-kind", []string{}, "Only score objects of this kind, such as Deployment, can be set multiple times. By default, objects of all kinds are scored"),
+		unknownKinds:                  fs.String("unknown-kinds", config.UnknownKindsWarn, "How objects of kinds that kube-score does not support, such as custom resources, are handled. Set to 'warn' to report them as skipped and log a warning, 'skip' to silently ignore them, or 'fail' to exit with an error"),
+		parallelism:                   fs.Int("parallelism", 0, "The number of checks that are run concurrently. By default, one check per CPU is run at a time"),
+		profiles:                      fs.StringSlice("profile", []string{}, "Only run the checks of this profile, can be set multiple times. Set to 'security', 'reliability', 'cost', 'all' or a profile from the configuration file"),
+	}
+}
+
+// configuration creates the configuration of the checks from the flags and the configuration file. The files
+// to score are not set.
+func (f *checkFlags) configuration(file config.File) (config.Configuration, error) {
+	ignoredTests := listToStructMap(f.ignoreTests)
+	enabledOptionalTests := listToStructMap(f.optionalTests)
+	gradeOverrides := make(map[string]scorecard.Grade)
+
+	for id, check := range file.Checks {
+		if check.Enabled != nil {
+			if *check.Enabled {
+				enabledOptionalTests[id] = struct{}{}
+			} else {
+				ignoredTests[id] = struct{}{}
+			}
+		}
+		if check.Grade != "" {
+			grade, err := scorecard.ParseGrade(check.Grade)
+			if err != nil {
+				return config.Configuration{}, fmt.Errorf("invalid grade for %s in configuration file: %w", id, err)
+			}
+			gradeOverrides[id] = grade
+		}
+	}
+
+	for _, ids := range []map[string]struct{}{ignoredTests, enabledOptionalTests} {
+		for id := range ids {
+			warnRenamedCheck(id)
+		}
+	}
+	for id := range gradeOverrides {
+		warnRenamedCheck(id)
+	}
+
+	kubeVer, err := config.ParseSemver(*f.kubernetesVersion)
+	if err != nil {
+		return config.Configuration{}, errors.New("Invalid --kubernetes-version. Use on format \"vN.NN\"")
+	}
+
+	if *f.podSecurityStandard != "" {
+		if _, err := podsecurity.ParseLevel(*f.podSecurityStandard); err != nil {
+			return config.Configuration{}, fmt.Errorf("Invalid --pod-security-standard: %w", err)
+		}
+	}
+
+	switch *f.unknownKinds {
+	case config.UnknownKindsWarn, config.UnknownKindsSkip, config.UnknownKindsFail:
+	default:
+		return config.Configuration{}, errors.New("Invalid --unknown-kinds, must be one of 'warn', 'skip' or 'fail'")
+	}
+
+	if *f.parallelism < 0 {
+		return config.Configuration{}, errors.New("Invalid --parallelism, must be a positive number")
+	}
+
+	profiles := *f.profiles
+	if len(profiles) == 0 {
+		profiles = file.Profiles
+	}
+	if _, err := checks.ProfileChecks(profiles, file.CustomProfiles); err != nil {
+		return config.Configuration{}, fmt.Errorf("Invalid --profile: %w", err)
+	}
+
+	loadedPlugins, err := loadPlugins(*f.pluginFiles, *f.pluginRuntime)
+	if err != nil {
+		return config.Configuration{}, err
+	}
+
+	return config.Configuration{
+		IgnoreContainerCpuLimitRequirement:    *f.ignoreContainerCpuLimit,
+		IgnoreContainerMemoryLimitRequirement: *f.ignoreContainerMemoryLimit,
+		IgnoredTests:                          ignoredTests,
+		EnabledOptionalTests:                  enabledOptionalTests,
+		EnableAllOptionalTests:                *f.allOptionalTests,
+		UseIgnoreChecksAnnotation:             !*f.disableIgnoreChecksAnnotation,
+		KubernetesVersion:                     kubeVer,
+		GradeOverrides:                        gradeOverrides,
+		RequiredDroppedCapabilities:           *f.requiredDroppedCapabilities,
+		PodSecurityStandard:                   *f.podSecurityStandard,
+		AllowedHostPaths:                      *f.allowedHostPaths,
+		MaxMemoryLimitRatio:                   *f.maxMemoryLimitRatio,
+		RequiredLabels:                        *f.requiredLabels,
+		ReadWriteOnceStorageClasses:           *f.readWriteOnceStorageClasses,
+		AllowedPriorityClasses:                *f.allowedPriorityClasses,
+		AllowedImageRegistries:                append(*f.allowedImageRegistries, file.AllowedImageRegistries...),
+		CustomChecks:                          file.CustomChecks,
+		Plugins:                               loadedPlugins,
+		Profiles:                              profiles,
+		CustomProfiles:                        file.CustomProfiles,
+		IgnoreRules:                           file.Ignore,
+		WorkloadKinds:                         file.WorkloadKinds,
+		IgnoredKinds:                          *f.ignoreKinds,
+		OnlyKinds:                             *f.onlyKinds,
+		UnknownKinds:                          *f.unknownKinds,
+		Parallelism:                           *f.parallelism,
+	}, nil
+}
+
+// warnRenamedCheck logs a warning if the check with the id has been renamed. The old ID still works.
+func warnRenamedCheck(id string) {
+	if renamed, ok := scorecard.RenamedChecks[id]; ok {
+		logger.Warn("The check has been renamed, use the new ID", "id", id, "new_id", renamed)
+	}
+}
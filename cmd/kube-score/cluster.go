@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+
+	"github.com/zegl/kube-score/internal/logger"
+)
+
+const clusterInputPrefix = "cluster://"
+
+// clusterKinds is the list of resources that are fetched from the cluster.
+// Pods and Jobs are not fetched, as they are most often owned by one of the other kinds,
+// and would otherwise be scored twice.
+var clusterKinds = []string{
+	"deployments",
+	"statefulsets",
+	"daemonsets",
+	"cronjobs",
+	"services",
+	"ingresses",
+	"networkpolicies",
+	"poddisruptionbudgets",
+	"horizontalpodautoscalers",
+	"persistentvolumeclaims",
+	"serviceaccounts",
+	"roles",
+	"clusterroles",
+	"rolebindings",
+	"clusterrolebindings",
+}
+
+// resourceReferencePattern matches references to a resource in the cluster, as used by kubectl, such as
+// "deployment/foo" or "deployments.apps/foo"
+var resourceReferencePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9.-]*/[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+
+type clusterOptions struct {
+	kubeconfig string
+	context    string
+	namespace  string
+	selector   string
+
+	// resources are the references to the resources to fetch. If empty, all resources of the clusterKinds are
+	// fetched.
+	resources []string
+}
+
+// splitResourceReferences separates the references to resources in the cluster, such as "deployment/foo", from
+// the files in args. An argument is only a reference if there is no file with the same name.
+func splitResourceReferences(args []string) (files, resources []string) {
+	for _, arg := range args {
+		if resourceReferencePattern.MatchString(arg) {
+			if _, err := os.Stat(arg); os.IsNotExist(err) {
+				resources = append(resources, arg)
+				continue
+			}
+		}
+		files = append(files, arg)
+	}
+	return files, resources
+}
+
+// clientConfig returns the configuration of the client. The kubeconfig is loaded with the same rules as kubectl, from
+// --kubeconfig, the KUBECONFIG environment variable or ~/.kube/config, and the service account of the pod is used
+// when there is no kubeconfig and kube-score is running in a cluster.
+func clientConfig(opts clusterOptions) clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = opts.kubeconfig
+
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: opts.context}
+	overrides.Context.Namespace = opts.namespace
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+}
+
+// fetchFromCluster fetches objects from a running cluster, in the same way as "kubectl get", and returns them as a
+// YAML stream
+func fetchFromCluster(ctx context.Context, opts clusterOptions) (namedReader, error) {
+	cnf := clientConfig(opts)
+
+	restConfig, err := cnf.ClientConfig()
+	if err != nil {
+		return namedReader{}, fmt.Errorf("failed to load the kubeconfig: %w", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return namedReader{}, fmt.Errorf("failed to create the client: %w", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return namedReader{}, fmt.Errorf("failed to create the client: %w", err)
+	}
+
+	mapper := restmapper.NewShortcutExpander(
+		restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		discoveryClient,
+		func(warning string) { logger.Warn(warning) },
+	)
+
+	namespace := opts.namespace
+	if len(opts.resources) > 0 {
+		// Named resources are fetched from the namespace of the current context, in the same way as "kubectl get"
+		namespace, _, err = cnf.Namespace()
+		if err != nil {
+			return namedReader{}, fmt.Errorf("failed to load the kubeconfig: %w", err)
+		}
+	}
+
+	objects, err := clusterObjects(ctx, dynamicClient, mapper, namespace, opts)
+	if err != nil {
+		return namedReader{}, fmt.Errorf("failed to fetch objects from the cluster: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, object := range objects {
+		// The managed fields are hidden by "kubectl get" as well, they are only noise when reading the objects
+		object.SetManagedFields(nil)
+
+		doc, err := yaml.Marshal(object.Object)
+		if err != nil {
+			return namedReader{}, fmt.Errorf("failed to fetch objects from the cluster: %w", err)
+		}
+		buf.WriteString("---\n")
+		buf.Write(doc)
+	}
+
+	name := opts.context
+	if name == "" {
+		name = "current-context"
+	}
+
+	return namedReader{Reader: &buf, name: clusterInputPrefix + name}, nil
+}
+
+// clusterObjects returns the objects referenced by opts.resources from the namespace, or all objects of the
+// clusterKinds if there are no references. The objects are fetched from all namespaces if the namespace is empty.
+func clusterObjects(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, namespace string, opts clusterOptions) ([]unstructured.Unstructured, error) {
+	if len(opts.resources) > 0 {
+		var objects []unstructured.Unstructured
+		for _, reference := range opts.resources {
+			parts := strings.SplitN(reference, "/", 2)
+			mapping, err := restMapping(mapper, parts[0])
+			if err != nil {
+				return nil, err
+			}
+			object, err := resourceClient(client, mapping, namespace).Get(ctx, parts[1], metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, *object)
+		}
+		return objects, nil
+	}
+
+	var objects []unstructured.Unstructured
+	for _, kind := range clusterKinds {
+		mapping, err := restMapping(mapper, kind)
+		if meta.IsNoMatchError(err) {
+			// Not all kinds are served by all clusters, such as if an API version has been removed
+			logger.Debug("Resource is not served by the cluster", "resource", kind)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		list, err := resourceClient(client, mapping, namespace).List(ctx, metav1.ListOptions{LabelSelector: opts.selector})
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, list.Items...)
+	}
+	return objects, nil
+}
+
+// restMapping returns the mapping of a resource as it's written in "kubectl get", such as "deployments", "deploy" or
+// "deployments.apps"
+func restMapping(mapper meta.RESTMapper, resource string) (*meta.RESTMapping, error) {
+	// "clusterroles.rbac.authorization.k8s.io" is parsed both as the resource in the version "rbac" of the group
+	// "authorization.k8s.io", and as the resource in the group "rbac.authorization.k8s.io"
+	fullySpecified, groupResource := schema.ParseResourceArg(strings.ToLower(resource))
+	if fullySpecified != nil {
+		if gvk, err := mapper.KindFor(*fullySpecified); err == nil {
+			return mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		}
+	}
+
+	gvk, err := mapper.KindFor(groupResource.WithVersion(""))
+	if err != nil {
+		return nil, err
+	}
+	return mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+}
+
+// resourceClient returns the client of the resource in the namespace. Resources that are not namespaced are always
+// fetched from the whole cluster.
+func resourceClient(client dynamic.Interface, mapping *meta.RESTMapping, namespace string) dynamic.ResourceInterface {
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return client.Resource(mapping.Resource)
+	}
+	return client.Resource(mapping.Resource).Namespace(namespace)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestClientConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kube-score")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	assert.NoError(t, ioutil.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com
+- name: prod
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: dev
+  context:
+    cluster: dev
+- name: prod
+  context:
+    cluster: prod
+    namespace: payments
+`), 0600))
+
+	for _, tc := range []struct {
+		opts      clusterOptions
+		host      string
+		namespace string
+	}{
+		{clusterOptions{kubeconfig: kubeconfig}, "https://dev.example.com", "default"},
+		{clusterOptions{kubeconfig: kubeconfig, context: "prod"}, "https://prod.example.com", "payments"},
+		{clusterOptions{kubeconfig: kubeconfig, context: "prod", namespace: "bar"}, "https://prod.example.com", "bar"},
+	} {
+		cnf := clientConfig(tc.opts)
+
+		restConfig, err := cnf.ClientConfig()
+		assert.NoError(t, err)
+		assert.Equal(t, tc.host, restConfig.Host)
+
+		namespace, _, err := cnf.Namespace()
+		assert.NoError(t, err)
+		assert.Equal(t, tc.namespace, namespace)
+	}
+
+	_, err = clientConfig(clusterOptions{kubeconfig: kubeconfig, context: "staging"}).ClientConfig()
+	assert.Error(t, err)
+}
+
+func TestClusterObjects(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+
+	object := func(apiVersion, kind, namespace, name string, labels map[string]string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetNamespace(namespace)
+		u.SetName(name)
+		u.SetLabels(labels)
+		return u
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Group: "apps", Version: "v1", Resource: "deployments"}:                       "DeploymentList",
+		{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}: "ClusterRoleList",
+	},
+		object("apps/v1", "Deployment", "foo", "a", map[string]string{"app": "a"}),
+		object("apps/v1", "Deployment", "bar", "b", map[string]string{"app": "b"}),
+		object("rbac.authorization.k8s.io/v1", "ClusterRole", "", "c", map[string]string{"app": "a"}),
+	)
+
+	names := func(objects []unstructured.Unstructured) (res []string) {
+		for _, o := range objects {
+			res = append(res, o.GetKind()+"/"+o.GetNamespace()+"/"+o.GetName())
+		}
+		return res
+	}
+
+	// All kinds, the kinds that are not served by the cluster are skipped
+	objects, err := clusterObjects(context.Background(), client, mapper, "", clusterOptions{})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Deployment/foo/a", "Deployment/bar/b", "ClusterRole//c"}, names(objects))
+
+	objects, err = clusterObjects(context.Background(), client, mapper, "foo", clusterOptions{})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Deployment/foo/a", "ClusterRole//c"}, names(objects))
+
+	objects, err = clusterObjects(context.Background(), client, mapper, "", clusterOptions{selector: "app=b"})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Deployment/bar/b"}, names(objects))
+
+	// Named resources
+	objects, err = clusterObjects(context.Background(), client, mapper, "bar", clusterOptions{resources: []string{"deployment/b", "clusterroles.rbac.authorization.k8s.io/c"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Deployment/bar/b", "ClusterRole//c"}, names(objects))
+
+	_, err = clusterObjects(context.Background(), client, mapper, "foo", clusterOptions{resources: []string{"deployment/b"}})
+	assert.Error(t, err)
+
+	_, err = clusterObjects(context.Background(), client, mapper, "foo", clusterOptions{resources: []string{"service/a"}})
+	assert.Error(t, err)
+}
+
+func TestSplitResourceReferences(t *testing.T) {
+	files, resources := splitResourceReferences([]string{
+		"deployment/foo",
+		"deployments.apps/foo-bar",
+		"manifests/base/deployment.yaml",
+		"-",
+		"deployment.yaml",
+		"deployment/Foo",
+		"helm://chart/path",
+	})
+	assert.Equal(t, []string{"deployment/foo", "deployments.apps/foo-bar"}, resources)
+	assert.Equal(t, []string{"manifests/base/deployment.yaml", "-", "deployment.yaml", "deployment/Foo", "helm://chart/path"}, files)
+}
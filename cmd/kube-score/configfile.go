@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/zegl/kube-score/config"
+)
+
+// readConfigFile reads the configuration file at path. If path is empty, the default configuration files
+// are read from the current directory if they exist.
+func readConfigFile(path string) (config.File, error) {
+	if path == "" {
+		for _, name := range config.DefaultFileNames {
+			if _, err := os.Stat(name); err == nil {
+				path = name
+				break
+			}
+		}
+	}
+
+	if path == "" {
+		return config.File{}, nil
+	}
+
+	fp, err := os.Open(path)
+	if err != nil {
+		return config.File{}, err
+	}
+	defer fp.Close()
+
+	f, err := config.ParseFile(fp)
+	if err != nil {
+		return config.File{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// applyConfigFileFlags sets the flags from the configuration file that have not been set on the command line
+func applyConfigFileFlags(fs *flag.FlagSet, file config.File) error {
+	for name, value := range file.Flags {
+		f := fs.Lookup(name)
+		if f == nil {
+			return fmt.Errorf("unknown option in configuration file: %s", name)
+		}
+
+		// Flags set on the command line take precedence
+		if f.Changed {
+			continue
+		}
+
+		values := []interface{}{value}
+		if list, ok := value.([]interface{}); ok {
+			values = list
+		}
+
+		for _, v := range values {
+			if err := fs.Set(name, fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("invalid value for %s in configuration file: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
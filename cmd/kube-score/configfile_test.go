@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	flag "github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+)
+
+func TestApplyConfigFileFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	exitOneOnWarning := fs.Bool("exit-one-on-warning", false, "")
+	kubernetesVersion := fs.String("kubernetes-version", "v1.18", "")
+	outputFormat := fs.String("output-format", "human", "")
+	optionalTests := fs.StringSlice("enable-optional-test", []string{}, "")
+	verbose := fs.CountP("verbose", "v", "")
+
+	assert.Nil(t, fs.Parse([]string{"--output-format", "ci"}))
+
+	err := applyConfigFileFlags(fs, config.File{
+		Flags: map[string]interface{}{
+			"exit-one-on-warning":  true,
+			"kubernetes-version":   "v1.22",
+			"output-format":        "json",
+			"enable-optional-test": []interface{}{"a", "b"},
+			"verbose":              2,
+		},
+	})
+	assert.Nil(t, err)
+
+	assert.True(t, *exitOneOnWarning)
+	assert.Equal(t, "v1.22", *kubernetesVersion)
+	assert.Equal(t, "ci", *outputFormat) // set on the command line
+	assert.Equal(t, []string{"a", "b"}, *optionalTests)
+	assert.Equal(t, 2, *verbose)
+}
+
+func TestApplyConfigFileFlagsUnknown(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	err := applyConfigFileFlags(fs, config.File{
+		Flags: map[string]interface{}{
+			"foo": true,
+		},
+	})
+	assert.NotNil(t, err)
+}
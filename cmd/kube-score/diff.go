@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/zegl/kube-score/compare"
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+)
+
+// diffFiles scores two versions of the same objects, and reports the findings that are new, fixed and changed.
+// It exits with code 1 if there are new findings, or findings with a lower grade than before.
+func diffFiles(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ContinueOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	compareWith := fs.String("compare-with", "", "Compare with a scorecard created with 'score --output-format json', instead of scoring the old files. All arguments are the new files")
+	outputFormat := fs.StringP("output-format", "o", "human", "Set to 'human' or 'json'")
+	exitCodeOn := fs.String("exit-code-on", "critical", "Exit with code 1 if any new or changed finding has this grade or lower. Set to 'critical', 'high', 'warning' or 'none'")
+	exitOneOnWarning := fs.Bool("exit-one-on-warning", false, "Exit with code 1 in case of new warnings, the same as --exit-code-on warning")
+	configFile := fs.String("config", "", "Path to a configuration file. By default .kube-score.yml is read from the current directory if it exists")
+	includeGlobs := fs.StringSlice("include", []string{}, "Only score files in directories matching this glob pattern, can be set multiple times")
+	excludeGlobs := fs.StringSlice("exclude", []string{}, "Skip files and directories matching this glob pattern when reading directories, can be set multiple times")
+	checks := registerCheckFlags(fs)
+	setDefault(fs, binName, "diff", false)
+
+	err := fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %s", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	if *outputFormat != "human" && *outputFormat != "json" {
+		return fmt.Errorf("Error: --output-format must be set to: 'human' or 'json'")
+	}
+
+	threshold, err := exitCodeOnGrade(*exitCodeOn, *exitOneOnWarning)
+	if err != nil {
+		return err
+	}
+
+	file, err := readConfigFile(*configFile)
+	if err != nil {
+		return err
+	}
+	if err := applyConfigFileFlags(fs, checkOptionsOnly(fs, file)); err != nil {
+		return err
+	}
+
+	cnf, err := checks.configuration(file)
+	if err != nil {
+		return err
+	}
+
+	var oldFindings []compare.Finding
+	var newPaths []string
+
+	switch {
+	case *compareWith != "":
+		newPaths = fs.Args()
+		fp, err := os.Open(*compareWith)
+		if err != nil {
+			return parseError{err}
+		}
+		oldFindings, err = compare.ParseFindings(fp)
+		fp.Close()
+		if err != nil {
+			return parseError{fmt.Errorf("%s: %w", *compareWith, err)}
+		}
+
+	case fs.NArg() == 2:
+		newPaths = fs.Args()[1:]
+		oldFindings, err = scoreFindings(context.Background(), cnf, fs.Args()[:1], *includeGlobs, *excludeGlobs)
+		if err != nil {
+			return fmt.Errorf("failed to score the old files: %w", err)
+		}
+	}
+
+	if len(newPaths) == 0 {
+		return usageError{fmt.Sprintf(`Error: No files given as arguments.
+
+Usage: %s diff [--flag1 --flag2] old new
+       %s diff --compare-with scorecard.json [--flag1 --flag2] file1 file2 ...
+
+The old and new files can be files or directories, directories are read recursively.`, execName(binName), execName(binName))}
+	}
+
+	newFindings, err := scoreFindings(context.Background(), cnf, newPaths, *includeGlobs, *excludeGlobs)
+	if err != nil {
+		return fmt.Errorf("failed to score the new files: %w", err)
+	}
+
+	res := compare.Compare(oldFindings, newFindings)
+
+	if *outputFormat == "json" {
+		if err := res.WriteJSON(os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		res.WriteHuman(os.Stdout)
+	}
+
+	if len(res.Regressions(threshold)) > 0 {
+		os.Exit(exitCodePolicyFailure)
+	}
+	return nil
+}
+
+// scoreFindings scores the files, and returns the findings
+func scoreFindings(ctx context.Context, cnf config.Configuration, paths, includeGlobs, excludeGlobs []string) ([]compare.Finding, error) {
+	files, err := expandPaths(paths, includeGlobs, excludeGlobs)
+	if err != nil {
+		return nil, parseError{err}
+	}
+
+	cnf.AllFiles, err = openInputs(ctx, files, nil, nil)
+	if err != nil {
+		return nil, parseError{err}
+	}
+
+	parsed, err := parser.ParseFiles(ctx, cnf)
+	if err != nil {
+		return nil, parseError{err}
+	}
+
+	card, err := score.Score(ctx, parsed, cnf)
+	if err != nil {
+		return nil, err
+	}
+
+	return compare.Findings(card), nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// The exit codes of the score, baseline and diff actions
+const (
+	exitCodeOK = 0
+
+	// exitCodePolicyFailure is used when a check has a grade at or below the --exit-code-on grade
+	exitCodePolicyFailure = 1
+
+	// exitCodeParseError is used when the input can't be read or parsed
+	exitCodeParseError = 2
+
+	// exitCodeError is used for all other errors, such as invalid flags, or if the objects can't be fetched from the cluster
+	exitCodeError = 3
+)
+
+// parseError is an error while reading or parsing the input
+type parseError struct {
+	err error
+}
+
+func (e parseError) Error() string {
+	return e.err.Error()
+}
+
+func (e parseError) Unwrap() error {
+	return e.err
+}
+
+// exitCodeForError returns the exit code for an error that is returned by an action
+func exitCodeForError(err error) int {
+	var pe parseError
+	if errors.As(err, &pe) {
+		return exitCodeParseError
+	}
+	return exitCodeError
+}
+
+// scoredChecks is the result of the checks that decides the exit code, a scorecard.Scorecard or the
+// scorecard.Totals of the objects that have been written
+type scoredChecks interface {
+	Score() float64
+	AnyBelowOrEqualToGrade(threshold scorecard.Grade) bool
+}
+
+// policyExitCode returns exitCodePolicyFailure if the score is below minScore, or if minScore is not set and any
+// check has a grade at or below exitGrade, such as a HIGH finding with --exit-code-on high
+func policyExitCode(checks scoredChecks, minScore float64, exitGrade scorecard.Grade) int {
+	if minScore > 0 {
+		if checks.Score() < minScore {
+			return exitCodePolicyFailure
+		}
+		return exitCodeOK
+	}
+	if checks.AnyBelowOrEqualToGrade(exitGrade) {
+		return exitCodePolicyFailure
+	}
+	return exitCodeOK
+}
+
+// exitCodeOnGrade returns the grade of --exit-code-on. --exit-one-on-warning is the same as --exit-code-on warning.
+func exitCodeOnGrade(exitCodeOn string, exitOneOnWarning bool) (scorecard.Grade, error) {
+	grade, err := parseThresholdGrade(exitCodeOn)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid --exit-code-on: %w", err)
+	}
+	if exitOneOnWarning {
+		return scorecard.GradeWarning, nil
+	}
+	return grade, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestExitCodeForError(t *testing.T) {
+	assert.Equal(t, exitCodeError, exitCodeForError(errors.New("kubectl failed")))
+	assert.Equal(t, exitCodeParseError, exitCodeForError(parseError{errors.New("yaml: line 3: did not find expected node content")}))
+	assert.Equal(t, exitCodeParseError, exitCodeForError(fmt.Errorf("failed to score the new files: %w", parseError{errors.New("no such file")})))
+}
+
+func TestExitCodeOnGrade(t *testing.T) {
+	g, err := exitCodeOnGrade("critical", false)
+	assert.NoError(t, err)
+	assert.Equal(t, scorecard.GradeCritical, g)
+
+	g, err = exitCodeOnGrade("warning", false)
+	assert.NoError(t, err)
+	assert.Equal(t, scorecard.GradeWarning, g)
+
+	g, err = exitCodeOnGrade("critical", true)
+	assert.NoError(t, err)
+	assert.Equal(t, scorecard.GradeWarning, g)
+
+	// No check has a grade at or below "none"
+	g, err = exitCodeOnGrade("none", false)
+	assert.NoError(t, err)
+	assert.False(t, scorecard.Scorecard{"a": &scorecard.ScoredObject{Checks: []scorecard.TestScore{{Grade: scorecard.GradeCritical}}}}.AnyBelowOrEqualToGrade(g))
+
+	_, err = exitCodeOnGrade("ok", false)
+	assert.Error(t, err)
+}
+
+func TestPolicyExitCodeHigh(t *testing.T) {
+	ctx := context.Background()
+	parsed, err := parser.ParseFiles(ctx, config.Configuration{AllFiles: []domain.NamedReader{namedReader{Reader: strings.NewReader(`
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: apps-reader
+  namespace: foo
+rules:
+- apiGroups: ["apps"]
+  resources: ["*"]
+  verbs: ["get", "list", "watch"]
+`), name: "role.yaml"}}})
+	assert.NoError(t, err)
+	scoreCard, err := score.Score(ctx, parsed, config.Configuration{})
+	assert.NoError(t, err)
+
+	// The wildcard in resources is a HIGH finding, it only fails with --exit-code-on high or lower
+	for exitCodeOn, expected := range map[string]int{
+		"critical": exitCodeOK,
+		"high":     exitCodePolicyFailure,
+		"warning":  exitCodePolicyFailure,
+		"none":     exitCodeOK,
+	} {
+		g, err := exitCodeOnGrade(exitCodeOn, false)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, policyExitCode(scoreCard, 0, g), exitCodeOn)
+	}
+
+	// The streamed output decides the exit code in the same way
+	var totals scorecard.Totals
+	for _, o := range *scoreCard {
+		totals.Add(o)
+	}
+	assert.Equal(t, exitCodePolicyFailure, policyExitCode(totals, 0, scorecard.GradeHigh))
+	assert.Equal(t, exitCodeOK, policyExitCode(totals, 0, scorecard.GradeCritical))
+
+	// --min-score replaces --exit-code-on
+	assert.Equal(t, exitCodeOK, policyExitCode(scoreCard, 1, scorecard.GradeHigh))
+	assert.Equal(t, exitCodePolicyFailure, policyExitCode(scoreCard, 10, scorecard.GradeCritical))
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/eidolon/wordwrap"
+	flag "github.com/spf13/pflag"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// runExplain prints the documentation of a check
+func runExplain(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	setDefault(fs, binName, "explain", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %s", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	if fs.NArg() != 1 {
+		return errors.New("expected the ID of a check, such as: explain pod-probes")
+	}
+
+	allChecks := score.RegisterAllChecks(parser.Empty(), config.Configuration{})
+	return explainCheck(os.Stdout, allChecks, fs.Arg(0))
+}
+
+// explainCheck writes the documentation of the check with the id to w
+func explainCheck(w io.Writer, allChecks *checks.Checks, id string) error {
+	id = scorecard.CheckID(id)
+	var check *ks.Check
+	for _, c := range allChecks.All() {
+		if c.ID == id {
+			c := c
+			check = &c
+			break
+		}
+	}
+	if check == nil {
+		return fmt.Errorf("unknown check %q, run \"list\" to see all checks", id)
+	}
+
+	doc, documented := allChecks.Documentation(id)
+
+	fmt.Fprintf(w, "%s (%s)\n\n", check.ID, check.Name)
+	fmt.Fprintf(w, "Target: %s\n", check.TargetType)
+	// The checks of plugins are not documented, and their grade is not known until they are run
+	if documented {
+		fmt.Fprintf(w, "Grade: %s\n", strings.ToLower(doc.Grade.String()))
+		fmt.Fprintf(w, "Severity: %s\n", doc.Grade.Severity().String())
+	}
+	if check.Optional {
+		fmt.Fprintf(w, "Enabled: optional, enable it with --enable-optional-test %s or --enable-all-optional-tests\n", check.ID)
+	} else {
+		fmt.Fprintf(w, "Enabled: default\n")
+	}
+	if profiles := checks.ProfilesOf(check.ID, nil); len(profiles) > 0 {
+		fmt.Fprintf(w, "Profiles: %s\n", strings.Join(profiles, ", "))
+	}
+
+	details := doc.Details
+	if details == "" {
+		details = check.Comment
+	}
+	writeSection(w, "What it checks", wrap(details))
+	writeSection(w, "Why it matters", wrap(doc.Rationale))
+	writeSection(w, "How to fix it", wrap(doc.Remediation))
+	writeSection(w, "Example of a failing object", doc.FailingExample)
+	writeSection(w, "Example of a passing object", doc.PassingExample)
+
+	ignore := fmt.Sprintf(`The check can be ignored for a single object with the kube-score/ignore annotation:
+
+metadata:
+  annotations:
+    kube-score/ignore: %s
+
+Or for all objects with --ignore-test %s, or in the configuration file:
+
+checks:
+  %s:
+    enabled: false`, check.ID, check.ID, check.ID)
+	writeSection(w, "How to ignore it", ignore)
+
+	fmt.Fprintf(w, "\nMore information: %s\n", doc.URL)
+	return nil
+}
+
+// wrap wraps the text to lines that fit in a terminal, after the indentation of the section
+func wrap(text string) string {
+	if text == "" {
+		return ""
+	}
+	return wordwrap.Wrapper(96, false)(text)
+}
+
+// writeSection writes a heading and the indented text, sections without text are not written
+func writeSection(w io.Writer, heading, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(w, "\n%s:\n", heading)
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		if line == "" {
+			fmt.Fprintln(w)
+			continue
+		}
+		fmt.Fprintf(w, "    %s\n", line)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+)
+
+func TestExplainCheck(t *testing.T) {
+	allChecks := score.RegisterAllChecks(parser.Empty(), config.Configuration{})
+
+	var out bytes.Buffer
+	assert.NoError(t, explainCheck(&out, allChecks, "pod-probes"))
+	assert.Contains(t, out.String(), "pod-probes (Pod Probes)\n")
+	assert.Contains(t, out.String(), "Grade: critical\n")
+	assert.Contains(t, out.String(), "Profiles: reliability\n")
+	assert.Contains(t, out.String(), "\nWhy it matters:\n")
+	assert.Contains(t, out.String(), "\nExample of a failing object:\n    apiVersion: v1\n")
+	assert.Contains(t, out.String(), "\nExample of a passing object:\n")
+	assert.Contains(t, out.String(), "        kube-score/ignore: pod-probes\n")
+	assert.Contains(t, out.String(), "More information: https://github.com/zegl/kube-score/blob/master/README_PROBES.md\n")
+
+	// Checks without a long description use the comment of the check, and have no examples
+	out.Reset()
+	assert.NoError(t, explainCheck(&out, allChecks, "container-seccomp-profile"))
+	assert.Contains(t, out.String(), "Enabled: optional, enable it with --enable-optional-test container-seccomp-profile or --enable-all-optional-tests\n")
+	assert.Contains(t, out.String(), "\nWhat it checks:\n    Makes sure that all pods have at a seccomp policy configured.")
+	assert.NotContains(t, out.String(), "Example of a failing object")
+
+	assert.Error(t, explainCheck(&out, allChecks, "no-such-check"))
+}
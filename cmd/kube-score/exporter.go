@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/exporter"
+	"github.com/zegl/kube-score/internal/logger"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+)
+
+// runExporter scores the objects in the cluster periodically, and exposes the results as Prometheus metrics
+func runExporter(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	listenAddress := fs.String("listen-address", ":8080", "The address that the metrics are served on")
+	interval := fs.Duration("interval", 5*time.Minute, "How often the objects in the cluster are scored")
+	timeout := fs.Duration("timeout", 0, "Stop fetching and scoring the objects in the cluster if it takes longer than this, such as 1m. By default there is no timeout")
+	configFile := fs.String("config", "", "Path to a configuration file. By default .kube-score.yml is read from the current directory if it exists")
+	kubeconfig := fs.String("kubeconfig", "", "Path to the kubeconfig file. By default, the kubeconfig of kubectl is used, or the ServiceAccount of the pod when running in a cluster")
+	kubeContext := fs.String("context", "", "The kubeconfig context to use")
+	namespace := fs.StringP("namespace", "n", "", "Only score objects in this namespace. By default, objects in all namespaces are scored")
+	selector := fs.StringP("selector", "l", "", "Only score objects matching this label selector")
+	checks := registerCheckFlags(fs)
+	verbose := fs.CountP("verbose", "v", "Enable debug logging")
+	logs := registerLogFlags(fs)
+	setDefault(fs, binName, "exporter", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %s", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	if *interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	if *timeout < 0 {
+		return fmt.Errorf("--timeout must not be negative")
+	}
+
+	file, err := readConfigFile(*configFile)
+	if err != nil {
+		return err
+	}
+	if err := applyConfigFileFlags(fs, checkOptionsOnly(fs, file)); err != nil {
+		return err
+	}
+	if err := logs.setup(*verbose > 0); err != nil {
+		return err
+	}
+
+	cnf, err := checks.configuration(file)
+	if err != nil {
+		return err
+	}
+
+	opts := clusterOptions{
+		kubeconfig: *kubeconfig,
+		context:    *kubeContext,
+		namespace:  *namespace,
+		selector:   *selector,
+	}
+
+	exp := exporter.New()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exp)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+
+	srv := &http.Server{
+		Addr:              *listenAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		logger.Info("Listening", "address", *listenAddress)
+		errs <- srv.ListenAndServe()
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		scoreCluster(exp, opts, cnf, *timeout)
+
+		select {
+		case err := <-errs:
+			return err
+		case <-stop:
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			return srv.Shutdown(ctx)
+		case <-ticker.C:
+		}
+	}
+}
+
+// scoreCluster fetches and scores the objects in the cluster, and updates the metrics. The run is cancelled if it takes
+// longer than the timeout, unless the timeout is zero.
+func scoreCluster(exp *exporter.Exporter, opts clusterOptions, cnf config.Configuration, timeout time.Duration) {
+	start := time.Now()
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := func() error {
+		fetched, err := fetchFromCluster(ctx, opts)
+		if err != nil {
+			return err
+		}
+
+		cnf.AllFiles = []ks.NamedReader{fetched}
+		parsed, err := parser.ParseFiles(ctx, cnf)
+		if err != nil {
+			return err
+		}
+
+		card, err := score.Score(ctx, parsed, cnf)
+		if err != nil {
+			return err
+		}
+
+		exp.Update(card, time.Now(), time.Since(start))
+		return nil
+	}()
+
+	if err != nil {
+		logger.Error("Failed to score the cluster", "error", err, "duration", time.Since(start))
+		exp.Failed(time.Now(), time.Since(start))
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/internal/glob"
+)
+
+var manifestExtensions = map[string]struct{}{
+	".yaml": {},
+	".yml":  {},
+	".json": {},
+}
+
+// expandPaths replaces all directories in paths with the manifest files found in them (recursively).
+// Only files with a .yaml, .yml or .json extension are included from directories.
+// The include and exclude glob patterns are matched against the path relative to the directory.
+// Files that are given explicitly are always kept.
+func expandPaths(paths, include, exclude []string) ([]string, error) {
+	var res []string
+
+	for _, path := range paths {
+		if path == "-" || strings.HasPrefix(path, helmInputPrefix) || strings.HasPrefix(path, kustomizeInputPrefix) {
+			res = append(res, path)
+			continue
+		}
+
+		stat, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !stat.IsDir() {
+			res = append(res, path)
+			continue
+		}
+
+		root := path
+		err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				if rel != "." && glob.MatchAny(exclude, rel) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if _, ok := manifestExtensions[strings.ToLower(filepath.Ext(path))]; !ok {
+				return nil
+			}
+			if len(include) > 0 && !glob.MatchAny(include, rel) {
+				return nil
+			}
+			if glob.MatchAny(exclude, rel) {
+				return nil
+			}
+
+			res = append(res, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// openInputs opens all files, and renders the Helm charts and kustomizations. "-" is read from STDIN. helm and
+// kustomize are killed if ctx is done.
+func openInputs(ctx context.Context, files, helmValues, helmSet []string) ([]ks.NamedReader, error) {
+	var res []ks.NamedReader
+
+	for _, file := range files {
+		switch {
+		case file == "-":
+			res = append(res, namedReader{Reader: os.Stdin, name: "STDIN"})
+
+		case strings.HasPrefix(file, helmInputPrefix):
+			rendered, err := renderHelmChart(ctx, file, helmValues, helmSet)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, rendered)
+
+		case strings.HasPrefix(file, kustomizeInputPrefix):
+			rendered, err := renderKustomization(ctx, file)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, rendered)
+
+		default:
+			fp, err := os.Open(file)
+			if err != nil {
+				return nil, err
+			}
+			filename, _ := filepath.Abs(file)
+			res = append(res, namedReader{Reader: fp, name: filename})
+		}
+	}
+
+	return res, nil
+}
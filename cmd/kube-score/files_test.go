@@ -0,0 +1,57 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPaths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kube-score")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, f := range []string{
+		"deployment.yaml",
+		"service.yml",
+		"README.md",
+		"nested/pod.json",
+		"nested/templates/deployment.yaml",
+		"vendor/lib.yaml",
+	} {
+		p := filepath.Join(dir, f)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(p), 0755))
+		assert.Nil(t, ioutil.WriteFile(p, []byte{}, 0644))
+	}
+
+	res, err := expandPaths([]string{dir}, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "deployment.yaml"),
+		filepath.Join(dir, "nested/pod.json"),
+		filepath.Join(dir, "nested/templates/deployment.yaml"),
+		filepath.Join(dir, "service.yml"),
+		filepath.Join(dir, "vendor/lib.yaml"),
+	}, res)
+
+	res, err = expandPaths([]string{dir}, nil, []string{"vendor", "**/templates/**"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "deployment.yaml"),
+		filepath.Join(dir, "nested/pod.json"),
+		filepath.Join(dir, "service.yml"),
+	}, res)
+
+	res, err = expandPaths([]string{dir, "-", filepath.Join(dir, "README.md")}, []string{"*.yaml"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "deployment.yaml"),
+		filepath.Join(dir, "nested/templates/deployment.yaml"),
+		filepath.Join(dir, "vendor/lib.yaml"),
+		"-",
+		filepath.Join(dir, "README.md"),
+	}, res)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/zegl/kube-score/fix"
+)
+
+// fixFiles applies the automatic remediations to all files in the input, and writes the fixed files in place.
+// With --dry-run, the changes are printed as a diff instead.
+func fixFiles(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	dryRun := fs.Bool("dry-run", false, "Print the changes as a diff, instead of writing them to the files")
+	ignoreTests := fs.StringSlice("ignore-test", []string{}, "Don't apply the fixes of a test, can be set multiple times")
+	includeGlobs := fs.StringSlice("include", []string{}, "Only fix files in directories matching this glob pattern, can be set multiple times")
+	excludeGlobs := fs.StringSlice("exclude", []string{}, "Skip files and directories matching this glob pattern when reading directories, can be set multiple times")
+	setDefault(fs, binName, "fix", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse files: %s", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	filesToFix, err := expandPaths(fs.Args(), *includeGlobs, *excludeGlobs)
+	if err != nil {
+		return err
+	}
+
+	if len(filesToFix) == 0 {
+		return usageError{fmt.Sprintf(`Error: No files given as arguments.
+
+Usage: %s fix [--flag1 --flag2] file1 file2 ...
+
+Use "-" as filename to read from STDIN, the fixed file is written to STDOUT.
+Directories are read recursively, use --include and --exclude to filter which files to read.`, execName(binName))}
+	}
+
+	ignoredTests := listToStructMap(ignoreTests)
+
+	for _, file := range filesToFix {
+		if strings.HasPrefix(file, helmInputPrefix) || strings.HasPrefix(file, kustomizeInputPrefix) {
+			return fmt.Errorf("%s: rendered Helm charts and kustomizations can not be fixed", file)
+		}
+
+		var contents []byte
+		if file == "-" {
+			contents, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			contents, err = ioutil.ReadFile(file)
+		}
+		if err != nil {
+			return err
+		}
+
+		res, err := fix.Fix(contents, ignoredTests)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		switch {
+		case *dryRun:
+			fmt.Print(res.Diff(file))
+		case file == "-":
+			fmt.Print(string(res.Fixed))
+		case len(res.Changes) > 0:
+			stat, err := os.Stat(file)
+			if err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(file, res.Fixed, stat.Mode()); err != nil {
+				return err
+			}
+		}
+
+		// Report the changes on stderr, to not mix them with the fixed file or the diff
+		for _, c := range res.Changes {
+			fmt.Fprintf(os.Stderr, "%s:%d: %s (%s)\n", file, c.Line, c.Description, c.Check)
+		}
+	}
+
+	return nil
+}
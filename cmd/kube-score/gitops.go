@@ -0,0 +1,675 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/internal/logger"
+)
+
+// maxGitOpsDepth is how many levels of GitOps objects that are rendered, an Argo CD Application that renders more
+// Applications ("app of apps") uses two levels
+const maxGitOpsDepth = 5
+
+// gitOpsSource is the source of the manifests of a Flux HelmRelease or Kustomization, or of an Argo CD Application
+type gitOpsSource struct {
+	// object is the kind, namespace and name of the GitOps object, used in logs and errors
+	object string
+
+	// dir is a local directory with a kustomization, a Helm chart or plain manifests. If empty, chart is a chart in
+	// the Helm repository repo.
+	dir     string
+	chart   string
+	repo    string
+	version string
+
+	releaseName string
+	namespace   string
+	valueFiles  []string
+	setValues   []string
+
+	// values are inline values, which are written to temporary values files that are used after valueFiles
+	values []map[string]interface{}
+}
+
+// key identifies the rendered manifests of the source, so that sources that are referenced more than once are only
+// rendered once
+func (s gitOpsSource) key() string {
+	if s.dir != "" {
+		return s.dir + "|" + s.releaseName + "|" + s.namespace
+	}
+	return s.repo + "|" + s.chart + "|" + s.version + "|" + s.releaseName + "|" + s.namespace
+}
+
+// gitOpsInput is an input that is scanned for GitOps objects, repo is the Git repository that the paths in the
+// objects are relative to
+type gitOpsInput struct {
+	name     string
+	contents []byte
+	repo     gitOpsRepository
+}
+
+// gitOpsRepository is the local Git repository of an input. Only the sources in this repository are rendered from
+// the local directory, as the sources in other repositories are not available locally.
+type gitOpsRepository struct {
+	// root is the root directory of the repository
+	root string
+
+	// urls are the normalized URLs of the remotes of the repository
+	urls map[string]struct{}
+}
+
+// newGitOpsRepository returns the Git repository that contains dir
+func newGitOpsRepository(dir string) gitOpsRepository {
+	repo := gitOpsRepository{root: gitRoot(dir), urls: make(map[string]struct{})}
+	for _, url := range gitRemoteURLs(repo.root) {
+		repo.urls[normalizeGitURL(url)] = struct{}{}
+	}
+	return repo
+}
+
+// isRepository reports whether url is the URL of one of the remotes of the repository
+func (r gitOpsRepository) isRepository(url string) bool {
+	_, ok := r.urls[normalizeGitURL(url)]
+	return ok
+}
+
+// path returns the local path of a path in the repository. false is returned if the path is outside of the
+// repository, such as "../other".
+func (r gitOpsRepository) path(path string) (string, bool) {
+	p := filepath.Join(r.root, path)
+	rel, err := filepath.Rel(r.root, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return p, true
+}
+
+// renderGitOps finds the Flux HelmReleases and Kustomizations and the Argo CD Applications in the inputs, and renders
+// their sources with helm or kustomize. The inputs are read, and returned together with the rendered manifests.
+// Sources in other Git repositories than the inputs are not rendered, as they are not available locally.
+func renderGitOps(ctx context.Context, inputs []ks.NamedReader) ([]ks.NamedReader, error) {
+	var res []ks.NamedReader
+	var queue []gitOpsInput
+
+	for _, input := range inputs {
+		contents, err := ioutil.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, namedReader{Reader: bytes.NewReader(contents), name: input.Name()})
+
+		dir := "."
+		if filepath.IsAbs(input.Name()) {
+			dir = filepath.Dir(input.Name())
+		}
+		queue = append(queue, gitOpsInput{name: input.Name(), contents: contents, repo: newGitOpsRepository(dir)})
+	}
+
+	rendered := make(map[string]struct{})
+	gitRepositories := make(map[string]string)
+	for depth := 0; depth < maxGitOpsDepth && len(queue) > 0; depth++ {
+		// The GitRepositories are often in other files than the objects that reference them, such as the
+		// GitRepository of the cluster that Flux creates when it's installed
+		for _, input := range queue {
+			for key, url := range findGitRepositories(input.contents) {
+				gitRepositories[key] = url
+			}
+		}
+
+		var next []gitOpsInput
+		for _, input := range queue {
+			for _, src := range findGitOpsSources(input.contents, input.repo, gitRepositories) {
+				if _, ok := rendered[src.key()]; ok {
+					continue
+				}
+				rendered[src.key()] = struct{}{}
+
+				if src.dir != "" && readsDir(inputs, src.dir) {
+					logger.Debug("Not rendering a GitOps source that is already in the input", "object", src.object, "path", src.dir)
+					continue
+				}
+
+				out, err := renderGitOpsSource(ctx, src)
+				if errors.Is(err, os.ErrNotExist) {
+					logger.Warn("Not rendering a GitOps source that is not available locally", "object", src.object, "path", src.dir)
+					continue
+				}
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", src.object, err)
+				}
+
+				for _, r := range out {
+					contents, err := ioutil.ReadAll(r)
+					if err != nil {
+						return nil, err
+					}
+					res = append(res, namedReader{Reader: bytes.NewReader(contents), name: r.Name()})
+					next = append(next, gitOpsInput{name: r.Name(), contents: contents, repo: input.repo})
+				}
+			}
+		}
+		queue = next
+	}
+
+	return res, nil
+}
+
+// readsDir reports whether any of the files in the inputs is in dir
+func readsDir(inputs []ks.NamedReader, dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	for _, input := range inputs {
+		if strings.HasPrefix(input.Name(), abs+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// gitRoot returns the closest directory at or above dir that contains a .git directory, or dir if there is none
+func gitRoot(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	for d := abs; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		if filepath.Dir(d) == d {
+			return abs
+		}
+	}
+}
+
+// gitRemoteURLs returns the URLs of the remotes of the Git repository at root, from the Git configuration of the
+// repository
+func gitRemoteURLs(root string) []string {
+	contents, err := ioutil.ReadFile(filepath.Join(root, ".git", "config"))
+	if err != nil {
+		return nil
+	}
+
+	var urls []string
+	inRemote := false
+	for _, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inRemote = strings.HasPrefix(line, "[remote ")
+			continue
+		}
+		if !inRemote {
+			continue
+		}
+		if kv := strings.SplitN(line, "=", 2); len(kv) == 2 && strings.TrimSpace(kv[0]) == "url" {
+			urls = append(urls, strings.TrimSpace(kv[1]))
+		}
+	}
+	return urls
+}
+
+// normalizeGitURL returns the host and path of a Git URL, so that the HTTPS and SSH URLs of a repository are equal.
+// "https://github.com/org/repo.git" and "git@github.com:org/repo" are both normalized to "github.com/org/repo".
+func normalizeGitURL(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+	} else if i := strings.Index(url, ":"); i >= 0 && !strings.Contains(url[:i], "/") {
+		// scp-like syntax, such as git@github.com:org/repo
+		url = url[:i] + "/" + url[i+1:]
+	}
+	if i := strings.Index(url, "@"); i >= 0 && i < strings.Index(url+"/", "/") {
+		url = url[i+1:]
+	}
+	url = strings.TrimSuffix(url, "/")
+	return strings.TrimSuffix(url, ".git")
+}
+
+// renderGitOpsSource renders the source with kustomize if it has a kustomization, or with helm if it is a chart.
+// Directories with other manifests are read as they are. An error wrapping os.ErrNotExist is returned if the source
+// directory does not exist.
+func renderGitOpsSource(ctx context.Context, src gitOpsSource) ([]ks.NamedReader, error) {
+	if src.dir != "" {
+		if _, err := os.Stat(src.dir); err != nil {
+			return nil, err
+		}
+		for _, name := range []string{"kustomization.yaml", "kustomization.yml", "Kustomization"} {
+			if _, err := os.Stat(filepath.Join(src.dir, name)); err == nil {
+				r, err := renderKustomization(ctx, src.dir)
+				return []ks.NamedReader{r}, err
+			}
+		}
+		if _, err := os.Stat(filepath.Join(src.dir, "Chart.yaml")); err != nil {
+			files, err := expandPaths([]string{src.dir}, nil, nil)
+			if err != nil {
+				return nil, err
+			}
+			return openInputs(ctx, files, nil, nil)
+		}
+	}
+
+	var valueFiles []string
+	for _, values := range src.values {
+		f, err := ioutil.TempFile("", "kube-score-values-*.yaml")
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(f.Name())
+		err = yaml.NewEncoder(f).Encode(values)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		valueFiles = append(valueFiles, f.Name())
+	}
+
+	chart := src.dir
+	if chart == "" {
+		chart = src.chart
+	}
+	r, err := runHelmTemplate(ctx, chart, gitOpsHelmArgs(src, valueFiles))
+	return []ks.NamedReader{r}, err
+}
+
+// gitOpsHelmArgs returns the arguments passed to "helm" to render the chart of the source, with the inline values in
+// inlineValueFiles
+func gitOpsHelmArgs(src gitOpsSource, inlineValueFiles []string) []string {
+	args := []string{"template", src.releaseName}
+	if src.dir != "" {
+		args = append(args, src.dir)
+	} else {
+		args = append(args, src.chart)
+		if src.repo != "" {
+			args = append(args, "--repo", src.repo)
+		}
+	}
+	if src.version != "" {
+		args = append(args, "--version", src.version)
+	}
+	if src.namespace != "" {
+		args = append(args, "--namespace", src.namespace)
+	}
+	for _, v := range src.valueFiles {
+		args = append(args, "--values", v)
+	}
+	for _, v := range inlineValueFiles {
+		args = append(args, "--values", v)
+	}
+	for _, s := range src.setValues {
+		args = append(args, "--set", s)
+	}
+	return args
+}
+
+type gitOpsObjectMeta struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+type gitOpsSourceRef struct {
+	Kind      string `yaml:"kind"`
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+type fluxKustomization struct {
+	Metadata gitOpsObjectMeta `yaml:"metadata"`
+	Spec     struct {
+		Path      string          `yaml:"path"`
+		SourceRef gitOpsSourceRef `yaml:"sourceRef"`
+	} `yaml:"spec"`
+}
+
+type fluxHelmRelease struct {
+	Metadata gitOpsObjectMeta `yaml:"metadata"`
+	Spec     struct {
+		ReleaseName     string `yaml:"releaseName"`
+		TargetNamespace string `yaml:"targetNamespace"`
+		Chart           struct {
+			Spec struct {
+				Chart       string          `yaml:"chart"`
+				Version     string          `yaml:"version"`
+				SourceRef   gitOpsSourceRef `yaml:"sourceRef"`
+				ValuesFiles []string        `yaml:"valuesFiles"`
+			} `yaml:"spec"`
+		} `yaml:"chart"`
+		Values map[string]interface{} `yaml:"values"`
+	} `yaml:"spec"`
+}
+
+type fluxGitRepository struct {
+	Metadata gitOpsObjectMeta `yaml:"metadata"`
+	Spec     struct {
+		URL string `yaml:"url"`
+	} `yaml:"spec"`
+}
+
+type fluxHelmRepository struct {
+	Metadata gitOpsObjectMeta `yaml:"metadata"`
+	Spec     struct {
+		URL  string `yaml:"url"`
+		Type string `yaml:"type"`
+	} `yaml:"spec"`
+}
+
+type argoApplication struct {
+	Metadata gitOpsObjectMeta `yaml:"metadata"`
+	Spec     struct {
+		Source      *argoApplicationSource  `yaml:"source"`
+		Sources     []argoApplicationSource `yaml:"sources"`
+		Destination struct {
+			Namespace string `yaml:"namespace"`
+		} `yaml:"destination"`
+	} `yaml:"spec"`
+}
+
+type argoApplicationSource struct {
+	RepoURL        string `yaml:"repoURL"`
+	Path           string `yaml:"path"`
+	Chart          string `yaml:"chart"`
+	TargetRevision string `yaml:"targetRevision"`
+	Helm           struct {
+		ReleaseName  string                 `yaml:"releaseName"`
+		ValueFiles   []string               `yaml:"valueFiles"`
+		Values       string                 `yaml:"values"`
+		ValuesObject map[string]interface{} `yaml:"valuesObject"`
+		Parameters   []struct {
+			Name  string `yaml:"name"`
+			Value string `yaml:"value"`
+		} `yaml:"parameters"`
+	} `yaml:"helm"`
+}
+
+// decodeGitOpsDocuments decodes the documents in the manifests. Documents that can not be decoded are skipped, they
+// are reported by the parser.
+func decodeGitOpsDocuments(contents []byte) []yaml.Node {
+	var docs []yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(contents))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if err != io.EOF {
+				logger.Debug("Failed to decode document when looking for GitOps objects", "error", err)
+			}
+			break
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+// gitOpsKind returns the API group and the kind of the document
+func gitOpsKind(doc *yaml.Node) (string, string) {
+	var kind struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+	}
+	if doc.Decode(&kind) != nil {
+		return "", ""
+	}
+	group := ""
+	if i := strings.Index(kind.APIVersion, "/"); i >= 0 {
+		group = kind.APIVersion[:i]
+	}
+	return group, kind.Kind
+}
+
+// findGitRepositories returns the URLs of the Flux GitRepositories in the manifests, keyed by namespace and name
+func findGitRepositories(contents []byte) map[string]string {
+	res := make(map[string]string)
+	docs := decodeGitOpsDocuments(contents)
+	for i := range docs {
+		if group, kind := gitOpsKind(&docs[i]); group != "source.toolkit.fluxcd.io" || kind != "GitRepository" {
+			continue
+		}
+		var repo fluxGitRepository
+		if docs[i].Decode(&repo) == nil {
+			res[repo.Metadata.Namespace+"/"+repo.Metadata.Name] = repo.Spec.URL
+		}
+	}
+	return res
+}
+
+// findGitOpsSources returns the sources of the GitOps objects in the manifests. Local paths are relative to the root
+// of repo, and sources in other Git repositories than repo are skipped. gitRepositories are the URLs of the Flux
+// GitRepositories, keyed by namespace and name.
+func findGitOpsSources(contents []byte, repo gitOpsRepository, gitRepositories map[string]string) []gitOpsSource {
+	docs := decodeGitOpsDocuments(contents)
+
+	// HelmRepositories are collected first, as HelmReleases reference them by name
+	helmRepositories := make(map[string]fluxHelmRepository)
+	for i := range docs {
+		if group, kind := gitOpsKind(&docs[i]); group != "source.toolkit.fluxcd.io" || kind != "HelmRepository" {
+			continue
+		}
+		var helmRepo fluxHelmRepository
+		if docs[i].Decode(&helmRepo) == nil {
+			helmRepositories[helmRepo.Metadata.Namespace+"/"+helmRepo.Metadata.Name] = helmRepo
+		}
+	}
+
+	var res []gitOpsSource
+	for i := range docs {
+		switch group, kind := gitOpsKind(&docs[i]); {
+		case group == "kustomize.toolkit.fluxcd.io" && kind == "Kustomization":
+			var k fluxKustomization
+			if docs[i].Decode(&k) != nil {
+				continue
+			}
+			object := fmt.Sprintf("Kustomization %s/%s", k.Metadata.Namespace, k.Metadata.Name)
+			if !isFluxSourceInRepository(object, k.Metadata.Namespace, k.Spec.SourceRef, repo, gitRepositories) {
+				continue
+			}
+			dir, ok := repo.path(k.Spec.Path)
+			if !ok {
+				logger.Warn("Not rendering a GitOps source with a path outside of the Git repository", "object", object, "path", k.Spec.Path)
+				continue
+			}
+			res = append(res, gitOpsSource{object: object, dir: dir})
+
+		case group == "helm.toolkit.fluxcd.io" && kind == "HelmRelease":
+			var h fluxHelmRelease
+			if docs[i].Decode(&h) != nil {
+				continue
+			}
+			if src, ok := fluxHelmReleaseSource(h, helmRepositories, repo, gitRepositories); ok {
+				res = append(res, src)
+			}
+
+		case group == "argoproj.io" && kind == "Application":
+			var a argoApplication
+			if docs[i].Decode(&a) != nil {
+				continue
+			}
+			res = append(res, argoApplicationSources(a, repo)...)
+		}
+	}
+
+	return res
+}
+
+// isFluxSourceInRepository reports whether the sourceRef of a Flux object in namespace is a GitRepository of repo.
+// A warning is logged if it's not.
+func isFluxSourceInRepository(object, namespace string, ref gitOpsSourceRef, repo gitOpsRepository, gitRepositories map[string]string) bool {
+	if ref.Kind != "GitRepository" {
+		logger.Warn("Not rendering a GitOps source of an unsupported kind", "object", object, "kind", ref.Kind)
+		return false
+	}
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	url, ok := gitRepositories[namespace+"/"+ref.Name]
+	if !ok {
+		logger.Warn("Not rendering a GitOps source with a GitRepository that is not in the input", "object", object, "repository", ref.Name)
+		return false
+	}
+	if !repo.isRepository(url) {
+		logger.Warn("Not rendering a GitOps source in another Git repository", "object", object, "url", url)
+		return false
+	}
+	return true
+}
+
+// fluxHelmReleaseSource returns the chart of the HelmRelease, from a GitRepository or from a HelmRepository in
+// repositories, keyed by namespace and name. Charts from a GitRepository are only rendered if it's repo. false is
+// returned if the chart can not be rendered.
+func fluxHelmReleaseSource(h fluxHelmRelease, repositories map[string]fluxHelmRepository, repo gitOpsRepository, gitRepositories map[string]string) (gitOpsSource, bool) {
+	chart := h.Spec.Chart.Spec
+	src := gitOpsSource{
+		object:      fmt.Sprintf("HelmRelease %s/%s", h.Metadata.Namespace, h.Metadata.Name),
+		releaseName: h.Spec.ReleaseName,
+		namespace:   h.Spec.TargetNamespace,
+	}
+	if src.releaseName == "" {
+		// The default release name of Flux is prefixed with the target namespace
+		src.releaseName = h.Metadata.Name
+		if h.Spec.TargetNamespace != "" {
+			src.releaseName = h.Spec.TargetNamespace + "-" + h.Metadata.Name
+		}
+	}
+	if src.namespace == "" {
+		src.namespace = h.Metadata.Namespace
+	}
+	if h.Spec.Values != nil {
+		src.values = append(src.values, h.Spec.Values)
+	}
+
+	switch chart.SourceRef.Kind {
+	case "GitRepository":
+		if !isFluxSourceInRepository(src.object, h.Metadata.Namespace, chart.SourceRef, repo, gitRepositories) {
+			return gitOpsSource{}, false
+		}
+		for _, p := range append([]string{chart.Chart}, chart.ValuesFiles...) {
+			path, ok := repo.path(p)
+			if !ok {
+				logger.Warn("Not rendering a GitOps source with a path outside of the Git repository", "object", src.object, "path", p)
+				return gitOpsSource{}, false
+			}
+			if src.dir == "" {
+				src.dir = path
+			} else {
+				src.valueFiles = append(src.valueFiles, path)
+			}
+		}
+	case "HelmRepository":
+		namespace := chart.SourceRef.Namespace
+		if namespace == "" {
+			namespace = h.Metadata.Namespace
+		}
+		helmRepo, ok := repositories[namespace+"/"+chart.SourceRef.Name]
+		if !ok {
+			logger.Warn("Not rendering a HelmRelease with a HelmRepository that is not in the input", "object", src.object, "repository", chart.SourceRef.Name)
+			return gitOpsSource{}, false
+		}
+		if helmRepo.Spec.Type == "oci" {
+			src.chart = strings.TrimSuffix(helmRepo.Spec.URL, "/") + "/" + chart.Chart
+		} else {
+			src.chart = chart.Chart
+			src.repo = helmRepo.Spec.URL
+		}
+		src.version = chart.Version
+		// The values files of charts from Helm repositories are in the chart, and can not be passed to helm
+		if len(chart.ValuesFiles) > 0 {
+			logger.Warn("Ignoring the valuesFiles of a HelmRelease with a chart from a HelmRepository", "object", src.object)
+		}
+	default:
+		logger.Warn("Not rendering a GitOps source of an unsupported kind", "object", src.object, "kind", chart.SourceRef.Kind)
+		return gitOpsSource{}, false
+	}
+
+	return src, true
+}
+
+// argoApplicationSources returns the sources of the Application. Local paths are relative to the root of repo, and
+// sources in other Git repositories are skipped.
+func argoApplicationSources(a argoApplication, repo gitOpsRepository) []gitOpsSource {
+	sources := a.Spec.Sources
+	if a.Spec.Source != nil {
+		sources = append([]argoApplicationSource{*a.Spec.Source}, sources...)
+	}
+
+	var res []gitOpsSource
+	for _, s := range sources {
+		src := gitOpsSource{
+			object:      fmt.Sprintf("Application %s/%s", a.Metadata.Namespace, a.Metadata.Name),
+			releaseName: s.Helm.ReleaseName,
+			namespace:   a.Spec.Destination.Namespace,
+		}
+		if src.releaseName == "" {
+			src.releaseName = a.Metadata.Name
+		}
+
+		switch {
+		case s.Chart != "":
+			src.version = s.TargetRevision
+			if strings.Contains(s.RepoURL, "://") && !strings.HasPrefix(s.RepoURL, "oci://") {
+				src.chart = s.Chart
+				src.repo = s.RepoURL
+			} else {
+				// Argo CD uses OCI registries for repository URLs without a scheme
+				src.chart = "oci://" + strings.TrimSuffix(strings.TrimPrefix(s.RepoURL, "oci://"), "/") + "/" + s.Chart
+			}
+		case s.Path != "":
+			if !repo.isRepository(s.RepoURL) {
+				logger.Warn("Not rendering a GitOps source in another Git repository", "object", src.object, "url", s.RepoURL)
+				continue
+			}
+			dir, ok := repo.path(s.Path)
+			if !ok {
+				logger.Warn("Not rendering a GitOps source with a path outside of the Git repository", "object", src.object, "path", s.Path)
+				continue
+			}
+			src.dir = dir
+		default:
+			// Sources without a path or a chart only provide values files to other sources
+			continue
+		}
+
+		for _, f := range s.Helm.ValueFiles {
+			if strings.HasPrefix(f, "$") || src.dir == "" {
+				logger.Warn("Ignoring a values file that is not available locally", "object", src.object, "file", f)
+				continue
+			}
+			// The values files are relative to the path of the chart
+			rel, err := filepath.Rel(repo.root, filepath.Join(src.dir, f))
+			path, ok := repo.path(rel)
+			if err != nil || !ok {
+				logger.Warn("Ignoring a values file outside of the Git repository", "object", src.object, "file", f)
+				continue
+			}
+			src.valueFiles = append(src.valueFiles, path)
+		}
+		if s.Helm.Values != "" {
+			var values map[string]interface{}
+			if err := yaml.Unmarshal([]byte(s.Helm.Values), &values); err != nil {
+				logger.Warn("Ignoring invalid helm values", "object", src.object, "error", err)
+			} else if values != nil {
+				src.values = append(src.values, values)
+			}
+		}
+		if s.Helm.ValuesObject != nil {
+			src.values = append(src.values, s.Helm.ValuesObject)
+		}
+		for _, p := range s.Helm.Parameters {
+			src.setValues = append(src.setValues, p.Name+"="+p.Value)
+		}
+
+		res = append(res, src)
+	}
+	return res
+}
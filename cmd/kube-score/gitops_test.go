@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testGitOpsRepository is a repository in /repo with the remote https://github.com/example/gitops
+var testGitOpsRepository = gitOpsRepository{
+	root: "/repo",
+	urls: map[string]struct{}{"github.com/example/gitops": {}},
+}
+
+func TestFindGitOpsSources(t *testing.T) {
+	sources := findGitOpsSources([]byte(`
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: apps
+  namespace: flux-system
+spec:
+  path: ./apps/prod
+  sourceRef:
+    kind: GitRepository
+    name: flux-system
+---
+apiVersion: source.toolkit.fluxcd.io/v1beta2
+kind: HelmRepository
+metadata:
+  name: podinfo
+  namespace: flux-system
+spec:
+  url: https://stefanprodan.github.io/podinfo
+---
+apiVersion: helm.toolkit.fluxcd.io/v2beta1
+kind: HelmRelease
+metadata:
+  name: podinfo
+  namespace: flux-system
+spec:
+  targetNamespace: podinfo
+  chart:
+    spec:
+      chart: podinfo
+      version: 6.5.0
+      sourceRef:
+        kind: HelmRepository
+        name: podinfo
+  values:
+    replicaCount: 2
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: guestbook
+  namespace: argocd
+spec:
+  source:
+    repoURL: https://github.com/example/gitops.git
+    path: charts/guestbook
+    helm:
+      valueFiles: [values-prod.yaml]
+      parameters:
+        - name: image.tag
+          value: v1.2.3
+  destination:
+    namespace: guestbook
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+`), testGitOpsRepository, map[string]string{"flux-system/flux-system": "ssh://git@github.com/example/gitops"})
+
+	assert.Equal(t, []gitOpsSource{
+		{
+			object: "Kustomization flux-system/apps",
+			dir:    "/repo/apps/prod",
+		},
+		{
+			object:      "HelmRelease flux-system/podinfo",
+			chart:       "podinfo",
+			repo:        "https://stefanprodan.github.io/podinfo",
+			version:     "6.5.0",
+			releaseName: "podinfo-podinfo",
+			namespace:   "podinfo",
+			values:      []map[string]interface{}{{"replicaCount": 2}},
+		},
+		{
+			object:      "Application argocd/guestbook",
+			dir:         "/repo/charts/guestbook",
+			releaseName: "guestbook",
+			namespace:   "guestbook",
+			valueFiles:  []string{"/repo/charts/guestbook/values-prod.yaml"},
+			setValues:   []string{"image.tag=v1.2.3"},
+		},
+	}, sources)
+}
+
+func TestFindGitOpsSourcesUnsupported(t *testing.T) {
+	// The HelmRepository and the GitRepository are not in the input, OCIRepositories are not rendered, and sources in
+	// other Git repositories and paths outside of the Git repository are not rendered
+	assert.Empty(t, findGitOpsSources([]byte(`
+apiVersion: helm.toolkit.fluxcd.io/v2beta1
+kind: HelmRelease
+metadata:
+  name: podinfo
+spec:
+  chart:
+    spec:
+      chart: podinfo
+      sourceRef:
+        kind: HelmRepository
+        name: podinfo
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: apps
+spec:
+  sourceRef:
+    kind: OCIRepository
+    name: apps
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: not-in-input
+  namespace: flux-system
+spec:
+  path: ./apps
+  sourceRef:
+    kind: GitRepository
+    name: not-in-input
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: other-repo
+  namespace: flux-system
+spec:
+  path: ./apps
+  sourceRef:
+    kind: GitRepository
+    name: other
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: outside
+  namespace: flux-system
+spec:
+  path: ../../etc
+  sourceRef:
+    kind: GitRepository
+    name: flux-system
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: other-repo
+spec:
+  source:
+    repoURL: https://github.com/example/other.git
+    path: apps
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: outside
+spec:
+  source:
+    repoURL: git@github.com:example/gitops.git
+    path: apps/../../other
+`), testGitOpsRepository, map[string]string{
+		"flux-system/flux-system": "https://github.com/example/gitops",
+		"flux-system/other":       "https://github.com/example/other",
+	}))
+}
+
+func TestNormalizeGitURL(t *testing.T) {
+	for _, url := range []string{
+		"https://github.com/example/gitops.git",
+		"https://github.com/Example/gitops/",
+		"ssh://git@github.com/example/gitops",
+		"git@github.com:example/gitops.git",
+		"https://user@github.com/example/gitops",
+	} {
+		assert.Equal(t, "github.com/example/gitops", normalizeGitURL(url), url)
+	}
+}
+
+func TestGitOpsHelmArgs(t *testing.T) {
+	assert.Equal(t, []string{"template", "podinfo", "podinfo", "--repo", "https://stefanprodan.github.io/podinfo", "--version", "6.5.0", "--namespace", "podinfo", "--values", "/tmp/values.yaml"},
+		gitOpsHelmArgs(gitOpsSource{
+			chart:       "podinfo",
+			repo:        "https://stefanprodan.github.io/podinfo",
+			version:     "6.5.0",
+			releaseName: "podinfo",
+			namespace:   "podinfo",
+		}, []string{"/tmp/values.yaml"}))
+	assert.Equal(t, []string{"template", "guestbook", "/repo/charts/guestbook", "--values", "/repo/values.yaml", "--set", "image.tag=v1"},
+		gitOpsHelmArgs(gitOpsSource{
+			dir:         "/repo/charts/guestbook",
+			releaseName: "guestbook",
+			valueFiles:  []string{"/repo/values.yaml"},
+			setValues:   []string{"image.tag=v1"},
+		}, nil))
+}
+
+func TestRenderGitOps(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kube-score")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for name, contents := range map[string]string{
+		"clusters/prod/apps.yaml": `
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: apps
+spec:
+  path: ./apps/prod
+  sourceRef:
+    kind: GitRepository
+    name: flux-system
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: does-not-exist
+spec:
+  path: ./does-not-exist
+  sourceRef:
+    kind: GitRepository
+    name: flux-system
+`,
+		"clusters/prod/flux-system/gotk-sync.yaml": `
+apiVersion: source.toolkit.fluxcd.io/v1
+kind: GitRepository
+metadata:
+  name: flux-system
+spec:
+  url: ssh://git@github.com/example/gitops
+`,
+		".git/config": `
+[core]
+	bare = false
+[remote "origin"]
+	url = git@github.com:example/gitops.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+`,
+		"apps/prod/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+`,
+	} {
+		p := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(p), 0755))
+		assert.Nil(t, ioutil.WriteFile(p, []byte(contents), 0644))
+	}
+	inputs, err := openInputs(context.Background(), []string{
+		filepath.Join(dir, "clusters/prod/apps.yaml"),
+		filepath.Join(dir, "clusters/prod/flux-system/gotk-sync.yaml"),
+	}, nil, nil)
+	assert.Nil(t, err)
+	res, err := renderGitOps(context.Background(), inputs)
+	assert.Nil(t, err)
+
+	var names []string
+	for _, r := range res {
+		names = append(names, r.Name())
+		contents, err := ioutil.ReadAll(r)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, contents)
+	}
+	assert.Equal(t, []string{
+		filepath.Join(dir, "clusters/prod/apps.yaml"),
+		filepath.Join(dir, "clusters/prod/flux-system/gotk-sync.yaml"),
+		filepath.Join(dir, "apps/prod/deployment.yaml"),
+	}, names)
+
+	// Sources that are already in the input are not read twice
+	inputs, err = openInputs(context.Background(), []string{
+		filepath.Join(dir, "clusters/prod/apps.yaml"),
+		filepath.Join(dir, "clusters/prod/flux-system/gotk-sync.yaml"),
+		filepath.Join(dir, "apps/prod/deployment.yaml"),
+	}, nil, nil)
+	assert.Nil(t, err)
+	res, err = renderGitOps(context.Background(), inputs)
+	assert.Nil(t, err)
+	assert.Len(t, res, 3)
+
+	// Sources are not rendered if the GitRepository is not in the input
+	inputs, err = openInputs(context.Background(), []string{filepath.Join(dir, "clusters/prod/apps.yaml")}, nil, nil)
+	assert.Nil(t, err)
+	res, err = renderGitOps(context.Background(), inputs)
+	assert.Nil(t, err)
+	assert.Len(t, res, 1)
+}
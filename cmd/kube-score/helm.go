@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const helmInputPrefix = "helm://"
+
+// helmTemplateArgs returns the arguments passed to "helm" to render a chart
+func helmTemplateArgs(chart string, valueFiles, setValues []string) []string {
+	args := []string{"template", filepath.Base(filepath.Clean(chart)), chart}
+	for _, v := range valueFiles {
+		args = append(args, "--values", v)
+	}
+	for _, s := range setValues {
+		args = append(args, "--set", s)
+	}
+	return args
+}
+
+// renderHelmChart renders the chart with "helm template" and returns the rendered manifests. Helm 3 must be installed.
+// The output from helm contains "# Source: " comments, which the parser uses to point
+// each object back to the template that produced it.
+func renderHelmChart(ctx context.Context, chart string, valueFiles, setValues []string) (namedReader, error) {
+	chart = strings.TrimPrefix(chart, helmInputPrefix)
+	return runHelmTemplate(ctx, chart, helmTemplateArgs(chart, valueFiles, setValues))
+}
+
+// runHelmTemplate runs helm with the arguments of "helm template", and returns the rendered manifests of the chart
+func runHelmTemplate(ctx context.Context, chart string, args []string) (namedReader, error) {
+	cmd, err := helmTool.command(ctx, args...)
+	if err != nil {
+		return namedReader{}, fmt.Errorf("failed to render helm chart %s: %w", chart, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return namedReader{}, fmt.Errorf("failed to render helm chart %s: %w: %s", chart, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return namedReader{Reader: &stdout, name: helmInputPrefix + chart}, nil
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelmTemplateArgs(t *testing.T) {
+	assert.Equal(t, []string{"template", "app", "./charts/app"}, helmTemplateArgs("./charts/app", nil, nil))
+	assert.Equal(t, []string{"template", "app", "charts/app/", "--values", "a.yaml", "--values", "b.yaml", "--set", "replicas=3"},
+		helmTemplateArgs("charts/app/", []string{"a.yaml", "b.yaml"}, []string{"replicas=3"}))
+}
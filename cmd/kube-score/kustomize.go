@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/zegl/kube-score/parser"
+)
+
+const kustomizeInputPrefix = parser.KustomizeInputPrefix
+
+// renderKustomization builds the kustomization in dir with the kustomize library, in the same way as
+// "kustomize build", and returns the rendered manifests. If the kustomization enables
+// "buildMetadata: [originAnnotations]", the parser uses the origin annotations to point each object back to the base
+// or overlay file that it was defined in.
+func renderKustomization(ctx context.Context, dir string) (namedReader, error) {
+	dir = strings.TrimPrefix(dir, kustomizeInputPrefix)
+
+	// Building can't be cancelled, the context is only checked before the build starts
+	if err := ctx.Err(); err != nil {
+		return namedReader{}, err
+	}
+
+	resources, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(filesys.MakeFsOnDisk(), dir)
+	if err != nil {
+		return namedReader{}, fmt.Errorf("failed to build kustomization %s: %w", dir, err)
+	}
+	rendered, err := resources.AsYaml()
+	if err != nil {
+		return namedReader{}, fmt.Errorf("failed to build kustomization %s: %w", dir, err)
+	}
+
+	return namedReader{Reader: bytes.NewReader(rendered), name: kustomizeInputPrefix + dir}, nil
+}
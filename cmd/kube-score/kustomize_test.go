@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser"
+)
+
+func TestRenderKustomization(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kube-score")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for name, contents := range map[string]string{
+		"base/kustomization.yaml": `
+resources:
+- deployment.yaml
+`,
+		"base/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+`,
+		"overlays/prod/kustomization.yaml": `
+resources:
+- ../../base
+namePrefix: prod-
+buildMetadata: [originAnnotations]
+`,
+	} {
+		p := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(p), 0755))
+		assert.Nil(t, ioutil.WriteFile(p, []byte(contents), 0644))
+	}
+
+	r, err := renderKustomization(context.Background(), kustomizeInputPrefix+filepath.Join(dir, "overlays/prod"))
+	assert.Nil(t, err)
+	assert.Equal(t, kustomizeInputPrefix+filepath.Join(dir, "overlays/prod"), r.Name())
+
+	// The objects point to the file in the base that they are defined in
+	parsed, err := parser.ParseFiles(context.Background(), config.Configuration{AllFiles: []domain.NamedReader{r}})
+	assert.Nil(t, err)
+	deployments := parsed.Deployments()
+	assert.Len(t, deployments, 1)
+	assert.Equal(t, "prod-app", deployments[0].Deployment().Name)
+	assert.Equal(t, filepath.Join(dir, "base/deployment.yaml"), deployments[0].FileLocation().Name)
+
+	_, err = renderKustomization(context.Background(), kustomizeInputPrefix+filepath.Join(dir, "does-not-exist"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zegl/kube-score/score/checks"
+)
+
+// listedCheck is a check as it's written by "list -o json" and "list -o yaml"
+type listedCheck struct {
+	ID               string   `json:"id" yaml:"id"`
+	Name             string   `json:"name" yaml:"name"`
+	TargetType       string   `json:"targetType" yaml:"targetType"`
+	Grade            string   `json:"grade,omitempty" yaml:"grade,omitempty"`
+	Severity         string   `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Optional         bool     `json:"optional" yaml:"optional"`
+	Profiles         []string `json:"profiles" yaml:"profiles"`
+	Description      string   `json:"description" yaml:"description"`
+	Remediation      string   `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+	DocumentationURL string   `json:"documentationUrl" yaml:"documentationUrl"`
+}
+
+// writeChecks writes all registered checks to w, as csv, json or yaml
+func writeChecks(w io.Writer, format string, allChecks *checks.Checks) error {
+	switch format {
+	case "csv":
+		output := csv.NewWriter(w)
+		for _, c := range allChecks.All() {
+			optionalString := "default"
+			if c.Optional {
+				optionalString = "optional"
+			}
+			if err := output.Write([]string{c.ID, c.TargetType, c.Comment, optionalString}); err != nil {
+				return err
+			}
+		}
+		output.Flush()
+		return output.Error()
+	case "json", "yaml":
+		res := []listedCheck{}
+		for _, c := range allChecks.All() {
+			l := listedCheck{
+				ID:          c.ID,
+				Name:        c.Name,
+				TargetType:  c.TargetType,
+				Optional:    c.Optional,
+				Profiles:    checks.ProfilesOf(c.ID, nil),
+				Description: c.Comment,
+			}
+			if l.Profiles == nil {
+				l.Profiles = []string{}
+			}
+			// Checks of plugins are not documented, and their grade is not known until they are run
+			doc, ok := allChecks.Documentation(c.ID)
+			if ok {
+				l.Grade = strings.ToLower(doc.Grade.String())
+				l.Severity = doc.Grade.Severity().String()
+				l.Remediation = doc.Remediation
+			}
+			l.DocumentationURL = doc.URL
+			res = append(res, l)
+		}
+
+		if format == "yaml" {
+			enc := yaml.NewEncoder(w)
+			enc.SetIndent(2)
+			if err := enc.Encode(res); err != nil {
+				return err
+			}
+			return enc.Close()
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	default:
+		return fmt.Errorf("unknown output format %q, must be one of: csv, json, yaml", format)
+	}
+}
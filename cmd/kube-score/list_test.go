@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+)
+
+func TestWriteChecks(t *testing.T) {
+	allChecks := score.RegisterAllChecks(parser.Empty(), config.Configuration{})
+
+	var csvOut bytes.Buffer
+	assert.NoError(t, writeChecks(&csvOut, "csv", allChecks))
+	assert.Contains(t, csvOut.String(), "pod-probes,Pod,Makes sure that all Pods have safe probe configurations,default\n")
+
+	var jsonOut bytes.Buffer
+	assert.NoError(t, writeChecks(&jsonOut, "json", allChecks))
+	var fromJSON []listedCheck
+	assert.NoError(t, json.Unmarshal(jsonOut.Bytes(), &fromJSON))
+	assert.Len(t, fromJSON, len(allChecks.All()))
+
+	var yamlOut bytes.Buffer
+	assert.NoError(t, writeChecks(&yamlOut, "yaml", allChecks))
+	var fromYAML []listedCheck
+	assert.NoError(t, yaml.Unmarshal(yamlOut.Bytes(), &fromYAML))
+	assert.Equal(t, fromJSON, fromYAML)
+
+	var probes listedCheck
+	for _, c := range fromJSON {
+		if c.ID == "pod-probes" {
+			probes = c
+		}
+	}
+	assert.Equal(t, "Pod Probes", probes.Name)
+	assert.Equal(t, "Pod", probes.TargetType)
+	assert.Equal(t, "critical", probes.Grade)
+	assert.Equal(t, "critical", probes.Severity)
+	assert.False(t, probes.Optional)
+	assert.Equal(t, []string{"reliability"}, probes.Profiles)
+	assert.NotEmpty(t, probes.Remediation)
+	assert.Equal(t, "https://github.com/zegl/kube-score/blob/master/README_PROBES.md", probes.DocumentationURL)
+
+	assert.Error(t, writeChecks(&bytes.Buffer{}, "xml", allChecks))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/zegl/kube-score/internal/logger"
+)
+
+// logFlags are the flags that configure the logging of the actions
+type logFlags struct {
+	format *string
+}
+
+func registerLogFlags(fs *flag.FlagSet) *logFlags {
+	return &logFlags{
+		format: fs.String("log-format", logger.FormatText, "The format of the logs that are written to stderr. Set to 'text' or 'json'"),
+	}
+}
+
+// setup replaces the default logger, debug messages are only written if debug is true
+func (f *logFlags) setup(debug bool) error {
+	level := logger.LevelInfo
+	if debug {
+		level = logger.LevelDebug
+	}
+	l, err := logger.New(os.Stderr, level, *f.format)
+	if err != nil {
+		return fmt.Errorf("Invalid --log-format: %w", err)
+	}
+	logger.SetDefault(l)
+	return nil
+}
+
+// usageError is an error in how the action is used, such as missing arguments. It includes the usage of the action,
+// and is printed as it is instead of being logged.
+type usageError struct {
+	message string
+}
+
+func (e usageError) Error() string {
+	return e.message
+}
+
+// exitWithError logs the error that an action failed with, and exits with the exit code
+func exitWithError(msg string, err error, exitCode int) {
+	var usage usageError
+	if errors.As(err, &usage) {
+		_, _ = fmt.Fprintln(os.Stderr, usage.message)
+	} else {
+		logger.Error(msg, "error", err)
+	}
+	os.Exit(exitCode)
+}
@@ -1,27 +1,26 @@
 package main
 
 import (
-	"bytes"
-	"encoding/csv"
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"log"
 	"os"
-	"path/filepath"
+	"time"
 
+	"github.com/fatih/color"
 	flag "github.com/spf13/pflag"
 	"golang.org/x/crypto/ssh/terminal"
+	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/zegl/kube-score/baseline"
 	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/parser"
 	"github.com/zegl/kube-score/renderer/ci"
 	"github.com/zegl/kube-score/renderer/human"
-	"github.com/zegl/kube-score/renderer/json_v2"
-	"github.com/zegl/kube-score/renderer/sarif"
+	"github.com/zegl/kube-score/renderer/json_v3"
+	"github.com/zegl/kube-score/renderer/template"
 	"github.com/zegl/kube-score/score"
 	"github.com/zegl/kube-score/scorecard"
 )
@@ -34,9 +33,44 @@ func main() {
 
 	cmds := map[string]cmdFunc{
 		"score": func(helpName string, args []string) {
-			if err := scoreFiles(helpName, args); err != nil {
-				_, _ = fmt.Fprintf(os.Stderr, "Failed to score files: %v", err)
-				os.Exit(1)
+			if err := scoreFiles(helpName, args, "score"); err != nil {
+				exitWithError("Failed to score files", err, exitCodeForError(err))
+			}
+		},
+
+		"baseline": func(helpName string, args []string) {
+			if err := scoreFiles(helpName, args, "baseline"); err != nil {
+				exitWithError("Failed to create baseline", err, exitCodeForError(err))
+			}
+		},
+
+		"diff": func(helpName string, args []string) {
+			if err := diffFiles(helpName, args); err != nil {
+				exitWithError("Failed to compare files", err, exitCodeForError(err))
+			}
+		},
+
+		"fix": func(helpName string, args []string) {
+			if err := fixFiles(helpName, args); err != nil {
+				exitWithError("Failed to fix files", err, 1)
+			}
+		},
+
+		"serve": func(helpName string, args []string) {
+			if err := serve(helpName, args); err != nil {
+				exitWithError("Failed to serve", err, 1)
+			}
+		},
+
+		"webhook": func(helpName string, args []string) {
+			if err := runWebhook(helpName, args); err != nil {
+				exitWithError("Failed to run webhook", err, 1)
+			}
+		},
+
+		"exporter": func(helpName string, args []string) {
+			if err := runExporter(helpName, args); err != nil {
+				exitWithError("Failed to run exporter", err, 1)
 			}
 		},
 
@@ -44,6 +78,24 @@ func main() {
 			listChecks(helpName, args)
 		},
 
+		"explain": func(helpName string, args []string) {
+			if err := runExplain(helpName, args); err != nil {
+				exitWithError("Failed to explain check", err, 1)
+			}
+		},
+
+		"export-policies": func(helpName string, args []string) {
+			if err := exportPolicies(helpName, args); err != nil {
+				exitWithError("Failed to export policies", err, exitCodeForError(err))
+			}
+		},
+
+		"schema": func(helpName string, args []string) {
+			if err := printSchema(helpName, args); err != nil {
+				exitWithError("Failed to print schema", err, 1)
+			}
+		},
+
 		"version": func(helpName string, args []string) {
 			cmdVersion()
 		},
@@ -75,7 +127,16 @@ func setDefault(fs *flag.FlagSet, binName, actionName string, displayForMoreInfo
 
 Actions:
 	score	Checks all files in the input, and gives them a score and recommendations
-	list	Prints a CSV list of all available score checks
+	baseline	Prints a baseline of all current findings in the input, to be used with "score --baseline"
+	diff	Scores two versions of the input, and reports the findings that are new, fixed or changed
+	fix	Applies safe automatic fixes to the files in the input
+	serve	Runs an HTTP server that scores the objects in the requests
+	webhook	Runs a validating admission webhook that denies objects that fail the checks
+	exporter	Scores the objects in a cluster periodically, and exposes the grades as Prometheus metrics
+	list	Prints a list of all available score checks, as CSV, JSON or YAML
+	explain	Prints a description of a check, why it matters, examples, and how to ignore it
+	export-policies	Prints the checks as Kyverno or OPA Gatekeeper policies, to enforce them in a cluster
+	schema	Prints the JSON Schema of the json output format with --output-version v3
 	version	Print the version of kube-score
 	help	Print this message`+"\n\n", binName, binName)
 
@@ -95,25 +156,52 @@ Actions:
 	}
 }
 
-func scoreFiles(binName string, args []string) error {
-	fs := flag.NewFlagSet(binName, flag.ExitOnError)
-	exitOneOnWarning := fs.Bool("exit-one-on-warning", false, "Exit with code 1 in case of warnings")
-	ignoreContainerCpuLimit := fs.Bool("ignore-container-cpu-limit", false, "Disables the requirement of setting a container CPU limit")
-	ignoreContainerMemoryLimit := fs.Bool("ignore-container-memory-limit", false, "Disables the requirement of setting a container memory limit")
-	verboseOutput := fs.CountP("verbose", "v", "Enable verbose output, can be set multiple times for increased verbosity.")
+// scoreFiles parses and scores all files in the input. If action is "baseline", a baseline of the
+// findings is printed instead of the scorecard.
+func scoreFiles(binName string, args []string, action string) error {
+	fs := flag.NewFlagSet(binName, flag.ContinueOnError)
+	exitCodeOn := fs.String("exit-code-on", "critical", "Exit with code 1 if any check has this grade or lower. Set to 'critical', 'high', 'warning' or 'none'")
+	exitOneOnWarning := fs.Bool("exit-one-on-warning", false, "Exit with code 1 in case of warnings, the same as --exit-code-on warning")
+	minScore := fs.Float64("min-score", 0, "Exit with code 1 if the score of the run is below this value, from 1 to 10. If set, the exit code only depends on the score, and not on the grades of the checks")
+	verboseOutput := fs.CountP("verbose", "v", "Enable verbose output, such as the file locations of the findings in the 'human' format. Set twice (-vv) to also write debug logs")
 	printHelp := fs.Bool("help", false, "Print help")
-	outputFormat := fs.StringP("output-format", "o", "human", "Set to 'human', 'json' or 'ci'. If set to ci, kube-score will output the program in a format that is easier to parse by other programs.")
-	outputFile := fs.StringP("output-file", "f", "", "Set to 'json' or 'txt'. By default, no output file is generated")
-	outputVersion := fs.String("output-version", "", "Changes the version of the --output-format. The 'json' format has version 'v2' (default) and 'v1' (deprecated, will be removed in v1.7.0). The 'human' and 'ci' formats has only version 'v1' (default). If not explicitly set, the default version for that particular output format will be used.")
-	optionalTests := fs.StringSlice("enable-optional-test", []string{}, "Enable an optional test, can be set multiple times")
-	ignoreTests := fs.StringSlice("ignore-test", []string{}, "Disable a test, can be set multiple times")
-	disableIgnoreChecksAnnotation := fs.Bool("disable-ignore-checks-annotations", false, "Set to true to disable the effect of the 'kube-score/ignore' annotations")
-	kubernetesVersion := fs.String("kubernetes-version", "v1.18", "Setting the kubernetes-version will affect the checks ran against the manifests. Set this to the version of Kubernetes that you're using in production for the best results.")
-	setDefault(fs, binName, "score", false)
+	checks := registerCheckFlags(fs)
+	logs := registerLogFlags(fs)
+	outputFormats := fs.StringSliceP("output-format", "o", []string{"human"}, "Set to 'human', 'json', 'ci', 'sarif', 'junit', 'github', 'markdown', 'html', 'checkstyle', 'tap', 'ndjson' or 'template'. If set to ci, kube-score will output the program in a format that is easier to parse by other programs. Can be set multiple times, use format=path to write a format to a file instead of stdout, such as sarif=report.sarif")
+	outputFilePaths := fs.StringSliceP("output-file", "f", []string{}, "Also write the output to this file, can be set multiple times. By default, no output file is generated")
+	outputFileFormats := fs.StringSlice("output-file-format", []string{}, "The format of the --output-file at the same position, can be set multiple times. Set to 'human', 'json', 'ci', 'sarif', 'junit', 'github', 'markdown', 'html', 'checkstyle', 'tap', 'ndjson' or 'template'. By default, the --output-format is used")
+	showPassed := fs.Bool("show-passed", false, "Write the checks that have passed. By default, they are not written in the 'human' format, and are written in the other formats. If set to true or false, it applies to all formats")
+	showSkipped := fs.Bool("show-skipped", false, "Write the checks that have been skipped. By default, they are not written in the 'human' format, and are written in the other formats. If set to true or false, it applies to all formats")
+	groupBy := fs.String("group-by", "object", "How the 'human' output format is grouped. Set to 'object' to list the checks of each object, or 'check' to list the objects of each check, with the checks that fail on the most objects first")
+	summaryOnly := fs.Bool("summary-only", false, "Only write a table with the number of scored objects, and the number of critical, high, warning, ok and skipped checks, in the 'human' output format")
+	colorMode := fs.String("color", "auto", "When to use colors in the output. Set to 'always', 'auto' or 'never'. With 'auto', colors are used if stdout is a terminal and the NO_COLOR environment variable is not set")
+	noEmoji := fs.Bool("no-emoji", false, "Write the status of each object as text instead of as an emoji, and only use ASCII characters in the 'human' output format")
+	templateFile := fs.String("template", "", "Path to a Go text/template file, that is used by the 'template' output format")
+	outputVersion := fs.String("output-version", "", "Changes the version of the --output-format. The 'json' format has version 'v2' (default), 'v3', that is described by the JSON Schema printed by the 'schema' action, and 'v1' (deprecated, will be removed in v1.7.0). The 'human' and 'ci' formats has only version 'v1' (default). If not explicitly set, the default version for that particular output format will be used.")
+	baselineFile := fs.String("baseline", "", "Path to a baseline file created with the 'baseline' action. Findings in the baseline are not reported")
+	configFile := fs.String("config", "", "Path to a configuration file. By default .kube-score.yml is read from the current directory if it exists")
+	helmCharts := fs.StringSlice("helm-chart", []string{}, "Render a Helm chart with 'helm template' and score the result, can be set multiple times. Charts can also be given as arguments on the format helm://path/to/chart")
+	helmValues := fs.StringSlice("helm-values", []string{}, "Values file passed to 'helm template' when rendering Helm charts, can be set multiple times")
+	helmSet := fs.StringSlice("helm-set", []string{}, "Value override (key=value) passed to 'helm template' when rendering Helm charts, can be set multiple times")
+	includeGlobs := fs.StringSlice("include", []string{}, "Only score files in directories matching this glob pattern, can be set multiple times")
+	excludeGlobs := fs.StringSlice("exclude", []string{}, "Skip files and directories matching this glob pattern when reading directories, can be set multiple times")
+	scoreCluster := fs.Bool("cluster", false, "Score the objects in a running cluster")
+	kubeconfig := fs.String("kubeconfig", "", "Path to the kubeconfig file to use when scoring a cluster")
+	kubeContext := fs.String("context", "", "The kubeconfig context to use when scoring a cluster")
+	namespace := fs.StringP("namespace", "n", "", "Only score objects in this namespace, objects without a namespace are always scored. By default, objects in all namespaces are scored, and resources in a cluster given as kind/name are fetched from the namespace of the current context")
+	selector := fs.StringP("selector", "l", "", "Only score objects matching this label selector, such as app=payments")
+	timeout := fs.Duration("timeout", 0, "Stop with an error if fetching, scoring and writing the output takes longer than this, such as 30s or 5m. By default there is no timeout")
+	kustomizations := fs.StringSlice("kustomize", []string{}, "Build a kustomization directory, in the same way as 'kustomize build', and score the result, can be set multiple times. Kustomizations can also be given as arguments on the format kustomize://path/to/dir")
+	renderGitOpsSources := fs.Bool("render-gitops", false, "Render the sources of the Flux HelmReleases and Kustomizations and the Argo CD Applications in the input with helm or kustomize, and score the result. Sources that are not in a local directory or a Helm repository are not rendered")
+	watchFiles := fs.Bool("watch", false, "Score the input again every time a file in the input changes, and print the findings that are new, fixed or changed since the last run. Stop with Ctrl+C")
+	setDefault(fs, binName, action, false)
 
 	err := fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to parse files: %s", err)
+		return fmt.Errorf("failed to parse flags: %s", err)
 	}
 
 	if *printHelp {
@@ -121,117 +209,329 @@ func scoreFiles(binName string, args []string) error {
 		return nil
 	}
 
-	if *outputFormat != "human" && *outputFormat != "ci" && *outputFormat != "json" && *outputFormat != "sarif" {
-		fs.Usage()
-		return fmt.Errorf("Error: --output-format must be set to: 'human', 'json', 'sarif' or 'ci'")
+	file, err := readConfigFile(*configFile)
+	if err != nil {
+		return err
+	}
+	if err := applyConfigFileFlags(fs, file); err != nil {
+		return err
 	}
 
-	filesToRead := fs.Args()
-	if len(filesToRead) == 0 {
-		return fmt.Errorf(`Error: No files given as arguments.
+	// Debug logs are written with -vv, as -v is used for the verbosity of the output
+	if err := logs.setup(*verboseOutput > 1); err != nil {
+		return err
+	}
 
-Usage: %s score [--flag1 --flag2] file1 file2 ...
+	outputFormat, outFiles, err := parseOutputFormats(*outputFormats)
+	if err != nil {
+		fs.Usage()
+		return err
+	}
 
-Use "-" as filename to read from STDIN.`, execName(binName))
+	// The --output-file files are written in the format of stdout by default
+	defaultFileFormat := outputFormat
+	if defaultFileFormat == "" {
+		defaultFileFormat = "human"
 	}
+	files, err := outputFiles(*outputFilePaths, *outputFileFormats, defaultFileFormat)
+	if err != nil {
+		return err
+	}
+	outFiles = append(outFiles, files...)
 
-	var allFilePointers []ks.NamedReader
+	if *groupBy != human.GroupByObject && *groupBy != human.GroupByCheck {
+		return fmt.Errorf("Error: --group-by must be set to 'object' or 'check'")
+	}
 
-	for _, file := range filesToRead {
-		var fp io.Reader
-		var filename string
+	useColor, err := colorEnabled(*colorMode, terminal.IsTerminal(int(os.Stdout.Fd())), os.Getenv)
+	if err != nil {
+		return err
+	}
+	color.NoColor = !useColor
 
-		if file == "-" {
-			fp = os.Stdin
-			filename = "STDIN"
-		} else {
-			var err error
-			fp, err = os.Open(file)
-			if err != nil {
-				return err
-			}
-			filename, _ = filepath.Abs(file)
+	opts := renderOptions{
+		verboseOutput: *verboseOutput,
+		human:         human.Options{GroupBy: *groupBy, SummaryOnly: *summaryOnly, NoEmoji: *noEmoji},
+		// The score is only written in the ci format when it decides the exit code
+		ci: ci.Options{ShowScore: *minScore > 0},
+	}
+	if fs.Changed("show-passed") {
+		opts.showPassed = showPassed
+	}
+	if fs.Changed("show-skipped") {
+		opts.showSkipped = showSkipped
+	}
+	if *templateFile != "" {
+		if opts.template, err = template.Parse(*templateFile); err != nil {
+			return err
 		}
-		allFilePointers = append(allFilePointers, namedReader{Reader: fp, name: filename})
+	} else if outputFormat == "template" || anyOutputFile(outFiles, "template") {
+		return fmt.Errorf("Error: --template must be set when using the template format")
 	}
 
-	ignoredTests := listToStructMap(ignoreTests)
-	enabledOptionalTests := listToStructMap(optionalTests)
+	if *minScore < 0 || *minScore > 10 {
+		return fmt.Errorf("Error: --min-score must be between 0 and 10")
+	}
 
-	kubeVer, err := config.ParseSemver(*kubernetesVersion)
+	if *timeout < 0 {
+		return fmt.Errorf("Error: --timeout must not be negative")
+	}
+	// newContext returns the context of a run, that times out after --timeout
+	newContext := func() (context.Context, context.CancelFunc) {
+		if *timeout > 0 {
+			return context.WithTimeout(context.Background(), *timeout)
+		}
+		return context.WithCancel(context.Background())
+	}
+	ctx, cancel := newContext()
+	defer cancel()
+
+	exitGrade, err := exitCodeOnGrade(*exitCodeOn, *exitOneOnWarning)
 	if err != nil {
-		return errors.New("Invalid --kubernetes-version. Use on format \"vN.NN\"")
+		return err
+	}
+
+	var labelSelector labels.Selector
+	if *selector != "" {
+		if labelSelector, err = labels.Parse(*selector); err != nil {
+			return fmt.Errorf("Invalid --selector: %w", err)
+		}
+	}
+
+	filesToRead := fs.Args()
+
+	// When running as a kubectl plugin, or with --cluster, resources in the cluster can be given as kind/name
+	var clusterResources []string
+	if *scoreCluster || isKubectlPlugin(binName) {
+		filesToRead, clusterResources = splitResourceReferences(filesToRead)
 	}
 
-	cnf := config.Configuration{
-		AllFiles:                              allFilePointers,
-		VerboseOutput:                         *verboseOutput,
-		IgnoreContainerCpuLimitRequirement:    *ignoreContainerCpuLimit,
-		IgnoreContainerMemoryLimitRequirement: *ignoreContainerMemoryLimit,
-		IgnoredTests:                          ignoredTests,
-		EnabledOptionalTests:                  enabledOptionalTests,
-		UseIgnoreChecksAnnotation:             !*disableIgnoreChecksAnnotation,
-		KubernetesVersion:                     kubeVer,
+	for _, chart := range *helmCharts {
+		filesToRead = append(filesToRead, helmInputPrefix+chart)
+	}
+	for _, dir := range *kustomizations {
+		filesToRead = append(filesToRead, kustomizeInputPrefix+dir)
 	}
 
-	parsedFiles, err := parser.ParseFiles(cnf)
+	inputPaths := filesToRead
+	filesToRead, err = expandPaths(filesToRead, *includeGlobs, *excludeGlobs)
 	if err != nil {
-		return err
+		return parseError{err}
+	}
+
+	if len(filesToRead) == 0 && len(clusterResources) == 0 && !*scoreCluster {
+		return usageError{fmt.Sprintf(`Error: No files given as arguments.
+
+Usage: %s score [--flag1 --flag2] file1 file2 ...
+
+Use "-" as filename to read from STDIN.
+Directories are read recursively, use --include and --exclude to filter which files to read.
+Use "helm://path/to/chart" to render and score a Helm chart.
+Use "kustomize://path/to/dir" to build and score a kustomization.
+Use --cluster to score the objects in a running cluster, or only the resources given as kind/name, such as deployment/foo.`, execName(binName))}
+	}
+
+	if *watchFiles {
+		if action != "score" || *scoreCluster || len(clusterResources) > 0 {
+			return fmt.Errorf("Error: --watch can only be used when scoring files")
+		}
+		for _, path := range filesToRead {
+			if path == "-" {
+				return fmt.Errorf("Error: --watch can not be used when reading from STDIN")
+			}
+		}
 	}
 
-	scoreCard, err := score.Score(parsedFiles, cnf)
+	cnf, err := checks.configuration(file)
 	if err != nil {
 		return err
 	}
+	cnf.VerboseOutput = *verboseOutput
+	opts.jsonV3 = json_v3.Options{
+		Run:    json_v3.Run{KubeScoreVersion: version, Flags: changedFlags(fs)},
+		Checks: checkDocumentation(score.RegisterAllChecks(parser.Empty(), cnf), cnf.CustomProfiles),
+	}
+	cnf.Namespace = *namespace
+	cnf.Selector = labelSelector
+
+	// parseInput reads and parses the files and the resources in the cluster
+	parseInput := func(ctx context.Context, filesToRead []string) (ks.AllTypes, config.Configuration, error) {
+		var allFilePointers []ks.NamedReader
+
+		if *scoreCluster || len(clusterResources) > 0 {
+			fetched, err := fetchFromCluster(ctx, clusterOptions{
+				kubeconfig: *kubeconfig,
+				context:    *kubeContext,
+				namespace:  *namespace,
+				selector:   *selector,
+				resources:  clusterResources,
+			})
+			if err != nil {
+				return nil, cnf, timeoutError(ctx, *timeout, err)
+			}
+			allFilePointers = append(allFilePointers, fetched)
+		}
 
-	var exitCode int
-	if scoreCard.AnyBelowOrEqualToGrade(scorecard.GradeCritical) {
-		exitCode = 1
-	} else if *exitOneOnWarning && scoreCard.AnyBelowOrEqualToGrade(scorecard.GradeWarning) {
-		exitCode = 1
-	} else {
-		exitCode = 0
+		inputs, err := openInputs(ctx, filesToRead, *helmValues, *helmSet)
+		if ctx.Err() != nil {
+			return nil, cnf, timeoutError(ctx, *timeout, err)
+		}
+		if err != nil {
+			return nil, cnf, parseError{err}
+		}
+		if *renderGitOpsSources {
+			inputs, err = renderGitOps(ctx, inputs)
+			if ctx.Err() != nil {
+				return nil, cnf, timeoutError(ctx, *timeout, err)
+			}
+			if err != nil {
+				return nil, cnf, parseError{err}
+			}
+		}
+		allFilePointers = append(allFilePointers, inputs...)
+
+		cnf := cnf
+		cnf.AllFiles = allFilePointers
+
+		parsedFiles, err := parser.ParseFiles(ctx, cnf)
+		if ctx.Err() != nil {
+			return nil, cnf, timeoutError(ctx, *timeout, err)
+		}
+		if err != nil {
+			return nil, cnf, parseError{err}
+		}
+		return parsedFiles, cnf, nil
 	}
 
-	var r io.Reader
+	// readBaseline reads the --baseline, it returns nil if no baseline is used
+	readBaseline := func() (*baseline.Baseline, error) {
+		if action == "baseline" || *baselineFile == "" {
+			return nil, nil
+		}
+		fp, err := os.Open(*baselineFile)
+		if err != nil {
+			return nil, err
+		}
+		defer fp.Close()
+		b, err := baseline.Parse(fp)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", *baselineFile, err)
+		}
+		return &b, nil
+	}
 
-	version := getOutputVersion(*outputVersion, *outputFormat)
+	// scoreInput reads, parses and scores the files and the resources in the cluster. Findings in the --baseline are
+	// removed from the scorecard, unless a baseline is created.
+	scoreInput := func(ctx context.Context, filesToRead []string) (*scorecard.Scorecard, error) {
+		parsedFiles, cnf, err := parseInput(ctx, filesToRead)
+		if err != nil {
+			return nil, err
+		}
 
-	if *outputFormat == "json" && version == "v1" {
-		d, _ := json.MarshalIndent(scoreCard, "", "    ")
-		w := bytes.NewBufferString("")
-		w.WriteString(string(d))
-		r = w
-	} else if *outputFormat == "json" && version == "v2" {
-		r = json_v2.Output(scoreCard)
-	} else if *outputFormat == "human" && version == "v1" {
-		termWidth, _, err := terminal.GetSize(int(os.Stdin.Fd()))
-		// Assume a width of 80 if it can't be detected
+		scoreCard, err := score.Score(ctx, parsedFiles, cnf)
 		if err != nil {
-			termWidth = 80
+			return nil, timeoutError(ctx, *timeout, err)
 		}
-		r = human.Human(scoreCard, *verboseOutput, termWidth)
-	} else if *outputFormat == "ci" && version == "v1" {
-		r = ci.CI(scoreCard)
-	} else if *outputFormat == "sarif" {
-		r = sarif.Output(scoreCard)
-	} else {
-		return fmt.Errorf("error: Unknown --output-format or --output-version")
+
+		b, err := readBaseline()
+		if err != nil {
+			return nil, err
+		}
+		if b != nil {
+			b.Apply(scoreCard, time.Now())
+		}
+		return scoreCard, nil
 	}
 
-	output, _ := ioutil.ReadAll(r)
-	fmt.Print(string(output))
-	if *outputFile != "" {
-		fileName := fmt.Sprintf("output.%s", *outputFile)
-		err = ioutil.WriteFile(fileName, output, 0644)
+	// writeOutput writes the scorecard to the output files, and to stdout if toStdout is true
+	writeOutput := func(ctx context.Context, scoreCard *scorecard.Scorecard, toStdout bool) error {
+		if toStdout && outputFormat != "" {
+			termWidth, _, err := terminal.GetSize(int(os.Stdout.Fd()))
+			// Assume a width of 80 if it can't be detected
+			if err != nil {
+				termWidth = 80
+			}
+
+			opts.termWidth = termWidth
+			r, err := render(ctx, scoreCard, outputFormat, getOutputVersion(*outputVersion, outputFormat), opts)
+			if err != nil {
+				return timeoutError(ctx, *timeout, err)
+			}
+			if _, err := io.Copy(os.Stdout, r); err != nil {
+				return err
+			}
+		}
+
+		if err := writeOutputFiles(ctx, outFiles, scoreCard, outputFormat, *outputVersion, opts); err != nil {
+			return timeoutError(ctx, *timeout, err)
+		}
+		return nil
+	}
+
+	if *watchFiles {
+		var outputPaths []string
+		for _, f := range outFiles {
+			outputPaths = append(outputPaths, f.path)
+		}
+		w := &inputWatcher{
+			paths:        inputPaths,
+			includeGlobs: *includeGlobs,
+			excludeGlobs: *excludeGlobs,
+			outputPaths:  outputPaths,
+			newContext:   newContext,
+			score:        scoreInput,
+			write:        writeOutput,
+			out:          os.Stdout,
+		}
+		return w.watch()
+	}
+
+	// The ndjson format is written to stdout while the objects are scored, so that the scorecard is never held in
+	// memory
+	if action == "score" && outputFormat == "ndjson" && len(outFiles) == 0 && getOutputVersion(*outputVersion, outputFormat) == "v1" {
+		parsedFiles, cnf, err := parseInput(ctx, filesToRead)
+		if err != nil {
+			return err
+		}
+		b, err := readBaseline()
+		if err != nil {
+			return err
+		}
+		totals, err := writeNDJSON(ctx, os.Stdout, parsedFiles, cnf, b, opts.filter(outputFormat))
 		if err != nil {
-			log.Fatalf("An error occurred while writing to file %s. Error: %v", fileName, err)
+			return timeoutError(ctx, *timeout, err)
 		}
+
+		os.Exit(policyExitCode(totals, *minScore, exitGrade))
+	}
+
+	scoreCard, err := scoreInput(ctx, filesToRead)
+	if err != nil {
+		return err
+	}
+
+	if action == "baseline" {
+		return baseline.New(scoreCard).Write(os.Stdout)
+	}
+
+	exitCode := policyExitCode(scoreCard, *minScore, exitGrade)
+
+	if err := writeOutput(ctx, scoreCard, true); err != nil {
+		return err
 	}
 	os.Exit(exitCode)
 	return nil
 }
 
+// timeoutError returns an error that explains that the action took longer than --timeout if ctx has timed out, and
+// err otherwise
+func timeoutError(ctx context.Context, timeout time.Duration, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("Error: timed out after %s, set by --timeout: %w", timeout, ctx.Err())
+	}
+	return err
+}
+
 func getOutputVersion(flagValue, format string) string {
 	if len(flagValue) > 0 {
 		return flagValue
@@ -248,6 +548,9 @@ func getOutputVersion(flagValue, format string) string {
 func listChecks(binName string, args []string) {
 	fs := flag.NewFlagSet(binName, flag.ExitOnError)
 	printHelp := fs.Bool("help", false, "Print help")
+	pluginFiles := fs.StringSlice("plugin", []string{}, "Include the checks of a WebAssembly (WASI) plugin, can be set multiple times")
+	pluginRuntime := fs.String("plugin-runtime", "wasmtime", "The WebAssembly runtime that is used to run the plugins")
+	outputFormat := fs.StringP("output-format", "o", "csv", "Set to 'csv', 'json' or 'yaml'. json and yaml include the default grade, remediation and documentation URL of the checks")
 	setDefault(fs, binName, "list", false)
 	fs.Parse(args)
 
@@ -256,17 +559,16 @@ func listChecks(binName string, args []string) {
 		return
 	}
 
-	allChecks := score.RegisterAllChecks(parser.Empty(), config.Configuration{})
+	loadedPlugins, err := loadPlugins(*pluginFiles, *pluginRuntime)
+	if err != nil {
+		exitWithError("Failed to load plugins", err, 1)
+	}
 
-	output := csv.NewWriter(os.Stdout)
-	for _, c := range allChecks.All() {
-		optionalString := "default"
-		if c.Optional {
-			optionalString = "optional"
-		}
-		output.Write([]string{c.ID, c.TargetType, c.Comment, optionalString})
+	allChecks := score.RegisterAllChecks(parser.Empty(), config.Configuration{Plugins: loadedPlugins})
+
+	if err := writeChecks(os.Stdout, *outputFormat, allChecks); err != nil {
+		exitWithError("Failed to list checks", err, 1)
 	}
-	output.Flush()
 }
 
 func listToStructMap(items *[]string) map[string]struct{} {
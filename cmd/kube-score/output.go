@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/fatih/color"
+	flag "github.com/spf13/pflag"
+
+	"github.com/zegl/kube-score/baseline"
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/renderer/checkstyle"
+	"github.com/zegl/kube-score/renderer/ci"
+	"github.com/zegl/kube-score/renderer/github"
+	"github.com/zegl/kube-score/renderer/html"
+	"github.com/zegl/kube-score/renderer/human"
+	"github.com/zegl/kube-score/renderer/json_v2"
+	"github.com/zegl/kube-score/renderer/json_v3"
+	"github.com/zegl/kube-score/renderer/junit"
+	"github.com/zegl/kube-score/renderer/markdown"
+	"github.com/zegl/kube-score/renderer/ndjson"
+	"github.com/zegl/kube-score/renderer/sarif"
+	"github.com/zegl/kube-score/renderer/tap"
+	"github.com/zegl/kube-score/renderer/template"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// colorEnabled reports whether colors are used in the output with the --color mode. With "auto", colors are used if
+// stdout is a terminal, and if the NO_COLOR environment variable is not set (https://no-color.org).
+func colorEnabled(mode string, stdoutIsTerminal bool, getenv func(string) string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		return stdoutIsTerminal && getenv("NO_COLOR") == "" && getenv("TERM") != "dumb", nil
+	default:
+		return false, fmt.Errorf("Error: --color must be set to 'always', 'auto' or 'never'")
+	}
+}
+
+// outputFormats are the valid values of --output-format and --output-file-format
+var outputFormats = []string{"human", "json", "ci", "sarif", "junit", "github", "markdown", "html", "checkstyle", "tap", "ndjson", "template"}
+
+func isOutputFormat(format string) bool {
+	for _, f := range outputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// renderOptions are the options of the formats that have any
+type renderOptions struct {
+	// verboseOutput, termWidth and human are used by the human format
+	verboseOutput int
+	termWidth     int
+	human         human.Options
+
+	// showPassed and showSkipped are the values of --show-passed and --show-skipped, they are nil if the flags are
+	// not set
+	showPassed  *bool
+	showSkipped *bool
+
+	// ci is used by the ci format
+	ci ci.Options
+
+	// template is used by the template format
+	template *texttemplate.Template
+
+	// jsonV3 is used by version v3 of the json format, its timestamp and filter are set when the output is rendered
+	jsonV3 json_v3.Options
+}
+
+// filter returns the checks that are written in the format. By default, the human format only writes the failing
+// checks, and the other formats write all checks.
+func (o renderOptions) filter(format string) scorecard.Filter {
+	show := format != "human"
+	f := scorecard.Filter{ShowPassed: show, ShowSkipped: show}
+	if o.showPassed != nil {
+		f.ShowPassed = *o.showPassed
+	}
+	if o.showSkipped != nil {
+		f.ShowSkipped = *o.showSkipped
+	}
+	return f
+}
+
+// render renders the scorecard in the format and version, nothing is rendered if ctx is done
+func render(ctx context.Context, scoreCard *scorecard.Scorecard, format, version string, opts renderOptions) (io.Reader, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// The human format and version v3 of the json format filter the checks themselves, as their summaries count all
+	// checks
+	filter := opts.filter(format)
+	selfFiltered := format == "human" || (format == "json" && version == "v3")
+	if !selfFiltered && (!filter.ShowPassed || !filter.ShowSkipped) {
+		filtered := scoreCard.Filter(filter)
+		scoreCard = &filtered
+	}
+
+	switch {
+	case format == "json" && version == "v1":
+		d, _ := json.MarshalIndent(scoreCard, "", "    ")
+		return bytes.NewBuffer(d), nil
+	case format == "json" && version == "v2":
+		return json_v2.Output(scoreCard), nil
+	case format == "json" && version == "v3":
+		jsonOptions := opts.jsonV3
+		jsonOptions.Run.Timestamp = time.Now().UTC()
+		jsonOptions.Filter = filter
+		return json_v3.Output(scoreCard, jsonOptions), nil
+	case format == "human" && version == "v1":
+		humanOptions := opts.human
+		humanOptions.ShowPassed, humanOptions.ShowSkipped = filter.ShowPassed, filter.ShowSkipped
+		return human.HumanWithOptions(scoreCard, opts.verboseOutput, opts.termWidth, humanOptions), nil
+	case format == "ci" && version == "v1":
+		return ci.CIWithOptions(scoreCard, opts.ci), nil
+	case format == "sarif":
+		return sarif.Output(scoreCard), nil
+	case format == "junit" && version == "v1":
+		return junit.JUnit(scoreCard), nil
+	case format == "github" && version == "v1":
+		return github.Output(scoreCard), nil
+	case format == "markdown" && version == "v1":
+		return markdown.Output(scoreCard), nil
+	case format == "html" && version == "v1":
+		return html.Output(scoreCard), nil
+	case format == "checkstyle" && version == "v1":
+		return checkstyle.Output(scoreCard), nil
+	case format == "tap" && version == "v1":
+		return tap.Output(scoreCard), nil
+	case format == "ndjson" && version == "v1":
+		return ndjson.Output(scoreCard), nil
+	case format == "template" && version == "v1":
+		if opts.template == nil {
+			return nil, fmt.Errorf("Error: --template must be set when using the template format")
+		}
+		return template.Output(scoreCard, opts.template)
+	default:
+		return nil, fmt.Errorf("error: Unknown --output-format or --output-version")
+	}
+}
+
+// changedFlags returns the values of the flags that are set on the command line or in the configuration file, by name
+func changedFlags(fs *flag.FlagSet) map[string]string {
+	res := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		res[f.Name] = f.Value.String()
+	})
+	return res
+}
+
+// checkDocumentation returns the severity, profiles and documentation URL of the checks, as written by version v3
+// of the json format. The checks without documentation, such as the checks of plugins, have no severity.
+func checkDocumentation(allChecks *checks.Checks, customProfiles map[string][]string) map[string]json_v3.CheckDocumentation {
+	res := make(map[string]json_v3.CheckDocumentation)
+	for _, check := range allChecks.All() {
+		doc, documented := allChecks.Documentation(check.ID)
+		var severity string
+		if documented {
+			severity = doc.Grade.Severity().String()
+		}
+		res[check.ID] = json_v3.CheckDocumentation{
+			Severity:         severity,
+			Categories:       checks.ProfilesOf(check.ID, customProfiles),
+			DocumentationURL: doc.URL,
+		}
+	}
+	return res
+}
+
+// parseOutputFormats parses the values of --output-format. A value is either a format, that is written to stdout,
+// or format=path, that is written to the file at path. At most one format can be written to stdout.
+func parseOutputFormats(values []string) (stdoutFormat string, files []outputFile, err error) {
+	for _, v := range values {
+		format, path := v, ""
+		if i := strings.Index(v, "="); i >= 0 {
+			format, path = v[:i], v[i+1:]
+			if path == "" {
+				return "", nil, fmt.Errorf("Error: --output-format %s has an empty path", v)
+			}
+		}
+
+		if !isOutputFormat(format) {
+			return "", nil, fmt.Errorf("Error: --output-format must be set to: '%s'", strings.Join(outputFormats, "', '"))
+		}
+
+		if path != "" {
+			files = append(files, outputFile{path: path, format: format})
+			continue
+		}
+		if stdoutFormat != "" {
+			return "", nil, fmt.Errorf("Error: --output-format can only write one format to stdout, got %s and %s. Use format=path to write to a file", stdoutFormat, format)
+		}
+		stdoutFormat = format
+	}
+	return stdoutFormat, files, nil
+}
+
+// outputFile is a file that the scorecard is written to, in addition to stdout
+type outputFile struct {
+	path   string
+	format string
+}
+
+// outputFiles pairs every --output-file with the --output-file-format at the same position. Files without an
+// --output-file-format are written in the --output-format.
+func outputFiles(paths, formats []string, defaultFormat string) ([]outputFile, error) {
+	if len(formats) > len(paths) {
+		return nil, fmt.Errorf("Error: --output-file-format is set %d times, but --output-file is only set %d times", len(formats), len(paths))
+	}
+
+	var res []outputFile
+	for i, path := range paths {
+		format := defaultFormat
+		if i < len(formats) {
+			format = formats[i]
+		}
+		if !isOutputFormat(format) {
+			return nil, fmt.Errorf("Error: --output-file-format must be set to: '%s'", strings.Join(outputFormats, "', '"))
+		}
+		res = append(res, outputFile{path: path, format: format})
+	}
+	return res, nil
+}
+
+// anyOutputFile returns true if any of the files is written in the format
+func anyOutputFile(files []outputFile, format string) bool {
+	for _, f := range files {
+		if f.format == format {
+			return true
+		}
+	}
+	return false
+}
+
+// writeNDJSON scores the objects and writes them to w in the ndjson format, one at a time as soon as each object has
+// been scored. The findings in the baseline are removed from the objects if it's not nil. It returns the totals of
+// the objects, that decide the exit code.
+func writeNDJSON(ctx context.Context, w io.Writer, allObjects ks.AllTypes, cnf config.Configuration, b *baseline.Baseline, filter scorecard.Filter) (scorecard.Totals, error) {
+	var totals scorecard.Totals
+	enc := ndjson.NewEncoder(w)
+	now := time.Now()
+	err := score.ScoreEach(ctx, allObjects, cnf, func(key string, o *scorecard.ScoredObject) error {
+		single := scorecard.Scorecard{key: o}
+		if b != nil {
+			b.Apply(&single, now)
+		}
+		totals.Add(o)
+		if !filter.ShowPassed || !filter.ShowSkipped {
+			single = single.Filter(filter)
+		}
+		return enc.Encode(key, single[key])
+	})
+	return totals, err
+}
+
+// writeOutputFiles renders the scorecard to all files. The --output-version is used for the files in the
+// --output-format, and all other files use the default version of their format.
+func writeOutputFiles(ctx context.Context, files []outputFile, scoreCard *scorecard.Scorecard, outputFormat, outputVersion string, opts renderOptions) error {
+	// The files are never written to a terminal
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	for _, f := range files {
+		version := getOutputVersion("", f.format)
+		if f.format == outputFormat {
+			version = getOutputVersion(outputVersion, f.format)
+		}
+
+		opts.termWidth = 80
+		r, err := render(ctx, scoreCard, f.format, version, opts)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(f.path, r); err != nil {
+			return fmt.Errorf("failed to write to file %s: %w", f.path, err)
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, r io.Reader) error {
+	fp, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(fp, r); err != nil {
+		fp.Close()
+		return err
+	}
+	return fp.Close()
+}
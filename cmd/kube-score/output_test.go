@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zegl/kube-score/baseline"
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/renderer/json_v3"
+	"github.com/zegl/kube-score/renderer/ndjson"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestParseOutputFormats(t *testing.T) {
+	stdout, files, err := parseOutputFormats([]string{"human", "sarif=report.sarif", "junit=out/report.xml"})
+	assert.NoError(t, err)
+	assert.Equal(t, "human", stdout)
+	assert.Equal(t, []outputFile{
+		{path: "report.sarif", format: "sarif"},
+		{path: "out/report.xml", format: "junit"},
+	}, files)
+
+	// Nothing is written to stdout if all formats are written to files
+	stdout, files, err = parseOutputFormats([]string{"json=report.json"})
+	assert.NoError(t, err)
+	assert.Equal(t, "", stdout)
+	assert.Equal(t, []outputFile{{path: "report.json", format: "json"}}, files)
+
+	_, _, err = parseOutputFormats([]string{"human", "ci"})
+	assert.Error(t, err)
+
+	_, _, err = parseOutputFormats([]string{"pdf=report.pdf"})
+	assert.Error(t, err)
+
+	_, _, err = parseOutputFormats([]string{"sarif="})
+	assert.Error(t, err)
+}
+
+func TestOutputFiles(t *testing.T) {
+	files, err := outputFiles([]string{"report.sarif", "report.xml", "report.txt"}, []string{"sarif", "junit"}, "human")
+	assert.NoError(t, err)
+	assert.Equal(t, []outputFile{
+		{path: "report.sarif", format: "sarif"},
+		{path: "report.xml", format: "junit"},
+		{path: "report.txt", format: "human"},
+	}, files)
+
+	_, err = outputFiles([]string{"report.sarif"}, []string{"sarif", "junit"}, "human")
+	assert.Error(t, err)
+
+	_, err = outputFiles([]string{"report.pdf"}, []string{"pdf"}, "human")
+	assert.Error(t, err)
+}
+
+func TestWriteOutputFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kube-score")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	card := scorecard.New()
+	o := card.NewObject(v1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}, v1.ObjectMeta{Name: "foo"}, false)
+	o.Checks = append(o.Checks, scorecard.TestScore{Grade: scorecard.GradeCritical})
+
+	files := []outputFile{
+		{path: filepath.Join(dir, "report.sarif"), format: "sarif"},
+		{path: filepath.Join(dir, "report.json"), format: "json"},
+		{path: filepath.Join(dir, "report.txt"), format: "human"},
+	}
+	assert.NoError(t, writeOutputFiles(context.Background(), files, &card, "human", "", renderOptions{}))
+
+	sarif, err := ioutil.ReadFile(files[0].path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(sarif), `"version": "2.1.0"`)
+
+	// The json file uses the default version, as --output-version only applies to the --output-format
+	json, err := ioutil.ReadFile(files[1].path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(json), `"object_name": "Deployment/apps/v1//foo"`)
+
+	human, err := ioutil.ReadFile(files[2].path)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(human), "apps/v1/Deployment foo"))
+	assert.NotContains(t, string(human), "\x1b[")
+}
+
+func TestColorEnabled(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	for _, tc := range []struct {
+		mode     string
+		terminal bool
+		env      map[string]string
+		expected bool
+	}{
+		{"auto", true, nil, true},
+		{"auto", false, nil, false},
+		{"auto", true, map[string]string{"NO_COLOR": "1"}, false},
+		{"auto", true, map[string]string{"TERM": "dumb"}, false},
+		{"always", false, map[string]string{"NO_COLOR": "1"}, true},
+		{"never", true, nil, false},
+	} {
+		enabled, err := colorEnabled(tc.mode, tc.terminal, env(tc.env))
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expected, enabled, "%+v", tc)
+	}
+
+	_, err := colorEnabled("yes", true, env(nil))
+	assert.NotNil(t, err)
+}
+
+func TestRenderShowPassed(t *testing.T) {
+	card := scorecard.New()
+	o := card.NewObject(v1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}, v1.ObjectMeta{Name: "foo"}, false)
+	o.Checks = append(o.Checks,
+		scorecard.TestScore{Check: domain.Check{Name: "failing"}, Grade: scorecard.GradeCritical},
+		scorecard.TestScore{Check: domain.Check{Name: "passing"}, Grade: scorecard.GradeAllOK},
+		scorecard.TestScore{Check: domain.Check{Name: "skipped"}, Skipped: true},
+	)
+
+	output := func(format string, opts renderOptions) string {
+		r, err := render(context.Background(), &card, format, getOutputVersion("", format), opts)
+		assert.NoError(t, err)
+		b, err := ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		return string(b)
+	}
+
+	// By default, the human format only writes the failing checks, and the other formats write all checks
+	assert.NotContains(t, output("human", renderOptions{}), "passing")
+	assert.Contains(t, output("ci", renderOptions{}), "[OK]")
+	assert.Contains(t, output("ci", renderOptions{}), "[SKIPPED]")
+
+	show, hide := true, false
+	human := output("human", renderOptions{showPassed: &show, showSkipped: &show})
+	assert.Contains(t, human, "[OK] passing")
+	assert.Contains(t, human, "[SKIPPED] skipped")
+
+	ci := output("ci", renderOptions{showPassed: &hide, showSkipped: &hide})
+	assert.Equal(t, "[CRITICAL] foo apps/v1/Deployment\n", ci)
+}
+
+func TestRenderJSONv3(t *testing.T) {
+	card := scorecard.New()
+	o := card.NewObject(v1.TypeMeta{Kind: "Service", APIVersion: "v1"}, v1.ObjectMeta{Name: "foo"}, false)
+	o.Checks = append(o.Checks,
+		scorecard.TestScore{Check: domain.Check{ID: "service-type"}, Grade: scorecard.GradeWarning},
+		scorecard.TestScore{Check: domain.Check{ID: "service-targets-pod"}, Grade: scorecard.GradeAllOK},
+	)
+
+	allChecks := score.RegisterAllChecks(parser.Empty(), config.Configuration{})
+	hide := false
+	r, err := render(context.Background(), &card, "json", "v3", renderOptions{
+		showPassed: &hide,
+		jsonV3:     json_v3.Options{Checks: checkDocumentation(allChecks, nil)},
+	})
+	assert.NoError(t, err)
+
+	var doc json_v3.Document
+	assert.NoError(t, json.NewDecoder(r).Decode(&doc))
+	assert.Equal(t, 1, doc.Summary.Passed)
+	assert.Len(t, doc.Objects[0].Results, 1)
+	assert.Equal(t, "medium", doc.Checks[0].Severity)
+	assert.Contains(t, doc.Checks[0].Categories, "security")
+	assert.False(t, doc.Run.Timestamp.IsZero())
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	ctx := context.Background()
+	parsed, err := parser.ParseFiles(ctx, config.Configuration{AllFiles: []domain.NamedReader{namedReader{Reader: strings.NewReader(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+spec:
+  type: NodePort
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: b
+`), name: "services.yaml"}}})
+	assert.NoError(t, err)
+
+	scoreCard, err := score.Score(ctx, parsed, config.Configuration{})
+	assert.NoError(t, err)
+
+	// The objects are written one per line, with the same content as when the scorecard is rendered
+	var out bytes.Buffer
+	totals, err := writeNDJSON(ctx, &out, parsed, config.Configuration{}, nil, scorecard.Filter{ShowPassed: true, ShowSkipped: true})
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	sort.Strings(lines)
+	var expected bytes.Buffer
+	assert.NoError(t, ndjson.Write(&expected, scoreCard))
+	assert.Equal(t, strings.Split(strings.TrimSpace(expected.String()), "\n"), lines)
+	assert.Equal(t, scoreCard.Score(), totals.Score())
+	assert.True(t, totals.AnyBelowOrEqualToGrade(scorecard.GradeWarning))
+
+	// Findings in the baseline are removed, and don't decide the exit code
+	b := baseline.New(scoreCard)
+	out.Reset()
+	totals, err = writeNDJSON(ctx, &out, parsed, config.Configuration{}, &b, scorecard.Filter{})
+	assert.NoError(t, err)
+	assert.False(t, totals.AnyBelowOrEqualToGrade(scorecard.GradeWarning))
+	assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), 2)
+	assert.NotContains(t, out.String(), "NodePort")
+}
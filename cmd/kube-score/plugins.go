@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/zegl/kube-score/plugins"
+)
+
+// loadPlugins loads the WebAssembly plugins, that are run with the runtime command
+func loadPlugins(paths []string, runtime string) ([]*plugins.Plugin, error) {
+	var loaded []*plugins.Plugin
+	for _, path := range paths {
+		p, err := plugins.Load(path, plugins.CommandRuntime{Command: runtime})
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, p)
+	}
+	return loaded, nil
+}
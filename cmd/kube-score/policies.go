@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/zegl/kube-score/policy"
+	"github.com/zegl/kube-score/score/checks"
+)
+
+// exportPolicies writes the checks as cluster policies for Kyverno or OPA Gatekeeper
+func exportPolicies(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	engine := fs.String("engine", "", "The policy engine to export the policies for. Set to 'kyverno' or 'gatekeeper'")
+	checkIDs := fs.StringSlice("check", []string{}, "Export the policy of this check, can be set multiple times. By default, the policies of all checks that can be exported are written")
+	profiles := fs.StringSlice("profile", []string{}, "Only export the policies of the checks of this profile, can be set multiple times. Set to 'security', 'reliability', 'cost' or 'all'")
+	audit := fs.Bool("audit", false, "Only report the objects that fail the policies, instead of denying them")
+	setDefault(fs, binName, "export-policies", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %s", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	if *engine == "" {
+		return usageError{fmt.Sprintf(`Error: No policy engine given.
+
+Usage: %s export-policies --engine kyverno|gatekeeper [--check id] [--profile name]
+
+The checks that can be exported are: %s`, execName(binName), strings.Join(policy.Checks(), ", "))}
+	}
+
+	ids, err := policyChecks(*checkIDs, *profiles)
+	if err != nil {
+		return err
+	}
+
+	return policy.Export(os.Stdout, *engine, ids, policy.Options{Audit: *audit})
+}
+
+// policyChecks returns the IDs of the checks to export. The checks that are not in any of the profiles are not
+// exported.
+func policyChecks(checkIDs, profiles []string) ([]string, error) {
+	if len(checkIDs) == 0 {
+		checkIDs = policy.Checks()
+	}
+
+	inProfiles, err := checks.ProfileChecks(profiles, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, id := range checkIDs {
+		if inProfiles != nil {
+			if _, ok := inProfiles[id]; !ok {
+				continue
+			}
+		}
+		res = append(res, id)
+	}
+	sort.Strings(res)
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("none of the checks are in the profiles: %s", strings.Join(profiles, ", "))
+	}
+	return res, nil
+}
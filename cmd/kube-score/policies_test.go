@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyChecks(t *testing.T) {
+	ids, err := policyChecks([]string{"service-type", "container-resources"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"container-resources", "service-type"}, ids)
+
+	// container-resources is not in the security profile
+	ids, err = policyChecks([]string{"service-type", "container-resources"}, []string{"security"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"service-type"}, ids)
+
+	ids, err = policyChecks(nil, []string{"all"})
+	assert.NoError(t, err)
+	assert.Contains(t, ids, "pod-host-network")
+
+	_, err = policyChecks([]string{"container-resources"}, []string{"security"})
+	assert.Error(t, err)
+
+	_, err = policyChecks(nil, []string{"no-such-profile"})
+	assert.Error(t, err)
+}
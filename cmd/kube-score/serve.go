@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/internal/logger"
+	"github.com/zegl/kube-score/server"
+)
+
+// serve runs the HTTP API until the process is interrupted
+func serve(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	listenAddress := fs.String("listen-address", ":8080", "The address that the server listens on")
+	timeout := fs.Duration("timeout", 0, "Cancel the scoring of a request if it takes longer than this, such as 5s. By default there is no timeout")
+	configFile := fs.String("config", "", "Path to a configuration file. By default .kube-score.yml is read from the current directory if it exists")
+	checks := registerCheckFlags(fs)
+	verbose := fs.CountP("verbose", "v", "Enable debug logging")
+	logs := registerLogFlags(fs)
+	setDefault(fs, binName, "serve", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %s", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	if *timeout < 0 {
+		return fmt.Errorf("--timeout must not be negative")
+	}
+
+	file, err := readConfigFile(*configFile)
+	if err != nil {
+		return err
+	}
+	if err := applyConfigFileFlags(fs, checkOptionsOnly(fs, file)); err != nil {
+		return err
+	}
+	if err := logs.setup(*verbose > 0); err != nil {
+		return err
+	}
+
+	cnf, err := checks.configuration(file)
+	if err != nil {
+		return err
+	}
+
+	scorer := server.New(cnf)
+	scorer.Timeout = *timeout
+
+	srv := &http.Server{
+		Addr:              *listenAddress,
+		Handler:           scorer.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		logger.Info("Listening", "address", *listenAddress)
+		errs <- srv.ListenAndServe()
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err := <-errs:
+		return err
+	case <-stop:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
+
+// checkOptionsOnly removes the options from the configuration file that are not flags of the action, such as the
+// output format, as the configuration file is shared with the score action
+func checkOptionsOnly(fs *flag.FlagSet, file config.File) config.File {
+	flags := make(map[string]interface{})
+	for name, value := range file.Flags {
+		if fs.Lookup(name) != nil {
+			flags[name] = value
+		}
+	}
+	file.Flags = flags
+	return file
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// tool is an external binary that kube-score runs, such as helm. The tools are not included in kube-score, and must
+// be installed separately, and be available in PATH.
+type tool struct {
+	name string
+
+	// purpose is what the tool is used for, such as "render Helm charts"
+	purpose string
+
+	// installURL describes how to install the tool
+	installURL string
+}
+
+var helmTool = tool{name: "helm", purpose: "render Helm charts", installURL: "https://helm.sh/docs/intro/install/"}
+
+// command returns the command that runs the tool with the arguments, or an error that explains how to install the
+// tool if it's not found in PATH
+func (t tool) command(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	path, err := exec.LookPath(t.name)
+	if err != nil {
+		return nil, fmt.Errorf("%s is required to %s, but was not found in PATH, see %s for how to install it: %w", t.name, t.purpose, t.installURL, err)
+	}
+	return exec.CommandContext(ctx, path, args...), nil
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolNotInstalled(t *testing.T) {
+	missing := tool{name: "kube-score-test-missing-tool", purpose: "run the tests", installURL: "https://example.com/install"}
+	_, err := missing.command(context.Background(), "version")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "kube-score-test-missing-tool is required to run the tests, but was not found in PATH, see https://example.com/install for how to install it")
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/zegl/kube-score/compare"
+	"github.com/zegl/kube-score/internal/logger"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// watchDebounce is how long to wait for more changes after a file has changed, before the input is scored again.
+// Editors often write a file in multiple steps, and tools such as git change many files at once.
+const watchDebounce = 200 * time.Millisecond
+
+// inputWatcher scores the input again every time a file in the input changes. The first scorecard is written in
+// full, and the findings that are new, fixed or changed since the last run are written after the following runs.
+type inputWatcher struct {
+	// paths are the files and directories in the input, as given on the command line
+	paths        []string
+	includeGlobs []string
+	excludeGlobs []string
+
+	// outputPaths are the files that the output is written to. They are written after every run, and changes to
+	// them must not make the watcher score the input again.
+	outputPaths []string
+
+	newContext func() (context.Context, context.CancelFunc)
+	score      func(ctx context.Context, files []string) (*scorecard.Scorecard, error)
+	write      func(ctx context.Context, scoreCard *scorecard.Scorecard, toStdout bool) error
+	out        io.Writer
+
+	// stop makes watch return when it's closed
+	stop chan struct{}
+
+	scored   bool
+	previous []compare.Finding
+}
+
+// watch scores the input, and scores it again when the files change, until stop is closed
+func (w *inputWatcher) watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch the input: %w", err)
+	}
+	defer watcher.Close()
+
+	for _, dir := range watchDirs(w.paths) {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+	}
+
+	w.run()
+
+	var rescore <-chan time.Time
+	for {
+		select {
+		case <-w.stop:
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod || !isWatchedFile(event.Name, w.paths, w.outputPaths) {
+				continue
+			}
+
+			// Directories that are created in the input are also watched
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					for _, dir := range watchDirs([]string{event.Name}) {
+						if err := watcher.Add(dir); err != nil {
+							logger.Warn("Failed to watch directory", "path", dir, "error", err)
+						}
+					}
+				}
+			}
+
+			logger.Debug("File changed", "path", event.Name, "op", event.Op.String())
+			rescore = time.After(watchDebounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Warn("Failed to watch the input", "error", err)
+
+		case <-rescore:
+			rescore = nil
+			w.run()
+		}
+	}
+}
+
+// run scores the input once. Errors are written to the output, as the files are often invalid while they are being
+// edited.
+func (w *inputWatcher) run() {
+	ctx, cancel := w.newContext()
+	defer cancel()
+
+	if w.scored {
+		fmt.Fprintf(w.out, "\nThe input has changed, scored again at %s\n", time.Now().Format("15:04:05"))
+	}
+
+	scoreCard, err := w.scoreInput(ctx)
+	if err != nil {
+		fmt.Fprintf(w.out, "Failed to score the input: %s\n", err)
+	} else {
+		findings := compare.Findings(scoreCard)
+		if w.scored {
+			compare.Compare(w.previous, findings).WriteHuman(w.out)
+		}
+		// The output files are always updated, but the full scorecard is only written to stdout after the first run
+		if err := w.write(ctx, scoreCard, !w.scored); err != nil {
+			fmt.Fprintf(w.out, "Failed to write the output: %s\n", err)
+		}
+		w.scored = true
+		w.previous = findings
+	}
+
+	fmt.Fprintln(w.out, "\nWatching for changes, press Ctrl+C to stop")
+}
+
+// scoreInput scores the files in the input. The directories are read again, to find files that have been created.
+func (w *inputWatcher) scoreInput(ctx context.Context) (*scorecard.Scorecard, error) {
+	files, err := expandPaths(w.paths, w.includeGlobs, w.excludeGlobs)
+	if err != nil {
+		return nil, err
+	}
+	return w.score(ctx, files)
+}
+
+// watchedPath returns the local path of an input, such as the directory of a Helm chart
+func watchedPath(path string) string {
+	path = strings.TrimPrefix(path, helmInputPrefix)
+	path = strings.TrimPrefix(path, kustomizeInputPrefix)
+	return filepath.Clean(path)
+}
+
+// watchDirs returns the directories that have to be watched to see changes of the input. Directories are watched
+// with all their subdirectories, and files are watched through the directory that they are in, to also see files
+// that are replaced instead of written to. Inputs that don't exist locally, such as Helm charts from a repository,
+// are not watched.
+func watchDirs(paths []string) []string {
+	dirs := make(map[string]struct{})
+	for _, path := range paths {
+		path = watchedPath(path)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			logger.Debug("Not watching an input that is not available locally", "path", path)
+			continue
+		}
+		if !info.IsDir() {
+			dirs[filepath.Dir(path)] = struct{}{}
+			continue
+		}
+
+		_ = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err != nil || !info.IsDir() {
+				return nil
+			}
+			if p != path && isHiddenFile(p) {
+				return filepath.SkipDir
+			}
+			dirs[p] = struct{}{}
+			return nil
+		})
+	}
+
+	var res []string
+	for dir := range dirs {
+		res = append(res, dir)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// isWatchedFile reports whether the changed file is one of the input files, or in one of the input directories.
+// Hidden files, the backup files of editors, and the output files are not watched.
+func isWatchedFile(name string, paths, outputPaths []string) bool {
+	name = filepath.Clean(name)
+	if isHiddenFile(name) || strings.HasSuffix(name, "~") {
+		return false
+	}
+	for _, output := range outputPaths {
+		if isSameFile(name, output) {
+			return false
+		}
+	}
+	for _, path := range paths {
+		path = watchedPath(path)
+		if name == path || strings.HasPrefix(name, path+string(filepath.Separator)) || path == "." {
+			return true
+		}
+	}
+	return false
+}
+
+// isSameFile reports whether the paths refer to the same file, also if one of them is relative
+func isSameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
+
+func isHiddenFile(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasPrefix(base, ".") && base != "." && base != ".."
+}
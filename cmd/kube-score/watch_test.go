@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// syncBuffer is a bytes.Buffer that can be written to and read from concurrently
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatchDirs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kube-score")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "apps", "prod"), 0755))
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, ".git", "objects"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "service.yaml"), []byte("kind: Service"), 0644))
+
+	assert.Equal(t, []string{
+		dir,
+		filepath.Join(dir, "apps"),
+		filepath.Join(dir, "apps", "prod"),
+	}, watchDirs([]string{dir, filepath.Join(dir, "service.yaml"), "helm://" + filepath.Join(dir, "apps"), "does-not-exist"}))
+}
+
+func TestIsWatchedFile(t *testing.T) {
+	paths := []string{"apps/", "service.yaml", "kustomize://overlays/prod"}
+	assert.True(t, isWatchedFile("apps/deployment.yaml", paths, nil))
+	assert.True(t, isWatchedFile("service.yaml", paths, nil))
+	assert.True(t, isWatchedFile("overlays/prod/kustomization.yaml", paths, nil))
+	assert.False(t, isWatchedFile("other.yaml", paths, nil))
+	assert.False(t, isWatchedFile("apps/.deployment.yaml.swp", paths, nil))
+	assert.False(t, isWatchedFile("apps/deployment.yaml~", paths, nil))
+	assert.False(t, isWatchedFile("services.yaml", paths, nil))
+
+	// The output files are not watched, also if they are in an input directory
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.False(t, isWatchedFile("apps/report.json", paths, []string{"apps/report.json"}))
+	assert.False(t, isWatchedFile(filepath.Join(wd, "apps", "report.json"), []string{filepath.Join(wd, "apps")}, []string{"apps/report.json"}))
+	assert.True(t, isWatchedFile("apps/deployment.yaml", paths, []string{"apps/report.json"}))
+}
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kube-score")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	service := filepath.Join(dir, "service.yaml")
+	assert.Nil(t, ioutil.WriteFile(service, []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  type: NodePort
+`), 0644))
+
+	var out syncBuffer
+	var written []bool
+	w := &inputWatcher{
+		paths: []string{dir},
+		newContext: func() (context.Context, context.CancelFunc) {
+			return context.WithCancel(context.Background())
+		},
+		score: func(ctx context.Context, files []string) (*scorecard.Scorecard, error) {
+			inputs, err := openInputs(ctx, files, nil, nil)
+			if err != nil {
+				return nil, err
+			}
+			parsed, err := parser.ParseFiles(ctx, config.Configuration{AllFiles: inputs})
+			if err != nil {
+				return nil, err
+			}
+			return score.Score(ctx, parsed, config.Configuration{})
+		},
+		write: func(ctx context.Context, scoreCard *scorecard.Scorecard, toStdout bool) error {
+			written = append(written, toStdout)
+			return nil
+		},
+		out:  &out,
+		stop: make(chan struct{}),
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- w.watch()
+	}()
+
+	waitForOutput := func(s string) {
+		for i := 0; i < 100 && !strings.Contains(out.String(), s); i++ {
+			time.Sleep(50 * time.Millisecond)
+		}
+		assert.Contains(t, out.String(), s)
+	}
+
+	waitForOutput("Watching for changes")
+
+	// Fixing the Service is reported as a fixed finding
+	assert.Nil(t, ioutil.WriteFile(service, []byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  type: ClusterIP
+`), 0644))
+	waitForOutput("[WARNING] Service/v1//app: service-type: The service is of type NodePort")
+	assert.Contains(t, out.String(), "Fixed findings (1):")
+
+	// Files that are not valid are reported, and the input is still watched
+	assert.Nil(t, ioutil.WriteFile(service, []byte("kind: [Service"), 0644))
+	waitForOutput("Failed to score the input")
+
+	close(w.stop)
+	assert.Nil(t, <-done)
+
+	// The full scorecard is only written after the first run
+	assert.Equal(t, []bool{true, false}, written)
+}
+
+func TestWatchIgnoresOutputFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kube-score")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "service.yaml"), []byte("kind: Service"), 0644))
+	report := filepath.Join(dir, "report.txt")
+
+	var out syncBuffer
+	w := &inputWatcher{
+		paths:       []string{dir},
+		outputPaths: []string{report},
+		newContext: func() (context.Context, context.CancelFunc) {
+			return context.WithCancel(context.Background())
+		},
+		score: func(ctx context.Context, files []string) (*scorecard.Scorecard, error) {
+			return &scorecard.Scorecard{}, nil
+		},
+		write: func(ctx context.Context, scoreCard *scorecard.Scorecard, toStdout bool) error {
+			return ioutil.WriteFile(report, []byte(time.Now().String()), 0644)
+		},
+		out:  &out,
+		stop: make(chan struct{}),
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- w.watch()
+	}()
+
+	// Writing the output file does not make the input be scored again
+	time.Sleep(5 * watchDebounce)
+	close(w.stop)
+	assert.Nil(t, <-done)
+	assert.Equal(t, 1, strings.Count(out.String(), "Watching for changes"))
+}
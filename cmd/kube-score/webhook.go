@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/zegl/kube-score/internal/logger"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/scorecard"
+	"github.com/zegl/kube-score/server"
+	"github.com/zegl/kube-score/webhook"
+)
+
+// runWebhook runs the validating admission webhook until the process is interrupted
+func runWebhook(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	listenAddress := fs.String("listen-address", ":8443", "The address that the webhook listens on")
+	tlsCertFile := fs.String("tls-cert-file", "", "Path to the TLS certificate of the webhook (required)")
+	tlsKeyFile := fs.String("tls-private-key-file", "", "Path to the private key of the TLS certificate (required)")
+	denyGrade := fs.String("deny-grade", "critical", "Deny objects with a check at or below this grade. Set to 'critical', 'high', 'warning' or 'none'")
+	warnGrade := fs.String("warn-grade", "warning", "Admit objects with a check at or below this grade with a warning. Set to 'critical', 'high', 'warning' or 'none'")
+	timeout := fs.Duration("timeout", 0, "Cancel the scoring of a request if it takes longer than this, such as 5s. By default there is no timeout")
+	failOpen := fs.Bool("fail-open", false, "Admit the objects that can't be scored, such as when the scoring times out, with a warning")
+	failClosed := fs.Bool("fail-closed", false, "Deny the objects that can't be scored, such as when the scoring times out. This is the default")
+	configFile := fs.String("config", "", "Path to a configuration file. By default .kube-score.yml is read from the current directory if it exists")
+	checks := registerCheckFlags(fs)
+	verbose := fs.CountP("verbose", "v", "Enable debug logging")
+	logs := registerLogFlags(fs)
+	setDefault(fs, binName, "webhook", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %s", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	if *tlsCertFile == "" || *tlsKeyFile == "" {
+		return fmt.Errorf("--tls-cert-file and --tls-private-key-file are required, the API server only calls webhooks over HTTPS")
+	}
+
+	if *timeout < 0 {
+		return fmt.Errorf("--timeout must not be negative")
+	}
+
+	if *failOpen && *failClosed {
+		return fmt.Errorf("--fail-open and --fail-closed can not be used together")
+	}
+
+	file, err := readConfigFile(*configFile)
+	if err != nil {
+		return err
+	}
+	if err := applyConfigFileFlags(fs, checkOptionsOnly(fs, file)); err != nil {
+		return err
+	}
+	if err := logs.setup(*verbose > 0); err != nil {
+		return err
+	}
+
+	cnf, err := checks.configuration(file)
+	if err != nil {
+		return err
+	}
+	// The webhook only sees one object at the time, so the checks that look at other objects would fail for all
+	// objects
+	for _, id := range score.RegisterAllChecks(parser.Empty(), cnf).CrossObject() {
+		cnf.IgnoredTests[id] = struct{}{}
+	}
+
+	scorer := server.New(cnf)
+	scorer.Timeout = *timeout
+
+	wh := webhook.New(scorer)
+	if wh.DenyGrade, err = parseThresholdGrade(*denyGrade); err != nil {
+		return fmt.Errorf("Invalid --deny-grade: %w", err)
+	}
+	if wh.WarnGrade, err = parseThresholdGrade(*warnGrade); err != nil {
+		return fmt.Errorf("Invalid --warn-grade: %w", err)
+	}
+	wh.FailOpen = *failOpen
+
+	mux := http.NewServeMux()
+	mux.Handle("/validate", wh)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+
+	srv := &http.Server{
+		Addr:              *listenAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		logger.Info("Listening", "address", *listenAddress)
+		errs <- srv.ListenAndServeTLS(*tlsCertFile, *tlsKeyFile)
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err := <-errs:
+		return err
+	case <-stop:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
+
+// parseThresholdGrade parses a grade threshold, where "none" disables the threshold
+func parseThresholdGrade(s string) (scorecard.Grade, error) {
+	switch s {
+	case "none":
+		return 0, nil
+	case "critical", "high", "warning", "medium":
+		return scorecard.ParseGrade(s)
+	default:
+		return 0, fmt.Errorf("unknown grade %q, must be one of: critical, high, warning (medium), none", s)
+	}
+}
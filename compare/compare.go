@@ -0,0 +1,163 @@
+// Package compare compares the findings of two scorecards, to report the findings that are new, fixed or changed
+// between two versions of the same objects.
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/zegl/kube-score/renderer/json_v2"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Finding is a failing check on an object. A finding is identified by the object, the check, and the path, in the
+// same way as in a baseline.
+type Finding struct {
+	// Object is the key of the object in the scorecard (Kind/APIVersion/Namespace/Name)
+	Object  string          `json:"object"`
+	Check   string          `json:"check"`
+	Path    string          `json:"path,omitempty"`
+	Grade   scorecard.Grade `json:"grade"`
+	Summary string          `json:"summary,omitempty"`
+}
+
+type findingKey struct {
+	object, check, path string
+}
+
+func (f Finding) key() findingKey {
+	return findingKey{f.Object, f.Check, f.Path}
+}
+
+// Change is a finding that exists in both scorecards, with different grades
+type Change struct {
+	Finding
+	OldGrade scorecard.Grade `json:"old_grade"`
+}
+
+// Result is the difference between the old and the new findings
+type Result struct {
+	New     []Finding `json:"new"`
+	Fixed   []Finding `json:"fixed"`
+	Changed []Change  `json:"changed"`
+}
+
+// Findings returns the WARNING and CRITICAL findings in the scorecard
+func Findings(card *scorecard.Scorecard) []Finding {
+	var res []Finding
+	for key, o := range *card {
+		for _, c := range o.Checks {
+			if c.Skipped || c.Grade > scorecard.GradeWarning {
+				continue
+			}
+			if len(c.Comments) == 0 {
+				res = append(res, Finding{Object: key, Check: c.Check.ID, Grade: c.Grade})
+			}
+			for _, comment := range c.Comments {
+				res = append(res, Finding{Object: key, Check: c.Check.ID, Path: comment.Path, Grade: c.Grade, Summary: comment.Summary})
+			}
+		}
+	}
+	return res
+}
+
+// ParseFindings reads the WARNING and CRITICAL findings from a scorecard in the json v2 format
+func ParseFindings(r io.Reader) ([]Finding, error) {
+	var objects []json_v2.ScoredObject
+	if err := json.NewDecoder(r).Decode(&objects); err != nil {
+		return nil, fmt.Errorf("invalid scorecard, expected the output of --output-format json: %w", err)
+	}
+
+	var res []Finding
+	for _, o := range objects {
+		for _, c := range o.Checks {
+			// The grade of v2 does not have the high and info grades, that are compared with the exact grade
+			grade := c.ExactGrade()
+			if c.Skipped || grade > scorecard.GradeWarning {
+				continue
+			}
+			if len(c.Comments) == 0 {
+				res = append(res, Finding{Object: o.ObjectName, Check: c.Check.ID, Grade: grade})
+			}
+			for _, comment := range c.Comments {
+				res = append(res, Finding{Object: o.ObjectName, Check: c.Check.ID, Path: comment.Path, Grade: grade, Summary: comment.Summary})
+			}
+		}
+	}
+	return res, nil
+}
+
+// Compare returns the findings that are new, fixed and changed in newFindings compared to oldFindings
+func Compare(oldFindings, newFindings []Finding) Result {
+	oldByKey := byKey(oldFindings)
+	newByKey := byKey(newFindings)
+
+	var res Result
+	for key, n := range newByKey {
+		o, ok := oldByKey[key]
+		switch {
+		case !ok:
+			res.New = append(res.New, n)
+		case o.Grade != n.Grade:
+			res.Changed = append(res.Changed, Change{Finding: n, OldGrade: o.Grade})
+		}
+	}
+	for key, o := range oldByKey {
+		if _, ok := newByKey[key]; !ok {
+			res.Fixed = append(res.Fixed, o)
+		}
+	}
+
+	sortFindings(res.New)
+	sortFindings(res.Fixed)
+	sort.Slice(res.Changed, func(i, j int) bool {
+		return less(res.Changed[i].Finding, res.Changed[j].Finding)
+	})
+	return res
+}
+
+// Regressions returns the new findings, and the findings that have a lower grade than before, with a grade at or
+// below the threshold
+func (r Result) Regressions(threshold scorecard.Grade) []Finding {
+	var res []Finding
+	for _, f := range r.New {
+		if f.Grade <= threshold {
+			res = append(res, f)
+		}
+	}
+	for _, c := range r.Changed {
+		if c.Grade < c.OldGrade && c.Grade <= threshold {
+			res = append(res, c.Finding)
+		}
+	}
+	return res
+}
+
+// byKey indexes the findings, if an object has multiple comments with the same path, the first one is kept
+func byKey(findings []Finding) map[findingKey]Finding {
+	res := make(map[findingKey]Finding)
+	for _, f := range findings {
+		if _, ok := res[f.key()]; !ok {
+			res[f.key()] = f
+		}
+	}
+	return res
+}
+
+func sortFindings(findings []Finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		return less(findings[i], findings[j])
+	})
+}
+
+func less(x, y Finding) bool {
+	if x.Object != y.Object {
+		return x.Object < y.Object
+	}
+	if x.Check != y.Check {
+		return x.Check < y.Check
+	}
+	return x.Path < y.Path
+}
@@ -0,0 +1,103 @@
+package compare
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/renderer/json_v2"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestCompare(t *testing.T) {
+	oldFindings := []Finding{
+		{Object: "Deployment/apps/v1//foo", Check: "container-resources", Path: "app", Grade: scorecard.GradeCritical},
+		{Object: "Deployment/apps/v1//foo", Check: "container-image-tag", Path: "app", Grade: scorecard.GradeCritical},
+		{Object: "Service/v1//foo", Check: "service-type", Grade: scorecard.GradeWarning},
+		{Object: "Deployment/apps/v1//foo", Check: "pod-probes", Grade: scorecard.GradeCritical},
+	}
+	newFindings := []Finding{
+		{Object: "Deployment/apps/v1//foo", Check: "container-resources", Path: "app", Grade: scorecard.GradeCritical},
+		{Object: "Deployment/apps/v1//foo", Check: "container-resources", Path: "sidecar", Grade: scorecard.GradeCritical},
+		{Object: "Service/v1//foo", Check: "service-type", Grade: scorecard.GradeCritical},
+		{Object: "Deployment/apps/v1//foo", Check: "pod-probes", Grade: scorecard.GradeWarning},
+	}
+
+	res := Compare(oldFindings, newFindings)
+	assert.Equal(t, []Finding{
+		{Object: "Deployment/apps/v1//foo", Check: "container-resources", Path: "sidecar", Grade: scorecard.GradeCritical},
+	}, res.New)
+	assert.Equal(t, []Finding{
+		{Object: "Deployment/apps/v1//foo", Check: "container-image-tag", Path: "app", Grade: scorecard.GradeCritical},
+	}, res.Fixed)
+	assert.Equal(t, []Change{
+		{Finding: Finding{Object: "Deployment/apps/v1//foo", Check: "pod-probes", Grade: scorecard.GradeWarning}, OldGrade: scorecard.GradeCritical},
+		{Finding: Finding{Object: "Service/v1//foo", Check: "service-type", Grade: scorecard.GradeCritical}, OldGrade: scorecard.GradeWarning},
+	}, res.Changed)
+
+	// The improved pod-probes finding is not a regression
+	assert.Equal(t, []Finding{
+		{Object: "Deployment/apps/v1//foo", Check: "container-resources", Path: "sidecar", Grade: scorecard.GradeCritical},
+		{Object: "Service/v1//foo", Check: "service-type", Grade: scorecard.GradeCritical},
+	}, res.Regressions(scorecard.GradeCritical))
+
+	assert.Empty(t, Compare(oldFindings, oldFindings).Regressions(scorecard.GradeWarning))
+}
+
+func TestRegressionsThreshold(t *testing.T) {
+	res := Compare(nil, []Finding{{Object: "Service/v1//foo", Check: "service-type", Grade: scorecard.GradeWarning}})
+	assert.Empty(t, res.Regressions(scorecard.GradeCritical))
+	assert.Len(t, res.Regressions(scorecard.GradeWarning), 1)
+}
+
+func TestParseFindings(t *testing.T) {
+	card := scorecard.New()
+	o := card.NewObject(metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}, metav1.ObjectMeta{Name: "foo"}, false)
+	o.Checks = []scorecard.TestScore{
+		{Check: ks.Check{ID: "container-resources"}, Grade: scorecard.GradeCritical, Comments: []scorecard.TestScoreComment{{Path: "app", Summary: "CPU limit is not set"}}},
+		{Check: ks.Check{ID: "pod-probes"}, Grade: scorecard.GradeWarning},
+		{Check: ks.Check{ID: "container-image-tag"}, Grade: scorecard.GradeAllOK},
+		{Check: ks.Check{ID: "container-seccomp-profile"}, Skipped: true},
+		{Check: ks.Check{ID: "role-wildcard-permissions"}, Grade: scorecard.GradeHigh},
+		{Check: ks.Check{ID: "native-sidecar-migration"}, Grade: scorecard.GradeInfo},
+	}
+
+	// The findings are the same when they are read from the json output
+	parsed, err := ParseFindings(json_v2.Output(&card))
+	assert.Nil(t, err)
+	assert.Equal(t, Findings(&card), parsed)
+	assert.Equal(t, []Finding{
+		{Object: "Deployment/apps/v1//foo", Check: "container-resources", Path: "app", Grade: scorecard.GradeCritical, Summary: "CPU limit is not set"},
+		{Object: "Deployment/apps/v1//foo", Check: "pod-probes", Grade: scorecard.GradeWarning},
+		{Object: "Deployment/apps/v1//foo", Check: "role-wildcard-permissions", Grade: scorecard.GradeHigh},
+	}, parsed)
+
+	// Findings without a severity, from older versions of kube-score, have the grade of v2
+	parsed, err = ParseFindings(strings.NewReader(`[{"object_name": "Service/v1//foo", "checks": [{"check": {"id": "service-type"}, "grade": 5}]}]`))
+	assert.Nil(t, err)
+	assert.Equal(t, []Finding{{Object: "Service/v1//foo", Check: "service-type", Grade: scorecard.GradeWarning}}, parsed)
+
+	_, err = ParseFindings(strings.NewReader("not json"))
+	assert.Error(t, err)
+}
+
+func TestWriteHuman(t *testing.T) {
+	var buf bytes.Buffer
+	Result{
+		New:     []Finding{{Object: "Deployment/apps/v1//foo", Check: "container-resources", Path: "app", Grade: scorecard.GradeCritical, Summary: "CPU limit is not set"}},
+		Changed: []Change{{Finding: Finding{Object: "Service/v1//foo", Check: "service-type", Grade: scorecard.GradeCritical}, OldGrade: scorecard.GradeWarning}},
+	}.WriteHuman(&buf)
+	assert.Equal(t, `New findings (1):
+    [CRITICAL] Deployment/apps/v1//foo: container-resources (app): CPU limit is not set
+Changed findings (1):
+    [WARNING -> CRITICAL] Service/v1//foo: service-type
+`, buf.String())
+
+	buf.Reset()
+	Result{}.WriteHuman(&buf)
+	assert.Equal(t, "No changes in the findings\n", buf.String())
+}
@@ -0,0 +1,65 @@
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// WriteHuman writes the result as a human readable report
+func (r Result) WriteHuman(w io.Writer) {
+	if len(r.New) == 0 && len(r.Fixed) == 0 && len(r.Changed) == 0 {
+		fmt.Fprintln(w, "No changes in the findings")
+		return
+	}
+
+	section := func(title string, findings []Finding) {
+		if len(findings) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "%s (%d):\n", title, len(findings))
+		for _, f := range findings {
+			fmt.Fprintf(w, "    [%s] %s\n", f.Grade, describe(f))
+		}
+	}
+
+	section("New findings", r.New)
+	section("Fixed findings", r.Fixed)
+
+	if len(r.Changed) > 0 {
+		fmt.Fprintf(w, "Changed findings (%d):\n", len(r.Changed))
+		for _, c := range r.Changed {
+			fmt.Fprintf(w, "    [%s -> %s] %s\n", c.OldGrade, c.Grade, describe(c.Finding))
+		}
+	}
+}
+
+// WriteJSON writes the result as JSON
+func (r Result) WriteJSON(w io.Writer) error {
+	// Empty lists are written as [] instead of null
+	out := r
+	if out.New == nil {
+		out.New = []Finding{}
+	}
+	if out.Fixed == nil {
+		out.Fixed = []Finding{}
+	}
+	if out.Changed == nil {
+		out.Changed = []Change{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(out)
+}
+
+func describe(f Finding) string {
+	s := fmt.Sprintf("%s: %s", f.Object, f.Check)
+	if f.Path != "" {
+		s += fmt.Sprintf(" (%s)", f.Path)
+	}
+	if f.Summary != "" {
+		s += ": " + f.Summary
+	}
+	return s
+}
@@ -6,7 +6,11 @@ import (
 	"strconv"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/labels"
+
 	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/plugins"
+	"github.com/zegl/kube-score/scorecard"
 )
 
 type Configuration struct {
@@ -16,10 +20,96 @@ type Configuration struct {
 	IgnoreContainerMemoryLimitRequirement bool
 	IgnoredTests                          map[string]struct{}
 	EnabledOptionalTests                  map[string]struct{}
+	EnableAllOptionalTests                bool
 	UseIgnoreChecksAnnotation             bool
 	KubernetesVersion                     Semver
+
+	// RequiredDroppedCapabilities are the capabilities that all containers must drop. If empty, all containers must
+	// drop ALL capabilities.
+	RequiredDroppedCapabilities []string
+
+	// AllowedHostPaths are paths that pods are allowed to mount as hostPath volumes, including all paths below them
+	AllowedHostPaths []string
+
+	// AllowedImageRegistries are the registries that images are allowed to be pulled from. A registry can be a host
+	// (gcr.io), or a host and a path (gcr.io/my-project).
+	AllowedImageRegistries []string
+
+	// RequiredLabels are the labels that all workloads must have. If empty, the recommended app.kubernetes.io labels
+	// are required.
+	RequiredLabels []string
+
+	// MaxMemoryLimitRatio is the highest allowed ratio between the memory limit and the memory request of a
+	// container. If zero, a ratio of 2 is used.
+	MaxMemoryLimitRatio float64
+
+	// AllowedPriorityClasses are the PriorityClasses that pods are allowed to use. If empty, all PriorityClasses are
+	// allowed.
+	AllowedPriorityClasses []string
+
+	// ReadWriteOnceStorageClasses are the StorageClasses that do not support the ReadWriteMany access mode. If empty,
+	// the default StorageClasses of GKE, EKS and AKS are used.
+	ReadWriteOnceStorageClasses []string
+
+	// PodSecurityStandard is the Pod Security Standards profile (privileged, baseline or restricted) that all pods
+	// must satisfy. If empty, no profile is enforced.
+	PodSecurityStandard string
+
+	// GradeOverrides changes the grade of failing checks, keyed by check ID
+	GradeOverrides map[string]scorecard.Grade
+
+	// CustomChecks are the checks that are defined in the configuration file
+	CustomChecks []CustomCheck
+
+	// Profiles are the names of the profiles of checks to run. If empty, or if it contains "all", the checks are
+	// not limited to a profile.
+	Profiles []string
+
+	// CustomProfiles are the profiles that are defined in the configuration file, with the IDs of their checks
+	CustomProfiles map[string][]string
+
+	// IgnoreRules ignore checks on the objects in some paths or namespaces
+	IgnoreRules []IgnoreRule
+
+	// WorkloadKinds are the workload custom resources that the pod checks are run on, in addition to the
+	// DefaultWorkloadKinds. A kind with the same group and kind as a default replaces the default.
+	WorkloadKinds []WorkloadKind
+
+	// IgnoredKinds are the kinds of objects that are not scored
+	IgnoredKinds []string
+
+	// OnlyKinds are the kinds of objects that are scored. If empty, objects of all kinds are scored.
+	OnlyKinds []string
+
+	// Namespace is the namespace of the objects that are parsed. If empty, objects in all namespaces are parsed.
+	// Objects without a namespace are always parsed.
+	Namespace string
+
+	// Selector selects the objects that are parsed by their labels. If nil, all objects are parsed.
+	Selector labels.Selector
+
+	// UnknownKinds is how objects of unknown kinds, that no checks are run on, are handled. One of UnknownKindsWarn,
+	// UnknownKindsSkip or UnknownKindsFail. If empty, UnknownKindsWarn is used.
+	UnknownKinds string
+
+	// Parallelism is the number of checks that are run concurrently. If zero, one check per CPU is run at a time.
+	Parallelism int
+
+	// Plugins are the loaded plugins, the checks of all plugins are run in addition to the built-in checks
+	Plugins []*plugins.Plugin
 }
 
+const (
+	// UnknownKindsWarn logs a warning, and adds the objects to the scorecard with a skipped check
+	UnknownKindsWarn = "warn"
+
+	// UnknownKindsSkip silently ignores the objects
+	UnknownKindsSkip = "skip"
+
+	// UnknownKindsFail stops scoring with an error
+	UnknownKindsFail = "fail"
+)
+
 type Semver struct {
 	Major int
 	Minor int
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zegl/kube-score/internal/cel"
+	"github.com/zegl/kube-score/internal/glob"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// DefaultFileNames are the names of the configuration files that are read automatically
+// from the current directory, if no configuration file has been given explicitly.
+var DefaultFileNames = []string{".kube-score.yml", ".kube-score.yaml"}
+
+// File is the format of a kube-score configuration file.
+//
+// All command line flags can be set in the file, using the name of the flag as the key.
+// Flags that are set on the command line take precedence over the values in the file.
+type File struct {
+	Flags  map[string]interface{} `yaml:",inline"`
+	Checks map[string]FileCheck   `yaml:"checks"`
+
+	// AllowedImageRegistries are the registries that images are allowed to be pulled from,
+	// used by the container-image-registry check
+	AllowedImageRegistries []string `yaml:"allowedImageRegistries"`
+
+	// CustomChecks are checks that are defined as expressions
+	CustomChecks []CustomCheck `yaml:"customChecks"`
+
+	// Profiles are the names of the profiles of checks to run, used if --profile is not set
+	Profiles []string `yaml:"profiles"`
+
+	// CustomProfiles are profiles that are defined in the configuration file, keyed by name, with the IDs of
+	// their checks
+	CustomProfiles map[string][]string `yaml:"customProfiles"`
+
+	// Ignore are rules that ignore checks on the objects in some paths or namespaces
+	Ignore []IgnoreRule `yaml:"ignore"`
+
+	// WorkloadKinds are the workload custom resources, and the paths of their pod templates, that the pod checks
+	// are run on
+	WorkloadKinds []WorkloadKind `yaml:"workloadKinds"`
+}
+
+// FileCheck configures a single check, identified by its ID
+type FileCheck struct {
+	// Enabled can be used to enable optional checks, or to disable default checks
+	Enabled *bool `yaml:"enabled"`
+
+	// Grade overrides the grade that the check gives when it's failing (critical, high, warning, low or info)
+	Grade string `yaml:"grade"`
+}
+
+// CustomCheck is a check that is defined in the configuration file, as a CEL expression that is
+// evaluated against the object. The check fails if the expression is false.
+type CustomCheck struct {
+	ID string `yaml:"id"`
+
+	// Name is the name of the check, the ID is used if it's not set
+	Name    string `yaml:"name"`
+	Comment string `yaml:"comment"`
+
+	// Kinds are the kinds of objects that the check is run on. If empty, the check is run on all objects.
+	Kinds []string `yaml:"kinds"`
+
+	Expression string `yaml:"expression"`
+
+	// Grade is the grade of the check when it's failing (critical, high, warning, low or info), critical is used if
+	// it's not set
+	Grade string `yaml:"grade"`
+
+	// Message and Description are the summary and description of the comment when the check is failing.
+	// Expressions can be embedded in them as {{ object.metadata.name }}.
+	Message     string `yaml:"message"`
+	Description string `yaml:"description"`
+}
+
+// Validate checks that the custom check has an ID, a valid grade, and that the expressions can be compiled
+func (c CustomCheck) Validate() error {
+	if c.ID == "" {
+		return errors.New("custom check without an id")
+	}
+	if c.Grade != "" {
+		if _, err := scorecard.ParseGrade(c.Grade); err != nil {
+			return fmt.Errorf("custom check %s: %w", c.ID, err)
+		}
+	}
+	if _, err := cel.Compile(c.Expression); err != nil {
+		return fmt.Errorf("custom check %s: invalid expression: %w", c.ID, err)
+	}
+	for _, template := range []string{c.Message, c.Description} {
+		if _, err := cel.CompileTemplate(template); err != nil {
+			return fmt.Errorf("custom check %s: invalid template: %w", c.ID, err)
+		}
+	}
+	return nil
+}
+
+// IgnoreRule ignores checks on the objects in the files matching Paths, and in the namespaces matching Namespaces.
+// If both are set, an object must match both to be ignored.
+type IgnoreRule struct {
+	// Checks are the IDs of the ignored checks. If empty, all checks are ignored.
+	Checks []string `yaml:"checks"`
+
+	// Paths are glob patterns, such as "legacy/**", that are matched against the names of the files of the objects.
+	// Absolute file names are made relative to the working directory before they are matched.
+	Paths []string `yaml:"paths"`
+
+	// Namespaces are glob patterns, such as "sandbox-*", that are matched against the namespaces of the objects
+	Namespaces []string `yaml:"namespaces"`
+}
+
+// Validate checks that the rule is limited to some paths or namespaces
+func (r IgnoreRule) Validate() error {
+	if len(r.Paths) == 0 && len(r.Namespaces) == 0 {
+		return errors.New("ignore rule without paths or namespaces, use ignore-test to ignore a check everywhere")
+	}
+	return nil
+}
+
+// Matches reports whether the rule ignores the check on an object in the file and namespace
+func (r IgnoreRule) Matches(checkID, path, namespace string) bool {
+	if len(r.Checks) > 0 {
+		found := false
+		for _, id := range r.Checks {
+			if id == checkID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(r.Paths) > 0 && (path == "" || !glob.MatchAny(r.Paths, relativePath(path))) {
+		return false
+	}
+	if len(r.Namespaces) > 0 && (namespace == "" || !glob.MatchAny(r.Namespaces, namespace)) {
+		return false
+	}
+	return true
+}
+
+// relativePath makes absolute paths relative to the working directory, paths outside of it are not changed
+func relativePath(name string) string {
+	if !filepath.IsAbs(name) {
+		return name
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return name
+	}
+	rel, err := filepath.Rel(wd, name)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return name
+	}
+	return rel
+}
+
+func ParseFile(r io.Reader) (File, error) {
+	var f File
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return f, err
+	}
+
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("invalid configuration file: %w", err)
+	}
+
+	for _, c := range f.CustomChecks {
+		if err := c.Validate(); err != nil {
+			return f, fmt.Errorf("invalid configuration file: %w", err)
+		}
+	}
+
+	for _, rule := range f.Ignore {
+		if err := rule.Validate(); err != nil {
+			return f, fmt.Errorf("invalid configuration file: %w", err)
+		}
+	}
+
+	for _, w := range f.WorkloadKinds {
+		if err := w.Validate(); err != nil {
+			return f, fmt.Errorf("invalid configuration file: %w", err)
+		}
+	}
+
+	return f, nil
+}
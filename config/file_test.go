@@ -0,0 +1,145 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFile(t *testing.T) {
+	f, err := ParseFile(strings.NewReader(`
+kubernetes-version: v1.22
+exit-one-on-warning: true
+enable-optional-test:
+  - container-seccomp-profile
+allowedImageRegistries:
+  - gcr.io/my-project
+checks:
+  container-image-tag:
+    enabled: false
+  service-type:
+    grade: critical
+`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "v1.22", f.Flags["kubernetes-version"])
+	assert.Equal(t, true, f.Flags["exit-one-on-warning"])
+	assert.Equal(t, []interface{}{"container-seccomp-profile"}, f.Flags["enable-optional-test"])
+	assert.NotContains(t, f.Flags, "checks")
+	assert.NotContains(t, f.Flags, "allowedImageRegistries")
+	assert.Equal(t, []string{"gcr.io/my-project"}, f.AllowedImageRegistries)
+
+	assert.False(t, *f.Checks["container-image-tag"].Enabled)
+	assert.Equal(t, "", f.Checks["container-image-tag"].Grade)
+	assert.Nil(t, f.Checks["service-type"].Enabled)
+	assert.Equal(t, "critical", f.Checks["service-type"].Grade)
+}
+
+func TestParseFileInvalid(t *testing.T) {
+	_, err := ParseFile(strings.NewReader(`checks: [foo]`))
+	assert.NotNil(t, err)
+}
+
+func TestParseFileCustomChecks(t *testing.T) {
+	f, err := ParseFile(strings.NewReader(`
+customChecks:
+  - id: deployment-min-replicas
+    kinds: [Deployment]
+    expression: object.spec.replicas >= 3
+    grade: warning
+    message: "{{ object.metadata.name }} has too few replicas"
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, []CustomCheck{{
+		ID:         "deployment-min-replicas",
+		Kinds:      []string{"Deployment"},
+		Expression: "object.spec.replicas >= 3",
+		Grade:      "warning",
+		Message:    "{{ object.metadata.name }} has too few replicas",
+	}}, f.CustomChecks)
+	assert.NotContains(t, f.Flags, "customChecks")
+
+	for _, invalid := range []string{
+		"customChecks: [{expression: 'true'}]",
+		"customChecks: [{id: foo, expression: 'object.spec.replicas >='}]",
+		"customChecks: [{id: foo, expression: 'true', grade: fatal}]",
+		"customChecks: [{id: foo, expression: 'true', message: '{{ object'}]",
+	} {
+		_, err := ParseFile(strings.NewReader(invalid))
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestParseFileIgnore(t *testing.T) {
+	f, err := ParseFile(strings.NewReader(`
+ignore:
+  - checks: [container-resources]
+    paths: ["legacy/**"]
+    namespaces: ["sandbox-*"]
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, []IgnoreRule{{
+		Checks:     []string{"container-resources"},
+		Paths:      []string{"legacy/**"},
+		Namespaces: []string{"sandbox-*"},
+	}}, f.Ignore)
+	assert.NotContains(t, f.Flags, "ignore")
+
+	_, err = ParseFile(strings.NewReader(`ignore: [{checks: [container-resources]}]`))
+	assert.NotNil(t, err)
+}
+
+func TestParseFileWorkloadKinds(t *testing.T) {
+	f, err := ParseFile(strings.NewReader(`
+workloadKinds:
+  - group: example.com
+    kind: Worker
+    podTemplatePath: spec.pod.template
+  - group: argoproj.io
+    kind: Rollout
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, []WorkloadKind{
+		{Group: "example.com", Kind: "Worker", PodTemplatePath: "spec.pod.template"},
+		{Group: "argoproj.io", Kind: "Rollout"},
+	}, f.WorkloadKinds)
+	assert.NotContains(t, f.Flags, "workloadKinds")
+
+	cnf := Configuration{WorkloadKinds: f.WorkloadKinds}
+	path, ok := cnf.PodTemplatePath("example.com", "Worker")
+	assert.True(t, ok)
+	assert.Equal(t, "spec.pod.template", path)
+	_, ok = cnf.PodTemplatePath("argoproj.io", "Rollout")
+	assert.False(t, ok)
+	path, ok = cnf.PodTemplatePath("apps.kruise.io", "CloneSet")
+	assert.True(t, ok)
+	assert.Equal(t, "spec.template", path)
+
+	_, err = ParseFile(strings.NewReader(`workloadKinds: [{kind: Worker, podTemplatePath: spec..template}]`))
+	assert.NotNil(t, err)
+}
+
+func TestIgnoreRuleMatches(t *testing.T) {
+	rule := IgnoreRule{Checks: []string{"container-resources"}, Paths: []string{"legacy/**"}}
+	assert.True(t, rule.Matches("container-resources", "legacy/app/deployment.yaml", ""))
+	assert.False(t, rule.Matches("container-image-tag", "legacy/app/deployment.yaml", ""))
+	assert.False(t, rule.Matches("container-resources", "app/deployment.yaml", ""))
+	assert.False(t, rule.Matches("container-resources", "", ""))
+
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.True(t, rule.Matches("container-resources", filepath.Join(wd, "legacy", "deployment.yaml"), ""))
+
+	// Rules without checks ignore all checks, and an object must match both the paths and the namespaces
+	rule = IgnoreRule{Paths: []string{"legacy/**"}, Namespaces: []string{"sandbox-*"}}
+	assert.True(t, rule.Matches("container-image-tag", "legacy/deployment.yaml", "sandbox-alice"))
+	assert.False(t, rule.Matches("container-image-tag", "legacy/deployment.yaml", "production"))
+	assert.False(t, rule.Matches("container-image-tag", "deployment.yaml", "sandbox-alice"))
+
+	rule = IgnoreRule{Namespaces: []string{"sandbox-*"}}
+	assert.True(t, rule.Matches("container-image-tag", "deployment.yaml", "sandbox-alice"))
+	assert.False(t, rule.Matches("container-image-tag", "deployment.yaml", ""))
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WorkloadKind maps a workload custom resource to the path of its pod template, so that the pod checks are run on
+// the objects of the kind
+type WorkloadKind struct {
+	// Group is the API group of the kind, such as "argoproj.io"
+	Group string `yaml:"group"`
+	Kind  string `yaml:"kind"`
+
+	// PodTemplatePath is the path of the PodTemplateSpec in the object, as field names separated by dots, such as
+	// "spec.template". If empty, the kind is not treated as a workload, which disables a default mapping.
+	PodTemplatePath string `yaml:"podTemplatePath"`
+}
+
+// DefaultWorkloadKinds are the workload custom resources of popular operators that are supported without
+// configuration
+var DefaultWorkloadKinds = []WorkloadKind{
+	{Group: "argoproj.io", Kind: "Rollout", PodTemplatePath: "spec.template"},
+	{Group: "apps.kruise.io", Kind: "CloneSet", PodTemplatePath: "spec.template"},
+	{Group: "apps.kruise.io", Kind: "StatefulSet", PodTemplatePath: "spec.template"},
+	{Group: "apps.kruise.io", Kind: "DaemonSet", PodTemplatePath: "spec.template"},
+	{Group: "flink.apache.org", Kind: "FlinkDeployment", PodTemplatePath: "spec.podTemplate"},
+	{Group: "sparkoperator.k8s.io", Kind: "SparkApplication", PodTemplatePath: "spec.driver.template"},
+}
+
+// Validate checks that the kind is set, and that the path has no empty field names
+func (w WorkloadKind) Validate() error {
+	if w.Kind == "" {
+		return errors.New("workload kind without a kind")
+	}
+	if w.PodTemplatePath == "" {
+		return nil
+	}
+	for _, field := range strings.Split(w.PodTemplatePath, ".") {
+		if field == "" {
+			return fmt.Errorf("workload kind %s: invalid podTemplatePath %q", w.Kind, w.PodTemplatePath)
+		}
+	}
+	return nil
+}
+
+// PodTemplatePath returns the path of the pod template of the objects of the group and kind, from WorkloadKinds or
+// from DefaultWorkloadKinds. false is returned if the kind is not a workload.
+func (c Configuration) PodTemplatePath(group, kind string) (string, bool) {
+	for _, kinds := range [][]WorkloadKind{c.WorkloadKinds, DefaultWorkloadKinds} {
+		for _, w := range kinds {
+			if w.Group == group && w.Kind == kind {
+				return w.PodTemplatePath, w.PodTemplatePath != ""
+			}
+		}
+	}
+	return "", false
+}
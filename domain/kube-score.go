@@ -5,9 +5,11 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,8 +27,30 @@ type NamedReader interface {
 }
 
 type FileLocation struct {
-	Name string
-	Line int
+	Name   string
+	Line   int
+	Column int
+
+	// Fields are the positions of the fields in the object, keyed by the name of list items (for example the name
+	// of a container) and by mapping keys (for example the key of a label). Fields is empty if the positions in the
+	// original file are not known, for example for objects rendered by Helm.
+	Fields map[string]FilePosition
+}
+
+// FilePosition is a line and column in a file, both 1 indexed
+type FilePosition struct {
+	Line   int
+	Column int
+}
+
+// Lookup returns the location of the field with the name, or the location of the object if the field is not known
+func (f FileLocation) Lookup(field string) FileLocation {
+	res := FileLocation{Name: f.Name, Line: f.Line, Column: f.Column}
+	if pos, ok := f.Fields[field]; ok && field != "" {
+		res.Line = pos.Line
+		res.Column = pos.Column
+	}
+	return res
 }
 
 type BothMeta struct {
@@ -50,13 +74,26 @@ type HpaTargeter interface {
 	GetTypeMeta() metav1.TypeMeta
 	GetObjectMeta() metav1.ObjectMeta
 	HpaTarget() autoscalingv1.CrossVersionObjectReference
+	MinReplicas() *int32
+	MaxReplicas() int32
+	// UtilizationMetrics returns the resource metrics that target a utilization of the requested resources
+	UtilizationMetrics() []HpaUtilizationMetric
 	FileLocationer
 }
 
+// HpaUtilizationMetric is a resource metric that scales on the utilization of the requested resources.
+// Container is empty if the utilization of the entire pod is used.
+type HpaUtilizationMetric struct {
+	Resource  corev1.ResourceName
+	Container string
+}
+
 type Ingress interface {
 	GetTypeMeta() metav1.TypeMeta
 	GetObjectMeta() metav1.ObjectMeta
 	Rules() []networkingv1.IngressRule
+	TLS() []networkingv1.IngressTLS
+	IngressClassName() *string
 	FileLocationer
 }
 
@@ -86,6 +123,59 @@ type Services interface {
 	Services() []Service
 }
 
+// EndpointSlice is an EndpointSlice of any supported version
+type EndpointSlice interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+
+	// ServiceName is the name of the Service that the EndpointSlice belongs to
+	ServiceName() string
+	FileLocationer
+}
+
+type EndpointSlices interface {
+	EndpointSlices() []EndpointSlice
+}
+
+// Object is any parsed object, including objects of kinds that have no checks of their own
+type Object interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+
+	// Unstructured is the object as decoded from JSON, with nil, bool, float64, string, []interface{} and
+	// map[string]interface{} values
+	Unstructured() map[string]interface{}
+	FileLocationer
+}
+
+type Objects interface {
+	Objects() []Object
+}
+
+// UnknownObjects are the objects of kinds that kube-score does not know, such as custom resources. They are only
+// checked by the checks that are run on all objects, such as custom checks and plugins.
+type UnknownObjects interface {
+	UnknownObjects() []Object
+}
+
+type ServiceAccount interface {
+	ServiceAccount() corev1.ServiceAccount
+	FileLocationer
+}
+
+type ServiceAccounts interface {
+	ServiceAccounts() []ServiceAccount
+}
+
+type PersistentVolumeClaim interface {
+	PersistentVolumeClaim() corev1.PersistentVolumeClaim
+	FileLocationer
+}
+
+type PersistentVolumeClaims interface {
+	PersistentVolumeClaims() []PersistentVolumeClaim
+}
+
 type StatefulSet interface {
 	StatefulSet() appsv1.StatefulSet
 	FileLocationer
@@ -104,6 +194,15 @@ type Deployments interface {
 	Deployments() []Deployment
 }
 
+type Job interface {
+	Job() batchv1.Job
+	FileLocationer
+}
+
+type Jobs interface {
+	Jobs() []Job
+}
+
 type NetworkPolicy interface {
 	NetworkPolicy() networkingv1.NetworkPolicy
 	FileLocationer
@@ -121,6 +220,11 @@ type CronJob interface {
 	GetTypeMeta() metav1.TypeMeta
 	GetObjectMeta() metav1.ObjectMeta
 	StartingDeadlineSeconds() *int64
+	ConcurrencyPolicy() batchv1.ConcurrencyPolicy
+	SuccessfulJobsHistoryLimit() *int32
+	FailedJobsHistoryLimit() *int32
+	// TimeZone returns nil if timeZone is not set, or if it's not supported by the API version
+	TimeZone() *string
 	FileLocationer
 }
 
@@ -141,6 +245,83 @@ type PodDisruptionBudgets interface {
 	PodDisruptionBudgets() []PodDisruptionBudget
 }
 
+// Role is either a Role or a ClusterRole
+type Role interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	Rules() []rbacv1.PolicyRule
+	FileLocationer
+}
+
+type Roles interface {
+	Roles() []Role
+}
+
+// RoleBinding is either a RoleBinding or a ClusterRoleBinding
+type RoleBinding interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	RoleRef() rbacv1.RoleRef
+	Subjects() []rbacv1.Subject
+	FileLocationer
+}
+
+type RoleBindings interface {
+	RoleBindings() []RoleBinding
+}
+
+// Gateway is a Gateway API (gateway.networking.k8s.io) Gateway
+type Gateway interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	Listeners() []GatewayListener
+	FileLocationer
+}
+
+// GatewayListener is a listener of a Gateway
+type GatewayListener struct {
+	Name string
+
+	// Hostname is empty if the listener accepts all hostnames
+	Hostname string
+	Port     int32
+	Protocol string
+
+	// TLSMode is Terminate or Passthrough, or empty if the listener has no TLS configuration
+	TLSMode         string
+	CertificateRefs int
+}
+
+type Gateways interface {
+	Gateways() []Gateway
+}
+
+// HTTPRoute is a Gateway API (gateway.networking.k8s.io) HTTPRoute
+type HTTPRoute interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	Hostnames() []string
+	ParentRefs() []GatewayObjectReference
+	BackendRefs() []GatewayObjectReference
+	FileLocationer
+}
+
+// GatewayObjectReference is a reference from a route to a parent or a backend.
+// Group, Kind and Namespace are set to their defaults if they are not set in the route.
+type GatewayObjectReference struct {
+	Group     string
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Port is nil if the reference does not select a port
+	Port *int32
+}
+
+type HTTPRoutes interface {
+	HTTPRoutes() []HTTPRoute
+}
+
 type HorizontalPodAutoscalers interface {
 	HorizontalPodAutoscalers() []HpaTargeter
 }
@@ -150,11 +331,21 @@ type AllTypes interface {
 	Pods
 	PodSpeccers
 	Services
+	EndpointSlices
+	ServiceAccounts
+	PersistentVolumeClaims
 	StatefulSets
 	Deployments
+	Jobs
 	NetworkPolicies
 	Ingresses
 	CronJobs
 	PodDisruptionBudgets
 	HorizontalPodAutoscalers
+	Roles
+	RoleBindings
+	Gateways
+	HTTPRoutes
+	Objects
+	UnknownObjects
 }
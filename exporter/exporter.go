@@ -0,0 +1,130 @@
+// Package exporter exposes the results of scoring the objects in a cluster as Prometheus metrics.
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Exporter holds the result of the latest scoring, and serves it in the Prometheus text format
+type Exporter struct {
+	mu sync.Mutex
+
+	grades []objectGrade
+
+	success   bool
+	timestamp time.Time
+	duration  time.Duration
+}
+
+type objectGrade struct {
+	namespace string
+	kind      string
+	name      string
+	check     string
+	grade     scorecard.Grade
+}
+
+func New() *Exporter {
+	return &Exporter{}
+}
+
+// Update replaces the metrics with the result of a scoring that took duration
+func (e *Exporter) Update(card *scorecard.Scorecard, now time.Time, duration time.Duration) {
+	var grades []objectGrade
+	for _, o := range *card {
+		for _, c := range o.Checks {
+			if c.Skipped {
+				continue
+			}
+			grades = append(grades, objectGrade{
+				namespace: o.ObjectMeta.Namespace,
+				kind:      o.TypeMeta.Kind,
+				name:      o.ObjectMeta.Name,
+				check:     c.Check.ID,
+				grade:     c.Grade,
+			})
+		}
+	}
+
+	sort.Slice(grades, func(i, j int) bool {
+		a, b := grades[i], grades[j]
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		return a.check < b.check
+	})
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.grades = grades
+	e.success = true
+	e.timestamp = now
+	e.duration = duration
+}
+
+// Failed records that the latest scoring failed. The grades from the last successful scoring are kept.
+func (e *Exporter) Failed(now time.Time, duration time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.success = false
+	e.timestamp = now
+	e.duration = duration
+}
+
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	e.Write(w)
+}
+
+// Write writes the metrics in the Prometheus text format
+func (e *Exporter) Write(w io.Writer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP kube_score_object_grade The grade of a check on an object: 1 is critical, 3 is high, 5 is warning, 7 is almost ok, 9 is info, and 10 is ok.")
+	fmt.Fprintln(w, "# TYPE kube_score_object_grade gauge")
+	for _, g := range e.grades {
+		fmt.Fprintf(w, "kube_score_object_grade{namespace=\"%s\",kind=\"%s\",name=\"%s\",check=\"%s\"} %d\n",
+			escapeLabel(g.namespace), escapeLabel(g.kind), escapeLabel(g.name), escapeLabel(g.check), g.grade)
+	}
+
+	success := 0
+	if e.success {
+		success = 1
+	}
+	fmt.Fprintln(w, "# HELP kube_score_last_run_success Whether the last scoring of the cluster succeeded.")
+	fmt.Fprintln(w, "# TYPE kube_score_last_run_success gauge")
+	fmt.Fprintf(w, "kube_score_last_run_success %d\n", success)
+
+	var timestamp float64
+	if !e.timestamp.IsZero() {
+		timestamp = float64(e.timestamp.UnixNano()) / 1e9
+	}
+	fmt.Fprintln(w, "# HELP kube_score_last_run_timestamp_seconds The time of the last scoring of the cluster.")
+	fmt.Fprintln(w, "# TYPE kube_score_last_run_timestamp_seconds gauge")
+	fmt.Fprintf(w, "kube_score_last_run_timestamp_seconds %v\n", timestamp)
+
+	fmt.Fprintln(w, "# HELP kube_score_last_run_duration_seconds The time it took to fetch and score the objects in the cluster.")
+	fmt.Fprintln(w, "# TYPE kube_score_last_run_duration_seconds gauge")
+	fmt.Fprintf(w, "kube_score_last_run_duration_seconds %v\n", e.duration.Seconds())
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
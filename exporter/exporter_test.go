@@ -0,0 +1,62 @@
+package exporter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestWrite(t *testing.T) {
+	card := scorecard.Scorecard{
+		"b": &scorecard.ScoredObject{
+			TypeMeta:   metav1.TypeMeta{Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+			Checks: []scorecard.TestScore{
+				{Check: ks.Check{ID: "service-type"}, Grade: scorecard.GradeWarning},
+			},
+		},
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: `foo"bar`, Namespace: "default"},
+			Checks: []scorecard.TestScore{
+				{Check: ks.Check{ID: "deployment-has-host-podantiaffinity"}, Grade: scorecard.GradeAllOK},
+				{Check: ks.Check{ID: "container-resources"}, Grade: scorecard.GradeCritical},
+				{Check: ks.Check{ID: "container-seccomp-profile"}, Skipped: true},
+			},
+		},
+	}
+
+	e := New()
+	e.Update(&card, time.Unix(1600000000, 0), 1500*time.Millisecond)
+
+	var buf bytes.Buffer
+	e.Write(&buf)
+	assert.Equal(t, `# HELP kube_score_object_grade The grade of a check on an object: 1 is critical, 3 is high, 5 is warning, 7 is almost ok, 9 is info, and 10 is ok.
+# TYPE kube_score_object_grade gauge
+kube_score_object_grade{namespace="default",kind="Deployment",name="foo\"bar",check="container-resources"} 1
+kube_score_object_grade{namespace="default",kind="Deployment",name="foo\"bar",check="deployment-has-host-podantiaffinity"} 10
+kube_score_object_grade{namespace="default",kind="Service",name="foo",check="service-type"} 5
+# HELP kube_score_last_run_success Whether the last scoring of the cluster succeeded.
+# TYPE kube_score_last_run_success gauge
+kube_score_last_run_success 1
+# HELP kube_score_last_run_timestamp_seconds The time of the last scoring of the cluster.
+# TYPE kube_score_last_run_timestamp_seconds gauge
+kube_score_last_run_timestamp_seconds 1.6e+09
+# HELP kube_score_last_run_duration_seconds The time it took to fetch and score the objects in the cluster.
+# TYPE kube_score_last_run_duration_seconds gauge
+kube_score_last_run_duration_seconds 1.5
+`, buf.String())
+
+	// The grades are kept when the scoring fails
+	e.Failed(time.Unix(1600000060, 0), time.Second)
+	buf.Reset()
+	e.Write(&buf)
+	assert.Contains(t, buf.String(), "kube_score_last_run_success 0\n")
+	assert.Contains(t, buf.String(), `check="service-type"} 5`)
+}
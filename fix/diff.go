@@ -0,0 +1,91 @@
+package fix
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines that are shown around the changes
+const diffContext = 3
+
+// Diff returns the changes as a unified diff, or an empty string if nothing has been changed
+func (r *Result) Diff(name string) string {
+	if len(r.edits) == 0 {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(r.Original), "\n"), "\n")
+
+	var changed []int
+	for line := range r.edits {
+		changed = append(changed, line)
+	}
+	sort.Ints(changed)
+
+	w := bytes.NewBufferString("")
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", name, name)
+
+	// delta is the number of lines that have been added before the current hunk
+	delta := 0
+
+	for i := 0; i < len(changed); {
+		// Group the changes that are close enough to share their context
+		j := i
+		for j+1 < len(changed) && changed[j+1]-changed[j] <= 2*diffContext {
+			j++
+		}
+
+		start := max(1, changed[i]-diffContext)
+		end := min(len(lines), changed[j]+diffContext)
+
+		var body []string
+		added := 0
+		for line := start; line <= end; line++ {
+			edit, ok := r.edits[line]
+			if !ok {
+				body = append(body, " "+lines[line-1])
+				continue
+			}
+			// The original line is kept as it is, unless it's the first field of a list item
+			if edit[len(edit)-1] == lines[line-1] {
+				for _, e := range edit[:len(edit)-1] {
+					body = append(body, "+"+e)
+				}
+				body = append(body, " "+lines[line-1])
+			} else {
+				body = append(body, "-"+lines[line-1])
+				for _, e := range edit {
+					body = append(body, "+"+e)
+				}
+			}
+			added += len(edit) - 1
+		}
+
+		oldLen := end - start + 1
+		fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", start, oldLen, start+delta, oldLen+added)
+		for _, b := range body {
+			fmt.Fprintln(w, b)
+		}
+
+		delta += added
+		i = j + 1
+	}
+
+	return w.String()
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
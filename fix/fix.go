@@ -0,0 +1,317 @@
+// Package fix applies safe automatic remediations to Kubernetes manifests.
+//
+// The manifests are edited as text, by inserting the missing fields before the first field of the mapping that they
+// belong to. Comments and the formatting of the rest of the file are kept as they are. Fields that are already set
+// are never changed, and mappings in flow style ({...}) are not edited.
+package fix
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const ignoredChecksAnnotation = "kube-score/ignore"
+
+// Change is a remediation that has been applied to the file
+type Change struct {
+	// Line is the line in the original file where the change was made
+	Line int
+
+	// Check is the ID of the check that the change fixes
+	Check string
+
+	Description string
+}
+
+// Result is the outcome of fixing a file
+type Result struct {
+	Original []byte
+	Fixed    []byte
+	Changes  []Change
+
+	// edits are the new contents of the changed lines in the original file, keyed by the 1 indexed line number
+	edits map[int][]string
+}
+
+// insertion is a snippet of YAML that is inserted in front of the field at the line and column
+type insertion struct {
+	line   int
+	column int
+	text   []string
+}
+
+// Fix applies the remediations to all YAML documents in the file. Checks in ignoredChecks, and checks that are
+// ignored with the kube-score/ignore annotation of an object, are not fixed.
+func Fix(contents []byte, ignoredChecks map[string]struct{}) (*Result, error) {
+	// Convert to unix style newlines
+	contents = bytes.Replace(contents, []byte("\r\n"), []byte("\n"), -1)
+
+	res := &Result{Original: contents, edits: make(map[int][]string)}
+
+	var insertions []insertion
+
+	for _, doc := range splitDocuments(contents) {
+		var node yaml.Node
+		if err := yaml.Unmarshal(doc.contents, &node); err != nil {
+			return nil, fmt.Errorf("line %d: %w", doc.line, err)
+		}
+		if len(node.Content) == 0 {
+			continue
+		}
+
+		f := &fixer{lineOffset: doc.line - 1, ignoredChecks: ignoredChecks}
+		f.fixObject(node.Content[0])
+		insertions = append(insertions, f.insertions...)
+		res.Changes = append(res.Changes, f.changes...)
+	}
+
+	lines := strings.Split(string(contents), "\n")
+	res.edits = applyInsertions(lines, insertions)
+
+	var fixed []string
+	for i, line := range lines {
+		if edit, ok := res.edits[i+1]; ok {
+			fixed = append(fixed, edit...)
+			continue
+		}
+		fixed = append(fixed, line)
+	}
+	res.Fixed = []byte(strings.Join(fixed, "\n"))
+
+	sort.SliceStable(res.Changes, func(i, j int) bool {
+		return res.Changes[i].Line < res.Changes[j].Line
+	})
+
+	return res, nil
+}
+
+type document struct {
+	// line is the line in the file where the document starts
+	line     int
+	contents []byte
+}
+
+// splitDocuments splits the file on "---" separators, in the same way as the parser
+func splitDocuments(contents []byte) []document {
+	docs := []document{{line: 1}}
+	for i, l := range bytes.Split(contents, []byte("\n")) {
+		if string(bytes.TrimRight(l, " ")) == "---" {
+			docs = append(docs, document{line: i + 2})
+			continue
+		}
+		d := &docs[len(docs)-1]
+		d.contents = append(append(d.contents, l...), '\n')
+	}
+	return docs
+}
+
+// applyInsertions returns the new contents of all lines with insertions
+func applyInsertions(lines []string, insertions []insertion) map[int][]string {
+	// Apply the insertions from the end of the line, so that the columns of the earlier insertions stay valid
+	sort.SliceStable(insertions, func(i, j int) bool {
+		if insertions[i].line != insertions[j].line {
+			return insertions[i].line < insertions[j].line
+		}
+		return insertions[i].column > insertions[j].column
+	})
+
+	// Insertions in front of the same field are merged, to keep them in the order that they were made
+	var merged []insertion
+	for _, ins := range insertions {
+		if n := len(merged); n > 0 && merged[n-1].line == ins.line && merged[n-1].column == ins.column {
+			merged[n-1].text = append(merged[n-1].text, ins.text...)
+			continue
+		}
+		merged = append(merged, insertion{line: ins.line, column: ins.column, text: append([]string(nil), ins.text...)})
+	}
+
+	edits := make(map[int][]string)
+	for _, ins := range merged {
+		current, ok := edits[ins.line]
+		if !ok {
+			current = []string{lines[ins.line-1]}
+		}
+
+		// Insertions are always made on the first of the edited lines, as they're applied from the end of the line
+		first := current[0]
+		col := ins.column - 1
+		indent := strings.Repeat(" ", col)
+
+		var newLines []string
+		for i, t := range ins.text {
+			if i == 0 {
+				newLines = append(newLines, first[:col]+t)
+				continue
+			}
+			newLines = append(newLines, indent+t)
+		}
+		newLines = append(newLines, indent+first[col:])
+
+		edits[ins.line] = append(newLines, current[1:]...)
+	}
+	return edits
+}
+
+type fixer struct {
+	lineOffset    int
+	ignoredChecks map[string]struct{}
+
+	insertions []insertion
+	changes    []Change
+}
+
+// podSpecPath returns the path to the pod spec in objects of the kind
+func podSpecPath(kind string) ([]string, bool) {
+	switch kind {
+	case "Pod":
+		return []string{"spec"}, true
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		return []string{"spec", "template", "spec"}, true
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}, true
+	default:
+		return nil, false
+	}
+}
+
+func (f *fixer) fixObject(obj *yaml.Node) {
+	kind := scalarValue(mappingValue(obj, "kind"))
+
+	if kind == "List" {
+		if items := mappingValue(obj, "items"); items != nil && items.Kind == yaml.SequenceNode {
+			for _, item := range items.Content {
+				f.fixObject(item)
+			}
+		}
+		return
+	}
+
+	path, ok := podSpecPath(kind)
+	if !ok {
+		return
+	}
+
+	ignored := make(map[string]struct{})
+	for id := range f.ignoredChecks {
+		ignored[id] = struct{}{}
+	}
+	annotations := mappingValue(mappingValue(obj, "metadata"), "annotations")
+	for _, id := range strings.Split(scalarValue(mappingValue(annotations, ignoredChecksAnnotation)), ",") {
+		ignored[strings.TrimSpace(id)] = struct{}{}
+	}
+	isIgnored := func(check string) bool {
+		_, ok := ignored[check]
+		return ok
+	}
+
+	spec := obj
+	for _, key := range path {
+		spec = mappingValue(spec, key)
+	}
+	if spec == nil || spec.Kind != yaml.MappingNode {
+		return
+	}
+
+	if !isIgnored("pod-automount-service-account-token") && mappingValue(spec, "automountServiceAccountToken") == nil {
+		f.insert(spec, "pod-automount-service-account-token", "Set automountServiceAccountToken to false",
+			"automountServiceAccountToken: false")
+	}
+
+	// Probes are not used by Jobs
+	needsProbes := kind != "Job" && kind != "CronJob"
+
+	for _, containersKey := range []string{"initContainers", "containers"} {
+		containers := mappingValue(spec, containersKey)
+		if containers == nil || containers.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		for _, container := range containers.Content {
+			if container.Kind != yaml.MappingNode {
+				continue
+			}
+			name := scalarValue(mappingValue(container, "name"))
+
+			if !isIgnored("container-image-pull-policy") && mappingValue(container, "imagePullPolicy") == nil {
+				// Images that are pinned to a digest can't change, and don't need to be pulled again
+				policy := "Always"
+				if strings.Contains(scalarValue(mappingValue(container, "image")), "@") {
+					policy = "IfNotPresent"
+				}
+				f.insert(container, "container-image-pull-policy", fmt.Sprintf("Set imagePullPolicy to %s in the container %s", policy, name),
+					"imagePullPolicy: "+policy)
+			}
+
+			if !isIgnored("container-security-context-privilege-escalation") {
+				securityContext := mappingValue(container, "securityContext")
+				description := fmt.Sprintf("Set securityContext.allowPrivilegeEscalation to false in the container %s", name)
+				if securityContext == nil {
+					f.insert(container, "container-security-context-privilege-escalation", description,
+						"securityContext:",
+						"  allowPrivilegeEscalation: false")
+				} else if securityContext.Kind == yaml.MappingNode && mappingValue(securityContext, "allowPrivilegeEscalation") == nil {
+					f.insert(securityContext, "container-security-context-privilege-escalation", description,
+						"allowPrivilegeEscalation: false")
+				}
+			}
+
+			if needsProbes && containersKey == "containers" && !isIgnored("pod-probes") && mappingValue(container, "readinessProbe") == nil {
+				if port := firstContainerPort(container); port != "" {
+					f.insert(container, "pod-probes", fmt.Sprintf("Add a readinessProbe to the container %s", name),
+						"readinessProbe:",
+						"  tcpSocket:",
+						"    port: "+port)
+				}
+			}
+		}
+	}
+}
+
+// insert adds the lines as the first fields of the mapping
+func (f *fixer) insert(mapping *yaml.Node, check, description string, lines ...string) {
+	if mapping.Kind != yaml.MappingNode || mapping.Style&yaml.FlowStyle != 0 || len(mapping.Content) == 0 {
+		return
+	}
+
+	first := mapping.Content[0]
+	line := f.lineOffset + first.Line
+
+	f.insertions = append(f.insertions, insertion{line: line, column: first.Column, text: lines})
+	f.changes = append(f.changes, Change{Line: line, Check: check, Description: description})
+}
+
+// firstContainerPort returns the name of the first port of the container, or its number if it has no name
+func firstContainerPort(container *yaml.Node) string {
+	ports := mappingValue(container, "ports")
+	if ports == nil || ports.Kind != yaml.SequenceNode || len(ports.Content) == 0 {
+		return ""
+	}
+	if name := scalarValue(mappingValue(ports.Content[0], "name")); name != "" {
+		return name
+	}
+	return scalarValue(mappingValue(ports.Content[0], "containerPort"))
+}
+
+// mappingValue returns the value of the key in a mapping node, or nil if it's not set
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func scalarValue(n *yaml.Node) string {
+	if n == nil || n.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return n.Value
+}
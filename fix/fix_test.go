@@ -0,0 +1,157 @@
+package fix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const deployment = `# A comment
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: foo:1.0
+        ports:
+        - name: http
+          containerPort: 8080
+      - name: sidecar
+        image: bar@sha256:abc
+        securityContext:
+          runAsNonRoot: true # keep this
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+`
+
+func TestFix(t *testing.T) {
+	t.Parallel()
+	res, err := Fix([]byte(deployment), nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, `# A comment
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+spec:
+  template:
+    spec:
+      automountServiceAccountToken: false
+      containers:
+      - imagePullPolicy: Always
+        securityContext:
+          allowPrivilegeEscalation: false
+        readinessProbe:
+          tcpSocket:
+            port: http
+        name: app
+        image: foo:1.0
+        ports:
+        - name: http
+          containerPort: 8080
+      - imagePullPolicy: IfNotPresent
+        name: sidecar
+        image: bar@sha256:abc
+        securityContext:
+          allowPrivilegeEscalation: false
+          runAsNonRoot: true # keep this
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+`, string(res.Fixed))
+
+	assert.Equal(t, []Change{
+		{Line: 9, Check: "pod-automount-service-account-token", Description: "Set automountServiceAccountToken to false"},
+		{Line: 10, Check: "container-image-pull-policy", Description: "Set imagePullPolicy to Always in the container app"},
+		{Line: 10, Check: "container-security-context-privilege-escalation", Description: "Set securityContext.allowPrivilegeEscalation to false in the container app"},
+		{Line: 10, Check: "pod-probes", Description: "Add a readinessProbe to the container app"},
+		{Line: 15, Check: "container-image-pull-policy", Description: "Set imagePullPolicy to IfNotPresent in the container sidecar"},
+		{Line: 18, Check: "container-security-context-privilege-escalation", Description: "Set securityContext.allowPrivilegeEscalation to false in the container sidecar"},
+	}, res.Changes)
+}
+
+func TestFixIgnored(t *testing.T) {
+	t.Parallel()
+	doc := `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: foo
+  annotations:
+    kube-score/ignore: container-image-pull-policy
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: foo:1.0
+        ports:
+        - containerPort: 8080
+`
+	res, err := Fix([]byte(doc), map[string]struct{}{"pod-automount-service-account-token": {}})
+	assert.Nil(t, err)
+
+	// Jobs don't get probes, and the ignored checks are not fixed
+	assert.Equal(t, []Change{
+		{Line: 11, Check: "container-security-context-privilege-escalation", Description: "Set securityContext.allowPrivilegeEscalation to false in the container app"},
+	}, res.Changes)
+}
+
+func TestFixAlreadyFixed(t *testing.T) {
+	t.Parallel()
+	doc := `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "foo"}, "spec": {"containers": [{"name": "app"}]}}`
+	res, err := Fix([]byte(doc), nil)
+	assert.Nil(t, err)
+
+	// Flow style mappings are not edited
+	assert.Len(t, res.Changes, 0)
+	assert.Equal(t, doc, string(res.Fixed))
+	assert.Equal(t, "", res.Diff("pod.json"))
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	res, err := Fix([]byte(deployment), nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, `--- deployment.yaml
++++ deployment.yaml
+@@ -6,16 +6,25 @@
+ spec:
+   template:
+     spec:
++      automountServiceAccountToken: false
+       containers:
+-      - name: app
++      - imagePullPolicy: Always
++        securityContext:
++          allowPrivilegeEscalation: false
++        readinessProbe:
++          tcpSocket:
++            port: http
++        name: app
+         image: foo:1.0
+         ports:
+         - name: http
+           containerPort: 8080
+-      - name: sidecar
++      - imagePullPolicy: IfNotPresent
++        name: sidecar
+         image: bar@sha256:abc
+         securityContext:
++          allowPrivilegeEscalation: false
+           runAsNonRoot: true # keep this
+ ---
+ apiVersion: v1
+ kind: Service
+`, res.Diff("deployment.yaml"))
+}
@@ -0,0 +1,135 @@
+// Package cel compiles and evaluates the Common Expression Language (https://github.com/google/cel-spec)
+// expressions of the custom checks in the configuration file, with github.com/google/cel-go.
+//
+// The expressions can use the variable object, that is the scored object with the same fields as in the manifest.
+// Whole numbers in the object are ints, and other numbers are doubles. Ints and doubles can be compared to each
+// other, but must be converted with int() or double() to be used in the same arithmetic operation.
+package cel
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var (
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+)
+
+// environment returns the environment that all expressions are compiled in
+func environment() (*cel.Env, error) {
+	envOnce.Do(func() {
+		env, envErr = cel.NewEnv(
+			cel.Variable("object", cel.DynType),
+			cel.CrossTypeNumericComparisons(true),
+		)
+	})
+	return env, envErr
+}
+
+// Program is a compiled expression
+type Program struct {
+	source  string
+	program cel.Program
+}
+
+// Compile parses and checks the expression
+func Compile(source string) (*Program, error) {
+	env, err := environment()
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(source)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	return &Program{source: source, program: program}, nil
+}
+
+// String returns the source of the expression
+func (p *Program) String() string {
+	return p.source
+}
+
+// Eval evaluates the expression. The values of the variables must be JSON values, as decoded by encoding/json:
+// nil, bool, float64, string, []interface{} and map[string]interface{}. The result is returned as a JSON value.
+func (p *Program) Eval(vars map[string]interface{}) (interface{}, error) {
+	activation := make(map[string]interface{}, len(vars))
+	for name, v := range vars {
+		activation[name] = wholeNumbersToInts(v)
+	}
+
+	out, _, err := p.program.Eval(activation)
+	if err != nil {
+		return nil, err
+	}
+	v, err := out.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+	if err != nil {
+		return nil, fmt.Errorf("the result of type %s can not be used: %w", out.Type().TypeName(), err)
+	}
+	return v.(*structpb.Value).AsInterface(), nil
+}
+
+// EvalBool evaluates the expression, and returns an error if the result is not a bool
+func (p *Program) EvalBool(vars map[string]interface{}) (bool, error) {
+	v, err := p.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("the expression evaluated to %s, and not to a bool", Format(v))
+	}
+	return b, nil
+}
+
+// wholeNumbersToInts returns a copy of the JSON value, where the whole numbers are int64, so that fields such as
+// spec.replicas can be used in arithmetic with int literals
+func wholeNumbersToInts(v interface{}) interface{} {
+	switch val := v.(type) {
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1<<53 {
+			return int64(val)
+		}
+		return val
+	case []interface{}:
+		res := make([]interface{}, len(val))
+		for i, item := range val {
+			res[i] = wholeNumbersToInts(item)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			res[k] = wholeNumbersToInts(item)
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+// Format formats the value for use in messages. Whole numbers are formatted without decimals.
+func Format(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
@@ -0,0 +1,148 @@
+package cel
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const deployment = `{
+	"kind": "Deployment",
+	"metadata": {"name": "foo", "labels": {"app": "foo", "team": "bar"}},
+	"spec": {
+		"replicas": 3,
+		"ratio": 0.5,
+		"template": {"spec": {"containers": [
+			{"name": "app", "image": "gcr.io/foo/app:1.0", "resources": {"limits": {"memory": "1Gi"}}},
+			{"name": "sidecar", "image": "envoy:latest"}
+		]}}
+	}
+}`
+
+func vars(t *testing.T) map[string]interface{} {
+	var object map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(deployment), &object))
+	return map[string]interface{}{"object": object}
+}
+
+func TestEval(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]interface{}{
+		`object.spec.replicas >= 3`:                                                         true,
+		`object.spec.replicas == 3.0`:                                                       true,
+		`object.spec.replicas * 2 + 1`:                                                      float64(7),
+		`object.spec.ratio * 2.0`:                                                           float64(1),
+		`object.spec.ratio < 1`:                                                             true,
+		`object.metadata.labels["app"] == "foo"`:                                            true,
+		`"team" in object.metadata.labels`:                                                  true,
+		`"owner" in object.metadata.labels`:                                                 false,
+		`has(object.spec.strategy)`:                                                         false,
+		`has(object.spec.replicas) && object.spec.replicas > 1`:                             true,
+		`!has(object.spec.strategy) || object.spec.strategy.foo == 1`:                       true,
+		`object.spec.template.spec.containers.all(c, has(c.resources))`:                     false,
+		`object.spec.template.spec.containers.exists(c, c.image.endsWith(":latest"))`:       true,
+		`object.spec.template.spec.containers.exists_one(c, c.name.startsWith("s"))`:        true,
+		`object.spec.template.spec.containers.map(c, c.name)`:                               []interface{}{"app", "sidecar"},
+		`object.spec.template.spec.containers.filter(c, c.image.contains("gcr.io")).size()`: float64(1),
+		`object.metadata.labels.all(k, k.matches("^[a-z]+$"))`:                              true,
+		`size(object.metadata.name) == 3 ? "short" : "long"`:                                "short",
+		`object.spec.template.spec.containers[1].name`:                                      "sidecar",
+		`[1, 2] + [3]`:                        []interface{}{float64(1), float64(2), float64(3)},
+		`{"a": 1}["a"] == 1`:                  true,
+		`double(int("42")) + double("0.5")`:   42.5,
+		`string(object.spec.replicas)`:        "3",
+		`-object.spec.replicas < 0 && !false`: true,
+		`'single' + "double"`:                 "singledouble",
+		`null == null`:                        true,
+		`7 % 4`:                               float64(3),
+	}
+
+	for expr, expected := range tests {
+		p, err := Compile(expr)
+		if !assert.Nil(t, err, expr) {
+			continue
+		}
+		v, err := p.Eval(vars(t))
+		assert.Nil(t, err, expr)
+		assert.Equal(t, expected, v, expr)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{
+		`object.spec.strategy.type == "RollingUpdate"`,
+		`object.spec.replicas + "a"`,
+		`object.spec.ratio * 2`,
+		`object.spec.template.spec.containers[5]`,
+		`1 / 0`,
+		`object.spec.replicas && true`,
+	} {
+		p, err := Compile(expr)
+		if !assert.Nil(t, err, expr) {
+			continue
+		}
+		_, err = p.Eval(vars(t))
+		assert.NotNil(t, err, expr)
+	}
+}
+
+func TestEvalBool(t *testing.T) {
+	t.Parallel()
+
+	p, err := Compile(`object.spec.replicas`)
+	assert.Nil(t, err)
+	_, err = p.EvalBool(vars(t))
+	assert.NotNil(t, err)
+
+	// Errors are ignored if the other side of the logical operator decides the result
+	p, err = Compile(`object.spec.strategy.type == "Recreate" || true`)
+	assert.Nil(t, err)
+	b, err := p.EvalBool(vars(t))
+	assert.Nil(t, err)
+	assert.True(t, b)
+}
+
+func TestCompileErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{
+		`object.spec.replicas >=`,
+		`"unterminated`,
+		`has(object)`,
+		`object.spec.containers.all(1, true)`,
+		`(1 + 2`,
+		`1 2`,
+		`object # comment`,
+		`foo`,
+		`object.metadata.name.size(1)`,
+	} {
+		_, err := Compile(expr)
+		assert.NotNil(t, err, expr)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "3", Format(float64(3)))
+	assert.Equal(t, "1.5", Format(1.5))
+	assert.Equal(t, "foo", Format("foo"))
+	assert.Equal(t, "true", Format(true))
+	assert.Equal(t, "null", Format(nil))
+}
+
+func TestTemplate(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := CompileTemplate("{{ object.kind }} {{object.metadata.name}} has {{ object.spec.replicas }} replicas")
+	assert.Nil(t, err)
+	s, err := tmpl.Execute(vars(t))
+	assert.Nil(t, err)
+	assert.Equal(t, "Deployment foo has 3 replicas", s)
+
+	_, err = CompileTemplate("{{ object.kind")
+	assert.NotNil(t, err)
+}
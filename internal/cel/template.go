@@ -0,0 +1,53 @@
+package cel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Template is a text with embedded expressions, on the format "Deployment {{ object.metadata.name }}"
+type Template struct {
+	texts    []string
+	programs []*Program
+}
+
+// CompileTemplate parses the text, and compiles all embedded expressions
+func CompileTemplate(text string) (*Template, error) {
+	t := &Template{}
+	for {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			t.texts = append(t.texts, text)
+			return t, nil
+		}
+		end := strings.Index(text[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated expression in %q", text)
+		}
+
+		p, err := Compile(strings.TrimSpace(text[start+2 : start+end]))
+		if err != nil {
+			return nil, err
+		}
+
+		t.texts = append(t.texts, text[:start])
+		t.programs = append(t.programs, p)
+		text = text[start+end+2:]
+	}
+}
+
+// Execute evaluates the embedded expressions, and returns the text with the formatted results
+func (t *Template) Execute(vars map[string]interface{}) (string, error) {
+	var sb strings.Builder
+	for i, text := range t.texts {
+		sb.WriteString(text)
+		if i < len(t.programs) {
+			v, err := t.programs[i].Eval(vars)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(Format(v))
+		}
+	}
+	return sb.String(), nil
+}
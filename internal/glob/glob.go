@@ -0,0 +1,71 @@
+// Package glob implements matching of slash separated paths against glob patterns
+package glob
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Match reports whether name matches the glob pattern.
+//
+// The pattern syntax is the same as for filepath.Match, with the addition of "**" which matches
+// zero or more directories. Patterns without a slash are matched against every path element of
+// name, so that "vendor" matches "vendor/foo.yaml" as well as "a/vendor/foo.yaml".
+func Match(pattern, name string) bool {
+	name = filepath.ToSlash(filepath.Clean(name))
+	pattern = filepath.ToSlash(pattern)
+
+	if !strings.Contains(pattern, "/") {
+		for _, element := range strings.Split(name, "/") {
+			if ok, _ := filepath.Match(pattern, element); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	re, err := regexp.Compile(toRegexp(pattern))
+	if err != nil {
+		return false
+	}
+	return re.MatchString(name)
+}
+
+// MatchAny reports whether name matches any of the patterns
+func MatchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if Match(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func toRegexp(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			i++
+			// "**/" matches zero or more directories
+			if i+1 < len(pattern) && pattern[i+1] == '/' {
+				i++
+				b.WriteString("(.*/)?")
+			} else {
+				b.WriteString(".*")
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	// A pattern matching a directory also matches everything in it
+	b.WriteString("(/.*)?$")
+	return b.String()
+}
@@ -0,0 +1,34 @@
+package glob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"*.yaml", "foo.yaml", true},
+		{"*.yaml", "a/b/foo.yaml", true},
+		{"*.yaml", "a/b/foo.json", false},
+		{"vendor", "vendor/foo.yaml", true},
+		{"vendor", "a/vendor/foo.yaml", true},
+		{"vendor", "a/vendors/foo.yaml", false},
+		{"legacy/**", "legacy/a/b.yaml", true},
+		{"legacy/**", "new/legacy/b.yaml", false},
+		{"**/templates/*.yaml", "charts/app/templates/deployment.yaml", true},
+		{"**/templates/*.yaml", "templates/deployment.yaml", true},
+		{"**/templates/*.yaml", "charts/app/values.yaml", false},
+		{"a/*/c.yaml", "a/b/c.yaml", true},
+		{"a/*/c.yaml", "a/b/b/c.yaml", false},
+		{"a/b", "a/b/c.yaml", true},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, Match(tc.pattern, tc.name), "%s %s", tc.pattern, tc.name)
+	}
+}
@@ -0,0 +1,196 @@
+// Package logger implements leveled, structured logging. Each message is written as a line of key=value pairs, or
+// as a JSON object, with the time, the level, the message, and the attributes of the message.
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log message, messages below the level of the logger are not written
+type Level int
+
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+const timeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// Logger writes log messages of at least its level to a writer
+type Logger struct {
+	mu     sync.Mutex
+	w      io.Writer
+	level  Level
+	format string
+
+	now func() time.Time
+}
+
+// New creates a logger that writes messages of at least level to w, in the "text" or "json" format
+func New(w io.Writer, level Level, format string) (*Logger, error) {
+	if format != FormatText && format != FormatJSON {
+		return nil, fmt.Errorf("unknown log format %q, must be one of: text, json", format)
+	}
+	return &Logger{w: w, level: level, format: format, now: time.Now}, nil
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultLogger = &Logger{w: os.Stderr, level: LevelInfo, format: FormatText, now: time.Now}
+)
+
+// Default returns the logger that is used by the package level functions
+func Default() *Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
+
+// SetDefault replaces the logger that is used by the package level functions
+func SetDefault(l *Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = l
+}
+
+func Debug(msg string, keysAndValues ...interface{}) {
+	Default().Log(LevelDebug, msg, keysAndValues...)
+}
+
+func Info(msg string, keysAndValues ...interface{}) {
+	Default().Log(LevelInfo, msg, keysAndValues...)
+}
+
+func Warn(msg string, keysAndValues ...interface{}) {
+	Default().Log(LevelWarn, msg, keysAndValues...)
+}
+
+func Error(msg string, keysAndValues ...interface{}) {
+	Default().Log(LevelError, msg, keysAndValues...)
+}
+
+// Enabled returns true if messages of the level are written, to avoid preparing the attributes of messages that are
+// not written
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.level
+}
+
+// Log writes the message, with the attributes given as alternating keys and values. A value without a key is
+// written with the key "!BADKEY".
+func (l *Logger) Log(level Level, msg string, keysAndValues ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	keys := []string{"time", "level", "msg"}
+	values := []interface{}{l.now().Format(timeFormat), level.String(), msg}
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			keys = append(keys, "!BADKEY")
+			values = append(values, keysAndValues[i])
+			break
+		}
+		keys = append(keys, fmt.Sprint(keysAndValues[i]))
+		values = append(values, keysAndValues[i+1])
+	}
+
+	var line []byte
+	if l.format == FormatJSON {
+		line = formatJSON(keys, values)
+	} else {
+		line = formatText(keys, values)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.w.Write(line)
+}
+
+func formatText(keys []string, values []interface{}) []byte {
+	var b bytes.Buffer
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		s := valueString(values[i])
+		if s == "" || strings.ContainsAny(s, " \"=\t\n\r\\") {
+			s = strconv.Quote(s)
+		}
+		b.WriteString(s)
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+func formatJSON(keys []string, values []interface{}) []byte {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		b.Write(k)
+		b.WriteByte(':')
+
+		var v []byte
+		var err error
+		switch value := values[i].(type) {
+		case error, fmt.Stringer:
+			v, err = json.Marshal(valueString(value))
+		default:
+			v, err = json.Marshal(value)
+		}
+		if err != nil {
+			v, _ = json.Marshal(valueString(values[i]))
+		}
+		b.Write(v)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func valueString(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case error:
+		return value.Error()
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
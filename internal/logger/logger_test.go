@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLogger(t *testing.T, level Level, format string) (*Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	l, err := New(&buf, level, format)
+	assert.Nil(t, err)
+	l.now = func() time.Time {
+		return time.Date(2022, 6, 30, 12, 0, 0, 0, time.UTC)
+	}
+	return l, &buf
+}
+
+func TestText(t *testing.T) {
+	l, buf := testLogger(t, LevelInfo, FormatText)
+
+	l.Log(LevelDebug, "Not written")
+	l.Log(LevelInfo, "Listening", "address", ":8080")
+	l.Log(LevelError, "Failed to score files", "error", errors.New("open a.yaml: no such file"), "took", time.Second, "odd")
+
+	assert.Equal(t, `time=2022-06-30T12:00:00.000Z level=INFO msg=Listening address=:8080
+time=2022-06-30T12:00:00.000Z level=ERROR msg="Failed to score files" error="open a.yaml: no such file" took=1s !BADKEY=odd
+`, buf.String())
+}
+
+func TestJSON(t *testing.T) {
+	l, buf := testLogger(t, LevelDebug, FormatJSON)
+
+	l.Log(LevelDebug, "Check failed", "check", "pod-probes", "grade", 1, "comments", []string{"a", "b"}, "error", errors.New("failed"))
+
+	var res map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &res))
+	assert.Equal(t, map[string]interface{}{
+		"time":     "2022-06-30T12:00:00.000Z",
+		"level":    "DEBUG",
+		"msg":      "Check failed",
+		"check":    "pod-probes",
+		"grade":    float64(1),
+		"comments": []interface{}{"a", "b"},
+		"error":    "failed",
+	}, res)
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, LevelInfo, "xml")
+	assert.NotNil(t, err)
+}
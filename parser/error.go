@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -12,7 +11,7 @@ func (p parseError) Error() string {
 	for _, e := range p {
 		s = append(s, e.Error())
 	}
-	return fmt.Sprintf(strings.Join(s, "\n"))
+	return strings.Join(s, "\n")
 }
 
 func (p *parseError) AddIfErr(err error) {
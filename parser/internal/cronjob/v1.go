@@ -10,12 +10,32 @@ import (
 type CronJobV1 struct {
 	Obj      v1.CronJob
 	Location ks.FileLocation
+
+	// Zone is the spec.timeZone of the CronJob, which is read from the raw object
+	// as it's not a part of the supported API version
+	Zone *string
 }
 
 func (c CronJobV1) StartingDeadlineSeconds() *int64 {
 	return c.Obj.Spec.StartingDeadlineSeconds
 }
 
+func (c CronJobV1) ConcurrencyPolicy() v1.ConcurrencyPolicy {
+	return c.Obj.Spec.ConcurrencyPolicy
+}
+
+func (c CronJobV1) SuccessfulJobsHistoryLimit() *int32 {
+	return c.Obj.Spec.SuccessfulJobsHistoryLimit
+}
+
+func (c CronJobV1) FailedJobsHistoryLimit() *int32 {
+	return c.Obj.Spec.FailedJobsHistoryLimit
+}
+
+func (c CronJobV1) TimeZone() *string {
+	return c.Zone
+}
+
 func (c CronJobV1) FileLocation() ks.FileLocation {
 	return c.Location
 }
@@ -2,6 +2,7 @@ package cronjob
 
 import (
 	ks "github.com/zegl/kube-score/domain"
+	v1 "k8s.io/api/batch/v1"
 	"k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -16,6 +17,23 @@ func (c CronJobV1beta1) StartingDeadlineSeconds() *int64 {
 	return c.Obj.Spec.StartingDeadlineSeconds
 }
 
+func (c CronJobV1beta1) ConcurrencyPolicy() v1.ConcurrencyPolicy {
+	return v1.ConcurrencyPolicy(c.Obj.Spec.ConcurrencyPolicy)
+}
+
+func (c CronJobV1beta1) SuccessfulJobsHistoryLimit() *int32 {
+	return c.Obj.Spec.SuccessfulJobsHistoryLimit
+}
+
+func (c CronJobV1beta1) FailedJobsHistoryLimit() *int32 {
+	return c.Obj.Spec.FailedJobsHistoryLimit
+}
+
+// TimeZone always returns nil, as timeZone is only supported by batch/v1
+func (c CronJobV1beta1) TimeZone() *string {
+	return nil
+}
+
 func (c CronJobV1beta1) FileLocation() ks.FileLocation {
 	return c.Location
 }
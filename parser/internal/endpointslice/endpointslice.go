@@ -0,0 +1,51 @@
+package endpointslice
+
+import (
+	discoveryv1 "k8s.io/api/discovery/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+type EndpointSliceV1 struct {
+	Obj      discoveryv1.EndpointSlice
+	Location ks.FileLocation
+}
+
+func (e EndpointSliceV1) GetTypeMeta() metav1.TypeMeta {
+	return e.Obj.TypeMeta
+}
+
+func (e EndpointSliceV1) GetObjectMeta() metav1.ObjectMeta {
+	return e.Obj.ObjectMeta
+}
+
+func (e EndpointSliceV1) ServiceName() string {
+	return e.Obj.Labels[discoveryv1.LabelServiceName]
+}
+
+func (e EndpointSliceV1) FileLocation() ks.FileLocation {
+	return e.Location
+}
+
+type EndpointSliceV1beta1 struct {
+	Obj      discoveryv1beta1.EndpointSlice
+	Location ks.FileLocation
+}
+
+func (e EndpointSliceV1beta1) GetTypeMeta() metav1.TypeMeta {
+	return e.Obj.TypeMeta
+}
+
+func (e EndpointSliceV1beta1) GetObjectMeta() metav1.ObjectMeta {
+	return e.Obj.ObjectMeta
+}
+
+func (e EndpointSliceV1beta1) ServiceName() string {
+	return e.Obj.Labels[discoveryv1beta1.LabelServiceName]
+}
+
+func (e EndpointSliceV1beta1) FileLocation() ks.FileLocation {
+	return e.Location
+}
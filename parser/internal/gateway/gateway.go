@@ -0,0 +1,108 @@
+package gateway
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+type Gateway struct {
+	Obj      GatewayObject
+	Location ks.FileLocation
+}
+
+func (g Gateway) GetTypeMeta() metav1.TypeMeta {
+	return g.Obj.TypeMeta
+}
+
+func (g Gateway) GetObjectMeta() metav1.ObjectMeta {
+	return g.Obj.ObjectMeta
+}
+
+func (g Gateway) Listeners() []ks.GatewayListener {
+	var res []ks.GatewayListener
+	for _, l := range g.Obj.Spec.Listeners {
+		listener := ks.GatewayListener{
+			Name:     l.Name,
+			Port:     l.Port,
+			Protocol: l.Protocol,
+		}
+		if l.Hostname != nil {
+			listener.Hostname = *l.Hostname
+		}
+		if l.TLS != nil {
+			// Terminate is the default mode
+			listener.TLSMode = "Terminate"
+			if l.TLS.Mode != nil {
+				listener.TLSMode = *l.TLS.Mode
+			}
+			listener.CertificateRefs = len(l.TLS.CertificateRefs)
+		}
+		res = append(res, listener)
+	}
+	return res
+}
+
+func (g Gateway) FileLocation() ks.FileLocation {
+	return g.Location
+}
+
+type HTTPRoute struct {
+	Obj      HTTPRouteObject
+	Location ks.FileLocation
+}
+
+func (r HTTPRoute) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r HTTPRoute) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r HTTPRoute) Hostnames() []string {
+	return r.Obj.Spec.Hostnames
+}
+
+func (r HTTPRoute) ParentRefs() []ks.GatewayObjectReference {
+	var res []ks.GatewayObjectReference
+	for _, ref := range r.Obj.Spec.ParentRefs {
+		res = append(res, r.reference(ref, Group, "Gateway"))
+	}
+	return res
+}
+
+func (r HTTPRoute) BackendRefs() []ks.GatewayObjectReference {
+	var res []ks.GatewayObjectReference
+	for _, rule := range r.Obj.Spec.Rules {
+		for _, ref := range rule.BackendRefs {
+			res = append(res, r.reference(ref, "", "Service"))
+		}
+	}
+	return res
+}
+
+// reference sets the default group, kind and namespace of ref
+func (r HTTPRoute) reference(ref ObjectReference, defaultGroup, defaultKind string) ks.GatewayObjectReference {
+	res := ks.GatewayObjectReference{
+		Group:     defaultGroup,
+		Kind:      defaultKind,
+		Namespace: r.Obj.Namespace,
+		Name:      ref.Name,
+		Port:      ref.Port,
+	}
+	if ref.Group != nil {
+		res.Group = *ref.Group
+	}
+	if ref.Kind != nil {
+		res.Kind = *ref.Kind
+	}
+	if ref.Namespace != nil {
+		res.Namespace = *ref.Namespace
+	}
+	return res
+}
+
+func (r HTTPRoute) FileLocation() ks.FileLocation {
+	return r.Location
+}
@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// The Gateway API types are not a part of k8s.io/api. The types in this file contain the subset of the API that is
+// used by kube-score, and are the same in all supported versions of the API.
+
+const Group = "gateway.networking.k8s.io"
+
+var (
+	SchemeGroupVersionV1       = schema.GroupVersion{Group: Group, Version: "v1"}
+	SchemeGroupVersionV1beta1  = schema.GroupVersion{Group: Group, Version: "v1beta1"}
+	SchemeGroupVersionV1alpha2 = schema.GroupVersion{Group: Group, Version: "v1alpha2"}
+)
+
+type GatewayObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              GatewaySpec `json:"spec"`
+}
+
+type GatewaySpec struct {
+	GatewayClassName string     `json:"gatewayClassName"`
+	Listeners        []Listener `json:"listeners"`
+}
+
+type Listener struct {
+	Name     string            `json:"name"`
+	Hostname *string           `json:"hostname,omitempty"`
+	Port     int32             `json:"port"`
+	Protocol string            `json:"protocol"`
+	TLS      *GatewayTLSConfig `json:"tls,omitempty"`
+}
+
+type GatewayTLSConfig struct {
+	Mode            *string           `json:"mode,omitempty"`
+	CertificateRefs []ObjectReference `json:"certificateRefs,omitempty"`
+}
+
+type HTTPRouteObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              HTTPRouteSpec `json:"spec"`
+}
+
+type HTTPRouteSpec struct {
+	ParentRefs []ObjectReference `json:"parentRefs,omitempty"`
+	Hostnames  []string          `json:"hostnames,omitempty"`
+	Rules      []HTTPRouteRule   `json:"rules,omitempty"`
+}
+
+type HTTPRouteRule struct {
+	BackendRefs []ObjectReference `json:"backendRefs,omitempty"`
+}
+
+// ObjectReference is used for parentRefs, backendRefs and certificateRefs
+type ObjectReference struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Namespace *string `json:"namespace,omitempty"`
+	Name      string  `json:"name"`
+	Port      *int32  `json:"port,omitempty"`
+}
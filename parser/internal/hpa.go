@@ -4,6 +4,7 @@ import (
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
 	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	ks "github.com/zegl/kube-score/domain"
@@ -30,6 +31,20 @@ func (d HPAv1) HpaTarget() autoscalingv1.CrossVersionObjectReference {
 	return d.Spec.ScaleTargetRef
 }
 
+func (d HPAv1) MinReplicas() *int32 {
+	return d.Spec.MinReplicas
+}
+
+func (d HPAv1) MaxReplicas() int32 {
+	return d.Spec.MaxReplicas
+}
+
+// UtilizationMetrics always returns the CPU utilization, as it's the only metric that is supported by v1,
+// and it's used by default if targetCPUUtilizationPercentage is not set.
+func (d HPAv1) UtilizationMetrics() []ks.HpaUtilizationMetric {
+	return []ks.HpaUtilizationMetric{{Resource: corev1.ResourceCPU}}
+}
+
 type HPAv2beta1 struct {
 	autoscalingv2beta1.HorizontalPodAutoscaler
 	Location ks.FileLocation
@@ -51,6 +66,32 @@ func (d HPAv2beta1) HpaTarget() autoscalingv1.CrossVersionObjectReference {
 	return autoscalingv1.CrossVersionObjectReference(d.Spec.ScaleTargetRef)
 }
 
+func (d HPAv2beta1) MinReplicas() *int32 {
+	return d.Spec.MinReplicas
+}
+
+func (d HPAv2beta1) MaxReplicas() int32 {
+	return d.Spec.MaxReplicas
+}
+
+func (d HPAv2beta1) UtilizationMetrics() []ks.HpaUtilizationMetric {
+	// The CPU utilization is used by default if no metrics are set
+	if len(d.Spec.Metrics) == 0 {
+		return []ks.HpaUtilizationMetric{{Resource: corev1.ResourceCPU}}
+	}
+
+	var res []ks.HpaUtilizationMetric
+	for _, m := range d.Spec.Metrics {
+		if m.Resource != nil && m.Resource.TargetAverageUtilization != nil {
+			res = append(res, ks.HpaUtilizationMetric{Resource: m.Resource.Name})
+		}
+		if m.ContainerResource != nil && m.ContainerResource.TargetAverageUtilization != nil {
+			res = append(res, ks.HpaUtilizationMetric{Resource: m.ContainerResource.Name, Container: m.ContainerResource.Container})
+		}
+	}
+	return res
+}
+
 type HPAv2beta2 struct {
 	autoscalingv2beta2.HorizontalPodAutoscaler
 	Location ks.FileLocation
@@ -71,3 +112,29 @@ func (d HPAv2beta2) GetObjectMeta() metav1.ObjectMeta {
 func (d HPAv2beta2) HpaTarget() autoscalingv1.CrossVersionObjectReference {
 	return autoscalingv1.CrossVersionObjectReference(d.Spec.ScaleTargetRef)
 }
+
+func (d HPAv2beta2) MinReplicas() *int32 {
+	return d.Spec.MinReplicas
+}
+
+func (d HPAv2beta2) MaxReplicas() int32 {
+	return d.Spec.MaxReplicas
+}
+
+func (d HPAv2beta2) UtilizationMetrics() []ks.HpaUtilizationMetric {
+	// The CPU utilization is used by default if no metrics are set
+	if len(d.Spec.Metrics) == 0 {
+		return []ks.HpaUtilizationMetric{{Resource: corev1.ResourceCPU}}
+	}
+
+	var res []ks.HpaUtilizationMetric
+	for _, m := range d.Spec.Metrics {
+		if m.Resource != nil && m.Resource.Target.Type == autoscalingv2beta2.UtilizationMetricType {
+			res = append(res, ks.HpaUtilizationMetric{Resource: m.Resource.Name})
+		}
+		if m.ContainerResource != nil && m.ContainerResource.Target.Type == autoscalingv2beta2.UtilizationMetricType {
+			res = append(res, ks.HpaUtilizationMetric{Resource: m.ContainerResource.Name, Container: m.ContainerResource.Container})
+		}
+	}
+	return res
+}
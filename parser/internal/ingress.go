@@ -34,11 +34,34 @@ func (i IngressV1) Rules() []networkingv1.IngressRule {
 	return i.Spec.Rules
 }
 
+func (i IngressV1) TLS() []networkingv1.IngressTLS {
+	return i.Spec.TLS
+}
+
+func (i IngressV1) IngressClassName() *string {
+	return i.Spec.IngressClassName
+}
+
 type IngressV1beta1 struct {
 	networkingv1beta1.Ingress
 	Location ks.FileLocation
 }
 
+func (i IngressV1beta1) TLS() []networkingv1.IngressTLS {
+	var res []networkingv1.IngressTLS
+	for _, tls := range i.Spec.TLS {
+		res = append(res, networkingv1.IngressTLS{
+			Hosts:      tls.Hosts,
+			SecretName: tls.SecretName,
+		})
+	}
+	return res
+}
+
+func (i IngressV1beta1) IngressClassName() *string {
+	return i.Spec.IngressClassName
+}
+
 func (i IngressV1beta1) FileLocation() ks.FileLocation {
 	return i.Location
 }
@@ -57,7 +80,8 @@ func (i IngressV1beta1) Rules() []networkingv1.IngressRule {
 	paths := func(in []networkingv1beta1.HTTPIngressPath) (out []networkingv1.HTTPIngressPath) {
 		for _, path := range in {
 			out = append(out, networkingv1.HTTPIngressPath{
-				Path: path.Path,
+				Path:     path.Path,
+				PathType: (*networkingv1.PathType)(path.PathType),
 				Backend: networkingv1.IngressBackend{
 					Service: &networkingv1.IngressServiceBackend{
 						Name: path.Backend.ServiceName,
@@ -110,7 +134,8 @@ func (i ExtensionsIngressV1beta1) Rules() []networkingv1.IngressRule {
 	paths := func(in []extensionsv1beta1.HTTPIngressPath) (out []networkingv1.HTTPIngressPath) {
 		for _, path := range in {
 			out = append(out, networkingv1.HTTPIngressPath{
-				Path: path.Path,
+				Path:     path.Path,
+				PathType: (*networkingv1.PathType)(path.PathType),
 				Backend: networkingv1.IngressBackend{
 					Service: &networkingv1.IngressServiceBackend{
 						Name: path.Backend.ServiceName,
@@ -144,6 +169,21 @@ func (i ExtensionsIngressV1beta1) Rules() []networkingv1.IngressRule {
 	return res
 }
 
+func (i ExtensionsIngressV1beta1) TLS() []networkingv1.IngressTLS {
+	var res []networkingv1.IngressTLS
+	for _, tls := range i.Spec.TLS {
+		res = append(res, networkingv1.IngressTLS{
+			Hosts:      tls.Hosts,
+			SecretName: tls.SecretName,
+		})
+	}
+	return res
+}
+
+func (i ExtensionsIngressV1beta1) IngressClassName() *string {
+	return i.Spec.IngressClassName
+}
+
 func (i ExtensionsIngressV1beta1) FileLocation() ks.FileLocation {
 	return i.Location
 }
@@ -9,7 +9,7 @@ import (
 )
 
 type Batchv1Job struct {
-	batchv1.Job
+	Obj      batchv1.Job
 	Location ks.FileLocation
 }
 
@@ -18,14 +18,18 @@ func (d Batchv1Job) FileLocation() ks.FileLocation {
 }
 
 func (d Batchv1Job) GetTypeMeta() metav1.TypeMeta {
-	return d.TypeMeta
+	return d.Obj.TypeMeta
 }
 
 func (d Batchv1Job) GetObjectMeta() metav1.ObjectMeta {
-	return d.ObjectMeta
+	return d.Obj.ObjectMeta
 }
 
 func (d Batchv1Job) GetPodTemplateSpec() corev1.PodTemplateSpec {
-	d.Spec.Template.ObjectMeta.Namespace = d.ObjectMeta.Namespace
-	return d.Spec.Template
+	d.Obj.Spec.Template.ObjectMeta.Namespace = d.Obj.ObjectMeta.Namespace
+	return d.Obj.Spec.Template
+}
+
+func (d Batchv1Job) Job() batchv1.Job {
+	return d.Obj
 }
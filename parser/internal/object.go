@@ -0,0 +1,32 @@
+package internal
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// Object is any parsed object, used by the custom checks
+type Object struct {
+	TypeMeta   metav1.TypeMeta   `json:"-"`
+	ObjectMeta metav1.ObjectMeta `json:"metadata"`
+
+	Raw      map[string]interface{} `json:"-"`
+	Location ks.FileLocation        `json:"-"`
+}
+
+func (o Object) GetTypeMeta() metav1.TypeMeta {
+	return o.TypeMeta
+}
+
+func (o Object) GetObjectMeta() metav1.ObjectMeta {
+	return o.ObjectMeta
+}
+
+func (o Object) Unstructured() map[string]interface{} {
+	return o.Raw
+}
+
+func (o Object) FileLocation() ks.FileLocation {
+	return o.Location
+}
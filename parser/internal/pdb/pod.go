@@ -34,7 +34,16 @@ func (p PodDisruptionBudgetV1beta1) FileLocation() ks.FileLocation {
 }
 
 func (p PodDisruptionBudgetV1beta1) Spec() policyv1.PodDisruptionBudgetSpec {
-	return policyv1.PodDisruptionBudgetSpec(p.Obj.Spec)
+	spec := policyv1.PodDisruptionBudgetSpec{
+		MinAvailable:   p.Obj.Spec.MinAvailable,
+		Selector:       p.Obj.Spec.Selector,
+		MaxUnavailable: p.Obj.Spec.MaxUnavailable,
+	}
+	if p.Obj.Spec.UnhealthyPodEvictionPolicy != nil {
+		policy := policyv1.UnhealthyPodEvictionPolicyType(*p.Obj.Spec.UnhealthyPodEvictionPolicy)
+		spec.UnhealthyPodEvictionPolicy = &policy
+	}
+	return spec
 }
 
 type PodDisruptionBudgetV1 struct {
@@ -0,0 +1,14 @@
+package internal
+
+import (
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// PodSecurityPolicy is parsed to be able to report that the API is deprecated, it has no checks of its own
+type PodSecurityPolicy struct {
+	Location ks.FileLocation
+}
+
+func (p PodSecurityPolicy) FileLocation() ks.FileLocation {
+	return p.Location
+}
@@ -0,0 +1,20 @@
+package pvc
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+type PersistentVolumeClaim struct {
+	Obj      corev1.PersistentVolumeClaim
+	Location ks.FileLocation
+}
+
+func (p PersistentVolumeClaim) PersistentVolumeClaim() corev1.PersistentVolumeClaim {
+	return p.Obj
+}
+
+func (p PersistentVolumeClaim) FileLocation() ks.FileLocation {
+	return p.Location
+}
@@ -0,0 +1,100 @@
+package rbac
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+type Role struct {
+	Obj      rbacv1.Role
+	Location ks.FileLocation
+}
+
+func (r Role) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r Role) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r Role) Rules() []rbacv1.PolicyRule {
+	return r.Obj.Rules
+}
+
+func (r Role) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+type ClusterRole struct {
+	Obj      rbacv1.ClusterRole
+	Location ks.FileLocation
+}
+
+func (r ClusterRole) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r ClusterRole) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r ClusterRole) Rules() []rbacv1.PolicyRule {
+	return r.Obj.Rules
+}
+
+func (r ClusterRole) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+type RoleBinding struct {
+	Obj      rbacv1.RoleBinding
+	Location ks.FileLocation
+}
+
+func (r RoleBinding) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r RoleBinding) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r RoleBinding) RoleRef() rbacv1.RoleRef {
+	return r.Obj.RoleRef
+}
+
+func (r RoleBinding) Subjects() []rbacv1.Subject {
+	return r.Obj.Subjects
+}
+
+func (r RoleBinding) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+type ClusterRoleBinding struct {
+	Obj      rbacv1.ClusterRoleBinding
+	Location ks.FileLocation
+}
+
+func (r ClusterRoleBinding) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r ClusterRoleBinding) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r ClusterRoleBinding) RoleRef() rbacv1.RoleRef {
+	return r.Obj.RoleRef
+}
+
+func (r ClusterRoleBinding) Subjects() []rbacv1.Subject {
+	return r.Obj.Subjects
+}
+
+func (r ClusterRoleBinding) FileLocation() ks.FileLocation {
+	return r.Location
+}
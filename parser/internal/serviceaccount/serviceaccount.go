@@ -0,0 +1,20 @@
+package serviceaccount
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+type ServiceAccount struct {
+	Obj      corev1.ServiceAccount
+	Location ks.FileLocation
+}
+
+func (s ServiceAccount) ServiceAccount() corev1.ServiceAccount {
+	return s.Obj
+}
+
+func (s ServiceAccount) FileLocation() ks.FileLocation {
+	return s.Location
+}
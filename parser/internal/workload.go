@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Workload is an object of a workload custom resource, such as an Argo Rollout, that has a pod template
+type Workload struct {
+	Object
+	Template corev1.PodTemplateSpec
+}
+
+func (w Workload) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	w.Template.ObjectMeta.Namespace = w.ObjectMeta.Namespace
+	return w.Template
+}
+
+// NewWorkload decodes the pod template at the path of the object. false is returned if the object has no pod
+// template at the path, such as an Argo Rollout that references a Deployment with workloadRef.
+func NewWorkload(object Object, path string) (Workload, bool, error) {
+	var value interface{} = object.Raw
+	for _, field := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return Workload{}, false, nil
+		}
+		if value, ok = m[field]; !ok {
+			return Workload{}, false, nil
+		}
+	}
+	if value == nil {
+		return Workload{}, false, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return Workload{}, false, err
+	}
+	w := Workload{Object: object}
+	if err := json.Unmarshal(data, &w.Template); err != nil {
+		return Workload{}, false, fmt.Errorf("Failed to parse the pod template of %s at %s: err=%w", object.TypeMeta.Kind, path, err)
+	}
+	return w, true, nil
+}
@@ -1,10 +1,12 @@
 package parser
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"fmt"
-	"io/ioutil"
-	"log"
+	"io"
+	"path/filepath"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -17,25 +19,43 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	policyv1 "k8s.io/api/policy/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	sigsyaml "sigs.k8s.io/yaml"
 
 	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/internal/logger"
 	"github.com/zegl/kube-score/parser/internal"
 	internalcronjob "github.com/zegl/kube-score/parser/internal/cronjob"
+	internalendpointslice "github.com/zegl/kube-score/parser/internal/endpointslice"
+	internalgateway "github.com/zegl/kube-score/parser/internal/gateway"
 	internalnetpol "github.com/zegl/kube-score/parser/internal/networkpolicy"
 	internalpdb "github.com/zegl/kube-score/parser/internal/pdb"
 	internalpod "github.com/zegl/kube-score/parser/internal/pod"
+	internalpvc "github.com/zegl/kube-score/parser/internal/pvc"
+	internalrbac "github.com/zegl/kube-score/parser/internal/rbac"
 	internalservice "github.com/zegl/kube-score/parser/internal/service"
+	internalserviceaccount "github.com/zegl/kube-score/parser/internal/serviceaccount"
 )
 
+const kustomizeOriginAnnotation = "config.kubernetes.io/origin"
+
+// KustomizeInputPrefix is the prefix of the name of the input that is built from a kustomization, such as
+// "kustomize://overlays/prod". The paths in the origin annotations of the objects are relative to that directory.
+const KustomizeInputPrefix = "kustomize://"
+
 var scheme = runtime.NewScheme()
 var codecs = serializer.NewCodecFactory(scheme)
 
@@ -54,6 +74,9 @@ func addToScheme(scheme *runtime.Scheme) {
 	batchv1beta1.AddToScheme(scheme)
 	policyv1beta1.AddToScheme(scheme)
 	policyv1.AddToScheme(scheme)
+	rbacv1.AddToScheme(scheme)
+	discoveryv1.AddToScheme(scheme)
+	discoveryv1beta1.AddToScheme(scheme)
 }
 
 type detectKind struct {
@@ -61,24 +84,54 @@ type detectKind struct {
 	Kind       string `yaml:"kind"`
 }
 
+// objectMetadata is the metadata that the objects are selected by
+type objectMetadata struct {
+	Metadata struct {
+		Namespace string            `yaml:"namespace"`
+		Labels    map[string]string `yaml:"labels"`
+	} `yaml:"metadata"`
+}
+
 type parsedObjects struct {
 	bothMetas            []ks.BothMeta
 	pods                 []ks.Pod
 	podspecers           []ks.PodSpecer
 	networkPolicies      []ks.NetworkPolicy
 	services             []ks.Service
+	endpointSlices       []ks.EndpointSlice
+	objects              []ks.Object
+	unknownObjects       []ks.Object
+	serviceAccounts      []ks.ServiceAccount
+	pvcs                 []ks.PersistentVolumeClaim
 	podDisruptionBudgets []ks.PodDisruptionBudget
 	deployments          []ks.Deployment
 	statefulsets         []ks.StatefulSet
+	jobs                 []ks.Job
 	ingresses            []ks.Ingress // supports multiple versions of ingress
 	cronjobs             []ks.CronJob
 	hpaTargeters         []ks.HpaTargeter // all versions of HPAs
+	roles                []ks.Role        // both Roles and ClusterRoles
+	roleBindings         []ks.RoleBinding // both RoleBindings and ClusterRoleBindings
+	gateways             []ks.Gateway
+	httpRoutes           []ks.HTTPRoute
 }
 
 func (p *parsedObjects) Services() []ks.Service {
 	return p.services
 }
 
+func (p *parsedObjects) EndpointSlices() []ks.EndpointSlice {
+	return p.endpointSlices
+}
+
+func (p *parsedObjects) ServiceAccounts() []ks.ServiceAccount {
+	return p.serviceAccounts
+}
+
+func (p *parsedObjects) PersistentVolumeClaims() []ks.PersistentVolumeClaim {
+	return p.pvcs
+}
+
 func (p *parsedObjects) Pods() []ks.Pod {
 	return p.pods
 }
@@ -107,6 +160,10 @@ func (p *parsedObjects) StatefulSets() []ks.StatefulSet {
 	return p.statefulsets
 }
 
+func (p *parsedObjects) Jobs() []ks.Job {
+	return p.jobs
+}
+
 func (p *parsedObjects) Metas() []ks.BothMeta {
 	return p.bothMetas
 }
@@ -119,53 +176,290 @@ func (p *parsedObjects) HorizontalPodAutoscalers() []ks.HpaTargeter {
 	return p.hpaTargeters
 }
 
+func (p *parsedObjects) Roles() []ks.Role {
+	return p.roles
+}
+
+func (p *parsedObjects) RoleBindings() []ks.RoleBinding {
+	return p.roleBindings
+}
+
+func (p *parsedObjects) Gateways() []ks.Gateway {
+	return p.gateways
+}
+
+func (p *parsedObjects) HTTPRoutes() []ks.HTTPRoute {
+	return p.httpRoutes
+}
+
+func (p *parsedObjects) Objects() []ks.Object {
+	return p.objects
+}
+
+func (p *parsedObjects) UnknownObjects() []ks.Object {
+	return p.unknownObjects
+}
+
 func Empty() ks.AllTypes {
 	return &parsedObjects{}
 }
 
-func ParseFiles(cnf config.Configuration) (ks.AllTypes, error) {
+// ParseFiles parses all files in the configuration. Parsing is stopped, and the error of the context is returned,
+// if the context is done.
+func ParseFiles(ctx context.Context, cnf config.Configuration) (ks.AllTypes, error) {
 	s := &parsedObjects{}
 
 	for _, namedReader := range cnf.AllFiles {
-		fullFile, err := ioutil.ReadAll(namedReader)
+		decodeDocument := func(isListItem bool) func(offset int, document []byte) error {
+			return func(offset int, document []byte) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if len(bytes.TrimSpace(document)) == 0 {
+					return nil
+				}
+				return detectAndDecode(cnf, s, namedReader.Name(), offset, document, isListItem)
+			}
+		}
+		err := splitDocuments(namedReader, decodeDocument(false), decodeDocument(true))
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		// Convert to unix style newlines
-		fullFile = bytes.Replace(fullFile, []byte("\r\n"), []byte("\n"), -1)
+	return s, nil
+}
 
-		offset := 1 // Line numbers are 1 indexed
+// splitDocuments reads the YAML documents, separated by "---" lines, from r one at a time, and calls fn with each
+// document and the line number that it starts at. Only one document is kept in memory at a time, so that large
+// multi-document files can be read with bounded memory.
+//
+// The items of a List, such as the output of "kubectl get -o yaml", are passed to item one at a time while they are
+// read, and are not kept in the document, so that a List is also read with bounded memory. Only Lists in block style
+// YAML are split, other Lists are passed to fn as a whole.
+func splitDocuments(r io.Reader, fn, item func(offset int, document []byte) error) error {
+	reader := bufio.NewReader(r)
+
+	offset := 1 // Line numbers are 1 indexed
+	line := 0
+	list := &listSplitter{item: item}
+
+	end := func() error {
+		document, err := list.end()
+		if err != nil {
+			return err
+		}
+		return fn(offset, document)
+	}
 
-		// Remove initial "---\n" if present
-		if bytes.HasPrefix(fullFile, []byte("---\n")) {
-			fullFile = fullFile[4:]
-			offset = 2
+	for {
+		row, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
 		}
+		if len(row) > 0 {
+			line++
 
-		for _, fileContents := range bytes.Split(fullFile, []byte("\n---\n")) {
+			// Convert to unix style newlines
+			if bytes.HasSuffix(row, []byte("\r\n")) {
+				row = append(row[:len(row)-2], '\n')
+			}
 
-			if len(bytes.TrimSpace(fileContents)) > 0 {
-				err := detectAndDecode(cnf, s, namedReader.Name(), offset, fileContents)
-				if err != nil {
-					return nil, err
+			if bytes.Equal(bytes.TrimRight(row, "\n"), []byte("---")) {
+				if err := end(); err != nil {
+					return err
 				}
+				list = &listSplitter{item: item}
+				offset = line + 1
+			} else if err := list.add(line, row); err != nil {
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	return end()
+}
+
+// listSplitter receives the lines of a document. The items of a top-level "items" sequence are passed to item one at
+// a time, and are not kept in the document, if the document is a List.
+//
+// The kind of the document is often written after the items, as in the output of kubectl, so the items are split
+// if the apiVersion is v1 and the kind is List or not yet known. There are no custom resources in the v1 API group,
+// so all other v1 objects with a top-level items field are also lists. If the items are written before the
+// apiVersion, the lines are kept until the apiVersion is known, or until the end of the document, and are only
+// split if the document is a v1 List.
+type listSplitter struct {
+	item func(offset int, item []byte) error
+
+	document   []byte
+	apiVersion string
+	kind       string
+
+	// inItems is true while the lines of the items are read. itemIndent is the indentation of the "-" of the items, it
+	// is -1 until the first item has been read.
+	inItems    bool
+	itemIndent int
+	itemOffset int
+	itemLines  []byte
+
+	// pending are the lines from a top-level items field that is read before the apiVersion of the document. They
+	// are added again when the apiVersion is known, or when the kind is known to not be List.
+	pending  []pendingLine
+	buffered bool
+}
+
+type pendingLine struct {
+	line int
+	row  []byte
+}
+
+func (l *listSplitter) add(line int, row []byte) error {
+	if l.pending != nil {
+		l.pending = append(l.pending, pendingLine{line, row})
+		l.readKeys(row)
+		if l.apiVersion == "" && (l.kind == "" || l.kind == "List") {
+			return nil
+		}
+		return l.addPending()
+	}
+
+	if l.inItems {
+		trimmed := bytes.TrimLeft(row, " ")
+		indent := len(row) - len(trimmed)
+		switch {
+		case len(bytes.TrimSpace(trimmed)) == 0 || trimmed[0] == '#':
+			// Blank lines and comments are kept in the current item
+			if l.itemLines != nil {
+				l.itemLines = append(l.itemLines, row...)
+			}
+			return nil
+		case isSequenceItem(trimmed) && (l.itemIndent < 0 || indent == l.itemIndent):
+			if err := l.flushItem(); err != nil {
+				return err
 			}
+			l.itemIndent = indent
+			l.itemOffset = line
+
+			// The "-" is replaced with a space, so that all lines of the item are dedented in the same way
+			first := append([]byte{}, row...)
+			first[indent] = ' '
+			l.itemLines = dedent(first, indent+2)
+			return nil
+		case l.itemIndent >= 0 && indent > l.itemIndent:
+			l.itemLines = append(l.itemLines, dedent(row, l.itemIndent+2)...)
+			return nil
+		}
 
-			offset += 2 + bytes.Count(fileContents, []byte("\n"))
+		// Any other line ends the items
+		if err := l.flushItem(); err != nil {
+			return err
 		}
+		l.inItems = false
 	}
 
-	return s, nil
+	if isTopLevelKey(row) && bytes.Equal(bytes.TrimSpace(row), []byte("items:")) && (l.kind == "" || l.kind == "List") {
+		switch {
+		case l.apiVersion == "" && !l.buffered:
+			l.pending = []pendingLine{{line, row}}
+			return nil
+		case l.apiVersion == "v1":
+			l.inItems = true
+			l.itemIndent = -1
+		}
+	}
+	l.readKeys(row)
+
+	l.document = append(l.document, row...)
+	return nil
+}
+
+// readKeys sets the apiVersion and kind of the document if the line is one of those top-level keys
+func (l *listSplitter) readKeys(row []byte) {
+	if !isTopLevelKey(row) {
+		return
+	}
+	var keys map[string]string
+	if err := yaml.Unmarshal(row, &keys); err == nil {
+		if v, ok := keys["apiVersion"]; ok {
+			l.apiVersion = v
+		}
+		if v, ok := keys["kind"]; ok {
+			l.kind = v
+		}
+	}
+}
+
+// addPending adds the pending lines again, now that it's known if the document is a List
+func (l *listSplitter) addPending() error {
+	pending := l.pending
+	l.pending = nil
+	l.buffered = true
+	for _, p := range pending {
+		if err := l.add(p.line, p.row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *listSplitter) flushItem() error {
+	if l.itemLines == nil {
+		return nil
+	}
+	item := l.itemLines
+	l.itemLines = nil
+	return l.item(l.itemOffset, item)
 }
 
-func detectAndDecode(cnf config.Configuration, s *parsedObjects, fileName string, fileOffset int, raw []byte) error {
+// end passes the last item to item, and returns the document without the items
+func (l *listSplitter) end() ([]byte, error) {
+	if l.pending != nil {
+		// The document has no apiVersion, and is not a List
+		if err := l.addPending(); err != nil {
+			return nil, err
+		}
+	}
+	if err := l.flushItem(); err != nil {
+		return nil, err
+	}
+	return l.document, nil
+}
+
+// isTopLevelKey returns true if the line is a key of the top-level mapping, keys start at the beginning of the line
+func isTopLevelKey(row []byte) bool {
+	return len(row) > 0 && row[0] != ' ' && row[0] != '#' && row[0] != '-' && row[0] != '\n'
+}
+
+// isSequenceItem returns true if the line, without its indentation, starts an item of a sequence
+func isSequenceItem(trimmed []byte) bool {
+	return bytes.HasPrefix(trimmed, []byte("- ")) || bytes.Equal(bytes.TrimRight(trimmed, "\n"), []byte("-"))
+}
+
+// dedent removes up to n spaces from the beginning of the line
+func dedent(row []byte, n int) []byte {
+	i := 0
+	for i < n && i < len(row) && row[i] == ' ' {
+		i++
+	}
+	return row[i:]
+}
+
+func detectAndDecode(cnf config.Configuration, s *parsedObjects, fileName string, fileOffset int, raw []byte, isListItem bool) error {
 	var detect detectKind
 	err := yaml.Unmarshal(raw, &detect)
 	if err != nil {
 		return err
 	}
 
+	// Documents without a kind and apiVersion are not objects, such as the documents with only a comment that
+	// "helm template" writes for empty templates
+	if detect.ApiVersion == "" && detect.Kind == "" {
+		return nil
+	}
+
 	detectedVersion := schema.FromAPIVersionAndKind(detect.ApiVersion, detect.Kind)
 
 	// Parse lists and their items recursively
@@ -176,7 +470,7 @@ func detectAndDecode(cnf config.Configuration, s *parsedObjects, fileName string
 			return err
 		}
 		for _, listItem := range list.Items {
-			err := detectAndDecode(cnf, s, fileName, fileOffset, listItem.Raw)
+			err := detectAndDecode(cnf, s, fileName, fileOffset, listItem.Raw, true)
 			if err != nil {
 				return err
 			}
@@ -184,7 +478,13 @@ func detectAndDecode(cnf config.Configuration, s *parsedObjects, fileName string
 		return nil
 	}
 
-	err = decodeItem(cnf, s, detectedVersion, fileName, fileOffset, raw)
+	// Objects in other namespaces, or that don't match the selector, are not scored, and are not used by the checks
+	// of other objects either
+	if !selected(cnf, raw) {
+		return nil
+	}
+
+	err = decodeItem(cnf, s, detectedVersion, fileName, fileOffset, raw, isListItem)
 	if err != nil {
 		return err
 	}
@@ -192,6 +492,24 @@ func detectAndDecode(cnf config.Configuration, s *parsedObjects, fileName string
 	return nil
 }
 
+// selected returns true if the object is in the namespace, and matches the label selector, of the configuration.
+// Objects without a namespace get their namespace when they are applied, or are not namespaced, and are always
+// selected. Objects with metadata that can't be read are selected, so that the error is reported when they are decoded.
+func selected(cnf config.Configuration, raw []byte) bool {
+	if cnf.Namespace == "" && cnf.Selector == nil {
+		return true
+	}
+
+	var meta objectMetadata
+	if err := yaml.Unmarshal(raw, &meta); err != nil {
+		return true
+	}
+	if cnf.Namespace != "" && meta.Metadata.Namespace != "" && meta.Metadata.Namespace != cnf.Namespace {
+		return false
+	}
+	return cnf.Selector == nil || cnf.Selector.Matches(labels.Set(meta.Metadata.Labels))
+}
+
 func decode(data []byte, object runtime.Object) error {
 	deserializer := codecs.UniversalDeserializer()
 	if _, _, err := deserializer.Decode(data, nil, object); err != nil {
@@ -201,6 +519,43 @@ func decode(data []byte, object runtime.Object) error {
 	return nil
 }
 
+// decodeJSONTagged decodes objects that are not registered in the scheme, such as the Gateway API types
+func decodeJSONTagged(data []byte, gvk schema.GroupVersionKind, object interface{}) error {
+	if err := sigsyaml.Unmarshal(data, object); err != nil {
+		return fmt.Errorf("Failed to parse %s: err=%w", gvk, err)
+	}
+	return nil
+}
+
+// cronJobTimeZone is used to read spec.timeZone from CronJobs, which is not a part of the supported API version
+type cronJobTimeZone struct {
+	Spec struct {
+		TimeZone *string `yaml:"timeZone"`
+	} `yaml:"spec"`
+}
+
+type originAnnotation struct {
+	Metadata struct {
+		Annotations map[string]string `yaml:"annotations"`
+	} `yaml:"metadata"`
+}
+
+type origin struct {
+	Path string `yaml:"path"`
+
+	// Repo is set if the object originates from a remote base, Path is then relative to the root of the repository
+	Repo string `yaml:"repo"`
+}
+
+// filePath returns the path of the file that the object originates from. The path in the annotation is relative to the
+// kustomization, and is joined with the directory of the kustomization if it's known from the name of the input.
+func (o origin) filePath(fileName string) string {
+	if o.Repo != "" || filepath.IsAbs(o.Path) || !strings.HasPrefix(fileName, KustomizeInputPrefix) {
+		return o.Path
+	}
+	return filepath.Join(strings.TrimPrefix(fileName, KustomizeInputPrefix), o.Path)
+}
+
 func detectFileLocation(fileName string, fileOffset int, fileContents []byte) ks.FileLocation {
 	// If the object YAML begins with a Helm style "# Source: " comment
 	// Use the information in there as the file name
@@ -213,13 +568,95 @@ func detectFileLocation(fileName string, fileOffset int, fileContents []byte) ks
 		}
 	}
 
+	// If the object has been built by Kustomize with "buildMetadata: [originAnnotations]"
+	// Use the path of the base or overlay file that the object originates from
+	var meta originAnnotation
+	if err := yaml.Unmarshal(fileContents, &meta); err == nil {
+		if originYAML, ok := meta.Metadata.Annotations[kustomizeOriginAnnotation]; ok {
+			var o origin
+			if err := yaml.Unmarshal([]byte(originYAML), &o); err == nil && o.Path != "" {
+				return ks.FileLocation{
+					Name: o.filePath(fileName),
+					Line: 1, // Set line to 1 as the line definition gets lost in Kustomize
+				}
+			}
+		}
+	}
+
 	return ks.FileLocation{
-		Name: fileName,
-		Line: fileOffset,
+		Name:   fileName,
+		Line:   fileOffset,
+		Column: 1,
+		Fields: detectFieldPositions(fileOffset, fileContents),
+	}
+}
+
+// detectFieldPositions returns the positions of the named list items and the mapping keys in the object.
+// List items are named by their "name" field, and take precedence over mapping keys with the same name.
+// The first occurrence of a name is used.
+func detectFieldPositions(fileOffset int, fileContents []byte) map[string]ks.FilePosition {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(fileContents, &doc); err != nil {
+		return nil
+	}
+
+	position := func(n *yaml.Node) ks.FilePosition {
+		return ks.FilePosition{Line: fileOffset + n.Line - 1, Column: n.Column}
+	}
+
+	items := make(map[string]ks.FilePosition)
+	keys := make(map[string]ks.FilePosition)
+
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.DocumentNode:
+			for _, c := range n.Content {
+				walk(c)
+			}
+		case yaml.SequenceNode:
+			for _, item := range n.Content {
+				if name, ok := mappingValue(item, "name"); ok {
+					if _, exists := items[name]; !exists {
+						items[name] = position(item)
+					}
+				}
+				walk(item)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key := n.Content[i]
+				if key.Kind == yaml.ScalarNode {
+					if _, exists := keys[key.Value]; !exists {
+						keys[key.Value] = position(key)
+					}
+				}
+				walk(n.Content[i+1])
+			}
+		}
+	}
+	walk(&doc)
+
+	for name, pos := range items {
+		keys[name] = pos
+	}
+	return keys
+}
+
+// mappingValue returns the scalar value of the key in a mapping node
+func mappingValue(n *yaml.Node, key string) (string, bool) {
+	if n.Kind != yaml.MappingNode {
+		return "", false
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key && n.Content[i+1].Kind == yaml.ScalarNode {
+			return n.Content[i+1].Value, true
+		}
 	}
+	return "", false
 }
 
-func decodeItem(cnf config.Configuration, s *parsedObjects, detectedVersion schema.GroupVersionKind, fileName string, fileOffset int, fileContents []byte) error {
+func decodeItem(cnf config.Configuration, s *parsedObjects, detectedVersion schema.GroupVersionKind, fileName string, fileOffset int, fileContents []byte, isListItem bool) error {
 	addPodSpeccer := func(ps ks.PodSpecer) {
 		s.podspecers = append(s.podspecers, ps)
 		s.bothMetas = append(s.bothMetas, ks.BothMeta{ps.GetTypeMeta(), ps.GetObjectMeta(), ps})
@@ -227,8 +664,22 @@ func decodeItem(cnf config.Configuration, s *parsedObjects, detectedVersion sche
 
 	fileLocation := detectFileLocation(fileName, fileOffset, fileContents)
 
+	// Items in a List have been re-encoded or dedented, and the positions of their fields are not the positions in
+	// the file
+	if isListItem {
+		fileLocation.Fields = nil
+	}
+
 	var errs parseError
 
+	object := internal.Object{
+		TypeMeta: metav1.TypeMeta{APIVersion: detectedVersion.GroupVersion().String(), Kind: detectedVersion.Kind},
+		Location: fileLocation,
+	}
+	errs.AddIfErr(decodeJSONTagged(fileContents, detectedVersion, &object))
+	errs.AddIfErr(decodeJSONTagged(fileContents, detectedVersion, &object.Raw))
+	s.objects = append(s.objects, object)
+
 	switch detectedVersion {
 	case corev1.SchemeGroupVersion.WithKind("Pod"):
 		var pod corev1.Pod
@@ -240,7 +691,9 @@ func decodeItem(cnf config.Configuration, s *parsedObjects, detectedVersion sche
 	case batchv1.SchemeGroupVersion.WithKind("Job"):
 		var job batchv1.Job
 		errs.AddIfErr(decode(fileContents, &job))
-		addPodSpeccer(internal.Batchv1Job{job, fileLocation})
+		j := internal.Batchv1Job{job, fileLocation}
+		addPodSpeccer(j)
+		s.jobs = append(s.jobs, j)
 
 	case batchv1beta1.SchemeGroupVersion.WithKind("CronJob"):
 		var cronjob batchv1beta1.CronJob
@@ -252,7 +705,9 @@ func decodeItem(cnf config.Configuration, s *parsedObjects, detectedVersion sche
 	case batchv1.SchemeGroupVersion.WithKind("CronJob"):
 		var cronjob batchv1.CronJob
 		errs.AddIfErr(decode(fileContents, &cronjob))
-		cjob := internalcronjob.CronJobV1{cronjob, fileLocation}
+		var timeZone cronJobTimeZone
+		errs.AddIfErr(yaml.Unmarshal(fileContents, &timeZone))
+		cjob := internalcronjob.CronJobV1{cronjob, fileLocation, timeZone.Spec.TimeZone}
 		addPodSpeccer(cjob)
 		s.cronjobs = append(s.cronjobs, cjob)
 
@@ -321,6 +776,58 @@ func decodeItem(cnf config.Configuration, s *parsedObjects, detectedVersion sche
 		s.services = append(s.services, serv)
 		s.bothMetas = append(s.bothMetas, ks.BothMeta{service.TypeMeta, service.ObjectMeta, serv})
 
+	case discoveryv1.SchemeGroupVersion.WithKind("EndpointSlice"):
+		var slice discoveryv1.EndpointSlice
+		errs.AddIfErr(decode(fileContents, &slice))
+		es := internalendpointslice.EndpointSliceV1{slice, fileLocation}
+		s.endpointSlices = append(s.endpointSlices, es)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{slice.TypeMeta, slice.ObjectMeta, es})
+	case discoveryv1beta1.SchemeGroupVersion.WithKind("EndpointSlice"):
+		var slice discoveryv1beta1.EndpointSlice
+		errs.AddIfErr(decode(fileContents, &slice))
+		es := internalendpointslice.EndpointSliceV1beta1{slice, fileLocation}
+		s.endpointSlices = append(s.endpointSlices, es)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{slice.TypeMeta, slice.ObjectMeta, es})
+
+	case corev1.SchemeGroupVersion.WithKind("ServiceAccount"):
+		var serviceAccount corev1.ServiceAccount
+		errs.AddIfErr(decode(fileContents, &serviceAccount))
+		sa := internalserviceaccount.ServiceAccount{serviceAccount, fileLocation}
+		s.serviceAccounts = append(s.serviceAccounts, sa)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{serviceAccount.TypeMeta, serviceAccount.ObjectMeta, sa})
+
+	case corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"):
+		var claim corev1.PersistentVolumeClaim
+		errs.AddIfErr(decode(fileContents, &claim))
+		c := internalpvc.PersistentVolumeClaim{claim, fileLocation}
+		s.pvcs = append(s.pvcs, c)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{claim.TypeMeta, claim.ObjectMeta, c})
+
+	case rbacv1.SchemeGroupVersion.WithKind("Role"):
+		var role rbacv1.Role
+		errs.AddIfErr(decode(fileContents, &role))
+		r := internalrbac.Role{role, fileLocation}
+		s.roles = append(s.roles, r)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{role.TypeMeta, role.ObjectMeta, r})
+	case rbacv1.SchemeGroupVersion.WithKind("ClusterRole"):
+		var role rbacv1.ClusterRole
+		errs.AddIfErr(decode(fileContents, &role))
+		r := internalrbac.ClusterRole{role, fileLocation}
+		s.roles = append(s.roles, r)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{role.TypeMeta, role.ObjectMeta, r})
+	case rbacv1.SchemeGroupVersion.WithKind("RoleBinding"):
+		var binding rbacv1.RoleBinding
+		errs.AddIfErr(decode(fileContents, &binding))
+		b := internalrbac.RoleBinding{binding, fileLocation}
+		s.roleBindings = append(s.roleBindings, b)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{binding.TypeMeta, binding.ObjectMeta, b})
+	case rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"):
+		var binding rbacv1.ClusterRoleBinding
+		errs.AddIfErr(decode(fileContents, &binding))
+		b := internalrbac.ClusterRoleBinding{binding, fileLocation}
+		s.roleBindings = append(s.roleBindings, b)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{binding.TypeMeta, binding.ObjectMeta, b})
+
 	case policyv1beta1.SchemeGroupVersion.WithKind("PodDisruptionBudget"):
 		var disruptBudget policyv1beta1.PodDisruptionBudget
 		errs.AddIfErr(decode(fileContents, &disruptBudget))
@@ -334,6 +841,13 @@ func decodeItem(cnf config.Configuration, s *parsedObjects, detectedVersion sche
 		s.podDisruptionBudgets = append(s.podDisruptionBudgets, dbug)
 		s.bothMetas = append(s.bothMetas, ks.BothMeta{disruptBudget.TypeMeta, disruptBudget.ObjectMeta, dbug})
 
+	case policyv1beta1.SchemeGroupVersion.WithKind("PodSecurityPolicy"),
+		extensionsv1beta1.SchemeGroupVersion.WithKind("PodSecurityPolicy"):
+		// PodSecurityPolicy has been removed from the API, and only the metadata is used
+		var psp metav1.PartialObjectMetadata
+		errs.AddIfErr(decodeJSONTagged(fileContents, detectedVersion, &psp))
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{psp.TypeMeta, psp.ObjectMeta, internal.PodSecurityPolicy{fileLocation}})
+
 	case extensionsv1beta1.SchemeGroupVersion.WithKind("Ingress"):
 		var ingress extensionsv1beta1.Ingress
 		errs.AddIfErr(decode(fileContents, &ingress))
@@ -355,6 +869,24 @@ func decodeItem(cnf config.Configuration, s *parsedObjects, detectedVersion sche
 		s.ingresses = append(s.ingresses, ing)
 		s.bothMetas = append(s.bothMetas, ks.BothMeta{ingress.TypeMeta, ingress.ObjectMeta, ing})
 
+	case internalgateway.SchemeGroupVersionV1.WithKind("Gateway"),
+		internalgateway.SchemeGroupVersionV1beta1.WithKind("Gateway"),
+		internalgateway.SchemeGroupVersionV1alpha2.WithKind("Gateway"):
+		var gateway internalgateway.GatewayObject
+		errs.AddIfErr(decodeJSONTagged(fileContents, detectedVersion, &gateway))
+		g := internalgateway.Gateway{gateway, fileLocation}
+		s.gateways = append(s.gateways, g)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{gateway.TypeMeta, gateway.ObjectMeta, g})
+
+	case internalgateway.SchemeGroupVersionV1.WithKind("HTTPRoute"),
+		internalgateway.SchemeGroupVersionV1beta1.WithKind("HTTPRoute"),
+		internalgateway.SchemeGroupVersionV1alpha2.WithKind("HTTPRoute"):
+		var route internalgateway.HTTPRouteObject
+		errs.AddIfErr(decodeJSONTagged(fileContents, detectedVersion, &route))
+		r := internalgateway.HTTPRoute{route, fileLocation}
+		s.httpRoutes = append(s.httpRoutes, r)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{route.TypeMeta, route.ObjectMeta, r})
+
 	case autoscalingv1.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"):
 		var hpa autoscalingv1.HorizontalPodAutoscaler
 		errs.AddIfErr(decode(fileContents, &hpa))
@@ -377,9 +909,19 @@ func decodeItem(cnf config.Configuration, s *parsedObjects, detectedVersion sche
 		s.bothMetas = append(s.bothMetas, ks.BothMeta{hpa.TypeMeta, hpa.ObjectMeta, h})
 
 	default:
-		if cnf.VerboseOutput > 1 {
-			log.Printf("Unknown datatype: %s", detectedVersion.String())
+		if path, ok := cnf.PodTemplatePath(detectedVersion.Group, detectedVersion.Kind); ok {
+			w, ok, err := internal.NewWorkload(object, path)
+			errs.AddIfErr(err)
+			if ok {
+				addPodSpeccer(w)
+				break
+			}
 		}
+
+		logger.Debug("Unknown datatype", "kind", detectedVersion.String(), "file", fileName)
+		s.unknownObjects = append(s.unknownObjects, object)
+		// The metadata of all objects is checked, including custom resources
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{object.TypeMeta, object.ObjectMeta, object})
 	}
 
 	if errs.Any() {
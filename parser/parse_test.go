@@ -1,9 +1,14 @@
 package parser
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
@@ -19,7 +24,7 @@ func TestParse(t *testing.T) {
 	}{
 		{
 			"testdata/invalid-yaml.yaml",
-			fmt.Errorf("Failed to parse /v1, Kind=Service: err=v1.Service.Spec: v1.ServiceSpec.Ports: []v1.ServicePort: v1.ServicePort.NodePort: readUint32: unexpected character: \xff, error found in #10 byte of ...|odePort\":\"${PORT}\",\"|..., bigger context ...|\"namespace\":\"test\"},\"spec\":{\"ports\":[{\"nodePort\":\"${PORT}\",\"port\":\"${PORT_EXPOSE}\",\"targetPort\":\"${P|..."),
+			fmt.Errorf("Failed to parse /v1, Kind=Service: err=json: cannot unmarshal string into Go struct field ServicePort.spec.ports.nodePort of type int32"),
 		}, {
 			"testdata/valid-yaml.yaml",
 			nil,
@@ -29,7 +34,7 @@ func TestParse(t *testing.T) {
 	for _, tc := range cases {
 		fp, err := os.Open(tc.fname)
 		assert.Nil(t, err)
-		_, err = ParseFiles(config.Configuration{
+		_, err = ParseFiles(context.Background(), config.Configuration{
 			AllFiles: []ks.NamedReader{fp},
 		})
 		if tc.expected == nil {
@@ -40,6 +45,197 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestSplitDocuments(t *testing.T) {
+	type document struct {
+		offset  int
+		content string
+	}
+
+	split := func(input string) []document {
+		var res []document
+		add := func(offset int, doc []byte) error {
+			res = append(res, document{offset, string(doc)})
+			return nil
+		}
+		err := splitDocuments(iotest.OneByteReader(strings.NewReader(input)), add, add)
+		assert.Nil(t, err)
+		return res
+	}
+
+	assert.Equal(t, []document{
+		{1, ""},
+		{2, "a: 1\nb: 2\n"},
+		{5, "c: 3\n"},
+		{7, "d: 4"},
+	}, split("---\na: 1\nb: 2\n---\nc: 3\n---\nd: 4"))
+
+	// Windows style newlines are converted, and "---" is only a separator on a line of its own
+	assert.Equal(t, []document{
+		{1, "a: 1\n"},
+		{3, "b: |\n  --- x\n"},
+	}, split("a: 1\r\n---\r\nb: |\r\n  --- x\r\n"))
+
+	// Lines are not limited in length
+	long := "a: " + strings.Repeat("x", 1<<20)
+	assert.Equal(t, []document{{1, long}}, split(long))
+
+	fail := func(offset int, doc []byte) error {
+		return fmt.Errorf("failed at line %d", offset)
+	}
+	err := splitDocuments(strings.NewReader("a: 1\n---\nb: 2\n"), fail, fail)
+	assert.Equal(t, "failed at line 1", err.Error())
+}
+
+func TestSplitDocumentsListItems(t *testing.T) {
+	type document struct {
+		offset  int
+		content string
+		item    bool
+	}
+
+	split := func(input string) ([]document, error) {
+		var res []document
+		err := splitDocuments(strings.NewReader(input), func(offset int, doc []byte) error {
+			res = append(res, document{offset, string(doc), false})
+			return nil
+		}, func(offset int, doc []byte) error {
+			res = append(res, document{offset, string(doc), true})
+			return nil
+		})
+		return res, err
+	}
+
+	// The items are split while they are read, also if the kind is written after them as in the output of kubectl
+	docs, err := split(`apiVersion: v1
+items:
+- apiVersion: v1
+  kind: Service
+
+  metadata:
+    name: a
+-
+  apiVersion: v1
+  kind: Service
+kind: List
+metadata:
+  resourceVersion: ""
+---
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: v1
+    kind: Pod
+    spec:
+      containers:
+        - name: foo
+`)
+	assert.Nil(t, err)
+	assert.Equal(t, []document{
+		{3, "apiVersion: v1\nkind: Service\n\nmetadata:\n  name: a\n", true},
+		{8, "\napiVersion: v1\nkind: Service\n", true},
+		{1, "apiVersion: v1\nitems:\nkind: List\nmetadata:\n  resourceVersion: \"\"\n", false},
+		{18, "apiVersion: v1\nkind: Pod\nspec:\n  containers:\n    - name: foo\n", true},
+		{15, "apiVersion: v1\nkind: List\nitems:\n", false},
+	}, docs)
+
+	// Items of other kinds are not split
+	docs, err = split("kind: Foo\nitems:\n- a: 1\n")
+	assert.Nil(t, err)
+	assert.Equal(t, []document{{1, "kind: Foo\nitems:\n- a: 1\n", false}}, docs)
+
+	// Items that are written before the apiVersion are kept until it's known if the document is a List
+	docs, err = split("items:\n- a: 1\nkind: Foo\napiVersion: example.com/v1\n")
+	assert.Nil(t, err)
+	assert.Equal(t, []document{{1, "items:\n- a: 1\nkind: Foo\napiVersion: example.com/v1\n", false}}, docs)
+
+	docs, err = split("items:\n- a: 1\nmetadata:\n  name: foo\napiVersion: example.com/v1\nkind: Foo\n")
+	assert.Nil(t, err)
+	assert.Equal(t, []document{{1, "items:\n- a: 1\nmetadata:\n  name: foo\napiVersion: example.com/v1\nkind: Foo\n", false}}, docs)
+
+	docs, err = split("items:\n- a: 1\n")
+	assert.Nil(t, err)
+	assert.Equal(t, []document{{1, "items:\n- a: 1\n", false}}, docs)
+
+	docs, err = split("items:\n- kind: Service\n  apiVersion: v1\nkind: List\napiVersion: v1\n")
+	assert.Nil(t, err)
+	assert.Equal(t, []document{
+		{2, "kind: Service\napiVersion: v1\n", true},
+		{1, "items:\nkind: List\napiVersion: v1\n", false},
+	}, docs)
+}
+
+type namedReader struct {
+	io.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
+
+func TestParseListItemLocation(t *testing.T) {
+	parsed, err := ParseFiles(context.Background(), config.Configuration{
+		AllFiles: []ks.NamedReader{namedReader{strings.NewReader(`apiVersion: v1
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: a
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: b
+kind: List
+`), "list.yaml"}},
+	})
+	assert.Nil(t, err)
+	services := parsed.Services()
+	assert.Len(t, services, 2)
+	assert.Equal(t, "a", services[0].Service().Name)
+	assert.Equal(t, 3, services[0].FileLocation().Line)
+	assert.Equal(t, 7, services[1].FileLocation().Line)
+}
+
+func TestParseItemsBeforeKind(t *testing.T) {
+	parsed, err := ParseFiles(context.Background(), config.Configuration{
+		AllFiles: []ks.NamedReader{namedReader{strings.NewReader(`items:
+- name: a
+- name: b
+kind: Inventory
+apiVersion: example.com/v1
+metadata:
+  name: foo
+`), "inventory.yaml"}},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, parsed.UnknownObjects(), 1)
+	assert.Equal(t, "Inventory", parsed.UnknownObjects()[0].GetTypeMeta().Kind)
+	assert.Equal(t, "foo", parsed.UnknownObjects()[0].GetObjectMeta().Name)
+	assert.Len(t, parsed.UnknownObjects()[0].Unstructured()["items"], 2)
+}
+
+func TestParseHelmEmptyTemplate(t *testing.T) {
+	parsed, err := ParseFiles(context.Background(), config.Configuration{
+		AllFiles: []ks.NamedReader{namedReader{strings.NewReader(`---
+# Source: app/templates/empty.yaml
+---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+---
+# Source: app/templates/null.yaml
+null
+`), "helm.yaml"}},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, parsed.Objects(), 1)
+	assert.Len(t, parsed.Metas(), 1)
+	assert.Empty(t, parsed.UnknownObjects())
+	assert.Equal(t, "foo", parsed.Objects()[0].GetObjectMeta().Name)
+}
+
 func TestFileLocationHelm(t *testing.T) {
 	doc := `# Source: app1/templates/deployment.yaml
 kind: Deployment
@@ -72,3 +268,53 @@ spec:
 	assert.Equal(t, "someName", fl.Name)
 	assert.Equal(t, 123, fl.Line)
 }
+
+func TestFileLocationKustomizeOrigin(t *testing.T) {
+	doc := `kind: Deployment
+apiVersion: apps/v1
+metadata:
+  name: foo
+  annotations:
+    config.kubernetes.io/origin: |
+      path: ../base/deployment.yaml
+spec:
+  template:
+    metadata:
+      labels:
+        foo: bar`
+
+	// The path is relative to the kustomization
+	fl := detectFileLocation("kustomize://overlays/prod", 5, []byte(doc))
+	assert.Equal(t, filepath.Join("overlays", "base", "deployment.yaml"), fl.Name)
+	assert.Equal(t, 1, fl.Line)
+
+	// The directory of the kustomization is not known if it was built outside of kube-score
+	fl = detectFileLocation("-", 5, []byte(doc))
+	assert.Equal(t, "../base/deployment.yaml", fl.Name)
+
+	// Paths in remote bases are not in the kustomization
+	remote := strings.Replace(doc, "path: ../base/deployment.yaml", "path: deploy/deployment.yaml\n      repo: https://github.com/example/app", 1)
+	fl = detectFileLocation("kustomize://overlays/prod", 5, []byte(remote))
+	assert.Equal(t, "deploy/deployment.yaml", fl.Name)
+}
+
+func TestFileLocationFields(t *testing.T) {
+	doc := `apiVersion: v1
+kind: Pod
+metadata:
+  name: foo
+  labels:
+    app: foo
+spec:
+  containers:
+  - name: app
+    image: foo:1.0
+  - name: sidecar
+    image: bar:1.0`
+
+	fl := detectFileLocation("someName", 10, []byte(doc))
+	assert.Equal(t, ks.FilePosition{Line: 18, Column: 5}, fl.Fields["app"])
+	assert.Equal(t, ks.FilePosition{Line: 20, Column: 5}, fl.Fields["sidecar"])
+	assert.Equal(t, ks.FileLocation{Name: "someName", Line: 20, Column: 5}, fl.Lookup("sidecar"))
+	assert.Equal(t, ks.FileLocation{Name: "someName", Line: 10, Column: 1}, fl.Lookup("missing"))
+}
@@ -0,0 +1,325 @@
+// Package kubescore is the supported Go API of kube-score, for tools that embed kube-score.
+//
+// The types in this package are covered by the API stability guarantees, and are not changed in backwards
+// incompatible ways. The other packages in this module are internal to kube-score, and can change in any release.
+package kubescore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/podsecurity"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// DefaultKubernetesVersion is the version of Kubernetes that is used if Options.KubernetesVersion is not set
+const DefaultKubernetesVersion = "v1.18"
+
+// Options configures the checks. The zero value runs all default checks, in the same way as "kube-score score"
+// without any flags.
+type Options struct {
+	// KubernetesVersion is the version of Kubernetes that the objects are deployed to, on the format "v1.18"
+	KubernetesVersion string
+
+	// EnabledOptionalChecks are the IDs of the optional checks to run
+	EnabledOptionalChecks []string
+
+	// EnableAllOptionalChecks runs all optional checks, including the checks that are added in new versions
+	EnableAllOptionalChecks bool
+
+	// IgnoredChecks are the IDs of the checks that are not run
+	IgnoredChecks []string
+
+	// DisableIgnoreChecksAnnotation disables the effect of the kube-score/ignore annotation on objects
+	DisableIgnoreChecksAnnotation bool
+
+	IgnoreContainerCpuLimit    bool
+	IgnoreContainerMemoryLimit bool
+
+	// RequiredDroppedCapabilities are the capabilities that all containers must drop. If empty, ALL must be dropped.
+	RequiredDroppedCapabilities []string
+
+	// AllowedHostPaths are the paths, and the paths below them, that pods are allowed to mount as hostPath volumes
+	AllowedHostPaths []string
+
+	// AllowedImageRegistries are the registries that images are allowed to be pulled from
+	AllowedImageRegistries []string
+
+	// RequiredLabels are the labels that all workloads must have. If empty, the app.kubernetes.io labels are required.
+	RequiredLabels []string
+
+	// MaxMemoryLimitRatio is the highest allowed ratio between the memory limit and request. If zero, 2 is used.
+	MaxMemoryLimitRatio float64
+
+	// AllowedPriorityClasses are the PriorityClasses that pods are allowed to use. If empty, all are allowed.
+	AllowedPriorityClasses []string
+
+	// ReadWriteOnceStorageClasses are the StorageClasses that do not support ReadWriteMany. If empty, the default
+	// StorageClasses of GKE, EKS and AKS are used.
+	ReadWriteOnceStorageClasses []string
+
+	// PodSecurityStandard is the Pod Security Standard (privileged, baseline or restricted) that all pods must
+	// satisfy. If empty, no standard is required.
+	PodSecurityStandard string
+
+	// GradeOverrides changes the grade of failing checks, keyed by check ID
+	GradeOverrides map[string]Grade
+
+	// CustomChecks are checks that are defined as CEL expressions, see the "Custom checks" section of README.md
+	CustomChecks []CustomCheck
+
+	// Profiles are the names of the profiles of checks to run (security, reliability, cost, all, or one of
+	// CustomProfiles). If empty, the checks are not limited to a profile.
+	Profiles []string
+
+	// CustomProfiles are additional profiles, keyed by name, with the IDs of their checks
+	CustomProfiles map[string][]string
+
+	// UnknownKinds is how objects of kinds that kube-score does not support are handled ("warn", "skip" or "fail").
+	// If empty, "warn" is used.
+	UnknownKinds string
+}
+
+// CustomCheck is a check that is defined as a CEL expression, that must be true for the object to pass
+type CustomCheck struct {
+	ID      string
+	Name    string
+	Comment string
+
+	// Kinds are the kinds of objects that the check runs on. If empty, the check runs on all objects.
+	Kinds []string
+
+	Expression string
+
+	// Grade is the grade of the check when it's failing. If zero, GradeCritical is used.
+	Grade Grade
+
+	// Message and Description are templates for the comment of a failing check
+	Message     string
+	Description string
+}
+
+// Input is a file with Kubernetes objects in YAML or JSON
+type Input interface {
+	io.Reader
+	Name() string
+}
+
+type namedReader struct {
+	io.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
+
+// NewInput creates an Input that reads the objects from r. The name is used as the file name of the objects.
+func NewInput(name string, r io.Reader) Input {
+	return namedReader{Reader: r, name: name}
+}
+
+// Run parses the objects in all inputs, and runs the checks on them. Run stops, and returns the error of ctx, when ctx
+// is done.
+func Run(ctx context.Context, options Options, inputs []Input) (*Scorecard, error) {
+	cnf, err := options.configuration(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parser.ParseFiles(ctx, cnf)
+	if err != nil {
+		return nil, err
+	}
+
+	card, err := score.Score(ctx, parsed, cnf)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertScorecard(card), nil
+}
+
+// Checks returns all checks, including the optional checks
+func Checks() []Check {
+	allChecks := score.RegisterAllChecks(parser.Empty(), config.Configuration{})
+
+	var res []Check
+	for _, c := range allChecks.All() {
+		res = append(res, convertCheck(c))
+	}
+	return res
+}
+
+func (o Options) configuration(inputs []Input) (config.Configuration, error) {
+	version := o.KubernetesVersion
+	if version == "" {
+		version = DefaultKubernetesVersion
+	}
+	kubeVer, err := config.ParseSemver(version)
+	if err != nil {
+		return config.Configuration{}, fmt.Errorf("invalid KubernetesVersion %q, use the format \"vN.NN\"", version)
+	}
+
+	if o.PodSecurityStandard != "" {
+		if _, err := podsecurity.ParseLevel(o.PodSecurityStandard); err != nil {
+			return config.Configuration{}, fmt.Errorf("invalid PodSecurityStandard: %w", err)
+		}
+	}
+
+	if _, err := checks.ProfileChecks(o.Profiles, o.CustomProfiles); err != nil {
+		return config.Configuration{}, fmt.Errorf("invalid Profiles: %w", err)
+	}
+
+	switch o.UnknownKinds {
+	case "", config.UnknownKindsWarn, config.UnknownKindsSkip, config.UnknownKindsFail:
+	default:
+		return config.Configuration{}, fmt.Errorf("invalid UnknownKinds %q, must be \"warn\", \"skip\" or \"fail\"", o.UnknownKinds)
+	}
+
+	var files []ks.NamedReader
+	for _, i := range inputs {
+		files = append(files, i)
+	}
+
+	var customChecks []config.CustomCheck
+	for _, c := range o.CustomChecks {
+		cc := config.CustomCheck{
+			ID:          c.ID,
+			Name:        c.Name,
+			Comment:     c.Comment,
+			Kinds:       c.Kinds,
+			Expression:  c.Expression,
+			Message:     c.Message,
+			Description: c.Description,
+		}
+		switch c.Grade {
+		case 0:
+		case GradeCritical, GradeHigh, GradeWarning, GradeAlmostOK, GradeInfo:
+			cc.Grade = c.Grade.Severity()
+		default:
+			return config.Configuration{}, fmt.Errorf("invalid grade of custom check %q, must be GradeCritical, GradeHigh, GradeWarning, GradeAlmostOK or GradeInfo", c.ID)
+		}
+		if err := cc.Validate(); err != nil {
+			return config.Configuration{}, fmt.Errorf("invalid custom check: %w", err)
+		}
+		customChecks = append(customChecks, cc)
+	}
+
+	gradeOverrides := make(map[string]scorecard.Grade)
+	for id, grade := range o.GradeOverrides {
+		gradeOverrides[id] = scorecard.Grade(grade)
+	}
+
+	return config.Configuration{
+		AllFiles:                              files,
+		IgnoreContainerCpuLimitRequirement:    o.IgnoreContainerCpuLimit,
+		IgnoreContainerMemoryLimitRequirement: o.IgnoreContainerMemoryLimit,
+		IgnoredTests:                          toStructMap(o.IgnoredChecks),
+		EnabledOptionalTests:                  toStructMap(o.EnabledOptionalChecks),
+		EnableAllOptionalTests:                o.EnableAllOptionalChecks,
+		UseIgnoreChecksAnnotation:             !o.DisableIgnoreChecksAnnotation,
+		KubernetesVersion:                     kubeVer,
+		GradeOverrides:                        gradeOverrides,
+		RequiredDroppedCapabilities:           o.RequiredDroppedCapabilities,
+		PodSecurityStandard:                   o.PodSecurityStandard,
+		AllowedHostPaths:                      o.AllowedHostPaths,
+		AllowedImageRegistries:                o.AllowedImageRegistries,
+		RequiredLabels:                        o.RequiredLabels,
+		MaxMemoryLimitRatio:                   o.MaxMemoryLimitRatio,
+		ReadWriteOnceStorageClasses:           o.ReadWriteOnceStorageClasses,
+		AllowedPriorityClasses:                o.AllowedPriorityClasses,
+		CustomChecks:                          customChecks,
+		Profiles:                              o.Profiles,
+		CustomProfiles:                        o.CustomProfiles,
+		UnknownKinds:                          o.UnknownKinds,
+	}, nil
+}
+
+func toStructMap(items []string) map[string]struct{} {
+	res := make(map[string]struct{})
+	for _, i := range items {
+		res[i] = struct{}{}
+	}
+	return res
+}
+
+func convertScorecard(card *scorecard.Scorecard) *Scorecard {
+	var keys []string
+	for k := range *card {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := &Scorecard{}
+	for _, k := range keys {
+		o := (*card)[k]
+		obj := ScoredObject{
+			APIVersion: o.TypeMeta.APIVersion,
+			Kind:       o.TypeMeta.Kind,
+			Namespace:  o.ObjectMeta.Namespace,
+			Name:       o.ObjectMeta.Name,
+			FileName:   o.FileLocation.Name,
+			FileLine:   o.FileLocation.Line,
+		}
+		for _, c := range o.Checks {
+			obj.Checks = append(obj.Checks, convertCheckResult(o, c))
+		}
+		res.Objects = append(res.Objects, obj)
+	}
+	return res
+}
+
+func convertCheckResult(o *scorecard.ScoredObject, c scorecard.TestScore) CheckResult {
+	res := CheckResult{
+		Check:   convertCheck(c.Check),
+		Grade:   Grade(c.Grade),
+		Skipped: c.Skipped,
+	}
+	for _, comment := range c.Comments {
+		location := o.CommentFileLocation(comment)
+		res.Comments = append(res.Comments, Comment{
+			Path:             comment.Path,
+			Summary:          comment.Summary,
+			Description:      comment.Description,
+			DocumentationURL: comment.DocumentationURL,
+			FileName:         location.Name,
+			FileLine:         location.Line,
+			FileColumn:       location.Column,
+			Remediation:      convertRemediation(comment.Remediation),
+		})
+	}
+	return res
+}
+
+func convertCheck(c ks.Check) Check {
+	return Check{
+		ID:         c.ID,
+		Name:       c.Name,
+		TargetType: c.TargetType,
+		Comment:    c.Comment,
+		Optional:   c.Optional,
+	}
+}
+
+func convertRemediation(in *scorecard.Remediation) *Remediation {
+	if in == nil {
+		return nil
+	}
+	res := &Remediation{StrategicMergePatch: in.StrategicMergePatch}
+	for _, op := range in.JSONPatch {
+		res.JSONPatch = append(res.JSONPatch, JSONPatchOperation{
+			Op:    op.Op,
+			Path:  op.Path,
+			Value: op.Value,
+		})
+	}
+	return res
+}
@@ -0,0 +1,134 @@
+package kubescore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const pod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: foo
+  namespace: bar
+spec:
+  containers:
+  - name: app
+    image: foo:latest
+`
+
+func findCheck(o ScoredObject, id string) *CheckResult {
+	for _, c := range o.Checks {
+		if c.Check.ID == id {
+			return &c
+		}
+	}
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	card, err := Run(context.Background(), Options{}, []Input{NewInput("pod.yaml", strings.NewReader(pod))})
+	assert.Nil(t, err)
+	assert.Len(t, card.Objects, 1)
+	assert.True(t, card.AnyBelowOrEqualToGrade(GradeCritical))
+
+	obj := card.Objects[0]
+	assert.Equal(t, "Pod", obj.Kind)
+	assert.Equal(t, "bar", obj.Namespace)
+	assert.Equal(t, "pod.yaml", obj.FileName)
+	assert.Equal(t, 1, obj.FileLine)
+
+	tag := findCheck(obj, "container-image-tag")
+	assert.NotNil(t, tag)
+	assert.Equal(t, GradeCritical, tag.Grade)
+	assert.Equal(t, []Comment{{
+		Path:        "app",
+		Summary:     "Image with latest tag",
+		Description: "Using a fixed tag is recommended to avoid accidental upgrades",
+		FileName:    "pod.yaml",
+		FileLine:    8,
+		FileColumn:  5,
+	}}, tag.Comments)
+
+	// Optional checks are not run by default
+	assert.Nil(t, findCheck(obj, "container-seccomp-profile"))
+}
+
+func TestRunOptions(t *testing.T) {
+	t.Parallel()
+	card, err := Run(context.Background(), Options{
+		EnabledOptionalChecks: []string{"container-seccomp-profile"},
+		IgnoredChecks:         []string{"container-image-tag"},
+		GradeOverrides:        map[string]Grade{"container-resources": GradeWarning},
+	}, []Input{NewInput("pod.yaml", strings.NewReader(pod))})
+	assert.Nil(t, err)
+
+	obj := card.Objects[0]
+	assert.NotNil(t, findCheck(obj, "container-seccomp-profile"))
+	assert.Nil(t, findCheck(obj, "container-image-tag"))
+	assert.Equal(t, GradeWarning, findCheck(obj, "container-resources").Grade)
+}
+
+func TestRunAllOptionalChecks(t *testing.T) {
+	t.Parallel()
+	card, err := Run(context.Background(), Options{
+		EnableAllOptionalChecks: true,
+		IgnoredChecks:           []string{"container-seccomp-profile"},
+	}, []Input{NewInput("pod.yaml", strings.NewReader(pod))})
+	assert.Nil(t, err)
+
+	obj := card.Objects[0]
+	assert.NotNil(t, findCheck(obj, "container-image-digest"))
+	assert.Nil(t, findCheck(obj, "container-seccomp-profile"))
+}
+
+func TestRunInvalidOptions(t *testing.T) {
+	t.Parallel()
+	_, err := Run(context.Background(), Options{KubernetesVersion: "latest"}, nil)
+	assert.Error(t, err)
+	_, err = Run(context.Background(), Options{PodSecurityStandard: "foo"}, nil)
+	assert.Error(t, err)
+	_, err = Run(context.Background(), Options{Profiles: []string{"foo"}}, nil)
+	assert.Error(t, err)
+	_, err = Run(context.Background(), Options{CustomChecks: []CustomCheck{{ID: "foo", Expression: "object.spec."}}}, nil)
+	assert.Error(t, err)
+}
+
+func TestRunCustomChecks(t *testing.T) {
+	t.Parallel()
+	card, err := Run(context.Background(), Options{
+		CustomChecks: []CustomCheck{{
+			ID:         "pod-namespace",
+			Kinds:      []string{"Pod"},
+			Expression: `object.metadata.namespace != "bar"`,
+			Grade:      GradeWarning,
+			Message:    "The Pod is in the {{ object.metadata.namespace }} namespace",
+		}},
+	}, []Input{NewInput("pod.yaml", strings.NewReader(pod))})
+	assert.Nil(t, err)
+
+	check := findCheck(card.Objects[0], "pod-namespace")
+	assert.Equal(t, GradeWarning, check.Grade)
+	assert.Equal(t, "The Pod is in the bar namespace", check.Comments[0].Summary)
+}
+
+func TestRunCanceled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Run(ctx, Options{}, []Input{NewInput("pod.yaml", strings.NewReader(pod))})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestChecks(t *testing.T) {
+	t.Parallel()
+	var ids []string
+	for _, c := range Checks() {
+		ids = append(ids, c.ID)
+	}
+	assert.Contains(t, ids, "container-image-tag")
+	assert.Contains(t, ids, "container-seccomp-profile")
+}
@@ -0,0 +1,131 @@
+package kubescore
+
+import (
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Grade is the result of a check. Higher is better.
+type Grade int
+
+const (
+	GradeCritical Grade = 1
+	GradeHigh     Grade = 3
+	GradeWarning  Grade = 5
+	GradeAlmostOK Grade = 7
+	GradeInfo     Grade = 9
+	GradeAllOK    Grade = 10
+)
+
+func (g Grade) String() string {
+	switch g {
+	case GradeCritical:
+		return "CRITICAL"
+	case GradeHigh:
+		return "HIGH"
+	case GradeWarning:
+		return "WARNING"
+	case GradeInfo:
+		return "INFO"
+	case GradeAlmostOK, GradeAllOK:
+		return "OK"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Severity returns the name of the severity of the grade: "critical", "high", "medium", "low", "info" or "none"
+func (g Grade) Severity() string {
+	return scorecard.Grade(g).Severity().String()
+}
+
+// Scorecard is the result of scoring all objects in the inputs
+type Scorecard struct {
+	// Objects are sorted by kind, API version, namespace and name
+	Objects []ScoredObject
+}
+
+// AnyBelowOrEqualToGrade returns true if any object has a check that is not skipped, with a grade that is lower
+// than or equal to the threshold
+func (s Scorecard) AnyBelowOrEqualToGrade(threshold Grade) bool {
+	for _, o := range s.Objects {
+		if o.AnyBelowOrEqualToGrade(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScoredObject is a Kubernetes object, and the results of all checks that have been run on it
+type ScoredObject struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+
+	// FileName and FileLine is the location of the object in the input
+	FileName string
+	FileLine int
+
+	Checks []CheckResult
+}
+
+// AnyBelowOrEqualToGrade returns true if the object has a check that is not skipped, with a grade that is lower
+// than or equal to the threshold
+func (o ScoredObject) AnyBelowOrEqualToGrade(threshold Grade) bool {
+	for _, c := range o.Checks {
+		if !c.Skipped && c.Grade <= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// Check describes a check, see README_CHECKS.md for all checks
+type Check struct {
+	ID         string
+	Name       string
+	TargetType string
+	Comment    string
+	Optional   bool
+}
+
+// CheckResult is the outcome of running a check on an object
+type CheckResult struct {
+	Check   Check
+	Grade   Grade
+	Skipped bool
+
+	Comments []Comment
+}
+
+// Comment is a finding of a check
+type Comment struct {
+	// Path is the part of the object that the comment refers to, for example the name of a container
+	Path             string
+	Summary          string
+	Description      string
+	DocumentationURL string
+
+	// FileName, FileLine and FileColumn is the location of the part of the object that the comment refers to
+	FileName   string
+	FileLine   int
+	FileColumn int
+
+	// Remediation is a suggested fix of the finding, or nil if no fix is known
+	Remediation *Remediation
+}
+
+// Remediation is a suggested change to the object that fixes a finding. Both patches describe the same change.
+type Remediation struct {
+	// JSONPatch is a RFC 6902 JSON Patch
+	JSONPatch []JSONPatchOperation
+
+	// StrategicMergePatch is a Kubernetes strategic merge patch
+	StrategicMergePatch map[string]interface{}
+}
+
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
@@ -0,0 +1,263 @@
+// Package plugins loads checks from third-party plugins, that are compiled to WebAssembly.
+//
+// A plugin is a WASI command module, that is run by an external WebAssembly runtime (wasmtime by default), so
+// that kube-score itself has no dependency on a WebAssembly runtime. The runtime binary must be installed
+// separately when plugins are used. The module is run once to describe its checks, and once for every object that
+// it scores:
+//
+//	describe    The module is run with the argument "describe", and writes a Description as JSON to stdout.
+//	score       The module is run with the argument "score", reads a ScoreRequest as JSON from stdin, and writes
+//	            a ScoreResponse as JSON to stdout.
+//
+// A module that exits with a non-zero exit code has failed, and everything that it wrote to stderr is included in
+// the error.
+package plugins
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// ABIVersion is the version of the plugin interface. Plugins must return it in their Description.
+const ABIVersion = 1
+
+// cacheSize is the number of objects that the results of a plugin are cached for. The checks of an object are run
+// close to each other, so the results are only needed while the object is scored.
+const cacheSize = 1024
+
+// Description is written by the plugin when it's run with "describe"
+type Description struct {
+	ABIVersion int                `json:"abiVersion"`
+	Checks     []CheckDescription `json:"checks"`
+}
+
+// CheckDescription describes a check in a plugin. The ID must be unique among all checks.
+type CheckDescription struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Comment string `json:"comment"`
+
+	// Kinds are the kinds of objects that the check is run on. If empty, the check is run on all objects.
+	Kinds []string `json:"kinds"`
+
+	// Optional checks are only run if they are enabled with --enable-optional-test
+	Optional bool `json:"optional"`
+}
+
+// ScoreRequest is read by the plugin from stdin when it's run with "score"
+type ScoreRequest struct {
+	// Checks are the IDs of the checks that are enabled, and that target the kind of the object
+	Checks []string               `json:"checks"`
+	Object map[string]interface{} `json:"object"`
+}
+
+// ScoreResponse is written by the plugin when it's run with "score"
+type ScoreResponse struct {
+	Results []Result `json:"results"`
+}
+
+// Result is the outcome of a check on the object
+type Result struct {
+	Check string `json:"check"`
+
+	// Grade is one of "critical", "high", "warning" (or "medium"), "almost-ok" (or "low"), "info" or "ok"
+	Grade    string    `json:"grade"`
+	Skipped  bool      `json:"skipped"`
+	Comments []Comment `json:"comments"`
+}
+
+type Comment struct {
+	Path             string `json:"path"`
+	Summary          string `json:"summary"`
+	Description      string `json:"description"`
+	DocumentationURL string `json:"documentationUrl"`
+}
+
+// Runtime runs a WebAssembly module with the arguments, and returns what it wrote to stdout
+type Runtime interface {
+	Run(module string, args []string, stdin []byte) ([]byte, error)
+}
+
+// CommandRuntime runs the modules with the "run" command of a runtime such as wasmtime or wasmer. The command is
+// looked up in PATH if it's not a path.
+type CommandRuntime struct {
+	Command string
+}
+
+func (r CommandRuntime) Run(module string, args []string, stdin []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(r.Command, append([]string{"run", module}, args...)...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("the WebAssembly runtime %s is not installed, install it or set --plugin-runtime: %w", r.Command, err)
+		}
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// Plugin is a loaded plugin
+type Plugin struct {
+	Path   string
+	Checks []CheckDescription
+
+	runtime Runtime
+
+	// cache holds the results of all checks of the most recently scored objects, as the plugin scores all checks in
+	// a single run. The elements of lru are *cacheEntry, with the most recently used first.
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]*list.Element
+	lru   *list.List
+}
+
+// cacheEntry is the result of a run of the plugin, done is closed when the run has finished
+type cacheEntry struct {
+	key     [sha256.Size]byte
+	done    chan struct{}
+	results map[string]Result
+	err     error
+}
+
+// Load runs the plugin to describe its checks
+func Load(path string, runtime Runtime) (*Plugin, error) {
+	out, err := runtime.Run(path, []string{"describe"}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe plugin %s: %w", path, err)
+	}
+
+	var desc Description
+	if err := json.Unmarshal(out, &desc); err != nil {
+		return nil, fmt.Errorf("failed to describe plugin %s: invalid description: %w", path, err)
+	}
+	if desc.ABIVersion != ABIVersion {
+		return nil, fmt.Errorf("plugin %s uses ABI version %d, kube-score supports version %d", path, desc.ABIVersion, ABIVersion)
+	}
+	for _, c := range desc.Checks {
+		if c.ID == "" {
+			return nil, fmt.Errorf("plugin %s has a check without an id", path)
+		}
+	}
+
+	return &Plugin{
+		Path:    path,
+		Checks:  desc.Checks,
+		runtime: runtime,
+		cache:   make(map[[sha256.Size]byte]*list.Element),
+		lru:     list.New(),
+	}, nil
+}
+
+// Score returns the result of the check on the object. The plugin is run once per object, with all checks in
+// checkIDs, and the results are reused for the other checks.
+func (p *Plugin) Score(object ks.Object, checkID string, checkIDs []string) (score scorecard.TestScore) {
+	results, err := p.run(object, checkIDs)
+	if err != nil {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", "The plugin failed", fmt.Sprintf("%s: %s", p.Path, err))
+		return
+	}
+
+	res, ok := results[checkID]
+	if !ok {
+		score.Skipped = true
+		score.AddComment("", "Skipped because the plugin did not return a result", p.Path)
+		return
+	}
+
+	if res.Skipped {
+		score.Skipped = true
+	} else if score.Grade, err = scorecard.ParseGrade(res.Grade); err != nil {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", "The plugin returned an invalid grade", fmt.Sprintf("%s: %s", p.Path, err))
+		return
+	}
+
+	for _, c := range res.Comments {
+		score.AddCommentWithURL(c.Path, c.Summary, c.Description, c.DocumentationURL)
+	}
+	return
+}
+
+// run runs the plugin on the object, or returns the results of an earlier run with the same request. The plugin is
+// run once if the object is scored by multiple checks at the same time, and the lock is not held while it runs.
+func (p *Plugin) run(object ks.Object, checkIDs []string) (map[string]Result, error) {
+	req, err := json.Marshal(ScoreRequest{Checks: checkIDs, Object: object.Unstructured()})
+	if err != nil {
+		return nil, err
+	}
+
+	// The results are cached by the request, so that objects are scored again if they have changed
+	entry, found := p.lookup(sha256.Sum256(req))
+	if found {
+		<-entry.done
+		return entry.results, entry.err
+	}
+
+	entry.results, entry.err = p.score(req)
+	close(entry.done)
+
+	// Failed runs are not cached, so that the object is scored again by the next check
+	if entry.err != nil {
+		p.mu.Lock()
+		if e, ok := p.cache[entry.key]; ok && e.Value.(*cacheEntry) == entry {
+			p.lru.Remove(e)
+			delete(p.cache, entry.key)
+		}
+		p.mu.Unlock()
+	}
+	return entry.results, entry.err
+}
+
+// lookup returns the cache entry of the request, and true if it was found. Otherwise a new entry is added, and the
+// caller must run the plugin and close done. The least recently used entry is removed if the cache is full.
+func (p *Plugin) lookup(key [sha256.Size]byte) (*cacheEntry, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e, ok := p.cache[key]; ok {
+		p.lru.MoveToFront(e)
+		return e.Value.(*cacheEntry), true
+	}
+
+	entry := &cacheEntry{key: key, done: make(chan struct{})}
+	p.cache[key] = p.lru.PushFront(entry)
+	if p.lru.Len() > cacheSize {
+		oldest := p.lru.Back()
+		p.lru.Remove(oldest)
+		delete(p.cache, oldest.Value.(*cacheEntry).key)
+	}
+	return entry, false
+}
+
+// score runs the plugin with the request
+func (p *Plugin) score(req []byte) (map[string]Result, error) {
+	out, err := p.runtime.Run(p.Path, []string{"score"}, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ScoreResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+
+	results := make(map[string]Result)
+	for _, r := range resp.Results {
+		results[r.Check] = r
+	}
+	return results, nil
+}
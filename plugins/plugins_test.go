@@ -0,0 +1,222 @@
+package plugins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+type fakeRuntime struct {
+	describe string
+	score    func(req ScoreRequest) (string, error)
+
+	mu   sync.Mutex
+	runs int
+}
+
+func (f *fakeRuntime) Run(module string, args []string, stdin []byte) ([]byte, error) {
+	f.mu.Lock()
+	f.runs++
+	f.mu.Unlock()
+	if args[0] == "describe" {
+		return []byte(f.describe), nil
+	}
+	var req ScoreRequest
+	if err := json.Unmarshal(stdin, &req); err != nil {
+		return nil, err
+	}
+	out, err := f.score(req)
+	return []byte(out), err
+}
+
+type fakeObject struct {
+	name string
+	raw  map[string]interface{}
+}
+
+func (o fakeObject) GetTypeMeta() metav1.TypeMeta {
+	return metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}
+}
+
+func (o fakeObject) GetObjectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: o.name}
+}
+
+func (o fakeObject) Unstructured() map[string]interface{} {
+	return o.raw
+}
+
+func (o fakeObject) FileLocation() ks.FileLocation {
+	return ks.FileLocation{Name: "foo.yaml", Line: 1}
+}
+
+const description = `{"abiVersion": 1, "checks": [{"id": "foo", "name": "Foo"}, {"id": "bar", "kinds": ["Deployment"], "optional": true}]}`
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	p, err := Load("foo.wasm", &fakeRuntime{describe: description})
+	assert.Nil(t, err)
+	assert.Equal(t, []CheckDescription{
+		{ID: "foo", Name: "Foo"},
+		{ID: "bar", Kinds: []string{"Deployment"}, Optional: true},
+	}, p.Checks)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, desc := range []string{
+		`not json`,
+		`{"abiVersion": 2, "checks": []}`,
+		`{"abiVersion": 1, "checks": [{"name": "Foo"}]}`,
+	} {
+		_, err := Load("foo.wasm", &fakeRuntime{describe: desc})
+		assert.Error(t, err, desc)
+	}
+}
+
+func TestScore(t *testing.T) {
+	t.Parallel()
+
+	rt := &fakeRuntime{
+		describe: description,
+		score: func(req ScoreRequest) (string, error) {
+			assert.Equal(t, []string{"foo", "bar"}, req.Checks)
+			assert.Equal(t, "app", req.Object["name"])
+			return `{"results": [
+				{"check": "foo", "grade": "warning", "comments": [{"path": "app", "summary": "Foo", "description": "Not foo", "documentationUrl": "https://example.com"}]},
+				{"check": "bar", "skipped": true, "comments": [{"summary": "Skipped because bar"}]}
+			]}`, nil
+		},
+	}
+	p, err := Load("foo.wasm", rt)
+	assert.Nil(t, err)
+
+	obj := fakeObject{name: "app", raw: map[string]interface{}{"name": "app"}}
+
+	foo := p.Score(obj, "foo", []string{"foo", "bar"})
+	assert.Equal(t, scorecard.GradeWarning, foo.Grade)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "app", Summary: "Foo", Description: "Not foo", DocumentationURL: "https://example.com"}}, foo.Comments)
+
+	bar := p.Score(obj, "bar", []string{"foo", "bar"})
+	assert.True(t, bar.Skipped)
+	assert.Equal(t, "Skipped because bar", bar.Comments[0].Summary)
+
+	missing := p.Score(obj, "baz", []string{"foo", "bar"})
+	assert.True(t, missing.Skipped)
+
+	// The plugin is described once, and run once per object
+	assert.Equal(t, 2, rt.runs)
+}
+
+func TestScoreFailed(t *testing.T) {
+	t.Parallel()
+
+	rt := &fakeRuntime{
+		describe: description,
+		score: func(req ScoreRequest) (string, error) {
+			if req.Object["name"] == "invalid-grade" {
+				return `{"results": [{"check": "foo", "grade": "great"}]}`, nil
+			}
+			return "", errors.New("exit status 1: out of memory")
+		},
+	}
+	p, err := Load("foo.wasm", rt)
+	assert.Nil(t, err)
+
+	failed := p.Score(fakeObject{name: "failed", raw: map[string]interface{}{"name": "failed"}}, "foo", []string{"foo"})
+	assert.Equal(t, scorecard.GradeCritical, failed.Grade)
+	assert.Equal(t, "The plugin failed", failed.Comments[0].Summary)
+	assert.Equal(t, "foo.wasm: exit status 1: out of memory", failed.Comments[0].Description)
+
+	invalid := p.Score(fakeObject{name: "invalid-grade", raw: map[string]interface{}{"name": "invalid-grade"}}, "foo", []string{"foo"})
+	assert.Equal(t, scorecard.GradeCritical, invalid.Grade)
+	assert.Equal(t, "The plugin returned an invalid grade", invalid.Comments[0].Summary)
+}
+
+func TestLoadRuntimeNotInstalled(t *testing.T) {
+	t.Parallel()
+
+	_, err := Load("foo.wasm", CommandRuntime{Command: "kube-score-test-no-such-runtime"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the WebAssembly runtime kube-score-test-no-such-runtime is not installed")
+}
+
+func TestScoreConcurrent(t *testing.T) {
+	t.Parallel()
+
+	slowStarted := make(chan struct{})
+	releaseSlow := make(chan struct{})
+	rt := &fakeRuntime{
+		describe: description,
+		score: func(req ScoreRequest) (string, error) {
+			if req.Object["name"] == "slow" {
+				close(slowStarted)
+				<-releaseSlow
+			}
+			return `{"results": [{"check": "foo", "grade": "ok"}, {"check": "bar", "grade": "critical"}]}`, nil
+		},
+	}
+	p, err := Load("foo.wasm", rt)
+	assert.Nil(t, err)
+
+	slow := fakeObject{name: "slow", raw: map[string]interface{}{"name": "slow"}}
+	var wg sync.WaitGroup
+	for _, id := range []string{"foo", "bar"} {
+		id := id
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Score(slow, id, []string{"foo", "bar"})
+		}()
+	}
+	<-slowStarted
+
+	// Other objects are scored while the plugin runs
+	fast := p.Score(fakeObject{name: "fast", raw: map[string]interface{}{"name": "fast"}}, "foo", []string{"foo", "bar"})
+	assert.Equal(t, scorecard.GradeAllOK, fast.Grade)
+
+	close(releaseSlow)
+	wg.Wait()
+
+	// The plugin is run once for each object, also when the checks of an object are run at the same time
+	assert.Equal(t, 3, rt.runs)
+}
+
+func TestScoreCacheSize(t *testing.T) {
+	t.Parallel()
+
+	rt := &fakeRuntime{
+		describe: description,
+		score: func(req ScoreRequest) (string, error) {
+			return `{"results": [{"check": "foo", "grade": "ok"}]}`, nil
+		},
+	}
+	p, err := Load("foo.wasm", rt)
+	assert.Nil(t, err)
+
+	object := func(i int) fakeObject {
+		name := fmt.Sprintf("app-%d", i)
+		return fakeObject{name: name, raw: map[string]interface{}{"name": name}}
+	}
+	for i := 0; i <= cacheSize; i++ {
+		p.Score(object(i), "foo", []string{"foo"})
+	}
+	assert.Equal(t, cacheSize, p.lru.Len())
+	assert.Equal(t, cacheSize+2, rt.runs)
+
+	// The most recent objects are cached, and the least recently used object has been removed
+	p.Score(object(cacheSize), "foo", []string{"foo"})
+	assert.Equal(t, cacheSize+2, rt.runs)
+	p.Score(object(0), "foo", []string{"foo"})
+	assert.Equal(t, cacheSize+3, rt.runs)
+}
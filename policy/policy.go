@@ -0,0 +1,207 @@
+// Package policy exports kube-score checks as cluster policies for Kyverno and OPA Gatekeeper, so that the objects
+// that are created in a cluster are denied by the same rules as the manifests are scored with in CI.
+//
+// Only checks that look at a single object can be exported, as the admission controllers only see one object at the
+// time. The policies of pods are also applied to the workloads that create pods.
+package policy
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EngineKyverno    = "kyverno"
+	EngineGatekeeper = "gatekeeper"
+)
+
+// Options configures the exported policies
+type Options struct {
+	// Audit makes the policies report the objects that fail, instead of denying them
+	Audit bool
+}
+
+// Checks returns the IDs of the checks that can be exported as policies, sorted by ID
+func Checks() []string {
+	var res []string
+	for id := range rules {
+		res = append(res, id)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Export writes the policies of the checks to w, as YAML documents for the engine
+func Export(w io.Writer, engine string, checkIDs []string, options Options) error {
+	var selected []rule
+	for _, id := range checkIDs {
+		r, ok := rules[id]
+		if !ok {
+			return fmt.Errorf("the check %q can not be exported as a policy, must be one of: %s", id, strings.Join(Checks(), ", "))
+		}
+		selected = append(selected, r)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].check < selected[j].check
+	})
+
+	var docs []interface{}
+	switch engine {
+	case EngineKyverno:
+		for _, r := range selected {
+			docs = append(docs, kyvernoPolicy(r, options))
+		}
+	case EngineGatekeeper:
+		// The kinds of the constraints are created by the templates, so all templates are written first
+		for _, r := range selected {
+			docs = append(docs, gatekeeperTemplate(r))
+		}
+		for _, r := range selected {
+			docs = append(docs, gatekeeperConstraint(r, options))
+		}
+	default:
+		return fmt.Errorf("unknown policy engine %q, must be one of: %s, %s", engine, EngineKyverno, EngineGatekeeper)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	for _, doc := range docs {
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
+
+// policyName is the name of the policy or constraint of the check
+func policyName(r rule) string {
+	return "kube-score-" + r.check
+}
+
+func kyvernoPolicy(r rule, options Options) map[string]interface{} {
+	action := "Enforce"
+	if options.Audit {
+		action = "Audit"
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "kyverno.io/v1",
+		"kind":       "ClusterPolicy",
+		"metadata": map[string]interface{}{
+			"name": policyName(r),
+			"annotations": map[string]interface{}{
+				"kube-score/check":                r.check,
+				"policies.kyverno.io/title":       r.check,
+				"policies.kyverno.io/category":    "kube-score",
+				"policies.kyverno.io/subject":     r.target,
+				"policies.kyverno.io/description": r.message,
+			},
+		},
+		"spec": map[string]interface{}{
+			"validationFailureAction": action,
+			"background":              true,
+			"rules": []interface{}{
+				map[string]interface{}{
+					"name": r.check,
+					// Kyverno generates the rules of the workloads from the rules of Pods
+					"match": map[string]interface{}{
+						"any": []interface{}{
+							map[string]interface{}{
+								"resources": map[string]interface{}{"kinds": []interface{}{r.target}},
+							},
+						},
+					},
+					"validate": map[string]interface{}{
+						"message": r.message,
+						"pattern": r.pattern,
+					},
+				},
+			},
+		},
+	}
+}
+
+// gatekeeperKind is the kind of the constraints of the check, such as KubeScorePodHostNetwork
+func gatekeeperKind(r rule) string {
+	kind := "KubeScore"
+	for _, part := range strings.Split(r.check, "-") {
+		kind += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return kind
+}
+
+func gatekeeperTemplate(r rule) map[string]interface{} {
+	kind := gatekeeperKind(r)
+
+	rego := fmt.Sprintf("package kubescore.%s\n", strings.Replace(r.check, "-", "_", -1))
+	if r.target == "Pod" {
+		rego += podRego
+	}
+	rego += r.rego
+
+	return map[string]interface{}{
+		"apiVersion": "templates.gatekeeper.sh/v1",
+		"kind":       "ConstraintTemplate",
+		"metadata": map[string]interface{}{
+			// The name of the template must be the lowercase kind of the constraints
+			"name": strings.ToLower(kind),
+			"annotations": map[string]interface{}{
+				"kube-score/check": r.check,
+				"description":      r.message,
+			},
+		},
+		"spec": map[string]interface{}{
+			"crd": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"names": map[string]interface{}{"kind": kind},
+				},
+			},
+			"targets": []interface{}{
+				map[string]interface{}{
+					"target": "admission.k8s.gatekeeper.sh",
+					"rego":   rego,
+				},
+			},
+		},
+	}
+}
+
+func gatekeeperConstraint(r rule, options Options) map[string]interface{} {
+	action := "deny"
+	if options.Audit {
+		action = "dryrun"
+	}
+
+	kinds := []interface{}{
+		map[string]interface{}{"apiGroups": []interface{}{""}, "kinds": []interface{}{r.target}},
+	}
+	// Gatekeeper does not know which objects create pods, so the workloads are matched explicitly
+	if r.target == "Pod" {
+		kinds = []interface{}{
+			map[string]interface{}{"apiGroups": []interface{}{""}, "kinds": []interface{}{"Pod", "ReplicationController"}},
+			map[string]interface{}{"apiGroups": []interface{}{"apps"}, "kinds": []interface{}{"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet"}},
+			map[string]interface{}{"apiGroups": []interface{}{"batch"}, "kinds": []interface{}{"Job", "CronJob"}},
+		}
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+		"kind":       gatekeeperKind(r),
+		"metadata": map[string]interface{}{
+			"name": policyName(r),
+			"annotations": map[string]interface{}{
+				"kube-score/check": r.check,
+			},
+		},
+		"spec": map[string]interface{}{
+			"enforcementAction": action,
+			"match": map[string]interface{}{
+				"kinds": kinds,
+			},
+		},
+	}
+}
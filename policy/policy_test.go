@@ -0,0 +1,85 @@
+package policy
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// decode returns all YAML documents in the output
+func decode(t *testing.T, out []byte) []map[string]interface{} {
+	var res []map[string]interface{}
+	dec := yaml.NewDecoder(bytes.NewReader(out))
+	for {
+		var doc map[string]interface{}
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			return res
+		}
+		assert.NoError(t, err)
+		res = append(res, doc)
+	}
+}
+
+func TestExportKyverno(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, Export(&out, EngineKyverno, []string{"service-type", "pod-host-network"}, Options{}))
+
+	docs := decode(t, out.Bytes())
+	assert.Len(t, docs, 2)
+	assert.Equal(t, "ClusterPolicy", docs[0]["kind"])
+	assert.Equal(t, "kube-score-pod-host-network", docs[0]["metadata"].(map[string]interface{})["name"])
+	assert.Equal(t, "kube-score-service-type", docs[1]["metadata"].(map[string]interface{})["name"])
+	assert.Equal(t, "Enforce", docs[0]["spec"].(map[string]interface{})["validationFailureAction"])
+
+	rule := docs[0]["spec"].(map[string]interface{})["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"spec": map[string]interface{}{"=(hostNetwork)": "false"}}, rule["validate"].(map[string]interface{})["pattern"])
+
+	out.Reset()
+	assert.NoError(t, Export(&out, EngineKyverno, []string{"service-type"}, Options{Audit: true}))
+	assert.Contains(t, out.String(), "validationFailureAction: Audit\n")
+}
+
+func TestExportGatekeeper(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, Export(&out, EngineGatekeeper, []string{"container-image-tag", "service-type"}, Options{}))
+
+	docs := decode(t, out.Bytes())
+	assert.Len(t, docs, 4)
+
+	// The templates are written before the constraints
+	assert.Equal(t, "ConstraintTemplate", docs[0]["kind"])
+	assert.Equal(t, "kubescorecontainerimagetag", docs[0]["metadata"].(map[string]interface{})["name"])
+	assert.Equal(t, "ConstraintTemplate", docs[1]["kind"])
+	assert.Equal(t, "KubeScoreContainerImageTag", docs[2]["kind"])
+	assert.Equal(t, "kube-score-container-image-tag", docs[2]["metadata"].(map[string]interface{})["name"])
+	assert.Equal(t, "deny", docs[2]["spec"].(map[string]interface{})["enforcementAction"])
+	assert.Equal(t, "KubeScoreServiceType", docs[3]["kind"])
+
+	target := docs[0]["spec"].(map[string]interface{})["targets"].([]interface{})[0].(map[string]interface{})
+	rego := target["rego"].(string)
+	assert.Contains(t, rego, "package kubescore.container_image_tag\n")
+	assert.Contains(t, rego, "containers[container] {")
+	assert.Contains(t, rego, "image_tag(container.image) == \"latest\"")
+
+	// The rego of Services does not look for pods
+	target = docs[1]["spec"].(map[string]interface{})["targets"].([]interface{})[0].(map[string]interface{})
+	assert.NotContains(t, target["rego"], "pod_spec")
+
+	// The policies of pods also match workloads
+	assert.Contains(t, out.String(), "- Deployment\n")
+}
+
+func TestExportErrors(t *testing.T) {
+	assert.Error(t, Export(&bytes.Buffer{}, "opa", []string{"service-type"}, Options{}))
+	assert.Error(t, Export(&bytes.Buffer{}, EngineKyverno, []string{"pod-networkpolicy"}, Options{}))
+}
+
+func TestChecks(t *testing.T) {
+	ids := Checks()
+	assert.Contains(t, ids, "container-resources")
+	assert.Equal(t, "container-image-pull-policy", ids[0])
+}
@@ -0,0 +1,260 @@
+package policy
+
+// rule is the policy of a kube-score check
+type rule struct {
+	check string
+
+	// target is the kind of the objects that the check scores, Pod or Service. The policies of pods also apply to
+	// workloads.
+	target string
+
+	// message is the reason that an object is denied
+	message string
+
+	// pattern is the validate pattern of the Kyverno policy
+	pattern map[string]interface{}
+
+	// rego is the Rego of the Gatekeeper template, that adds a violation for each finding of the check. The
+	// rules of podRego can be used in the policies of pods.
+	rego string
+}
+
+// podRego finds the pod spec of pods and workloads, and all containers of the pod
+const podRego = `
+pod_spec = spec {
+  input.review.object.kind == "Pod"
+  spec := input.review.object.spec
+}
+
+pod_spec = spec {
+  input.review.object.kind == "CronJob"
+  spec := input.review.object.spec.jobTemplate.spec.template.spec
+}
+
+pod_spec = spec {
+  not input.review.object.kind == "Pod"
+  not input.review.object.kind == "CronJob"
+  spec := input.review.object.spec.template.spec
+}
+
+containers[container] {
+  container := pod_spec.initContainers[_]
+}
+
+containers[container] {
+  container := pod_spec.containers[_]
+}
+
+containers[container] {
+  container := pod_spec.ephemeralContainers[_]
+}
+`
+
+// imageTagRego finds the tag of an image in the same way as the container-image-tag check. The tag is after the
+// last colon, unless the colon is a part of the registry host (registry:5000/image).
+const imageTagRego = `
+image_tag(image) = tag {
+  parts := split(split(image, "@")[0], ":")
+  count(parts) > 1
+  tag := parts[count(parts) - 1]
+  not contains(tag, "/")
+}
+
+image_tag(image) = "" {
+  parts := split(split(image, "@")[0], ":")
+  count(parts) > 1
+  contains(parts[count(parts) - 1], "/")
+}
+
+image_tag(image) = "" {
+  not contains(split(image, "@")[0], ":")
+}
+`
+
+// containersPattern is a Kyverno pattern that matches if all containers, init containers and ephemeral containers
+// of the pod matches the pattern
+func containersPattern(container map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"spec": map[string]interface{}{
+			"=(initContainers)":      []interface{}{container},
+			"containers":             []interface{}{container},
+			"=(ephemeralContainers)": []interface{}{container},
+		},
+	}
+}
+
+// podSpecFieldRule is the rule of a check that makes sure that a boolean field of the pod spec is not true
+func podSpecFieldRule(check, field, message string) rule {
+	return rule{
+		check:   check,
+		target:  "Pod",
+		message: message,
+		pattern: map[string]interface{}{
+			"spec": map[string]interface{}{"=(" + field + ")": "false"},
+		},
+		rego: `
+violation[{"msg": msg}] {
+  pod_spec.` + field + ` == true
+  msg := "` + message + `"
+}
+`,
+	}
+}
+
+var rules = map[string]rule{
+	"container-resources": {
+		check:   "container-resources",
+		target:  "Pod",
+		message: "All containers must set CPU and memory requests and limits",
+		pattern: containersPattern(map[string]interface{}{
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "?*", "memory": "?*"},
+				"limits":   map[string]interface{}{"cpu": "?*", "memory": "?*"},
+			},
+		}),
+		rego: `
+violation[{"msg": msg}] {
+  container := containers[_]
+  resource := ["cpu", "memory"][_]
+  not container.resources.limits[resource]
+  msg := sprintf("Container %v: %v limit is not set", [container.name, resource])
+}
+
+violation[{"msg": msg}] {
+  container := containers[_]
+  resource := ["cpu", "memory"][_]
+  not container.resources.requests[resource]
+  msg := sprintf("Container %v: %v request is not set", [container.name, resource])
+}
+`,
+	},
+
+	// Kyverno patterns can not tell a registry port from a tag, so images such as registry:5000/image are allowed by
+	// the Kyverno policy
+	"container-image-tag": {
+		check:   "container-image-tag",
+		target:  "Pod",
+		message: "All images must use an explicit tag that is not latest, or be pinned to a digest",
+		pattern: containersPattern(map[string]interface{}{
+			"image": "*:* & !*:latest",
+		}),
+		rego: imageTagRego + `
+violation[{"msg": msg}] {
+  container := containers[_]
+  not contains(container.image, "@")
+  image_tag(container.image) == ""
+  msg := sprintf("Container %v: Image without tag", [container.name])
+}
+
+violation[{"msg": msg}] {
+  container := containers[_]
+  not contains(container.image, "@")
+  image_tag(container.image) == "latest"
+  msg := sprintf("Container %v: Image with latest tag", [container.name])
+}
+`,
+	},
+
+	// The Kyverno policy requires the imagePullPolicy to be set, also for images with the latest tag where Kubernetes
+	// defaults it to Always
+	"container-image-pull-policy": {
+		check:   "container-image-pull-policy",
+		target:  "Pod",
+		message: "All containers with images that are not pinned to a digest must have the imagePullPolicy Always",
+		pattern: containersPattern(map[string]interface{}{
+			"(image)":         "!*@*",
+			"imagePullPolicy": "Always",
+		}),
+		rego: imageTagRego + `
+violation[{"msg": msg}] {
+  container := containers[_]
+  not contains(container.image, "@")
+  container.imagePullPolicy != "Always"
+  msg := sprintf("Container %v: ImagePullPolicy is not set to Always", [container.name])
+}
+
+violation[{"msg": msg}] {
+  container := containers[_]
+  not contains(container.image, "@")
+  not container.imagePullPolicy
+  not latest(container.image)
+  msg := sprintf("Container %v: ImagePullPolicy is not set to Always", [container.name])
+}
+
+latest(image) {
+  image_tag(image) == ""
+}
+
+latest(image) {
+  image_tag(image) == "latest"
+}
+`,
+	},
+
+	"container-security-context-privileged": {
+		check:   "container-security-context-privileged",
+		target:  "Pod",
+		message: "Containers must not be privileged",
+		pattern: containersPattern(map[string]interface{}{
+			"=(securityContext)": map[string]interface{}{"=(privileged)": "false"},
+		}),
+		rego: `
+violation[{"msg": msg}] {
+  container := containers[_]
+  container.securityContext.privileged == true
+  msg := sprintf("Container %v: The container is privileged", [container.name])
+}
+`,
+	},
+
+	"container-security-context-privilege-escalation": {
+		check:   "container-security-context-privilege-escalation",
+		target:  "Pod",
+		message: "All containers must set securityContext.allowPrivilegeEscalation to false",
+		pattern: containersPattern(map[string]interface{}{
+			"securityContext": map[string]interface{}{"allowPrivilegeEscalation": "false"},
+		}),
+		rego: `
+violation[{"msg": msg}] {
+  container := containers[_]
+  not container.securityContext.allowPrivilegeEscalation == false
+  msg := sprintf("Container %v: The container does not disallow privilege escalation", [container.name])
+}
+`,
+	},
+
+	"container-security-context-readonlyrootfilesystem": {
+		check:   "container-security-context-readonlyrootfilesystem",
+		target:  "Pod",
+		message: "All containers must set securityContext.readOnlyRootFilesystem to true",
+		pattern: containersPattern(map[string]interface{}{
+			"securityContext": map[string]interface{}{"readOnlyRootFilesystem": "true"},
+		}),
+		rego: `
+violation[{"msg": msg}] {
+  container := containers[_]
+  not container.securityContext.readOnlyRootFilesystem == true
+  msg := sprintf("Container %v: The container has a writable root filesystem", [container.name])
+}
+`,
+	},
+
+	"pod-host-network": podSpecFieldRule("pod-host-network", "hostNetwork", "The pod must not use the host network"),
+	"pod-host-pid":     podSpecFieldRule("pod-host-pid", "hostPID", "The pod must not use the host PID namespace"),
+	"pod-host-ipc":     podSpecFieldRule("pod-host-ipc", "hostIPC", "The pod must not use the host IPC namespace"),
+
+	"service-type": {
+		check:   "service-type",
+		target:  "Service",
+		message: "Services must not be of type NodePort",
+		pattern: map[string]interface{}{
+			"spec": map[string]interface{}{"=(type)": "!NodePort"},
+		},
+		rego: `
+violation[{"msg": msg}] {
+  input.review.object.spec.type == "NodePort"
+  msg := "The service is of type NodePort"
+}
+`,
+	},
+}
@@ -0,0 +1,113 @@
+// Package checkstyle is currently considered to be in alpha status, and is not covered
+// by the API stability guarantees
+package checkstyle
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"sort"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+type checkstyle struct {
+	XMLName xml.Name `xml:"checkstyle"`
+	Version string   `xml:"version,attr"`
+	Files   []file   `xml:"file"`
+}
+
+type file struct {
+	Name   string      `xml:"name,attr"`
+	Errors []fileError `xml:"error"`
+}
+
+type fileError struct {
+	Line     int    `xml:"line,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// Output outputs the scorecard as Checkstyle XML, which is supported by many CI plugins and tools such as
+// reviewdog. The findings are grouped by file, the severities critical and high are reported as "error", medium
+// as "warning", and low and info as "info". Checks that are OK or skipped are not reported.
+func Output(scoreCard *scorecard.Scorecard) io.Reader {
+	// Print the items sorted by scorecard key
+	var keys []string
+	for k := range *scoreCard {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := checkstyle{Version: "4.3"}
+
+	// The files are in the order of their first object
+	fileIndex := make(map[string]int)
+
+	for _, key := range keys {
+		scoredObject := (*scoreCard)[key]
+
+		for _, card := range scoredObject.Checks {
+			if card.Skipped {
+				continue
+			}
+
+			var severity string
+			switch {
+			case card.Grade <= scorecard.GradeHigh:
+				severity = "error"
+			case card.Grade <= scorecard.GradeWarning:
+				severity = "warning"
+			case card.Grade < scorecard.GradeAllOK:
+				severity = "info"
+			default:
+				continue
+			}
+
+			name := scoredObject.FileLocation.Name
+			i, ok := fileIndex[name]
+			if !ok {
+				i = len(res.Files)
+				fileIndex[name] = i
+				res.Files = append(res.Files, file{Name: name})
+			}
+
+			source := "kube-score." + card.Check.ID
+
+			if len(card.Comments) == 0 {
+				res.Files[i].Errors = append(res.Files[i].Errors, fileError{
+					Line:     scoredObject.FileLocation.Line,
+					Severity: severity,
+					Message:  scoredObject.HumanFriendlyRef() + ": " + card.Check.Name,
+					Source:   source,
+				})
+			}
+
+			for _, comment := range card.Comments {
+				message := scoredObject.HumanFriendlyRef() + ": " + comment.Summary
+				if comment.Path != "" {
+					message = scoredObject.HumanFriendlyRef() + ": (" + comment.Path + ") " + comment.Summary
+				}
+				if comment.Description != "" {
+					message += ": " + comment.Description
+				}
+				res.Files[i].Errors = append(res.Files[i].Errors, fileError{
+					Line:     scoredObject.CommentFileLocation(comment).Line,
+					Severity: severity,
+					Message:  message,
+					Source:   source,
+				})
+			}
+		}
+	}
+
+	w := bytes.NewBufferString(xml.Header)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "    ")
+	if err := enc.Encode(res); err != nil {
+		panic(err)
+	}
+	w.WriteString("\n")
+	return w
+}
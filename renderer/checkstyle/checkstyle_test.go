@@ -0,0 +1,58 @@
+package checkstyle
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/renderer/internal/testcard"
+	"github.com/zegl/kube-score/scorecard"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getTestCard returns the shared test scorecard, with an ID on the failed check
+func getTestCard() *scorecard.Scorecard {
+	card := testcard.New()
+	(*card)["a"].Checks[0].Check.ID = "test-warning"
+	return card
+}
+
+func TestCheckstyleOutput(t *testing.T) {
+	t.Parallel()
+	all, err := ioutil.ReadAll(Output(getTestCard()))
+	assert.Nil(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+    <file name="foo.yaml">
+        <error line="12" severity="warning" message="foo/foofoo v1/Testing: (a) summary: description" source="kube-score.test-warning"></error>
+        <error line="12" severity="warning" message="foo/foofoo v1/Testing: summary: description" source="kube-score.test-warning"></error>
+    </file>
+    <file name="">
+        <error line="0" severity="warning" message="bar-no-namespace v1/Testing: (a) summary: description" source="kube-score.test-warning"></error>
+        <error line="0" severity="warning" message="bar-no-namespace v1/Testing: summary: description" source="kube-score.test-warning"></error>
+    </file>
+</checkstyle>
+`, string(all))
+}
+
+func TestCheckstyleOutputSeverities(t *testing.T) {
+	t.Parallel()
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:     v1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta:   v1.ObjectMeta{Name: "foo"},
+			FileLocation: domain.FileLocation{Name: "pod.yaml", Line: 1},
+			Checks: []scorecard.TestScore{
+				{Check: domain.Check{Name: "Critical", ID: "critical"}, Grade: scorecard.GradeCritical},
+				{Check: domain.Check{Name: "Almost OK", ID: "almost-ok"}, Grade: scorecard.GradeAlmostOK},
+				{Check: domain.Check{Name: "OK", ID: "ok"}, Grade: scorecard.GradeAllOK},
+			},
+		},
+	}
+	all, err := ioutil.ReadAll(Output(card))
+	assert.Nil(t, err)
+	assert.Contains(t, string(all), `<error line="1" severity="error" message="foo v1/Pod: Critical" source="kube-score.critical"></error>`)
+	assert.Contains(t, string(all), `<error line="1" severity="info" message="foo v1/Pod: Almost OK" source="kube-score.almost-ok"></error>`)
+	assert.NotContains(t, string(all), `kube-score.ok"`)
+}
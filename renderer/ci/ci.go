@@ -11,8 +11,19 @@ import (
 	"github.com/zegl/kube-score/scorecard"
 )
 
+// Options configure the CI output
+type Options struct {
+	// ShowScore also writes the score of the run, as a last line on the format "[SCORE] 7.5"
+	ShowScore bool
+}
+
 // "Machine" / CI friendly output
 func CI(scoreCard *scorecard.Scorecard) io.Reader {
+	return CIWithOptions(scoreCard, Options{})
+}
+
+// CIWithOptions writes the CI output in the same way as CI, configured by options
+func CIWithOptions(scoreCard *scorecard.Scorecard, options Options) io.Reader {
 	w := bytes.NewBufferString("")
 
 	// Print the items sorted by scorecard key
@@ -44,6 +55,9 @@ func CI(scoreCard *scorecard.Scorecard) io.Reader {
 				if comment.Path != "" {
 					message = "(" + comment.Path + ") " + comment.Summary
 				}
+				if location := scoredObject.CommentFileLocation(comment); location.Name != "" {
+					message += fmt.Sprintf(" (%s:%d)", location.Name, location.Line)
+				}
 
 				if card.Skipped {
 					fmt.Fprintf(w, "[SKIPPED] %s: %s\n",
@@ -61,5 +75,9 @@ func CI(scoreCard *scorecard.Scorecard) io.Reader {
 		}
 	}
 
+	if options.ShowScore && len(keys) > 0 {
+		fmt.Fprintf(w, "[SCORE] %.1f\n", scoreCard.Score())
+	}
+
 	return w
 }
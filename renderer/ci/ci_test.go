@@ -2,6 +2,7 @@ package ci
 
 import (
 	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -108,4 +109,9 @@ func TestCiOutput(t *testing.T) {
 [SKIPPED] bar-no-namespace v1/Testing: (a) skipped sum
 [SKIPPED] bar-no-namespace v1/Testing
 `, string(all))
+
+	r = CIWithOptions(getTestCard(), Options{ShowScore: true})
+	all, err = ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasSuffix(string(all), "[SKIPPED] bar-no-namespace v1/Testing\n[SCORE] 7.5\n"))
 }
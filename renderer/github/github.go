@@ -0,0 +1,110 @@
+// Package github is currently considered to be in alpha status, and is not covered
+// by the API stability guarantees
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Output outputs the scorecard as GitHub Actions workflow commands, which are displayed as
+// annotations on the pull request diff.
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions
+func Output(scoreCard *scorecard.Scorecard) io.Reader {
+	w := bytes.NewBufferString("")
+
+	// Print the items sorted by scorecard key
+	var keys []string
+	for k := range *scoreCard {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		scoredObject := (*scoreCard)[key]
+
+		for _, card := range scoredObject.Checks {
+			if card.Skipped {
+				continue
+			}
+
+			var command string
+			switch {
+			case card.Grade <= scorecard.GradeHigh:
+				command = "error"
+			case card.Grade <= scorecard.GradeWarning:
+				command = "warning"
+			default:
+				continue
+			}
+
+			properties := func(location ks.FileLocation) string {
+				return fmt.Sprintf("file=%s,line=%d,title=%s",
+					escapeProperty(relativePath(location.Name)),
+					location.Line,
+					escapeProperty(card.Check.Name),
+				)
+			}
+
+			if len(card.Comments) == 0 {
+				fmt.Fprintf(w, "::%s %s::%s\n", command, properties(scoredObject.FileLocation), escapeData(scoredObject.HumanFriendlyRef()))
+			}
+
+			for _, comment := range card.Comments {
+				message := scoredObject.HumanFriendlyRef() + ": " + comment.Summary
+				if comment.Path != "" {
+					message = scoredObject.HumanFriendlyRef() + ": (" + comment.Path + ") " + comment.Summary
+				}
+				if comment.Description != "" {
+					message += "\n" + comment.Description
+				}
+				fmt.Fprintf(w, "::%s %s::%s\n", command, properties(scoredObject.CommentFileLocation(comment)), escapeData(message))
+			}
+		}
+	}
+
+	if len(keys) > 0 {
+		fmt.Fprintf(w, "::notice title=kube-score::Score: %.1f/10\n", scoreCard.Score())
+	}
+
+	return w
+}
+
+// relativePath makes absolute paths relative to the working directory, as GitHub expects
+// paths to be relative to the root of the repository.
+func relativePath(name string) string {
+	if !filepath.IsAbs(name) {
+		return name
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return name
+	}
+	rel, err := filepath.Rel(wd, name)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return name
+	}
+	return filepath.ToSlash(rel)
+}
+
+func escapeData(s string) string {
+	s = strings.Replace(s, "%", "%25", -1)
+	s = strings.Replace(s, "\r", "%0D", -1)
+	s = strings.Replace(s, "\n", "%0A", -1)
+	return s
+}
+
+func escapeProperty(s string) string {
+	s = escapeData(s)
+	s = strings.Replace(s, ":", "%3A", -1)
+	s = strings.Replace(s, ",", "%2C", -1)
+	return s
+}
@@ -0,0 +1,22 @@
+package github
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zegl/kube-score/renderer/internal/testcard"
+)
+
+func TestGithubOutput(t *testing.T) {
+	t.Parallel()
+	r := Output(testcard.New())
+	all, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, `::warning file=foo.yaml,line=12,title=test-warning-two-comments::foo/foofoo v1/Testing: (a) summary%0Adescription
+::warning file=foo.yaml,line=12,title=test-warning-two-comments::foo/foofoo v1/Testing: summary%0Adescription
+::warning file=,line=0,title=test-warning-two-comments::bar-no-namespace v1/Testing: (a) summary%0Adescription
+::warning file=,line=0,title=test-warning-two-comments::bar-no-namespace v1/Testing: summary%0Adescription
+::notice title=kube-score::Score: 7.5/10
+`, string(all))
+}
@@ -0,0 +1,211 @@
+// Package html is currently considered to be in alpha status, and is not covered
+// by the API stability guarantees
+package html
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"sort"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// checksDocumentationURL is the documentation of all checks, that the check names link to
+const checksDocumentationURL = "https://github.com/zegl/kube-score/blob/master/README_CHECKS.md"
+
+type report struct {
+	Score      float64
+	Objects    int
+	Critical   int
+	Warning    int
+	Namespaces []string
+	Kinds      []string
+	Checks     []string
+	Rows       []row
+	ChecksURL  string
+}
+
+// row is a comment of a check on an object, or the check itself if it has no comments
+type row struct {
+	Namespace   string
+	Kind        string
+	Object      string
+	File        string
+	Check       string
+	Grade       string
+	Class       string
+	Path        string
+	Summary     string
+	Description string
+	URL         string
+}
+
+// Output outputs the scorecard as a self-contained HTML report, that can be published as a CI artifact. The
+// findings can be filtered by namespace, kind, check and grade in the browser. Skipped checks are not included.
+func Output(scoreCard *scorecard.Scorecard) io.Reader {
+	w := bytes.NewBufferString("")
+
+	// Print the items sorted by scorecard key
+	var keys []string
+	for k := range *scoreCard {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rep := report{
+		Score:     scoreCard.Score(),
+		Objects:   len(keys),
+		ChecksURL: checksDocumentationURL,
+	}
+	namespaces := make(map[string]struct{})
+	kinds := make(map[string]struct{})
+	checks := make(map[string]struct{})
+
+	for _, key := range keys {
+		scoredObject := (*scoreCard)[key]
+		kind := scoredObject.TypeMeta.APIVersion + "/" + scoredObject.TypeMeta.Kind
+		namespaces[scoredObject.ObjectMeta.Namespace] = struct{}{}
+		kinds[kind] = struct{}{}
+
+		for _, card := range scoredObject.Checks {
+			if card.Skipped {
+				continue
+			}
+			checks[card.Check.Name] = struct{}{}
+
+			switch {
+			case card.Grade <= scorecard.GradeCritical:
+				rep.Critical++
+			case card.Grade <= scorecard.GradeWarning:
+				rep.Warning++
+			}
+
+			r := row{
+				Namespace: scoredObject.ObjectMeta.Namespace,
+				Kind:      kind,
+				Object:    scoredObject.ObjectMeta.Name,
+				File:      scoredObject.FileLocation.Name,
+				Check:     card.Check.Name,
+				Grade:     card.Grade.String(),
+				Class:     gradeClass(card.Grade),
+			}
+			if len(card.Comments) == 0 {
+				rep.Rows = append(rep.Rows, r)
+			}
+			for _, comment := range card.Comments {
+				c := r
+				c.Path = comment.Path
+				c.Summary = comment.Summary
+				c.Description = comment.Description
+				c.URL = comment.DocumentationURL
+				rep.Rows = append(rep.Rows, c)
+			}
+		}
+	}
+
+	rep.Namespaces = sortedKeys(namespaces)
+	rep.Kinds = sortedKeys(kinds)
+	rep.Checks = sortedKeys(checks)
+
+	if err := reportTemplate.Execute(w, rep); err != nil {
+		panic(err)
+	}
+
+	return w
+}
+
+func gradeClass(grade scorecard.Grade) string {
+	switch {
+	case grade <= scorecard.GradeCritical:
+		return "critical"
+	case grade <= scorecard.GradeHigh:
+		return "high"
+	case grade < scorecard.GradeAllOK:
+		return "warning"
+	default:
+		return "ok"
+	}
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	var res []string
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// The namespaces are prefixed with "ns:" in the filter, as objects without a namespace would otherwise match "All"
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>kube-score report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1 { margin-bottom: 0.2em; }
+.summary { margin-bottom: 1.5em; color: #57606a; }
+.filters { margin-bottom: 1em; }
+.filters label { margin-right: 1em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #d0d7de; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.grade { font-weight: bold; white-space: nowrap; }
+tr.critical .grade { color: #fff; background: #cf222e; }
+tr.high .grade { color: #fff; background: #bc4c00; }
+tr.warning .grade { color: #24292f; background: #f2cc60; }
+tr.ok .grade { color: #fff; background: #2da44e; }
+.description { color: #57606a; white-space: pre-wrap; }
+.path { font-family: monospace; }
+</style>
+</head>
+<body>
+<h1>kube-score</h1>
+<div class="summary">Score: <strong>{{printf "%.1f" .Score}}/10</strong> · {{.Objects}} objects · {{.Critical}} critical · {{.Warning}} warnings</div>
+<div class="filters">
+<label>Namespace <select id="namespace"><option value="">All</option>{{range .Namespaces}}<option value="ns:{{.}}">{{if .}}{{.}}{{else}}(none){{end}}</option>{{end}}</select></label>
+<label>Kind <select id="kind"><option value="">All</option>{{range .Kinds}}<option>{{.}}</option>{{end}}</select></label>
+<label>Check <select id="check"><option value="">All</option>{{range .Checks}}<option>{{.}}</option>{{end}}</select></label>
+<label>Grade <select id="grade"><option value="">All</option><option value="critical">Critical</option><option value="high">High</option><option value="warning">Warning</option><option value="ok">OK</option></select></label>
+</div>
+<table>
+<thead><tr><th>Grade</th><th>Namespace</th><th>Kind</th><th>Object</th><th>Check</th><th>Finding</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr class="{{.Class}}" data-namespace="ns:{{.Namespace}}" data-kind="{{.Kind}}" data-check="{{.Check}}" data-grade="{{.Class}}">
+<td class="grade">{{.Grade}}</td>
+<td>{{.Namespace}}</td>
+<td>{{.Kind}}</td>
+<td>{{.Object}}{{if .File}}<br><small>{{.File}}</small>{{end}}</td>
+<td><a href="{{$.ChecksURL}}">{{.Check}}</a></td>
+<td>{{if .Path}}<span class="path">{{.Path}}</span>: {{end}}{{.Summary}}{{if .Description}}<div class="description">{{.Description}}</div>{{end}}{{if .URL}}<a href="{{.URL}}">More information</a>{{end}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+(function () {
+  var filters = ["namespace", "kind", "check", "grade"];
+  function update() {
+    var rows = document.querySelectorAll("tbody tr");
+    for (var i = 0; i < rows.length; i++) {
+      var visible = true;
+      for (var j = 0; j < filters.length; j++) {
+        var value = document.getElementById(filters[j]).value;
+        if (value !== "" && rows[i].getAttribute("data-" + filters[j]) !== value) {
+          visible = false;
+        }
+      }
+      rows[i].style.display = visible ? "" : "none";
+    }
+  }
+  for (var i = 0; i < filters.length; i++) {
+    document.getElementById(filters[i]).addEventListener("change", update);
+  }
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,63 @@
+package html
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/renderer/internal/testcard"
+	"github.com/zegl/kube-score/scorecard"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getTestCard returns the shared test scorecard, with a documentation URL on the first comment
+func getTestCard() *scorecard.Scorecard {
+	card := testcard.New()
+	(*card)["a"].Checks[0].Comments[0].DocumentationURL = "https://kube-score.com/whatever"
+	return card
+}
+
+func TestHTMLOutput(t *testing.T) {
+	t.Parallel()
+	all, err := ioutil.ReadAll(Output(getTestCard()))
+	assert.Nil(t, err)
+	out := string(all)
+
+	assert.Contains(t, out, "Score: <strong>7.5/10</strong> · 2 objects · 0 critical · 2 warnings")
+
+	// Filters
+	assert.Contains(t, out, `<option value="ns:">(none)</option><option value="ns:foofoo">foofoo</option>`)
+	assert.Contains(t, out, `<option>v1/Testing</option>`)
+	assert.Contains(t, out, `<option>test-ok-comment</option><option>test-warning-two-comments</option>`)
+
+	assert.Contains(t, out, `<tr class="warning" data-namespace="ns:foofoo" data-kind="v1/Testing" data-check="test-warning-two-comments" data-grade="warning">`)
+	assert.Contains(t, out, `<tr class="ok" data-namespace="ns:" data-kind="v1/Testing" data-check="test-ok-comment" data-grade="ok">`)
+	assert.Contains(t, out, `<span class="path">a</span>: summary<div class="description">description</div><a href="https://kube-score.com/whatever">More information</a>`)
+
+	// Skipped checks are not included
+	assert.NotContains(t, out, "test-skipped-comment")
+}
+
+func TestHTMLOutputEscaping(t *testing.T) {
+	t.Parallel()
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   v1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: v1.ObjectMeta{Name: "foo"},
+			Checks: []scorecard.TestScore{
+				{
+					Check: domain.Check{Name: "script"},
+					Grade: scorecard.GradeCritical,
+					Comments: []scorecard.TestScoreComment{
+						{Summary: "<script>alert(1)</script>"},
+					},
+				},
+			},
+		},
+	}
+	all, err := ioutil.ReadAll(Output(card))
+	assert.Nil(t, err)
+	assert.Contains(t, string(all), "&lt;script&gt;alert(1)&lt;/script&gt;")
+	assert.NotContains(t, string(all), "<script>alert(1)</script>")
+}
@@ -15,19 +15,81 @@ import (
 	"github.com/zegl/kube-score/scorecard"
 )
 
+const (
+	GroupByObject = "object"
+	GroupByCheck  = "check"
+)
+
+// Options changes how the scorecard is written
+type Options struct {
+	// GroupBy is GroupByObject to list the checks of each object, or GroupByCheck to list the objects of each check,
+	// with the checks that fail on the most objects first. The output is grouped by object by default.
+	GroupBy string
+
+	// ShowPassed and ShowSkipped also write the checks that have passed, and the checks that have been skipped. By
+	// default, only the failing checks are written, and the objects without any failing checks are not written.
+	ShowPassed  bool
+	ShowSkipped bool
+
+	// SummaryOnly only writes a table with the number of objects, and the number of checks with each grade
+	SummaryOnly bool
+
+	// NoEmoji writes the status of each object as text instead of as an emoji, and only uses ASCII characters in
+	// the decorations of the output
+	NoEmoji bool
+}
+
+// Human writes the failing checks of each object. The file locations of the findings are written if verboseOutput is
+// 1 or higher.
 func Human(scoreCard *scorecard.Scorecard, verboseOutput int, termWidth int) io.Reader {
-	// Print the items sorted by scorecard key
+	return HumanWithOptions(scoreCard, verboseOutput, termWidth, Options{})
+}
+
+// HumanWithOptions writes the scorecard grouped by object or by check, or only the summary of the scorecard
+func HumanWithOptions(scoreCard *scorecard.Scorecard, verboseOutput int, termWidth int, options Options) io.Reader {
+	switch {
+	case options.SummaryOnly:
+		return summary(scoreCard)
+	case options.GroupBy == GroupByCheck:
+		return groupByCheck(scoreCard, verboseOutput, termWidth, options)
+	default:
+		return groupByObject(scoreCard, verboseOutput, termWidth, options)
+	}
+}
+
+// sortedKeys returns the keys of the objects in the scorecard, sorted
+func sortedKeys(scoreCard *scorecard.Scorecard) []string {
 	var keys []string
 	for k := range *scoreCard {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
+	return keys
+}
+
+// objectName is the name of the object in the output, such as "apps/v1/Deployment foo in bar"
+func objectName(scoredObject *scorecard.ScoredObject) string {
+	name := fmt.Sprintf("%s/%s %s", scoredObject.TypeMeta.APIVersion, scoredObject.TypeMeta.Kind, scoredObject.ObjectMeta.Name)
+	if scoredObject.ObjectMeta.Namespace != "" {
+		name += fmt.Sprintf(" in %s", scoredObject.ObjectMeta.Namespace)
+	}
+	return name
+}
+
+func groupByObject(scoreCard *scorecard.Scorecard, verboseOutput int, termWidth int, options Options) io.Reader {
+	// Print the items sorted by scorecard key
+	keys := sortedKeys(scoreCard)
 
 	w := bytes.NewBufferString("")
 
 	for _, key := range keys {
 		scoredObject := (*scoreCard)[key]
 
+		// Objects without any checks to write are not written at all
+		if !anyWritten(scoredObject, options) {
+			continue
+		}
+
 		// Headers for each object
 		var writtenHeaderChars int
 		writtenHeaderChars, _ = color.New(color.FgMagenta).Fprintf(w, "%s/%s %s", scoredObject.TypeMeta.APIVersion, scoredObject.TypeMeta.Kind, scoredObject.ObjectMeta.Name)
@@ -36,31 +98,169 @@ func Human(scoreCard *scorecard.Scorecard, verboseOutput int, termWidth int) io.
 			writtenHeaderChars += written2
 		}
 
+		status, statusWidth := objectStatus(scoredObject, options.NoEmoji)
+
 		// Adjust to termsize
-		fmt.Fprintf(w, safeRepeat(" ", min(80, termWidth)-writtenHeaderChars-2))
+		fmt.Fprint(w, safeRepeat(" ", min(80, termWidth)-writtenHeaderChars-statusWidth))
+		fmt.Fprintf(w, "%s\n", status)
 
-		if scoredObject.AnyBelowOrEqualToGrade(scorecard.GradeCritical) {
-			fmt.Fprintf(w, "💥\n")
-		} else if scoredObject.AnyBelowOrEqualToGrade(scorecard.GradeWarning) {
-			fmt.Fprintf(w, "🤔\n")
-		} else {
-			fmt.Fprintf(w, "✅\n")
+		for _, card := range scoredObject.Checks {
+			r := outputHumanStep(card, card.Check.Name, verboseOutput, termWidth, options)
+			io.Copy(w, r)
 		}
+	}
 
+	if len(keys) > 0 {
+		fmt.Fprintf(w, "\nScore: %.1f/10\n", scoreCard.Score())
+	}
+
+	return w
+}
+
+// objectStatus returns the status of the object as an emoji, or as text with noEmoji, and the number of columns
+// that it takes up in a terminal
+func objectStatus(scoredObject *scorecard.ScoredObject, noEmoji bool) (string, int) {
+	var emoji, text string
+	if scoredObject.AnyBelowOrEqualToGrade(scorecard.GradeCritical) {
+		emoji, text = "💥", "[CRITICAL]"
+	} else if scoredObject.AnyBelowOrEqualToGrade(scorecard.GradeWarning) {
+		emoji, text = "🤔", "[WARNING]"
+	} else {
+		emoji, text = "✅", "[OK]"
+	}
+
+	if noEmoji {
+		return text, len(text)
+	}
+	// The emojis are two columns wide
+	return emoji, 2
+}
+
+// checkGroup is the results of a check on all objects
+type checkGroup struct {
+	name    string
+	objects []*scorecard.ScoredObject
+	cards   []scorecard.TestScore
+
+	// failing is the number of objects with a WARNING or CRITICAL grade
+	failing int
+}
+
+func groupByCheck(scoreCard *scorecard.Scorecard, verboseOutput int, termWidth int, options Options) io.Reader {
+	keys := sortedKeys(scoreCard)
+
+	groups := make(map[string]*checkGroup)
+	var names []string
+	for _, key := range keys {
+		scoredObject := (*scoreCard)[key]
 		for _, card := range scoredObject.Checks {
-			r := outputHumanStep(card, verboseOutput, termWidth)
+			// Only include the checks that would have been written when grouping by object
+			if !isWritten(card, options) {
+				continue
+			}
+			g, ok := groups[card.Check.Name]
+			if !ok {
+				g = &checkGroup{name: card.Check.Name}
+				groups[card.Check.Name] = g
+				names = append(names, card.Check.Name)
+			}
+			g.objects = append(g.objects, scoredObject)
+			g.cards = append(g.cards, card)
+			if !card.Skipped && card.Grade <= scorecard.GradeWarning {
+				g.failing++
+			}
+		}
+	}
+
+	// The checks that fail on the most objects are written first
+	sort.Slice(names, func(i, j int) bool {
+		a, b := groups[names[i]], groups[names[j]]
+		if a.failing != b.failing {
+			return a.failing > b.failing
+		}
+		return a.name < b.name
+	})
+
+	w := bytes.NewBufferString("")
+
+	for _, name := range names {
+		g := groups[name]
+		color.New(color.FgMagenta).Fprint(w, g.name)
+		if len(g.objects) == 1 {
+			fmt.Fprintf(w, " (1 object)\n")
+		} else {
+			fmt.Fprintf(w, " (%d objects)\n", len(g.objects))
+		}
+
+		for i, card := range g.cards {
+			r := outputHumanStep(card, objectName(g.objects[i]), verboseOutput, termWidth, options)
 			io.Copy(w, r)
 		}
 	}
 
+	if len(keys) > 0 {
+		fmt.Fprintf(w, "\nScore: %.1f/10\n", scoreCard.Score())
+	}
+
 	return w
 }
 
-func outputHumanStep(card scorecard.TestScore, verboseOutput int, termWidth int) io.Reader {
+// summary writes the number of objects, and the number of checks with each grade, as a table
+func summary(scoreCard *scorecard.Scorecard) io.Reader {
+	var critical, high, warning, ok, skipped int
+	for _, scoredObject := range *scoreCard {
+		for _, card := range scoredObject.Checks {
+			switch {
+			case card.Skipped:
+				skipped++
+			case card.Grade <= scorecard.GradeCritical:
+				critical++
+			case card.Grade <= scorecard.GradeHigh:
+				high++
+			case card.Grade <= scorecard.GradeWarning:
+				warning++
+			default:
+				ok++
+			}
+		}
+	}
+
 	w := bytes.NewBufferString("")
+	row := func(col color.Attribute, title string, count int) {
+		color.New(col).Fprintf(w, "%-16s", title)
+		fmt.Fprintf(w, "%d\n", count)
+	}
+	row(color.FgMagenta, "Objects scored", len(*scoreCard))
+	row(color.FgRed, "Critical", critical)
+	row(color.FgRed, "High", high)
+	row(color.FgYellow, "Warning", warning)
+	row(color.FgGreen, "OK", ok)
+	row(color.FgGreen, "Skipped", skipped)
+	fmt.Fprintf(w, "\nScore: %.1f/10\n", scoreCard.Score())
+
+	return w
+}
+
+// isWritten reports whether the result of a check is written with the options. Failing checks are always written.
+func isWritten(card scorecard.TestScore, options Options) bool {
+	return scorecard.Filter{ShowPassed: options.ShowPassed, ShowSkipped: options.ShowSkipped}.Shows(card)
+}
+
+// anyWritten reports whether the result of any of the checks of the object is written with the options
+func anyWritten(scoredObject *scorecard.ScoredObject, options Options) bool {
+	for _, card := range scoredObject.Checks {
+		if isWritten(card, options) {
+			return true
+		}
+	}
+	return false
+}
 
-	// Only print skipped items if verbosity is at least 2
-	if card.Skipped && verboseOutput < 2 {
+// outputHumanStep writes the result of a check with the title, which is the name of the check or of the object
+func outputHumanStep(card scorecard.TestScore, title string, verboseOutput int, termWidth int, options Options) io.Reader {
+	w := bytes.NewBufferString("")
+
+	if !isWritten(card, options) {
 		return w
 	}
 
@@ -69,12 +269,6 @@ func outputHumanStep(card scorecard.TestScore, verboseOutput int, termWidth int)
 	if card.Skipped || card.Grade >= scorecard.GradeAllOK {
 		// Higher than or equal to --threshold-ok
 		col = color.FgGreen
-
-		// If verbose output is disabled, skip OK items in the output
-		if verboseOutput == 0 {
-			return w
-		}
-
 	} else if card.Grade >= scorecard.GradeWarning {
 		// Higher than or equal to --threshold-warning
 		col = color.FgYellow
@@ -84,13 +278,18 @@ func outputHumanStep(card scorecard.TestScore, verboseOutput int, termWidth int)
 	}
 
 	if card.Skipped {
-		color.New(col).Fprintf(w, "    [SKIPPED] %s\n", card.Check.Name)
+		color.New(col).Fprintf(w, "    [SKIPPED] %s\n", title)
 	} else {
-		color.New(col).Fprintf(w, "    [%s] %s\n", card.Grade.String(), card.Check.Name)
+		color.New(col).Fprintf(w, "    [%s] %s\n", card.Grade.String(), title)
+	}
+
+	bullet := "·"
+	if options.NoEmoji {
+		bullet = "-"
 	}
 
 	for _, comment := range card.Comments {
-		fmt.Fprintf(w, "        · ")
+		fmt.Fprintf(w, "        %s ", bullet)
 
 		if len(comment.Path) > 0 {
 			fmt.Fprintf(w, "%s -> ", comment.Path)
@@ -106,7 +305,7 @@ func outputHumanStep(card scorecard.TestScore, verboseOutput int, termWidth int)
 			wrapper := wordwrap.Wrapper(wrapWidth, false)
 			wrapped := wrapper(comment.Description)
 			fmt.Fprintln(w)
-			fmt.Fprintf(w, wordwrap.Indent(wrapped, strings.Repeat(" ", 12), false))
+			fmt.Fprint(w, wordwrap.Indent(wrapped, strings.Repeat(" ", 12), false))
 		}
 
 		if len(comment.DocumentationURL) > 0 {
@@ -114,6 +313,11 @@ func outputHumanStep(card scorecard.TestScore, verboseOutput int, termWidth int)
 			fmt.Fprintf(w, "%sMore information: %s", strings.Repeat(" ", 12), comment.DocumentationURL)
 		}
 
+		if location := comment.FileLocation; location.Name != "" && verboseOutput > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintf(w, "%sLocation: %s:%d:%d", strings.Repeat(" ", 12), location.Name, location.Line, location.Column)
+		}
+
 		fmt.Fprintln(w)
 	}
 
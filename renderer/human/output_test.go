@@ -112,12 +112,14 @@ v1/Testing bar-no-namespace                                                   
         · summary
             description
             More information: https://kube-score.com/whatever
+
+Score: 7.5/10
 `, string(all))
 }
 
-func TestHumanOutputVerbose1(t *testing.T) {
+func TestHumanOutputShowPassed(t *testing.T) {
 	t.Parallel()
-	r := Human(getTestCard(), 1, 100)
+	r := HumanWithOptions(getTestCard(), 0, 100, Options{ShowPassed: true})
 	all, err := ioutil.ReadAll(r)
 	assert.Nil(t, err)
 	assert.Equal(t, `v1/Testing foo in foofoo                                                      🤔
@@ -140,12 +142,14 @@ v1/Testing bar-no-namespace                                                   
     [OK] test-ok-comment
         · a -> summary
             description
+
+Score: 7.5/10
 `, string(all))
 }
 
-func TestHumanOutputVerbose2(t *testing.T) {
+func TestHumanOutputShowPassedAndSkipped(t *testing.T) {
 	t.Parallel()
-	r := Human(getTestCard(), 2, 100)
+	r := HumanWithOptions(getTestCard(), 0, 100, Options{ShowPassed: true, ShowSkipped: true})
 	all, err := ioutil.ReadAll(r)
 	assert.Nil(t, err)
 	assert.Equal(t, `v1/Testing foo in foofoo                                                      🤔
@@ -176,6 +180,8 @@ v1/Testing bar-no-namespace                                                   
         · a -> skipped sum
             skipped description
     [SKIPPED] test-skipped-no-comment
+
+Score: 7.5/10
 `, string(all))
 }
 
@@ -265,9 +271,17 @@ func TestHumanOutputAllOKDefault(t *testing.T) {
 	r := Human(getTestCardAllOK(), 0, 100)
 	all, err := ioutil.ReadAll(r)
 	assert.Nil(t, err)
-	assert.Equal(t, `v1/Testing foo in foofoo                                                      ✅
-v1/Testing bar-no-namespace                                                   ✅
+	// Objects without any failing checks are not written
+	assert.Equal(t, `
+Score: 10.0/10
 `, string(all))
+
+	r = HumanWithOptions(getTestCardAllOK(), 0, 100, Options{ShowPassed: true})
+	all, err = ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Contains(t, string(all), `v1/Testing bar-no-namespace                                                   ✅
+    [OK] test-warning-two-comments
+`)
 }
 
 func getTestCardLongDescription() *scorecard.Scorecard {
@@ -314,6 +328,8 @@ func TestHumanOutputLogDescription120Width(t *testing.T) {
             lobortis vel. Pellentesque habitant morbi tristique senectus et netus et malesuada fames ac turpis egestas.
             Nulla eu neque erat. Vestibulum ante ipsum primis in faucibus orci luctus et ultrices posuere cubilia Curae;
             Maecenas et nisl venenatis, elementum augue a, porttitor libero.
+
+Score: 5.0/10
 `, string(all))
 }
 
@@ -330,6 +346,8 @@ func TestHumanOutputLogDescription100Width(t *testing.T) {
             malesuada fames ac turpis egestas. Nulla eu neque erat. Vestibulum ante ipsum primis in
             faucibus orci luctus et ultrices posuere cubilia Curae; Maecenas et nisl venenatis,
             elementum augue a, porttitor libero.
+
+Score: 5.0/10
 `, string(all))
 }
 
@@ -347,6 +365,8 @@ func TestHumanOutputLogDescription80Width(t *testing.T) {
             turpis egestas. Nulla eu neque erat. Vestibulum ante ipsum primis in
             faucibus orci luctus et ultrices posuere cubilia Curae; Maecenas et
             nisl venenatis, elementum augue a, porttitor libero.
+
+Score: 5.0/10
 `, string(all))
 }
 
@@ -368,6 +388,8 @@ func TestHumanOutputLogDescription0Width(t *testing.T) {
             orci luctus et ultrices posuere cubilia
             Curae; Maecenas et nisl venenatis,
             elementum augue a, porttitor libero.
+
+Score: 5.0/10
 `, string(all))
 }
 
@@ -417,5 +439,87 @@ func TestHumanOutputWithLongObjectNames(t *testing.T) {
             turpis egestas. Nulla eu neque erat. Vestibulum ante ipsum primis in
             faucibus orci luctus et ultrices posuere cubilia Curae; Maecenas et
             nisl venenatis, elementum augue a, porttitor libero.
+
+Score: 5.0/10
+`, string(all))
+}
+
+func TestHumanOutputGroupByCheck(t *testing.T) {
+	t.Parallel()
+	r := HumanWithOptions(getTestCard(), 0, 100, Options{GroupBy: GroupByCheck, ShowPassed: true})
+	all, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, `test-warning-two-comments (2 objects)
+    [WARNING] v1/Testing foo in foofoo
+        · a -> summary
+            description
+        · summary
+            description
+            More information: https://kube-score.com/whatever
+    [WARNING] v1/Testing bar-no-namespace
+        · a -> summary
+            description
+        · summary
+            description
+            More information: https://kube-score.com/whatever
+test-ok-comment (2 objects)
+    [OK] v1/Testing foo in foofoo
+        · a -> summary
+            description
+    [OK] v1/Testing bar-no-namespace
+        · a -> summary
+            description
+
+Score: 7.5/10
+`, string(all))
+}
+
+func TestHumanOutputGroupByCheckAllOKDefault(t *testing.T) {
+	t.Parallel()
+	r := HumanWithOptions(getTestCardAllOK(), 0, 100, Options{GroupBy: GroupByCheck})
+	all, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, `
+Score: 10.0/10
+`, string(all))
+}
+
+func TestHumanOutputSummaryOnly(t *testing.T) {
+	t.Parallel()
+	r := HumanWithOptions(getTestCard(), 2, 100, Options{GroupBy: GroupByCheck, SummaryOnly: true})
+	all, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, `Objects scored  2
+Critical        0
+High            0
+Warning         2
+OK              2
+Skipped         4
+
+Score: 7.5/10
+`, string(all))
+}
+
+func TestHumanOutputNoEmoji(t *testing.T) {
+	t.Parallel()
+	r := HumanWithOptions(getTestCard(), 0, 100, Options{NoEmoji: true})
+	all, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, `v1/Testing foo in foofoo                                               [WARNING]
+    [WARNING] test-warning-two-comments
+        - a -> summary
+            description
+        - summary
+            description
+            More information: https://kube-score.com/whatever
+v1/Testing bar-no-namespace                                            [WARNING]
+    [WARNING] test-warning-two-comments
+        - a -> summary
+            description
+        - summary
+            description
+            More information: https://kube-score.com/whatever
+
+Score: 7.5/10
 `, string(all))
 }
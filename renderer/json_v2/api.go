@@ -25,33 +25,41 @@ type ScoredObject struct {
 	Checks     []TestScore       `json:"checks"`
 	FileName   string            `json:"file_name"`
 	FileRow    int               `json:"file_row"`
+	Score      float64           `json:"score"`
 }
 
 type TestScore struct {
-	Check    Check              `json:"check"`
-	Grade    scorecard.Grade    `json:"grade"`
+	Check Check `json:"check"`
+
+	// Grade is the numeric grade, where 1 is critical, 5 is warning, 7 is almost ok and 10 is ok. The grades of v2
+	// are not changed: high findings have grade 1, and info findings have grade 7. The exact grade is in Severity.
+	Grade scorecard.Grade `json:"grade"`
+
+	// Severity is the name of the severity of the grade: critical, high, medium, low, info or none
+	Severity string             `json:"severity"`
 	Skipped  bool               `json:"skipped"`
 	Comments []TestScoreComment `json:"comments"`
 }
 
 type TestScoreComment struct {
-	Path        string `json:"path"`
-	Summary     string `json:"summary"`
-	Description string `json:"description"`
+	Path        string       `json:"path"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	FileRow     int          `json:"file_row,omitempty"`
+	FileColumn  int          `json:"file_column,omitempty"`
+	Remediation *Remediation `json:"remediation,omitempty"`
+}
+
+type Remediation struct {
+	JSONPatch           []scorecard.JSONPatchOperation `json:"json_patch,omitempty"`
+	StrategicMergePatch map[string]interface{}         `json:"strategic_merge_patch,omitempty"`
 }
 
 func Output(input *scorecard.Scorecard) io.Reader {
 	var objs []ScoredObject
 
 	for k, v := range *input {
-		objs = append(objs, ScoredObject{
-			ObjectName: k,
-			TypeMeta:   v.TypeMeta,
-			ObjectMeta: v.ObjectMeta,
-			Checks:     convertTestScore(v.Checks),
-			FileName:   v.FileLocation.Name,
-			FileRow:    v.FileLocation.Line,
-		})
+		objs = append(objs, ConvertObject(k, v))
 	}
 
 	j, err := json.MarshalIndent(objs, "", "    ")
@@ -61,29 +69,88 @@ func Output(input *scorecard.Scorecard) io.Reader {
 	return bytes.NewBuffer(j)
 }
 
-func convertTestScore(in []scorecard.TestScore) (res []TestScore) {
+// ConvertObject converts the object with the scorecard key objectName
+func ConvertObject(objectName string, v *scorecard.ScoredObject) ScoredObject {
+	return ScoredObject{
+		ObjectName: objectName,
+		TypeMeta:   v.TypeMeta,
+		ObjectMeta: v.ObjectMeta,
+		Checks:     convertTestScore(v, v.Checks),
+		FileName:   v.FileLocation.Name,
+		FileRow:    v.FileLocation.Line,
+		Score:      v.Score(),
+	}
+}
+
+func convertTestScore(so *scorecard.ScoredObject, in []scorecard.TestScore) (res []TestScore) {
 	for _, v := range in {
 		res = append(res, TestScore{
 			Check:    convertCheck(v.Check),
-			Grade:    v.Grade,
+			Grade:    v2Grade(v),
+			Severity: v.Severity().String(),
 			Skipped:  v.Skipped,
-			Comments: convertComments(v.Comments),
+			Comments: convertComments(so, v.Comments),
 		})
 	}
 	return
 }
 
-func convertComments(in []scorecard.TestScoreComment) (res []TestScoreComment) {
+// ExactGrade returns the grade of the severity, that can also be high or info, or Grade if the severity is not set,
+// such as in the output of older versions of kube-score
+func (ts TestScore) ExactGrade() scorecard.Grade {
+	if ts.Skipped || ts.Severity == "" || ts.Severity == scorecard.SeverityNone.String() {
+		return ts.Grade
+	}
+	grade, err := scorecard.ParseGrade(ts.Severity)
+	if err != nil {
+		return ts.Grade
+	}
+	return grade
+}
+
+// v2Grade returns the grade of the result on the scale of v2, that only has the grades critical, warning, almost ok
+// and ok
+func v2Grade(ts scorecard.TestScore) scorecard.Grade {
+	if ts.Skipped {
+		return ts.Grade
+	}
+	switch ts.Grade.Severity() {
+	case scorecard.SeverityCritical, scorecard.SeverityHigh:
+		return scorecard.GradeCritical
+	case scorecard.SeverityMedium:
+		return scorecard.GradeWarning
+	case scorecard.SeverityLow, scorecard.SeverityInfo:
+		return scorecard.GradeAlmostOK
+	default:
+		return scorecard.GradeAllOK
+	}
+}
+
+func convertComments(so *scorecard.ScoredObject, in []scorecard.TestScoreComment) (res []TestScoreComment) {
 	for _, v := range in {
+		location := so.CommentFileLocation(v)
 		res = append(res, TestScoreComment{
 			Path:        v.Path,
 			Summary:     v.Summary,
 			Description: v.Description,
+			FileRow:     location.Line,
+			FileColumn:  location.Column,
+			Remediation: convertRemediation(v.Remediation),
 		})
 	}
 	return
 }
 
+func convertRemediation(in *scorecard.Remediation) *Remediation {
+	if in == nil {
+		return nil
+	}
+	return &Remediation{
+		JSONPatch:           in.JSONPatch,
+		StrategicMergePatch: in.StrategicMergePatch,
+	}
+}
+
 func convertCheck(v ks.Check) Check {
 	return Check{
 		Name:       v.Name,
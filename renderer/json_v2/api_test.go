@@ -0,0 +1,34 @@
+package json_v2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestConvertObjectGrades(t *testing.T) {
+	t.Parallel()
+	var checks []scorecard.TestScore
+	for _, grade := range []scorecard.Grade{
+		scorecard.GradeCritical, scorecard.GradeHigh, scorecard.GradeWarning, scorecard.GradeAlmostOK, scorecard.GradeInfo, scorecard.GradeAllOK,
+	} {
+		checks = append(checks, scorecard.TestScore{Check: domain.Check{ID: grade.String()}, Grade: grade})
+	}
+	checks = append(checks, scorecard.TestScore{Check: domain.Check{ID: "skipped"}, Skipped: true})
+
+	var grades, exactGrades []scorecard.Grade
+	var severities []string
+	for _, c := range ConvertObject("a", &scorecard.ScoredObject{Checks: checks}).Checks {
+		grades = append(grades, c.Grade)
+		exactGrades = append(exactGrades, c.ExactGrade())
+		severities = append(severities, c.Severity)
+	}
+
+	// The grades of v2 only have the values 1, 5, 7 and 10, the exact grade is in the severity
+	assert.Equal(t, []scorecard.Grade{1, 1, 5, 7, 7, 10, 0}, grades)
+	assert.Equal(t, []string{"critical", "high", "medium", "low", "info", "none", "none"}, severities)
+	assert.Equal(t, []scorecard.Grade{1, 3, 5, 7, 9, 10, 0}, exactGrades)
+}
@@ -0,0 +1,309 @@
+// Package json_v3 writes the scorecard in the v3 JSON format, that is described by the JSON Schema in Schema. Fields
+// are only added to the format, and are never removed or changed, until the next version of the format.
+package json_v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Version is the version of the format, it's written in the "version" field of the output
+const Version = "v3"
+
+const (
+	StatusFailed  = "failed"
+	StatusPassed  = "passed"
+	StatusSkipped = "skipped"
+)
+
+// Options are the information about the run and the checks, that is not in the scorecard
+type Options struct {
+	// Run is written in the "run" field of the output
+	Run Run
+
+	// Checks are the documentation of the checks by ID. Checks without documentation, such as the checks of
+	// plugins, are written with only the information that is in the scorecard.
+	Checks map[string]CheckDocumentation
+
+	// Filter selects the results that are written. The summary counts the results of all checks.
+	Filter scorecard.Filter
+}
+
+// CheckDocumentation is the documentation of a check
+type CheckDocumentation struct {
+	// Severity is the severity of the findings of the check
+	Severity string
+
+	// Categories are the profiles that the check is in, such as "security"
+	Categories []string
+
+	// DocumentationURL is a link to more information about the check, and about how to fix the objects that fail it
+	DocumentationURL string
+}
+
+// Document is the output of a run
+type Document struct {
+	Version string         `json:"version"`
+	Run     Run            `json:"run"`
+	Summary Summary        `json:"summary"`
+	Checks  []Check        `json:"checks"`
+	Objects []ScoredObject `json:"objects"`
+}
+
+// Run describes the run of kube-score that wrote the output
+type Run struct {
+	// KubeScoreVersion is the version of kube-score
+	KubeScoreVersion string `json:"kube_score_version"`
+
+	// Timestamp is the time when the output was written
+	Timestamp time.Time `json:"timestamp"`
+
+	// Flags are the flags that were set on the command line or in the configuration file, with their values
+	Flags map[string]string `json:"flags"`
+}
+
+// Summary is the number of objects, and the number of results of each status and severity
+type Summary struct {
+	Objects  int     `json:"objects"`
+	Score    float64 `json:"score"`
+	Passed   int     `json:"passed"`
+	Skipped  int     `json:"skipped"`
+	Failed   int     `json:"failed"`
+	Critical int     `json:"critical"`
+	High     int     `json:"high"`
+	Medium   int     `json:"medium"`
+	Low      int     `json:"low"`
+	Info     int     `json:"info"`
+}
+
+// Check is the metadata of a check that has any results in the output
+type Check struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	TargetType       string   `json:"target_type"`
+	Description      string   `json:"description"`
+	Optional         bool     `json:"optional"`
+	Severity         string   `json:"severity,omitempty"`
+	Categories       []string `json:"categories"`
+	DocumentationURL string   `json:"documentation_url,omitempty"`
+}
+
+type ScoredObject struct {
+	// ID is the unique key of the object in the scorecard
+	ID         string    `json:"id"`
+	APIVersion string    `json:"api_version"`
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Location   *Location `json:"location,omitempty"`
+	Score      float64   `json:"score"`
+	Results    []Result  `json:"results"`
+}
+
+// Location is a position in an input file. Line and Column start at 1, and are omitted if they're not known.
+type Location struct {
+	File   string `json:"file"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// Result is the result of a check on an object
+type Result struct {
+	CheckID string `json:"check_id"`
+	Status  string `json:"status"`
+
+	// Grade is the numeric grade from 1 to 10, where 1 is critical, 3 is high, 5 is medium, 7 is low, 9 is info
+	// and 10 is passed
+	Grade    scorecard.Grade `json:"grade"`
+	Severity string          `json:"severity"`
+	Comments []Comment       `json:"comments"`
+}
+
+type Comment struct {
+	Path             string       `json:"path,omitempty"`
+	Summary          string       `json:"summary"`
+	Description      string       `json:"description,omitempty"`
+	DocumentationURL string       `json:"documentation_url,omitempty"`
+	Location         *Location    `json:"location,omitempty"`
+	Remediation      *Remediation `json:"remediation,omitempty"`
+}
+
+type Remediation struct {
+	JSONPatch           []scorecard.JSONPatchOperation `json:"json_patch,omitempty"`
+	StrategicMergePatch map[string]interface{}         `json:"strategic_merge_patch,omitempty"`
+}
+
+// Write writes the scorecard in the v3 format to w
+func Write(w io.Writer, scoreCard *scorecard.Scorecard, options Options) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(Convert(scoreCard, options))
+}
+
+// Output outputs the scorecard in the v3 format
+func Output(scoreCard *scorecard.Scorecard, options Options) io.Reader {
+	w := bytes.NewBufferString("")
+	if err := Write(w, scoreCard, options); err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// Convert converts the scorecard to the v3 format. The objects are sorted by ID, and the checks by ID.
+func Convert(scoreCard *scorecard.Scorecard, options Options) Document {
+	out := Document{
+		Version: Version,
+		Run:     options.Run,
+		Checks:  []Check{},
+		Objects: []ScoredObject{},
+		Summary: Summary{
+			Objects: len(*scoreCard),
+			Score:   scoreCard.Score(),
+		},
+	}
+	if out.Run.Flags == nil {
+		out.Run.Flags = map[string]string{}
+	}
+
+	var keys []string
+	for k := range *scoreCard {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	checks := make(map[string]Check)
+	for _, key := range keys {
+		so := (*scoreCard)[key]
+		obj := ScoredObject{
+			ID:         key,
+			APIVersion: so.TypeMeta.APIVersion,
+			Kind:       so.TypeMeta.Kind,
+			Name:       so.ObjectMeta.Name,
+			Namespace:  so.ObjectMeta.Namespace,
+			Location:   location(so.FileLocation.Name, so.FileLocation.Line, so.FileLocation.Column),
+			Score:      so.Score(),
+			Results:    []Result{},
+		}
+
+		for _, card := range so.Checks {
+			out.Summary.add(card)
+			if !options.Filter.Shows(card) {
+				continue
+			}
+			if _, ok := checks[card.Check.ID]; !ok {
+				checks[card.Check.ID] = convertCheck(card, options.Checks)
+			}
+			obj.Results = append(obj.Results, convertResult(so, card))
+		}
+
+		out.Objects = append(out.Objects, obj)
+	}
+
+	for _, check := range checks {
+		out.Checks = append(out.Checks, check)
+	}
+	sort.Slice(out.Checks, func(i, j int) bool {
+		return out.Checks[i].ID < out.Checks[j].ID
+	})
+
+	return out
+}
+
+func (s *Summary) add(card scorecard.TestScore) {
+	switch {
+	case card.Skipped:
+		s.Skipped++
+		return
+	case card.Grade >= scorecard.GradeAllOK:
+		s.Passed++
+		return
+	}
+
+	s.Failed++
+	switch card.Severity() {
+	case scorecard.SeverityCritical:
+		s.Critical++
+	case scorecard.SeverityHigh:
+		s.High++
+	case scorecard.SeverityMedium:
+		s.Medium++
+	case scorecard.SeverityLow:
+		s.Low++
+	default:
+		s.Info++
+	}
+}
+
+func status(card scorecard.TestScore) string {
+	switch {
+	case card.Skipped:
+		return StatusSkipped
+	case card.Grade >= scorecard.GradeAllOK:
+		return StatusPassed
+	default:
+		return StatusFailed
+	}
+}
+
+func convertCheck(card scorecard.TestScore, docs map[string]CheckDocumentation) Check {
+	doc := docs[card.Check.ID]
+	categories := doc.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+	return Check{
+		ID:               card.Check.ID,
+		Name:             card.Check.Name,
+		TargetType:       card.Check.TargetType,
+		Description:      card.Check.Comment,
+		Optional:         card.Check.Optional,
+		Severity:         doc.Severity,
+		Categories:       categories,
+		DocumentationURL: doc.DocumentationURL,
+	}
+}
+
+func convertResult(so *scorecard.ScoredObject, card scorecard.TestScore) Result {
+	res := Result{
+		CheckID:  card.Check.ID,
+		Status:   status(card),
+		Grade:    card.Grade,
+		Severity: card.Severity().String(),
+		Comments: []Comment{},
+	}
+	for _, c := range card.Comments {
+		loc := so.CommentFileLocation(c)
+		res.Comments = append(res.Comments, Comment{
+			Path:             c.Path,
+			Summary:          c.Summary,
+			Description:      c.Description,
+			DocumentationURL: c.DocumentationURL,
+			Location:         location(loc.Name, loc.Line, loc.Column),
+			Remediation:      convertRemediation(c.Remediation),
+		})
+	}
+	return res
+}
+
+// location returns the location in the file, or nil if the file is not known
+func location(file string, line, column int) *Location {
+	if file == "" {
+		return nil
+	}
+	return &Location{File: file, Line: line, Column: column}
+}
+
+func convertRemediation(in *scorecard.Remediation) *Remediation {
+	if in == nil {
+		return nil
+	}
+	return &Remediation{
+		JSONPatch:           in.JSONPatch,
+		StrategicMergePatch: in.StrategicMergePatch,
+	}
+}
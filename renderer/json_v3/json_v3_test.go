@@ -0,0 +1,218 @@
+package json_v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func getTestCard() *scorecard.Scorecard {
+	return &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:     v1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta:   v1.ObjectMeta{Name: "foo", Namespace: "foofoo"},
+			FileLocation: domain.FileLocation{Name: "deployment.yaml", Line: 3, Column: 1},
+			Checks: []scorecard.TestScore{
+				{
+					Check: domain.Check{ID: "container-resources", Name: "Container Resources", TargetType: "Pod"},
+					Grade: scorecard.GradeCritical,
+					Comments: []scorecard.TestScoreComment{{
+						Path:         "app",
+						Summary:      "CPU limit is not set",
+						FileLocation: domain.FileLocation{Name: "deployment.yaml", Line: 12, Column: 9},
+						Remediation: &scorecard.Remediation{
+							JSONPatch: []scorecard.JSONPatchOperation{{Op: "add", Path: "/spec/template/spec/containers/0/resources/limits/cpu", Value: "1"}},
+						},
+					}},
+				},
+				{
+					Check: domain.Check{ID: "pod-probes", Name: "Pod Probes", TargetType: "Pod"},
+					Grade: scorecard.GradeAllOK,
+				},
+				{
+					Check:   domain.Check{ID: "plugin-check", Name: "Plugin Check", TargetType: "Deployment"},
+					Skipped: true,
+				},
+			},
+		},
+		"b": &scorecard.ScoredObject{
+			TypeMeta:   v1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+			ObjectMeta: v1.ObjectMeta{Name: "bar"},
+			Checks: []scorecard.TestScore{
+				{
+					Check:    domain.Check{ID: "service-type", Name: "Service Type", TargetType: "Service"},
+					Grade:    scorecard.GradeWarning,
+					Comments: []scorecard.TestScoreComment{{Summary: "The service is of type NodePort"}},
+				},
+			},
+		},
+	}
+}
+
+func getTestOptions() Options {
+	return Options{
+		Run: Run{
+			KubeScoreVersion: "1.2.3",
+			Timestamp:        time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC),
+			Flags:            map[string]string{"output-version": "v3"},
+		},
+		Checks: map[string]CheckDocumentation{
+			"container-resources": {Severity: "critical", Categories: []string{"cost", "reliability"}, DocumentationURL: "https://example.com/container-resources"},
+		},
+		Filter: scorecard.Filter{ShowPassed: true, ShowSkipped: true},
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+	out := Convert(getTestCard(), getTestOptions())
+
+	assert.Equal(t, "v3", out.Version)
+	assert.Equal(t, "1.2.3", out.Run.KubeScoreVersion)
+	assert.Equal(t, Summary{Objects: 2, Score: 5.3, Passed: 1, Skipped: 1, Failed: 2, Critical: 1, Medium: 1}, out.Summary)
+
+	assert.Len(t, out.Checks, 4)
+	assert.Equal(t, Check{
+		ID:               "container-resources",
+		Name:             "Container Resources",
+		TargetType:       "Pod",
+		Severity:         "critical",
+		Categories:       []string{"cost", "reliability"},
+		DocumentationURL: "https://example.com/container-resources",
+	}, out.Checks[0])
+	assert.Equal(t, "plugin-check", out.Checks[1].ID)
+	assert.Equal(t, []string{}, out.Checks[1].Categories)
+
+	assert.Len(t, out.Objects, 2)
+	obj := out.Objects[0]
+	assert.Equal(t, "a", obj.ID)
+	assert.Equal(t, &Location{File: "deployment.yaml", Line: 3, Column: 1}, obj.Location)
+	assert.Equal(t, []string{StatusFailed, StatusPassed, StatusSkipped}, []string{obj.Results[0].Status, obj.Results[1].Status, obj.Results[2].Status})
+	assert.Equal(t, &Location{File: "deployment.yaml", Line: 12, Column: 9}, obj.Results[0].Comments[0].Location)
+	assert.Equal(t, "critical", obj.Results[0].Severity)
+
+	// Objects without a file have no location
+	assert.Nil(t, out.Objects[1].Location)
+	assert.Nil(t, out.Objects[1].Results[0].Comments[0].Location)
+}
+
+func TestConvertFilter(t *testing.T) {
+	t.Parallel()
+	options := getTestOptions()
+	options.Filter = scorecard.Filter{}
+	out := Convert(getTestCard(), options)
+
+	// The summary counts all results, and only the failing results and their checks are written
+	assert.Equal(t, 1, out.Summary.Passed)
+	assert.Len(t, out.Objects[0].Results, 1)
+	assert.Len(t, out.Checks, 2)
+}
+
+func TestOutputMatchesSchema(t *testing.T) {
+	t.Parallel()
+
+	var schema map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(Schema), &schema))
+
+	for _, card := range []*scorecard.Scorecard{getTestCard(), {}} {
+		var out bytes.Buffer
+		assert.NoError(t, Write(&out, card, getTestOptions()))
+
+		var doc interface{}
+		assert.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+		for _, err := range validate(schema, schema, doc, "") {
+			t.Error(err)
+		}
+	}
+
+	assert.NotEmpty(t, validate(schema, schema, map[string]interface{}{"version": "v2"}, ""))
+}
+
+// validate is a minimal validator of the parts of JSON Schema that are used by Schema
+func validate(root, schema map[string]interface{}, doc interface{}, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		schema = root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			schema = schema[part].(map[string]interface{})
+		}
+	}
+
+	var errs []string
+	if c, ok := schema["const"]; ok && c != doc {
+		errs = append(errs, fmt.Sprintf("%s: %v is not %v", path, doc, c))
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, v := range enum {
+			found = found || v == doc
+		}
+		if !found {
+			errs = append(errs, fmt.Sprintf("%s: %v is not one of %v", path, doc, enum))
+		}
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: is not an object", path))
+		}
+		for _, r := range asSlice(schema["required"]) {
+			if _, ok := obj[r.(string)]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: %s is required", path, r))
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for k, v := range obj {
+			if p, ok := properties[k]; ok {
+				errs = append(errs, validate(root, p.(map[string]interface{}), v, path+"."+k)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				errs = append(errs, validate(root, additional, v, path+"."+k)...)
+			} else if schema["additionalProperties"] == false {
+				errs = append(errs, fmt.Sprintf("%s: %s is not allowed", path, k))
+			}
+		}
+	case "array":
+		arr, ok := doc.([]interface{})
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: is not an array", path))
+		}
+		for i, v := range arr {
+			errs = append(errs, validate(root, schema["items"].(map[string]interface{}), v, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		if _, ok := doc.(string); !ok {
+			errs = append(errs, fmt.Sprintf("%s: is not a string", path))
+		}
+	case "boolean":
+		if _, ok := doc.(bool); !ok {
+			errs = append(errs, fmt.Sprintf("%s: is not a boolean", path))
+		}
+	case "integer", "number":
+		n, ok := doc.(float64)
+		if !ok || (schema["type"] == "integer" && n != float64(int(n))) {
+			errs = append(errs, fmt.Sprintf("%s: is not an %s", path, schema["type"]))
+		}
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			errs = append(errs, fmt.Sprintf("%s: %v is below %v", path, n, min))
+		}
+		if max, ok := schema["maximum"].(float64); ok && n > max {
+			errs = append(errs, fmt.Sprintf("%s: %v is above %v", path, n, max))
+		}
+	}
+	return errs
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
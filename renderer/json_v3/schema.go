@@ -0,0 +1,291 @@
+package json_v3
+
+// Schema is the JSON Schema of the v3 format. It's printed by "kube-score schema", and can be used to validate the
+// output in the pipelines that read it.
+const Schema = `{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "title": "kube-score v3 output",
+    "description": "The output of kube-score with --output-format json --output-version v3",
+    "type": "object",
+    "required": ["version", "run", "summary", "checks", "objects"],
+    "additionalProperties": false,
+    "properties": {
+        "version": {
+            "description": "The version of the format",
+            "const": "v3"
+        },
+        "run": {
+            "description": "The run of kube-score that wrote the output",
+            "type": "object",
+            "required": ["kube_score_version", "timestamp", "flags"],
+            "additionalProperties": false,
+            "properties": {
+                "kube_score_version": {
+                    "description": "The version of kube-score",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "The time when the output was written",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "flags": {
+                    "description": "The flags that were set on the command line or in the configuration file, with their values",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "summary": {
+            "description": "The number of objects, and the number of results of each status and severity. All results are counted, also the results that are not written in the objects",
+            "type": "object",
+            "required": ["objects", "score", "passed", "skipped", "failed", "critical", "high", "medium", "low", "info"],
+            "additionalProperties": false,
+            "properties": {
+                "objects": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "score": {
+                    "description": "The mean of the scores of the objects, from 1 to 10",
+                    "type": "number",
+                    "minimum": 1,
+                    "maximum": 10
+                },
+                "passed": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "skipped": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "failed": {
+                    "description": "The number of failed results, the sum of critical, high, medium, low and info",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "critical": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "high": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "medium": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "low": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "info": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "checks": {
+            "description": "The checks that have any results in the objects, sorted by id",
+            "type": "array",
+            "items": {
+                "$ref": "#/definitions/check"
+            }
+        },
+        "objects": {
+            "description": "The scored objects, sorted by id",
+            "type": "array",
+            "items": {
+                "$ref": "#/definitions/object"
+            }
+        }
+    },
+    "definitions": {
+        "check": {
+            "type": "object",
+            "required": ["id", "name", "target_type", "description", "optional", "categories"],
+            "additionalProperties": false,
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "description": "The kind of the objects that the check is run on",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "optional": {
+                    "description": "If the check is only run when it has been enabled",
+                    "type": "boolean"
+                },
+                "severity": {
+                    "description": "The severity of the findings of the check, it's not set for checks without documentation, such as the checks of plugins",
+                    "$ref": "#/definitions/severity"
+                },
+                "categories": {
+                    "description": "The profiles that the check is in, such as security, reliability and cost",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "documentation_url": {
+                    "type": "string",
+                    "format": "uri"
+                }
+            }
+        },
+        "object": {
+            "type": "object",
+            "required": ["id", "api_version", "kind", "name", "score", "results"],
+            "additionalProperties": false,
+            "properties": {
+                "id": {
+                    "description": "The unique key of the object",
+                    "type": "string"
+                },
+                "api_version": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "description": "The namespace of the object, it's not set for objects without a namespace",
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/location"
+                },
+                "score": {
+                    "description": "The mean of the grades of the checks that are not skipped, from 1 to 10",
+                    "type": "number",
+                    "minimum": 1,
+                    "maximum": 10
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/result"
+                    }
+                }
+            }
+        },
+        "result": {
+            "type": "object",
+            "required": ["check_id", "status", "grade", "severity", "comments"],
+            "additionalProperties": false,
+            "properties": {
+                "check_id": {
+                    "description": "The id of the check, that is described in checks",
+                    "type": "string"
+                },
+                "status": {
+                    "enum": ["failed", "passed", "skipped"]
+                },
+                "grade": {
+                    "description": "The grade from 1 to 10, where 1 is critical, 3 is high, 5 is medium, 7 is low, 9 is info and 10 is passed. Skipped results have no meaningful grade",
+                    "type": "integer",
+                    "minimum": 0,
+                    "maximum": 10
+                },
+                "severity": {
+                    "$ref": "#/definitions/severity"
+                },
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/comment"
+                    }
+                }
+            }
+        },
+        "comment": {
+            "type": "object",
+            "required": ["summary"],
+            "additionalProperties": false,
+            "properties": {
+                "path": {
+                    "description": "The field of the object that the comment refers to",
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "documentation_url": {
+                    "type": "string",
+                    "format": "uri"
+                },
+                "location": {
+                    "$ref": "#/definitions/location"
+                },
+                "remediation": {
+                    "description": "A change to the object that fixes the finding",
+                    "type": "object",
+                    "additionalProperties": false,
+                    "properties": {
+                        "json_patch": {
+                            "description": "A RFC 6902 JSON Patch",
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "required": ["op", "path"],
+                                "properties": {
+                                    "op": {
+                                        "type": "string"
+                                    },
+                                    "path": {
+                                        "type": "string"
+                                    },
+                                    "value": {}
+                                }
+                            }
+                        },
+                        "strategic_merge_patch": {
+                            "description": "A Kubernetes strategic merge patch",
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "location": {
+            "description": "A position in an input file, line and column start at 1 and are not set if they're not known",
+            "type": "object",
+            "required": ["file"],
+            "additionalProperties": false,
+            "properties": {
+                "file": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "column": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "severity": {
+            "enum": ["critical", "high", "medium", "low", "info", "none"]
+        }
+    }
+}
+`
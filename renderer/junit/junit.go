@@ -0,0 +1,153 @@
+// Package junit is currently considered to be in alpha status, and is not covered
+// by the API stability guarantees
+package junit
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+type testSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Suites   []testSuite `xml:"testsuite"`
+}
+
+type testSuite struct {
+	Name       string     `xml:"name,attr"`
+	Tests      int        `xml:"tests,attr"`
+	Failures   int        `xml:"failures,attr"`
+	Skipped    int        `xml:"skipped,attr"`
+	File       string     `xml:"file,attr,omitempty"`
+	Properties []property `xml:"properties>property"`
+	TestCases  []testCase `xml:"testcase"`
+}
+
+type property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type testCase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	File      string   `xml:"file,attr,omitempty"`
+	Line      int      `xml:"line,attr,omitempty"`
+	Failure   *failure `xml:"failure,omitempty"`
+	Skipped   *skipped `xml:"skipped,omitempty"`
+}
+
+type failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type skipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// JUnit outputs the scorecard as JUnit XML. Each object is a testsuite, and each check is a testcase.
+// Checks with a grade of WARNING or CRITICAL are reported as failures.
+func JUnit(scoreCard *scorecard.Scorecard) io.Reader {
+	// Print the items sorted by scorecard key
+	var keys []string
+	for k := range *scoreCard {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	suites := testSuites{Name: "kube-score"}
+
+	for _, key := range keys {
+		scoredObject := (*scoreCard)[key]
+
+		suite := testSuite{
+			Name:       scoredObject.HumanFriendlyRef(),
+			File:       scoredObject.FileLocation.Name,
+			Properties: []property{{Name: "score", Value: fmt.Sprintf("%.1f", scoredObject.Score())}},
+		}
+
+		for _, card := range scoredObject.Checks {
+			tc := testCase{
+				Name:      card.Check.Name,
+				ClassName: scoredObject.HumanFriendlyRef(),
+				File:      scoredObject.FileLocation.Name,
+				Line:      scoredObject.FileLocation.Line,
+			}
+
+			// Point at the field of the first comment, if it's known
+			if len(card.Comments) > 0 {
+				tc.Line = scoredObject.CommentFileLocation(card.Comments[0]).Line
+			}
+
+			if card.Skipped {
+				tc.Skipped = &skipped{Message: commentSummaries(card.Comments)}
+				suite.Skipped++
+			} else if card.Grade <= scorecard.GradeWarning {
+				tc.Failure = &failure{
+					Message: commentSummaries(card.Comments),
+					Type:    card.Grade.String(),
+					Text:    commentDetails(card.Comments),
+				}
+				suite.Failures++
+			}
+
+			suite.TestCases = append(suite.TestCases, tc)
+			suite.Tests++
+		}
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	w := bytes.NewBufferString(xml.Header)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "    ")
+	if err := enc.Encode(suites); err != nil {
+		panic(err)
+	}
+	w.WriteString("\n")
+	return w
+}
+
+func commentSummaries(comments []scorecard.TestScoreComment) string {
+	var s []string
+	for _, c := range comments {
+		s = append(s, formatSummary(c))
+	}
+	return strings.Join(s, ", ")
+}
+
+func commentDetails(comments []scorecard.TestScoreComment) string {
+	var s []string
+	for _, c := range comments {
+		line := formatSummary(c)
+		if c.Description != "" {
+			line += ": " + c.Description
+		}
+		if c.DocumentationURL != "" {
+			line += " (" + c.DocumentationURL + ")"
+		}
+		s = append(s, line)
+	}
+	return strings.Join(s, "\n")
+}
+
+func formatSummary(c scorecard.TestScoreComment) string {
+	if c.Path != "" {
+		return "(" + c.Path + ") " + c.Summary
+	}
+	return c.Summary
+}
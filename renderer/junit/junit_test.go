@@ -0,0 +1,52 @@
+package junit
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zegl/kube-score/renderer/internal/testcard"
+)
+
+func TestJUnitOutput(t *testing.T) {
+	t.Parallel()
+	r := JUnit(testcard.New())
+	all, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="kube-score" tests="8" failures="2" skipped="4">
+    <testsuite name="foo/foofoo v1/Testing" tests="4" failures="1" skipped="2" file="foo.yaml">
+        <properties>
+            <property name="score" value="7.5"></property>
+        </properties>
+        <testcase name="test-warning-two-comments" classname="foo/foofoo v1/Testing" file="foo.yaml" line="12">
+            <failure message="(a) summary, summary" type="WARNING"><![CDATA[(a) summary: description
+summary: description]]></failure>
+        </testcase>
+        <testcase name="test-ok-comment" classname="foo/foofoo v1/Testing" file="foo.yaml" line="12"></testcase>
+        <testcase name="test-skipped-comment" classname="foo/foofoo v1/Testing" file="foo.yaml" line="12">
+            <skipped message="(a) skipped sum"></skipped>
+        </testcase>
+        <testcase name="test-skipped-no-comment" classname="foo/foofoo v1/Testing" file="foo.yaml" line="12">
+            <skipped></skipped>
+        </testcase>
+    </testsuite>
+    <testsuite name="bar-no-namespace v1/Testing" tests="4" failures="1" skipped="2">
+        <properties>
+            <property name="score" value="7.5"></property>
+        </properties>
+        <testcase name="test-warning-two-comments" classname="bar-no-namespace v1/Testing">
+            <failure message="(a) summary, summary" type="WARNING"><![CDATA[(a) summary: description
+summary: description]]></failure>
+        </testcase>
+        <testcase name="test-ok-comment" classname="bar-no-namespace v1/Testing"></testcase>
+        <testcase name="test-skipped-comment" classname="bar-no-namespace v1/Testing">
+            <skipped message="(a) skipped sum"></skipped>
+        </testcase>
+        <testcase name="test-skipped-no-comment" classname="bar-no-namespace v1/Testing">
+            <skipped></skipped>
+        </testcase>
+    </testsuite>
+</testsuites>
+`, string(all))
+}
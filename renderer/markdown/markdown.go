@@ -0,0 +1,169 @@
+// Package markdown is currently considered to be in alpha status, and is not covered
+// by the API stability guarantees
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Output outputs the scorecard as a Markdown report, that is suitable to post as a comment on a pull request.
+// The report starts with a table with a summary of every object, followed by a collapsible section with the
+// findings of every object that has any. Checks that are OK or skipped are not included in the findings.
+func Output(scoreCard *scorecard.Scorecard) io.Reader {
+	w := bytes.NewBufferString("")
+
+	// Print the items sorted by scorecard key
+	var keys []string
+	for k := range *scoreCard {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var critical, warning int
+	for _, key := range keys {
+		c, wa := count((*scoreCard)[key])
+		critical += c
+		warning += wa
+	}
+
+	fmt.Fprintf(w, "## kube-score\n\n")
+	fmt.Fprintf(w, "**Score: %.1f/10** · %d objects · %s %d critical · %s %d warnings\n\n",
+		scoreCard.Score(), len(keys),
+		emoji(scorecard.GradeCritical), critical,
+		emoji(scorecard.GradeWarning), warning,
+	)
+
+	if len(keys) == 0 {
+		return w
+	}
+
+	fmt.Fprintf(w, "| | Object | Kind | Score | Critical | Warning |\n")
+	fmt.Fprintf(w, "|---|---|---|---|---|---|\n")
+	for _, key := range keys {
+		scoredObject := (*scoreCard)[key]
+		c, wa := count(scoredObject)
+		fmt.Fprintf(w, "| %s | %s | %s | %.1f | %d | %d |\n",
+			emoji(worstGrade(scoredObject)),
+			escape(objectName(scoredObject)),
+			escape(scoredObject.TypeMeta.APIVersion+"/"+scoredObject.TypeMeta.Kind),
+			scoredObject.Score(), c, wa,
+		)
+	}
+
+	for _, key := range keys {
+		scoredObject := (*scoreCard)[key]
+		if worstGrade(scoredObject) == scorecard.GradeAllOK {
+			continue
+		}
+
+		summary := scoredObject.TypeMeta.APIVersion + "/" + scoredObject.TypeMeta.Kind + " " + objectName(scoredObject)
+		if scoredObject.FileLocation.Name != "" {
+			summary += fmt.Sprintf(" (%s:%d)", scoredObject.FileLocation.Name, scoredObject.FileLocation.Line)
+		}
+
+		fmt.Fprintf(w, "\n<details>\n<summary>%s %s</summary>\n\n", emoji(worstGrade(scoredObject)), html(summary))
+		fmt.Fprintf(w, "| Grade | Check | Finding |\n")
+		fmt.Fprintf(w, "|---|---|---|\n")
+
+		for _, card := range scoredObject.Checks {
+			if card.Skipped || card.Grade == scorecard.GradeAllOK {
+				continue
+			}
+
+			grade := emoji(card.Grade) + " " + card.Grade.String()
+			if len(card.Comments) == 0 {
+				fmt.Fprintf(w, "| %s | %s | |\n", grade, escape(card.Check.Name))
+			}
+			for _, comment := range card.Comments {
+				fmt.Fprintf(w, "| %s | %s | %s |\n", grade, escape(card.Check.Name), finding(comment))
+			}
+		}
+
+		fmt.Fprintf(w, "\n</details>\n")
+	}
+
+	return w
+}
+
+func finding(comment scorecard.TestScoreComment) string {
+	var s string
+	if comment.Path != "" {
+		s = "**" + escape(comment.Path) + "**: "
+	}
+	s += escape(comment.Summary)
+	if comment.Description != "" {
+		s += "<br>" + escape(comment.Description)
+	}
+	if comment.DocumentationURL != "" {
+		s += " ([docs](" + comment.DocumentationURL + "))"
+	}
+	return s
+}
+
+func objectName(so *scorecard.ScoredObject) string {
+	if so.ObjectMeta.Namespace != "" {
+		return so.ObjectMeta.Namespace + "/" + so.ObjectMeta.Name
+	}
+	return so.ObjectMeta.Name
+}
+
+// count returns the number of critical and warning checks of the object
+func count(so *scorecard.ScoredObject) (critical, warning int) {
+	for _, card := range so.Checks {
+		if card.Skipped {
+			continue
+		}
+		switch {
+		case card.Grade <= scorecard.GradeCritical:
+			critical++
+		case card.Grade <= scorecard.GradeWarning:
+			warning++
+		}
+	}
+	return
+}
+
+func worstGrade(so *scorecard.ScoredObject) scorecard.Grade {
+	worst := scorecard.GradeAllOK
+	for _, card := range so.Checks {
+		if !card.Skipped && card.Grade < worst {
+			worst = card.Grade
+		}
+	}
+	return worst
+}
+
+func emoji(grade scorecard.Grade) string {
+	switch {
+	case grade <= scorecard.GradeCritical:
+		return "🔴"
+	case grade <= scorecard.GradeHigh:
+		return "🟠"
+	case grade < scorecard.GradeAllOK:
+		return "🟡"
+	default:
+		return "🟢"
+	}
+}
+
+// escape escapes text in a table cell, where newlines and pipes would break the table
+func escape(s string) string {
+	s = html(s)
+	s = strings.Replace(s, "|", "\\|", -1)
+	s = strings.Replace(s, "\r\n", "<br>", -1)
+	s = strings.Replace(s, "\n", "<br>", -1)
+	return s
+}
+
+func html(s string) string {
+	s = strings.Replace(s, "&", "&amp;", -1)
+	s = strings.Replace(s, "<", "&lt;", -1)
+	s = strings.Replace(s, ">", "&gt;", -1)
+	return s
+}
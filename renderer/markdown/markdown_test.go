@@ -0,0 +1,84 @@
+package markdown
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/renderer/internal/testcard"
+	"github.com/zegl/kube-score/scorecard"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// getTestCard returns the shared test scorecard, with a documentation URL on the first comment
+func getTestCard() *scorecard.Scorecard {
+	card := testcard.New()
+	(*card)["a"].Checks[0].Comments[0].DocumentationURL = "https://kube-score.com/whatever"
+	return card
+}
+
+func TestMarkdownOutput(t *testing.T) {
+	t.Parallel()
+	r := Output(getTestCard())
+	all, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, `## kube-score
+
+**Score: 7.5/10** · 2 objects · 🔴 0 critical · 🟡 2 warnings
+
+| | Object | Kind | Score | Critical | Warning |
+|---|---|---|---|---|---|
+| 🟡 | foofoo/foo | v1/Testing | 7.5 | 0 | 1 |
+| 🟡 | bar-no-namespace | v1/Testing | 7.5 | 0 | 1 |
+
+<details>
+<summary>🟡 v1/Testing foofoo/foo (foo.yaml:12)</summary>
+
+| Grade | Check | Finding |
+|---|---|---|
+| 🟡 WARNING | test-warning-two-comments | **a**: summary<br>description ([docs](https://kube-score.com/whatever)) |
+| 🟡 WARNING | test-warning-two-comments | summary<br>description |
+
+</details>
+
+<details>
+<summary>🟡 v1/Testing bar-no-namespace</summary>
+
+| Grade | Check | Finding |
+|---|---|---|
+| 🟡 WARNING | test-warning-two-comments | **a**: summary<br>description ([docs](https://kube-score.com/whatever)) |
+| 🟡 WARNING | test-warning-two-comments | summary<br>description |
+
+</details>
+`, string(all))
+}
+
+func TestMarkdownOutputEscaping(t *testing.T) {
+	t.Parallel()
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   v1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: v1.ObjectMeta{Name: "foo"},
+			Checks: []scorecard.TestScore{
+				{
+					Check: domain.Check{Name: "pipes"},
+					Grade: scorecard.GradeCritical,
+					Comments: []scorecard.TestScoreComment{
+						{Summary: "a | b", Description: "<script>\nnext line"},
+					},
+				},
+			},
+		},
+	}
+	all, err := ioutil.ReadAll(Output(card))
+	assert.Nil(t, err)
+	assert.Contains(t, string(all), "| 🔴 CRITICAL | pipes | a \\| b<br>&lt;script&gt;<br>next line |\n")
+}
+
+func TestMarkdownOutputEmpty(t *testing.T) {
+	t.Parallel()
+	all, err := ioutil.ReadAll(Output(&scorecard.Scorecard{}))
+	assert.Nil(t, err)
+	assert.Equal(t, "## kube-score\n\n**Score: 10.0/10** · 0 objects · 🔴 0 critical · 🟡 0 warnings\n\n", string(all))
+}
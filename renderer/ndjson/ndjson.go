@@ -0,0 +1,55 @@
+// Package ndjson is currently considered to be in alpha status, and is not covered
+// by the API stability guarantees
+package ndjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/zegl/kube-score/renderer/json_v2"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Encoder writes scored objects as newline delimited JSON, with one object of the json v2 format per line
+type Encoder struct {
+	enc *json.Encoder
+}
+
+// NewEncoder returns an encoder that writes to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{enc: json.NewEncoder(w)}
+}
+
+// Encode writes the object with the scorecard key to the output. It's used to write the objects while they are
+// scored, so that the output of large inputs is never held in memory.
+func (e *Encoder) Encode(key string, o *scorecard.ScoredObject) error {
+	return e.enc.Encode(json_v2.ConvertObject(key, o))
+}
+
+// Write writes the scorecard as newline delimited JSON to w, sorted by the scorecard key
+func Write(w io.Writer, scoreCard *scorecard.Scorecard) error {
+	var keys []string
+	for k := range *scoreCard {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	enc := NewEncoder(w)
+	for _, key := range keys {
+		if err := enc.Encode(key, (*scoreCard)[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Output outputs the scorecard as newline delimited JSON
+func Output(scoreCard *scorecard.Scorecard) io.Reader {
+	w := bytes.NewBufferString("")
+	if err := Write(w, scoreCard); err != nil {
+		panic(err)
+	}
+	return w
+}
@@ -0,0 +1,57 @@
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zegl/kube-score/renderer/internal/testcard"
+	"github.com/zegl/kube-score/renderer/json_v2"
+)
+
+func TestNDJSONOutput(t *testing.T) {
+	t.Parallel()
+	scanner := bufio.NewScanner(Output(testcard.New()))
+
+	var objects []json_v2.ScoredObject
+	for scanner.Scan() {
+		var o json_v2.ScoredObject
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &o))
+		objects = append(objects, o)
+	}
+	assert.NoError(t, scanner.Err())
+
+	// The objects are sorted by the scorecard key
+	assert.Len(t, objects, 2)
+	assert.Equal(t, "a", objects[0].ObjectName)
+	assert.Equal(t, "foo", objects[0].ObjectMeta.Name)
+	assert.Equal(t, "foo.yaml", objects[0].FileName)
+	assert.Equal(t, 7.5, objects[0].Score)
+	assert.Len(t, objects[0].Checks, 4)
+	assert.Equal(t, "b", objects[1].ObjectName)
+	assert.Equal(t, "bar-no-namespace", objects[1].ObjectMeta.Name)
+}
+
+func TestEncoder(t *testing.T) {
+	t.Parallel()
+	card := *testcard.New()
+
+	// Objects are written in the order that they are encoded
+	var out bytes.Buffer
+	enc := NewEncoder(&out)
+	assert.NoError(t, enc.Encode("b", card["b"]))
+	assert.NoError(t, enc.Encode("a", card["a"]))
+
+	var objects []json_v2.ScoredObject
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var o json_v2.ScoredObject
+		assert.NoError(t, dec.Decode(&o))
+		objects = append(objects, o)
+	}
+	assert.Len(t, objects, 2)
+	assert.Equal(t, "b", objects[0].ObjectName)
+	assert.Equal(t, "a", objects[1].ObjectName)
+}
@@ -4,27 +4,44 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"path/filepath"
+	"strings"
 
 	"github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/sarif"
 	"github.com/zegl/kube-score/scorecard"
 )
 
+// checksDocumentationURL is the documentation of all checks, used as the help URI of the rules
+const checksDocumentationURL = "https://github.com/zegl/kube-score/blob/master/README_CHECKS.md"
+
 func Output(input *scorecard.Scorecard) io.Reader {
 	var results []sarif.Results
 	var rules []sarif.Rules
 
-	addRule := func(check domain.Check) {
-		for _, r := range rules {
+	// addRule adds the rule for the check if it doesn't exist yet, and returns the index of the rule
+	addRule := func(check domain.Check, level string) int {
+		for i, r := range rules {
 			if r.ID == check.ID {
-				return
+				if level == "error" {
+					rules[i].Properties.ProblemSeverity = level
+				}
+				return i
 			}
 		}
 
 		rules = append(rules, sarif.Rules{
-			ID:   check.ID,
-			Name: check.Name,
+			ID:               check.ID,
+			Name:             check.Name,
+			ShortDescription: &sarif.Message{Text: check.Name},
+			FullDescription:  &sarif.Message{Text: check.Comment},
+			HelpURI:          checksDocumentationURL,
+			Properties: &sarif.RuleProperties{
+				ProblemSeverity: level,
+				Tags:            []string{check.TargetType},
+			},
 		})
+		return len(rules) - 1
 	}
 
 	for _, v := range *input {
@@ -34,33 +51,47 @@ func Output(input *scorecard.Scorecard) io.Reader {
 			}
 
 			var level string
-			switch check.Grade {
-			case scorecard.GradeCritical:
+			severity := check.Severity()
+			switch severity {
+			case scorecard.SeverityCritical, scorecard.SeverityHigh:
 				level = "error"
-			case scorecard.GradeWarning:
+			case scorecard.SeverityMedium:
 				level = "warning"
 			default:
 				continue
 			}
 
-			addRule(check.Check)
+			ruleIndex := addRule(check.Check, level)
 
 			for _, comment := range check.Comments {
+				location := v.CommentFileLocation(comment)
+				column := location.Column
+				if column == 0 {
+					column = 1
+				}
 				results = append(results, sarif.Results{
 					Message: sarif.Message{
 						Text: comment.Summary,
 					},
-					RuleID: check.Check.ID,
-					Level:  level,
+					RuleID:    check.Check.ID,
+					RuleIndex: ruleIndex,
+					Level:     level,
 					Properties: sarif.ResultsProperties{
 						IssueConfidence: "HIGH",
-						IssueSeverity:   "HIGH",
+						IssueSeverity:   strings.ToUpper(severity.String()),
+						Remediation:     convertRemediation(comment.Remediation),
 					},
 					Locations: []sarif.Locations{
 						{
 							PhysicalLocation: sarif.PhysicalLocation{
 								ArtifactLocation: sarif.ArtifactLocation{
-									URI: "file://" + v.FileLocation.Name,
+									URI: artifactURI(location.Name),
+								},
+								// The location is the field that the comment refers to, or the start of the YAML
+								// document of the object
+								Region: sarif.Region{
+									StartLine:   location.Line,
+									StartColumn: column,
 								},
 								ContextRegion: sarif.ContextRegion{
 									StartLine: v.FileLocation.Line,
@@ -80,6 +111,9 @@ func Output(input *scorecard.Scorecard) io.Reader {
 				Rules: rules,
 			},
 		},
+		Properties: sarif.Properties{
+			Score: input.Score(),
+		},
 		Results: results,
 	}
 	res := sarif.Sarif{
@@ -94,3 +128,27 @@ func Output(input *scorecard.Scorecard) io.Reader {
 	}
 	return bytes.NewBuffer(j)
 }
+
+// artifactURI returns the URI of the file. Relative paths are kept relative, so that they can be resolved against
+// the root of the repository by tools such as GitHub Code Scanning.
+func artifactURI(name string) string {
+	if filepath.IsAbs(name) {
+		return "file://" + filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(name)
+}
+
+func convertRemediation(in *scorecard.Remediation) *sarif.Remediation {
+	if in == nil {
+		return nil
+	}
+	res := &sarif.Remediation{StrategicMergePatch: in.StrategicMergePatch}
+	for _, op := range in.JSONPatch {
+		res.JSONPatch = append(res.JSONPatch, sarif.JSONPatchOperation{
+			Op:    op.Op,
+			Path:  op.Path,
+			Value: op.Value,
+		})
+	}
+	return res
+}
@@ -0,0 +1,87 @@
+package sarif
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/sarif"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestSarifOutput(t *testing.T) {
+	t.Parallel()
+
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:     v1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta:   v1.ObjectMeta{Name: "foo"},
+			FileLocation: domain.FileLocation{Name: "deploy/app.yaml", Line: 12},
+			Checks: []scorecard.TestScore{
+				{
+					Check:    domain.Check{ID: "test-critical", Name: "Test Critical", TargetType: "Deployment", Comment: "Makes sure that it's critical"},
+					Grade:    scorecard.GradeCritical,
+					Comments: []scorecard.TestScoreComment{{Summary: "critical summary"}},
+				},
+				{
+					Check: domain.Check{ID: "test-warning", Name: "Test Warning", TargetType: "Deployment"},
+					Grade: scorecard.GradeWarning,
+					Comments: []scorecard.TestScoreComment{{
+						Summary: "warning summary",
+						Remediation: &scorecard.Remediation{
+							JSONPatch: []scorecard.JSONPatchOperation{{Op: "add", Path: "/spec/template/spec/hostNetwork", Value: false}},
+						},
+					}},
+				},
+				{
+					Check:    domain.Check{ID: "test-ok", Name: "Test OK"},
+					Grade:    scorecard.GradeAllOK,
+					Comments: []scorecard.TestScoreComment{{Summary: "ok summary"}},
+				},
+			},
+		},
+	}
+
+	output, err := ioutil.ReadAll(Output(card))
+	assert.Nil(t, err)
+
+	var res sarif.Sarif
+	assert.Nil(t, json.Unmarshal(output, &res))
+	assert.Len(t, res.Runs, 1)
+
+	rules := res.Runs[0].Tool.Driver.Rules
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "test-critical", rules[0].ID)
+	assert.Equal(t, "Makes sure that it's critical", rules[0].FullDescription.Text)
+	assert.Equal(t, checksDocumentationURL, rules[0].HelpURI)
+	assert.Equal(t, "error", rules[0].Properties.ProblemSeverity)
+	assert.Equal(t, "warning", rules[1].Properties.ProblemSeverity)
+
+	results := res.Runs[0].Results
+	assert.Len(t, results, 2)
+	assert.Equal(t, "error", results[0].Level)
+	assert.Equal(t, "CRITICAL", results[0].Properties.IssueSeverity)
+	assert.Equal(t, 0, results[0].RuleIndex)
+	assert.Equal(t, "warning", results[1].Level)
+	assert.Equal(t, "MEDIUM", results[1].Properties.IssueSeverity)
+	assert.Equal(t, 1, results[1].RuleIndex)
+	assert.Nil(t, results[0].Properties.Remediation)
+	assert.Equal(t, &sarif.Remediation{
+		JSONPatch: []sarif.JSONPatchOperation{{Op: "add", Path: "/spec/template/spec/hostNetwork", Value: false}},
+	}, results[1].Properties.Remediation)
+
+	location := results[0].Locations[0].PhysicalLocation
+	assert.Equal(t, "deploy/app.yaml", location.ArtifactLocation.URI)
+	assert.Equal(t, 12, location.Region.StartLine)
+	assert.Equal(t, 1, location.Region.StartColumn)
+}
+
+func TestArtifactURI(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "deploy/app.yaml", artifactURI("deploy/app.yaml"))
+	assert.Equal(t, "file:///tmp/app.yaml", artifactURI("/tmp/app.yaml"))
+}
@@ -0,0 +1,123 @@
+// Package tap is currently considered to be in alpha status, and is not covered
+// by the API stability guarantees
+package tap
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// diagnostics is the YAML block that follows a test line that is not ok
+type diagnostics struct {
+	Grade    string    `yaml:"grade"`
+	Severity string    `yaml:"severity"`
+	File     string    `yaml:"file,omitempty"`
+	Line     int       `yaml:"line,omitempty"`
+	Comments []comment `yaml:"comments,omitempty"`
+}
+
+type comment struct {
+	Path             string `yaml:"path,omitempty"`
+	Summary          string `yaml:"summary"`
+	Description      string `yaml:"description,omitempty"`
+	DocumentationURL string `yaml:"documentationUrl,omitempty"`
+}
+
+// Output outputs the scorecard in the Test Anything Protocol (TAP) version 13. Each check of each object is a
+// test, checks with a grade of WARNING or CRITICAL are not ok, and are followed by a YAML diagnostics block.
+// Skipped checks are reported with the SKIP directive.
+// https://testanything.org/tap-version-13-specification.html
+func Output(scoreCard *scorecard.Scorecard) io.Reader {
+	w := bytes.NewBufferString("TAP version 13\n")
+
+	// Print the items sorted by scorecard key
+	var keys []string
+	for k := range *scoreCard {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var tests int
+	for _, key := range keys {
+		tests += len((*scoreCard)[key].Checks)
+	}
+	fmt.Fprintf(w, "1..%d\n", tests)
+
+	var n int
+	for _, key := range keys {
+		scoredObject := (*scoreCard)[key]
+
+		for _, card := range scoredObject.Checks {
+			n++
+			description := escape(scoredObject.HumanFriendlyRef() + ": " + card.Check.Name)
+
+			if card.Skipped {
+				var reason string
+				if len(card.Comments) > 0 {
+					reason = " " + escape(card.Comments[0].Summary)
+				}
+				fmt.Fprintf(w, "ok %d - %s # SKIP%s\n", n, description, reason)
+				continue
+			}
+
+			if card.Grade > scorecard.GradeWarning {
+				fmt.Fprintf(w, "ok %d - %s\n", n, description)
+				continue
+			}
+
+			fmt.Fprintf(w, "not ok %d - %s\n", n, description)
+
+			diag := diagnostics{
+				Grade:    card.Grade.String(),
+				Severity: card.Severity().String(),
+				File:     scoredObject.FileLocation.Name,
+				Line:     scoredObject.FileLocation.Line,
+			}
+			// Point at the field of the first comment, if it's known
+			if len(card.Comments) > 0 {
+				diag.Line = scoredObject.CommentFileLocation(card.Comments[0]).Line
+			}
+			for _, c := range card.Comments {
+				diag.Comments = append(diag.Comments, comment{
+					Path:             c.Path,
+					Summary:          c.Summary,
+					Description:      c.Description,
+					DocumentationURL: c.DocumentationURL,
+				})
+			}
+			writeDiagnostics(w, diag)
+		}
+	}
+
+	return w
+}
+
+func writeDiagnostics(w io.Writer, diag diagnostics) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(diag); err != nil {
+		panic(err)
+	}
+
+	fmt.Fprintf(w, "  ---\n")
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+	fmt.Fprintf(w, "  ...\n")
+}
+
+// escape escapes the characters that have a special meaning in a test line
+func escape(s string) string {
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	s = strings.Replace(s, "#", "\\#", -1)
+	s = strings.Replace(s, "\n", " ", -1)
+	return s
+}
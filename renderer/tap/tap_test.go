@@ -0,0 +1,53 @@
+package tap
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zegl/kube-score/renderer/internal/testcard"
+)
+
+func TestTAPOutput(t *testing.T) {
+	t.Parallel()
+	all, err := ioutil.ReadAll(Output(testcard.New()))
+	assert.Nil(t, err)
+	assert.Equal(t, `TAP version 13
+1..8
+not ok 1 - foo/foofoo v1/Testing: test-warning-two-comments
+  ---
+  grade: WARNING
+  severity: medium
+  file: foo.yaml
+  line: 12
+  comments:
+    - path: a
+      summary: summary
+      description: description
+    - summary: summary
+      description: description
+  ...
+ok 2 - foo/foofoo v1/Testing: test-ok-comment
+ok 3 - foo/foofoo v1/Testing: test-skipped-comment # SKIP skipped sum
+ok 4 - foo/foofoo v1/Testing: test-skipped-no-comment # SKIP
+not ok 5 - bar-no-namespace v1/Testing: test-warning-two-comments
+  ---
+  grade: WARNING
+  severity: medium
+  comments:
+    - path: a
+      summary: summary
+      description: description
+    - summary: summary
+      description: description
+  ...
+ok 6 - bar-no-namespace v1/Testing: test-ok-comment
+ok 7 - bar-no-namespace v1/Testing: test-skipped-comment # SKIP skipped sum
+ok 8 - bar-no-namespace v1/Testing: test-skipped-no-comment # SKIP
+`, string(all))
+}
+
+func TestTAPEscape(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, `foo \# bar \\ baz`, escape("foo # bar \\ baz"))
+}
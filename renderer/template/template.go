@@ -0,0 +1,119 @@
+// Package template is currently considered to be in alpha status, and is not covered
+// by the API stability guarantees
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Data is the data that the template is executed with
+type Data struct {
+	// Score is the score of the run, from 1 to 10
+	Score float64
+
+	// Objects are all scored objects, sorted by their scorecard key
+	Objects []*scorecard.ScoredObject
+}
+
+// Funcs are the functions that are available in the templates, in addition to the text/template builtins
+var Funcs = template.FuncMap{
+	// gradeName returns the name of a grade, such as "CRITICAL"
+	"gradeName": func(g scorecard.Grade) string {
+		return g.String()
+	},
+
+	// severity returns the name of the severity of a grade, such as "high"
+	"severity": func(g scorecard.Grade) string {
+		return g.Severity().String()
+	},
+
+	// atOrBelow returns the checks that are not skipped, and that have the grade or a lower grade. The grade is
+	// the name of a grade or a severity, such as "critical", "high" or "warning".
+	"atOrBelow": func(grade string, checks []scorecard.TestScore) ([]scorecard.TestScore, error) {
+		threshold, err := scorecard.ParseGrade(grade)
+		if err != nil {
+			return nil, err
+		}
+		var res []scorecard.TestScore
+		for _, c := range checks {
+			if !c.Skipped && c.Grade <= threshold {
+				res = append(res, c)
+			}
+		}
+		return res, nil
+	},
+
+	// objectsAtOrBelow returns the objects that have any check with the grade or a lower grade
+	"objectsAtOrBelow": func(grade string, objects []*scorecard.ScoredObject) ([]*scorecard.ScoredObject, error) {
+		threshold, err := scorecard.ParseGrade(grade)
+		if err != nil {
+			return nil, err
+		}
+		var res []*scorecard.ScoredObject
+		for _, o := range objects {
+			if o.AnyBelowOrEqualToGrade(threshold) {
+				res = append(res, o)
+			}
+		}
+		return res, nil
+	},
+
+	// skipped returns the checks that are skipped
+	"skipped": func(checks []scorecard.TestScore) []scorecard.TestScore {
+		var res []scorecard.TestScore
+		for _, c := range checks {
+			if c.Skipped {
+				res = append(res, c)
+			}
+		}
+		return res
+	},
+
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"join":      strings.Join,
+	"repeat":    strings.Repeat,
+	"trimSpace": strings.TrimSpace,
+}
+
+// Parse parses the template file at path, with Funcs
+func Parse(path string) (*template.Template, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(Funcs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Output executes the template with the scorecard as Data
+func Output(scoreCard *scorecard.Scorecard, tmpl *template.Template) (io.Reader, error) {
+	var keys []string
+	for k := range *scoreCard {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	data := Data{Score: scoreCard.Score()}
+	for _, key := range keys {
+		data.Objects = append(data.Objects, (*scoreCard)[key])
+	}
+
+	w := bytes.NewBufferString("")
+	if err := tmpl.Execute(w, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+	return w, nil
+}
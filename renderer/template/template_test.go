@@ -0,0 +1,65 @@
+package template
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zegl/kube-score/renderer/internal/testcard"
+)
+
+func TestTemplateOutput(t *testing.T) {
+	t.Parallel()
+	tmpl := template.Must(template.New("test").Funcs(Funcs).Parse(`Score: {{printf "%.1f" .Score}}
+{{range objectsAtOrBelow "warning" .Objects -}}
+{{.HumanFriendlyRef}}
+{{range atOrBelow "warning" .Checks}}  [{{gradeName .Grade | lower}}] {{.Check.Name}}
+{{end}}  skipped: {{len (skipped .Checks)}}
+{{end}}`))
+
+	r, err := Output(testcard.New(), tmpl)
+	assert.NoError(t, err)
+	all, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, `Score: 7.5
+foo/foofoo v1/Testing
+  [warning] test-warning-two-comments
+  skipped: 2
+bar-no-namespace v1/Testing
+  [warning] test-warning-two-comments
+  skipped: 2
+`, string(all))
+}
+
+func TestTemplateOutputError(t *testing.T) {
+	t.Parallel()
+	tmpl := template.Must(template.New("test").Funcs(Funcs).Parse(`{{range objectsAtOrBelow "bad" .Objects}}{{end}}`))
+	_, err := Output(testcard.New(), tmpl)
+	assert.Error(t, err)
+}
+
+func TestParse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kube-score")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "report.tmpl")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{{len .Objects}} objects`), 0644))
+	tmpl, err := Parse(path)
+	assert.NoError(t, err)
+	r, err := Output(testcard.New(), tmpl)
+	assert.NoError(t, err)
+	all, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "2 objects", string(all))
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{{unknownFunc}}`), 0644))
+	_, err = Parse(path)
+	assert.Error(t, err)
+
+	_, err = Parse(filepath.Join(dir, "missing.tmpl"))
+	assert.Error(t, err)
+}
@@ -11,9 +11,18 @@ type Sarif struct {
 }
 
 type Rules struct {
-	ID      string `json:"id,omitempty"`
-	Name    string `json:"name,omitempty"`
-	HelpURI string `json:"helpUri,omitempty"`
+	ID               string          `json:"id,omitempty"`
+	Name             string          `json:"name,omitempty"`
+	ShortDescription *Message        `json:"shortDescription,omitempty"`
+	FullDescription  *Message        `json:"fullDescription,omitempty"`
+	HelpURI          string          `json:"helpUri,omitempty"`
+	Properties       *RuleProperties `json:"properties,omitempty"`
+}
+
+type RuleProperties struct {
+	// ProblemSeverity is the highest severity of the results of the rule, one of "error", "warning" or "recommendation"
+	ProblemSeverity string   `json:"problem.severity,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
 }
 
 type Driver struct {
@@ -49,6 +58,8 @@ type Invocations struct {
 }
 
 type Properties struct {
+	// Score is the kube-score score of the run, from 1 to 10
+	Score float64 `json:"score"`
 }
 
 type Message struct {
@@ -60,8 +71,9 @@ type Snippet struct {
 }
 
 type Region struct {
-	Snippet   Snippet `json:"snippet,omitempty"`
-	StartLine int     `json:"startLine,omitempty"`
+	Snippet     Snippet `json:"snippet,omitempty"`
+	StartLine   int     `json:"startLine,omitempty"`
+	StartColumn int     `json:"startColumn,omitempty"`
 }
 
 type ArtifactLocation struct {
@@ -85,8 +97,22 @@ type Locations struct {
 }
 
 type ResultsProperties struct {
-	IssueConfidence string `json:"issue_confidence,omitempty"`
-	IssueSeverity   string `json:"issue_severity,omitempty"`
+	IssueConfidence string       `json:"issue_confidence,omitempty"`
+	IssueSeverity   string       `json:"issue_severity,omitempty"`
+	Remediation     *Remediation `json:"remediation,omitempty"`
+}
+
+// Remediation is a machine readable fix of a result, as a JSON Patch (RFC 6902) and as a Kubernetes strategic merge
+// patch. It's not a part of the SARIF standard, and is stored in the property bag of the result.
+type Remediation struct {
+	JSONPatch           []JSONPatchOperation   `json:"json_patch,omitempty"`
+	StrategicMergePatch map[string]interface{} `json:"strategic_merge_patch,omitempty"`
+}
+
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
 }
 
 type Results struct {
@@ -95,7 +121,7 @@ type Results struct {
 	Locations  []Locations       `json:"locations,omitempty"`
 	Properties ResultsProperties `json:"properties,omitempty"`
 	RuleID     string            `json:"ruleId,omitempty"`
-	RuleIndex  int               `json:"ruleIndex,omitempty"`
+	RuleIndex  int               `json:"ruleIndex"`
 }
 
 type Run struct {
@@ -15,14 +15,104 @@ import (
 )
 
 func Register(allChecks *checks.Checks, allHPAs []ks.HpaTargeter, allServices []ks.Service) {
-	allChecks.RegisterDeploymentCheck("Deployment has host PodAntiAffinity", "Makes sure that a podAntiAffinity has been set that prevents multiple pods from being scheduled on the same node. https://kubernetes.io/docs/concepts/configuration/assign-pod-node/", deploymentHasAntiAffinity)
-	allChecks.RegisterStatefulSetCheck("StatefulSet has host PodAntiAffinity", "Makes sure that a podAntiAffinity has been set that prevents multiple pods from being scheduled on the same node. https://kubernetes.io/docs/concepts/configuration/assign-pod-node/", statefulsetHasAntiAffinity)
+	allChecks.RegisterDeploymentCheck("Deployment has pod spread", "Makes sure that a podAntiAffinity or topologySpreadConstraints has been set that prevents multiple pods from being scheduled on the same node or in the same zone. https://kubernetes.io/docs/concepts/workloads/pods/pod-topology-spread-constraints/", deploymentHasPodSpread)
+	allChecks.RegisterStatefulSetCheck("StatefulSet has pod spread", "Makes sure that a podAntiAffinity or topologySpreadConstraints has been set that prevents multiple pods from being scheduled on the same node or in the same zone. https://kubernetes.io/docs/concepts/workloads/pods/pod-topology-spread-constraints/", statefulsetHasPodSpread)
 
 	allChecks.RegisterDeploymentCheck("Deployment targeted by HPA does not have replicas configured", "Makes sure that Deployments using a HorizontalPodAutoscaler doesn't have a statically configured replica count set", hpaDeploymentNoReplicas(allHPAs))
+	allChecks.RegisterStatefulSetCheck("StatefulSet targeted by HPA does not have replicas configured", "Makes sure that StatefulSets using a HorizontalPodAutoscaler doesn't have a statically configured replica count set", hpaStatefulSetNoReplicas(allHPAs))
+	allChecks.RegisterOptionalDeploymentCheck("Deployment Service Replicas", "Makes sure that Deployments that are exposed by a Service have more than one replica, unless they are targeted by a HorizontalPodAutoscaler", deploymentServiceReplicas(allHPAs, allServices))
+	allChecks.RegisterOptionalStatefulSetCheck("StatefulSet Service Replicas", "Makes sure that StatefulSets that are exposed by a Service have more than one replica, unless they are targeted by a HorizontalPodAutoscaler", statefulsetServiceReplicas(allHPAs, allServices))
 	allChecks.RegisterStatefulSetCheck("StatefulSet has ServiceName", "Makes sure that StatefulSets have an existing headless serviceName.", statefulsetHasServiceName(allServices))
+	allChecks.MarkCrossObject("statefulset-has-servicename")
 
 	allChecks.RegisterDeploymentCheck("Deployment Pod Selector labels match template metadata labels", "Ensure the StatefulSet selector labels match the template metadata labels.", deploymentSelectorLabelsMatching)
 	allChecks.RegisterStatefulSetCheck("StatefulSet Pod Selector labels match template metadata labels", "Ensure the StatefulSet selector labels match the template metadata labels.", statefulSetSelectorLabelsMatching)
+
+	allChecks.RegisterStatefulSetCheck("StatefulSet Update Strategy", "Makes sure that StatefulSets explicitly set an updateStrategy", statefulsetUpdateStrategy)
+	allChecks.RegisterOptionalStatefulSetCheck("StatefulSet Pod Management Policy", "Makes sure that StatefulSets explicitly set a podManagementPolicy", statefulsetPodManagementPolicy)
+	allChecks.RegisterStatefulSetCheck("StatefulSet Volume Claim Templates Storage", "Makes sure that all volumeClaimTemplates of StatefulSets request storage", statefulsetVolumeClaimTemplatesStorage)
+
+	allChecks.RegisterDeploymentCheck("Deployment Rolling Update", "Makes sure that the rollingUpdate maxUnavailable does not allow all replicas to be unavailable during a rollout", deploymentRollingUpdate)
+	allChecks.RegisterDeploymentCheck("Deployment Progress Deadline", "Makes sure that progressDeadlineSeconds is not excessively large", deploymentProgressDeadline)
+	allChecks.RegisterOptionalDeploymentCheck("Deployment Min Ready Seconds", "Makes sure that minReadySeconds is set, so that pods that crash shortly after becoming ready stop the rollout", deploymentMinReadySeconds)
+	allChecks.RegisterOptionalDeploymentCheck("Deployment Revision History Limit", "Makes sure that revisionHistoryLimit is explicitly set, and not larger than 10", deploymentRevisionHistoryLimit)
+	allChecks.Document("deployment-has-pod-spread", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Add a podAntiAffinity or a topologySpreadConstraint that spreads the pods over nodes or zones",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/pods/pod-topology-spread-constraints/",
+	})
+	allChecks.Document("statefulset-has-pod-spread", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Add a podAntiAffinity or a topologySpreadConstraint that spreads the pods over nodes or zones",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/pods/pod-topology-spread-constraints/",
+	})
+	allChecks.Document("deployment-targeted-by-hpa-does-not-have-replicas-configured", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Remove replicas from the Deployment, and let the HorizontalPodAutoscaler control it",
+		URL:         "https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale/#migrating-deployments-and-statefulsets-to-horizontal-autoscaling",
+	})
+	allChecks.Document("statefulset-targeted-by-hpa-does-not-have-replicas-configured", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Remove replicas from the StatefulSet, and let the HorizontalPodAutoscaler control it",
+		URL:         "https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale/#migrating-deployments-and-statefulsets-to-horizontal-autoscaling",
+	})
+	allChecks.Document("deployment-service-replicas", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set replicas to 2 or more, or let a HorizontalPodAutoscaler control the replicas",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/deployment/#scaling-a-deployment",
+	})
+	allChecks.Document("statefulset-service-replicas", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set replicas to 2 or more, or let a HorizontalPodAutoscaler control the replicas",
+		URL:         "https://kubernetes.io/docs/tasks/run-application/scale-stateful-set/",
+	})
+	allChecks.Document("statefulset-has-servicename", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set serviceName to the name of a headless Service that selects the pods of the StatefulSet",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#stable-network-id",
+	})
+	allChecks.Document("deployment-pod-selector-labels-match-template-metadata-labels", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Make sure that all labels of the selector are set on the pod template",
+	})
+	allChecks.Document("statefulset-pod-selector-labels-match-template-metadata-labels", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Make sure that all labels of the selector are set on the pod template",
+	})
+	allChecks.Document("statefulset-update-strategy", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set updateStrategy.type to RollingUpdate or OnDelete",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#update-strategies",
+	})
+	allChecks.Document("statefulset-pod-management-policy", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set podManagementPolicy to OrderedReady or Parallel",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#pod-management-policies",
+	})
+	allChecks.Document("statefulset-volume-claim-templates-storage", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set resources.requests.storage on all volumeClaimTemplates",
+	})
+	allChecks.Document("deployment-rolling-update", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Lower rollingUpdate.maxUnavailable, so that at least one replica is available during a rollout",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/deployment/#max-unavailable",
+	})
+	allChecks.Document("deployment-progress-deadline", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Lower progressDeadlineSeconds, or remove it to use the default of 600 seconds",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/deployment/#progress-deadline-seconds",
+	})
+	allChecks.Document("deployment-min-ready-seconds", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set minReadySeconds to the number of seconds that a new pod should be ready before it is considered available",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/deployment/#min-ready-seconds",
+	})
+	allChecks.Document("deployment-revision-history-limit", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set revisionHistoryLimit to 10 or lower",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/deployment/#revision-history-limit",
+	})
 }
 
 func hpaDeploymentNoReplicas(allHPAs []ks.HpaTargeter) func(deployment appsv1.Deployment) (scorecard.TestScore, error) {
@@ -53,79 +143,79 @@ func hpaDeploymentNoReplicas(allHPAs []ks.HpaTargeter) func(deployment appsv1.De
 	}
 }
 
-func deploymentHasAntiAffinity(deployment appsv1.Deployment) (score scorecard.TestScore, err error) {
-	// Ignore if the deployment only has a single replica
-	// If replicas is not explicitly set, we'll still warn if the anti affinity is missing
-	// as that might indicate use of a Horizontal Pod Autoscaler
-	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas < 2 {
-		score.Skipped = true
-		score.AddComment("", "Skipped because the deployment has less than 2 replicas", "")
-		return
-	}
+func hpaStatefulSetNoReplicas(allHPAs []ks.HpaTargeter) func(statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
+	return func(statefulset appsv1.StatefulSet) (score scorecard.TestScore, err error) {
+		// If is targeted by a HPA
+		for _, hpa := range allHPAs {
+			target := hpa.HpaTarget()
 
-	warn := func() {
-		score.Grade = scorecard.GradeWarning
-		score.AddComment("", "Deployment does not have a host podAntiAffinity set", "It's recommended to set a podAntiAffinity that stops multiple pods from a deployment from being scheduled on the same node. This increases availability in case the node becomes unavailable.")
-	}
+			if hpa.GetObjectMeta().Namespace == statefulset.Namespace &&
+				strings.ToLower(target.Kind) == strings.ToLower(statefulset.Kind) &&
+				target.Name == statefulset.Name {
 
-	affinity := deployment.Spec.Template.Spec.Affinity
-	if affinity == nil || affinity.PodAntiAffinity == nil {
-		warn()
-		return
-	}
+				if statefulset.Spec.Replicas == nil {
+					score.Grade = scorecard.GradeAllOK
+					return
+				}
 
-	lables := internal.MapLables(deployment.Spec.Template.GetObjectMeta().GetLabels())
+				score.Grade = scorecard.GradeCritical
+				score.AddComment("", "The statefulset is targeted by a HPA, but a static replica count is configured in the StatefulSetSpec", "When replicas are both statically set and managed by the HPA, the replicas will be changed to the statically configured count when the spec is applied, even if the HPA wants the replica count to be higher.")
+				return
+			}
+		}
 
-	if hasPodAntiAffinity(lables, affinity) {
 		score.Grade = scorecard.GradeAllOK
+		score.Skipped = true
+		score.AddComment("", "Skipped because the statefulset is not targeted by a HorizontalPodAutoscaler", "")
 		return
 	}
+}
 
-	warn()
-	return
+func deploymentHasPodSpread(deployment appsv1.Deployment) (score scorecard.TestScore, err error) {
+	return podSpread("deployment", deployment.Spec.Replicas, deployment.Spec.Template), nil
 }
 
-func statefulsetHasAntiAffinity(statefulset appsv1.StatefulSet) (score scorecard.TestScore, err error) {
-	// Ignore if the statefulset only has a single replica
-	// If replicas is not explicitly set, we'll still warn if the anti affinity is missing
+func statefulsetHasPodSpread(statefulset appsv1.StatefulSet) (score scorecard.TestScore, err error) {
+	return podSpread("statefulset", statefulset.Spec.Replicas, statefulset.Spec.Template), nil
+}
+
+func podSpread(kind string, replicas *int32, template corev1.PodTemplateSpec) (score scorecard.TestScore) {
+	// Ignore if the workload only has a single replica
+	// If replicas is not explicitly set, we'll still warn if the spread is missing
 	// as that might indicate use of a Horizontal Pod Autoscaler
-	if statefulset.Spec.Replicas != nil && *statefulset.Spec.Replicas < 2 {
+	if replicas != nil && *replicas < 2 {
 		score.Skipped = true
-		score.AddComment("", "Skipped because the statefulset has less than 2 replicas", "")
+		score.AddComment("", fmt.Sprintf("Skipped because the %s has less than 2 replicas", kind), "")
 		return
 	}
 
-	warn := func() {
-		score.Grade = scorecard.GradeWarning
-		score.AddComment("", "StatefulSet does not have a host podAntiAffinity set", "It's recommended to set a podAntiAffinity that stops multiple pods from a statefulset from being scheduled on the same node. This increases availability in case the node becomes unavailable.")
-	}
+	lables := internal.MapLables(template.GetObjectMeta().GetLabels())
 
-	affinity := statefulset.Spec.Template.Spec.Affinity
-	if affinity == nil || affinity.PodAntiAffinity == nil {
-		warn()
-		return
-	}
-
-	lables := internal.MapLables(statefulset.Spec.Template.GetObjectMeta().GetLabels())
-
-	if hasPodAntiAffinity(lables, affinity) {
+	if hasPodAntiAffinity(lables, template.Spec.Affinity) || hasTopologySpreadConstraint(lables, template.Spec.TopologySpreadConstraints) {
 		score.Grade = scorecard.GradeAllOK
 		return
 	}
 
-	warn()
+	score.Grade = scorecard.GradeWarning
+	score.AddComment("", "No podAntiAffinity or topologySpreadConstraints across nodes or zones is set",
+		fmt.Sprintf("It's recommended to set a podAntiAffinity or topologySpreadConstraints that stops multiple pods from a %s from being scheduled on the same node or in the same zone. This increases availability in case the node or zone becomes unavailable.", kind))
 	return
 }
 
+// approvedTopologyKeys are the topology keys that spread pods across nodes, zones or regions
+var approvedTopologyKeys = map[string]struct{}{
+	"kubernetes.io/hostname":        {},
+	"topology.kubernetes.io/region": {},
+	"topology.kubernetes.io/zone":   {},
+
+	// Deprecated in Kubernetes v1.17
+	"failure-domain.beta.kubernetes.io/region": {},
+	"failure-domain.beta.kubernetes.io/zone":   {},
+}
+
 func hasPodAntiAffinity(selfLabels internal.MapLables, affinity *corev1.Affinity) bool {
-	approvedTopologyKeys := map[string]struct{}{
-		"kubernetes.io/hostname":        {},
-		"topology.kubernetes.io/region": {},
-		"topology.kubernetes.io/zone":   {},
-
-		// Deprecated in Kubernetes v1.17
-		"failure-domain.beta.kubernetes.io/region": {},
-		"failure-domain.beta.kubernetes.io/zone":   {},
+	if affinity == nil || affinity.PodAntiAffinity == nil {
+		return false
 	}
 
 	for _, pref := range affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
@@ -151,6 +241,20 @@ func hasPodAntiAffinity(selfLabels internal.MapLables, affinity *corev1.Affinity
 	return false
 }
 
+func hasTopologySpreadConstraint(selfLabels internal.MapLables, constraints []corev1.TopologySpreadConstraint) bool {
+	for _, constraint := range constraints {
+		if _, ok := approvedTopologyKeys[constraint.TopologyKey]; ok {
+			if selector, err := metav1.LabelSelectorAsSelector(constraint.LabelSelector); err == nil {
+				if selector.Matches(selfLabels) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
 func statefulsetHasServiceName(allServices []ks.Service) func(statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
 	return func(statefulset appsv1.StatefulSet) (score scorecard.TestScore, err error) {
 		for _, service := range allServices {
@@ -164,7 +164,7 @@ func antiAffinityTestCases() []testcase {
 	}
 }
 
-func TestStatefulsetHasAntiAffinity(t *testing.T) {
+func TestStatefulsetHasPodSpread(t *testing.T) {
 	t.Parallel()
 	for caseID, tc := range antiAffinityTestCases() {
 		s := appsv1.StatefulSet{
@@ -183,13 +183,13 @@ func TestStatefulsetHasAntiAffinity(t *testing.T) {
 			},
 		}
 
-		score, err := statefulsetHasAntiAffinity(s)
+		score, err := statefulsetHasPodSpread(s)
 		assert.Nil(t, err)
 		assert.Equal(t, tc.expectedGrade, score.Grade, "caseID=%d", caseID)
 	}
 }
 
-func TestDeploymentHasAntiAffinity(t *testing.T) {
+func TestDeploymentHasPodSpread(t *testing.T) {
 	t.Parallel()
 	for caseID, tc := range antiAffinityTestCases() {
 		s := appsv1.Deployment{
@@ -208,7 +208,7 @@ func TestDeploymentHasAntiAffinity(t *testing.T) {
 			},
 		}
 
-		score, err := deploymentHasAntiAffinity(s)
+		score, err := deploymentHasPodSpread(s)
 		assert.Nil(t, err)
 		assert.Equal(t, tc.expectedGrade, score.Grade, "unexpected grade caseID=%d", caseID)
 		assert.Equal(t, tc.expectedSkipped, score.Skipped, "unexpected skipped, caseID=%d", caseID)
@@ -359,6 +359,18 @@ func (d hpav1) HpaTarget() autoscalingv1.CrossVersionObjectReference {
 	return d.Spec.ScaleTargetRef
 }
 
+func (d hpav1) MinReplicas() *int32 {
+	return d.Spec.MinReplicas
+}
+
+func (d hpav1) MaxReplicas() int32 {
+	return d.Spec.MaxReplicas
+}
+
+func (d hpav1) UtilizationMetrics() []ks.HpaUtilizationMetric {
+	return nil
+}
+
 func (hpav1) FileLocation() ks.FileLocation {
 	return ks.FileLocation{}
 }
@@ -0,0 +1,70 @@
+package apps
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/internal"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func deploymentServiceReplicas(allHPAs []ks.HpaTargeter, allServices []ks.Service) func(deployment appsv1.Deployment) (scorecard.TestScore, error) {
+	return func(deployment appsv1.Deployment) (scorecard.TestScore, error) {
+		return serviceReplicas("deployment", deployment.TypeMeta, deployment.ObjectMeta, deployment.Spec.Replicas, deployment.Spec.Template, allHPAs, allServices), nil
+	}
+}
+
+func statefulsetServiceReplicas(allHPAs []ks.HpaTargeter, allServices []ks.Service) func(statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
+	return func(statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
+		return serviceReplicas("statefulset", statefulset.TypeMeta, statefulset.ObjectMeta, statefulset.Spec.Replicas, statefulset.Spec.Template, allHPAs, allServices), nil
+	}
+}
+
+// serviceReplicas warns if a workload that is selected by a Service only has a single replica, as the Service is
+// unavailable when the pod is restarted. Workloads that are targeted by a HPA are skipped, as the HPA controls the
+// replicas.
+func serviceReplicas(kind string, typeMeta metav1.TypeMeta, meta metav1.ObjectMeta, replicas *int32, template corev1.PodTemplateSpec, allHPAs []ks.HpaTargeter, allServices []ks.Service) (score scorecard.TestScore) {
+	for _, hpa := range allHPAs {
+		target := hpa.HpaTarget()
+		if hpa.GetObjectMeta().Namespace == meta.Namespace &&
+			strings.ToLower(target.Kind) == strings.ToLower(typeMeta.Kind) &&
+			target.Name == meta.Name {
+			score.Skipped = true
+			score.AddComment("", fmt.Sprintf("Skipped because the %s is targeted by a HorizontalPodAutoscaler", kind), "")
+			return
+		}
+	}
+
+	var service string
+	for _, s := range allServices {
+		svc := s.Service()
+		if svc.Namespace == meta.Namespace && len(svc.Spec.Selector) > 0 &&
+			internal.LabelSelectorMatchesLabels(svc.Spec.Selector, template.GetObjectMeta().GetLabels()) {
+			service = svc.Name
+			break
+		}
+	}
+	if service == "" {
+		score.Skipped = true
+		score.AddComment("", fmt.Sprintf("Skipped because the %s is not selected by a Service", kind), "")
+		return
+	}
+
+	if replicas != nil && *replicas != 1 {
+		score.Grade = scorecard.GradeAllOK
+		return
+	}
+
+	summary := fmt.Sprintf("The %s has a single replica, and is exposed by the Service %s", kind, service)
+	if replicas == nil {
+		summary = fmt.Sprintf("The %s does not set replicas, which defaults to 1, and is exposed by the Service %s", kind, service)
+	}
+	score.Grade = scorecard.GradeWarning
+	score.AddComment("", summary, "The Service has no endpoints while the pod is restarted, such as during a rollout or when the node is drained. Set replicas to 2 or more, or let a HorizontalPodAutoscaler control the replicas")
+	return
+}
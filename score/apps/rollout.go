@@ -0,0 +1,97 @@
+package apps
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// maxProgressDeadlineSeconds is the longest progressDeadlineSeconds that is not considered to be excessive
+const maxProgressDeadlineSeconds = 3600
+
+// maxRevisionHistoryLimit is the highest revisionHistoryLimit that is not considered to be excessive
+const maxRevisionHistoryLimit = 10
+
+func deploymentRollingUpdate(deployment appsv1.Deployment) (score scorecard.TestScore, err error) {
+	if deployment.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType {
+		score.Skipped = true
+		score.AddComment("", "Skipped because the deployment uses the Recreate strategy", "")
+		return
+	}
+
+	replicas := 1
+	if deployment.Spec.Replicas != nil {
+		replicas = int(*deployment.Spec.Replicas)
+	}
+
+	// The default maxUnavailable is 25%
+	maxUnavailable := intstr.FromString("25%")
+	if ru := deployment.Spec.Strategy.RollingUpdate; ru != nil && ru.MaxUnavailable != nil {
+		maxUnavailable = *ru.MaxUnavailable
+	}
+
+	// maxUnavailable is rounded down, in the same way as the deployment controller
+	unavailable, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, replicas, false)
+	if err != nil {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", "Invalid maxUnavailable", err.Error())
+		return score, nil
+	}
+
+	if replicas > 0 && unavailable >= replicas {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", fmt.Sprintf("maxUnavailable %s allows all %d replicas to be unavailable during a rollout", maxUnavailable.String(), replicas),
+			"All pods can be terminated at the same time during a rollout, which causes downtime. Lower strategy.rollingUpdate.maxUnavailable")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func deploymentProgressDeadline(deployment appsv1.Deployment) (score scorecard.TestScore, err error) {
+	if d := deployment.Spec.ProgressDeadlineSeconds; d != nil && *d > maxProgressDeadlineSeconds {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", fmt.Sprintf("progressDeadlineSeconds is set to %d", *d),
+			fmt.Sprintf("A failing rollout is not reported until the deadline has passed. Set progressDeadlineSeconds to %d or less", maxProgressDeadlineSeconds))
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func deploymentMinReadySeconds(deployment appsv1.Deployment) (score scorecard.TestScore, err error) {
+	if deployment.Spec.MinReadySeconds == 0 {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "minReadySeconds is not set",
+			"Without minReadySeconds, a new pod is considered available as soon as it's ready, and the rollout continues even if the pod crashes shortly after starting. Set minReadySeconds")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func deploymentRevisionHistoryLimit(deployment appsv1.Deployment) (score scorecard.TestScore, err error) {
+	limit := deployment.Spec.RevisionHistoryLimit
+	if limit == nil {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "revisionHistoryLimit is not set",
+			"The default keeps 10 old ReplicaSets for every Deployment, which are stored in etcd. Set revisionHistoryLimit to the number of revisions that you need to be able to roll back to")
+		return
+	}
+
+	if *limit > maxRevisionHistoryLimit {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", fmt.Sprintf("revisionHistoryLimit is set to %d", *limit),
+			fmt.Sprintf("Every old ReplicaSet is stored in etcd. Set revisionHistoryLimit to %d or less", maxRevisionHistoryLimit))
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
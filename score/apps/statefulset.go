@@ -0,0 +1,49 @@
+package apps
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func statefulsetUpdateStrategy(statefulset appsv1.StatefulSet) (score scorecard.TestScore, err error) {
+	if statefulset.Spec.UpdateStrategy.Type == "" {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "The StatefulSet does not set an updateStrategy",
+			"Set updateStrategy.type to RollingUpdate or OnDelete, to make it explicit how pods are replaced when the StatefulSet is updated")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func statefulsetPodManagementPolicy(statefulset appsv1.StatefulSet) (score scorecard.TestScore, err error) {
+	if statefulset.Spec.PodManagementPolicy == "" {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "The StatefulSet does not set a podManagementPolicy",
+			"The default OrderedReady starts and stops pods one at a time. Set podManagementPolicy to OrderedReady, or to Parallel if the pods do not depend on each other")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func statefulsetVolumeClaimTemplatesStorage(statefulset appsv1.StatefulSet) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+
+	for _, claim := range statefulset.Spec.VolumeClaimTemplates {
+		if storage, ok := claim.Spec.Resources.Requests[corev1.ResourceStorage]; ok && !storage.IsZero() {
+			continue
+		}
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", fmt.Sprintf("The volumeClaimTemplate %s does not request any storage", claim.Name),
+			"Set resources.requests.storage on the volumeClaimTemplate")
+	}
+
+	return
+}
@@ -1,66 +1,127 @@
 package score
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/scorecard"
 )
 
 func TestDeploymentHasPodAntiAffinityPreffered(t *testing.T) {
 	t.Parallel()
-	testExpectedScore(t, "deployment-host-antiaffinity-preffered.yaml", "Deployment has host PodAntiAffinity", scorecard.GradeAllOK)
+	testExpectedScore(t, "deployment-host-antiaffinity-preffered.yaml", "Deployment has pod spread", scorecard.GradeAllOK)
 }
 
 func TestDeploymentHasPodAntiAffinityPrefferedNoSelectorMatch(t *testing.T) {
 	t.Parallel()
-	testExpectedScore(t, "deployment-host-antiaffinity-preffered-selector-no-match.yaml", "Deployment has host PodAntiAffinity", scorecard.GradeWarning)
+	testExpectedScore(t, "deployment-host-antiaffinity-preffered-selector-no-match.yaml", "Deployment has pod spread", scorecard.GradeWarning)
 }
 
 func TestDeploymentHasPodAntiAffinityPrefferedSelectorExpression(t *testing.T) {
 	t.Parallel()
-	testExpectedScore(t, "deployment-host-antiaffinity-preffered-selector-expression.yaml", "Deployment has host PodAntiAffinity", scorecard.GradeAllOK)
+	testExpectedScore(t, "deployment-host-antiaffinity-preffered-selector-expression.yaml", "Deployment has pod spread", scorecard.GradeAllOK)
 }
 
 func TestDeploymentHasPodAntiAffinityRequired(t *testing.T) {
 	t.Parallel()
-	testExpectedScore(t, "deployment-host-antiaffinity-required.yaml", "Deployment has host PodAntiAffinity", scorecard.GradeAllOK)
+	testExpectedScore(t, "deployment-host-antiaffinity-required.yaml", "Deployment has pod spread", scorecard.GradeAllOK)
 }
 
 func TestDeploymentHasPodAntiAffinityNotSet(t *testing.T) {
 	t.Parallel()
-	testExpectedScore(t, "deployment-host-antiaffinity-not-set.yaml", "Deployment has host PodAntiAffinity", scorecard.GradeWarning)
+	testExpectedScore(t, "deployment-host-antiaffinity-not-set.yaml", "Deployment has pod spread", scorecard.GradeWarning)
 }
 
 func TestDeploymentHasPodAntiAffinityOneReplica(t *testing.T) {
 	t.Parallel()
 	// skipped
-	testExpectedScore(t, "deployment-host-antiaffinity-1-replica.yaml", "Deployment has host PodAntiAffinity", 0)
+	testExpectedScore(t, "deployment-host-antiaffinity-1-replica.yaml", "Deployment has pod spread", 0)
 }
 
 func TestStatefulSetHasPodAntiAffinityPreffered(t *testing.T) {
 	t.Parallel()
-	testExpectedScore(t, "statefulset-host-antiaffinity-preffered.yaml", "StatefulSet has host PodAntiAffinity", scorecard.GradeAllOK)
+	testExpectedScore(t, "statefulset-host-antiaffinity-preffered.yaml", "StatefulSet has pod spread", scorecard.GradeAllOK)
 }
 
 func TestStatefulSetHasPodAntiAffinityRequired(t *testing.T) {
 	t.Parallel()
-	testExpectedScore(t, "statefulset-host-antiaffinity-required.yaml", "StatefulSet has host PodAntiAffinity", scorecard.GradeAllOK)
+	testExpectedScore(t, "statefulset-host-antiaffinity-required.yaml", "StatefulSet has pod spread", scorecard.GradeAllOK)
 }
 
 func TestStatefulSetHasPodAntiAffinityNotSet(t *testing.T) {
 	t.Parallel()
-	testExpectedScore(t, "statefulset-host-antiaffinity-not-set.yaml", "StatefulSet has host PodAntiAffinity", scorecard.GradeWarning)
+	testExpectedScore(t, "statefulset-host-antiaffinity-not-set.yaml", "StatefulSet has pod spread", scorecard.GradeWarning)
 }
 
 func TestStatefulSetHasPodAntiAffinityOneReplica(t *testing.T) {
 	t.Parallel()
 	// skipped
-	testExpectedScore(t, "statefulset-host-antiaffinity-1-replica.yaml", "StatefulSet has host PodAntiAffinity", 0)
+	testExpectedScore(t, "statefulset-host-antiaffinity-1-replica.yaml", "StatefulSet has pod spread", 0)
 }
 
 func TestStatefulSetHasPodAntiAffinityUndefinedReplicas(t *testing.T) {
 	t.Parallel()
-	testExpectedScore(t, "statefulset-host-antiaffinity-undefined-replicas.yaml", "StatefulSet has host PodAntiAffinity", scorecard.GradeWarning)
+	testExpectedScore(t, "statefulset-host-antiaffinity-undefined-replicas.yaml", "StatefulSet has pod spread", scorecard.GradeWarning)
+}
+
+// The old IDs of the pod spread checks, from before they also accepted topologySpreadConstraints, still work
+func TestPodSpreadRenamedID(t *testing.T) {
+	t.Parallel()
+	const oldID = "deployment-has-host-podantiaffinity"
+
+	gradeOf := func(cnf config.Configuration) (scorecard.Grade, bool) {
+		sc, err := testScore(cnf)
+		assert.NoError(t, err)
+		for _, o := range sc {
+			for _, c := range o.Checks {
+				if c.Check.ID == "deployment-has-pod-spread" {
+					return c.Grade, c.Skipped
+				}
+			}
+		}
+		return 0, true
+	}
+
+	// --ignore-test
+	_, skipped := gradeOf(config.Configuration{
+		AllFiles:     []ks.NamedReader{testFile("deployment-host-antiaffinity-not-set.yaml")},
+		IgnoredTests: map[string]struct{}{oldID: {}},
+	})
+	assert.True(t, skipped)
+
+	// Grade overrides in the configuration file
+	grade, _ := gradeOf(config.Configuration{
+		AllFiles:       []ks.NamedReader{testFile("deployment-host-antiaffinity-not-set.yaml")},
+		GradeOverrides: map[string]scorecard.Grade{oldID: scorecard.GradeCritical},
+	})
+	assert.Equal(t, scorecard.GradeCritical, grade)
+
+	// The ignore annotation
+	_, skipped = gradeOf(config.Configuration{
+		AllFiles: []ks.NamedReader{unnamedReader{strings.NewReader(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  annotations:
+    kube-score/ignore: ` + oldID + `
+spec:
+  replicas: 10
+  template:
+    metadata:
+      labels:
+        app: foo
+    spec:
+      containers:
+      - name: foobar
+        image: foo:bar
+`)}},
+		UseIgnoreChecksAnnotation: true,
+	})
+	assert.True(t, skipped)
 }
 
 func TestDeploymentWithHPAHasReplicas(t *testing.T) {
@@ -102,3 +163,150 @@ func TestStatefulsetSelectorLabels(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(t, "statefulset-different-labels.yaml", "StatefulSet Pod Selector labels match template metadata labels", scorecard.GradeCritical)
 }
+
+func TestDeploymentHasTopologySpreadConstraints(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "deployment-topology-spread-constraints.yaml", "Deployment has pod spread", scorecard.GradeAllOK)
+}
+
+func TestStatefulSetHasTopologySpreadConstraintsOtherTopologyKey(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "statefulset-topology-spread-constraints-other-key.yaml", "StatefulSet has pod spread", scorecard.GradeWarning)
+}
+
+func TestDeploymentRollingUpdateMaxUnavailable100Percent(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "deployment-rollout-max-unavailable-100-percent.yaml", "Deployment Rolling Update", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "maxUnavailable 100% allows all 3 replicas to be unavailable during a rollout", comments[0].Summary)
+}
+
+func TestDeploymentRollingUpdateConfigured(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "deployment-rollout-configured.yaml", "Deployment Rolling Update", scorecard.GradeAllOK)
+}
+
+func TestDeploymentRollingUpdateDefault(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "deployment-rollout-revision-history-limit-large.yaml", "Deployment Rolling Update", scorecard.GradeAllOK)
+}
+
+func TestDeploymentProgressDeadlineLarge(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "deployment-rollout-max-unavailable-100-percent.yaml", "Deployment Progress Deadline", scorecard.GradeWarning)
+}
+
+func TestDeploymentProgressDeadlineConfigured(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "deployment-rollout-configured.yaml", "Deployment Progress Deadline", scorecard.GradeAllOK)
+}
+
+func TestDeploymentMinReadySecondsNotSet(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("deployment-rollout-max-unavailable-100-percent.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"deployment-min-ready-seconds": {}},
+	}, "Deployment Min Ready Seconds", scorecard.GradeWarning)
+}
+
+func TestDeploymentMinReadySecondsConfigured(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("deployment-rollout-configured.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"deployment-min-ready-seconds": {}},
+	}, "Deployment Min Ready Seconds", scorecard.GradeAllOK)
+}
+
+func TestDeploymentMinReadySecondsAllOptionalEnabled(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:               []ks.NamedReader{testFile("deployment-rollout-max-unavailable-100-percent.yaml")},
+		EnableAllOptionalTests: true,
+	}, "Deployment Min Ready Seconds", scorecard.GradeWarning)
+}
+
+func TestDeploymentRevisionHistoryLimitNotSet(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("deployment-rollout-max-unavailable-100-percent.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"deployment-revision-history-limit": {}},
+	}, "Deployment Revision History Limit", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "revisionHistoryLimit is not set", comments[0].Summary)
+}
+
+func TestDeploymentRevisionHistoryLimitLarge(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("deployment-rollout-revision-history-limit-large.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"deployment-revision-history-limit": {}},
+	}, "Deployment Revision History Limit", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "revisionHistoryLimit is set to 50", comments[0].Summary)
+}
+
+func TestDeploymentRevisionHistoryLimitConfigured(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("deployment-rollout-configured.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"deployment-revision-history-limit": {}},
+	}, "Deployment Revision History Limit", scorecard.GradeAllOK)
+}
+
+func TestStatefulSetUpdateStrategy(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "statefulset-configured.yaml", "StatefulSet Update Strategy", scorecard.GradeAllOK)
+	testExpectedScore(t, "statefulset-not-configured.yaml", "StatefulSet Update Strategy", scorecard.GradeWarning)
+}
+
+func TestStatefulSetPodManagementPolicy(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("statefulset-configured.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"statefulset-pod-management-policy": {}},
+	}, "StatefulSet Pod Management Policy", scorecard.GradeAllOK)
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("statefulset-not-configured.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"statefulset-pod-management-policy": {}},
+	}, "StatefulSet Pod Management Policy", scorecard.GradeWarning)
+}
+
+func TestStatefulSetVolumeClaimTemplatesStorage(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "statefulset-configured.yaml", "StatefulSet Volume Claim Templates Storage", scorecard.GradeAllOK)
+	comments := testExpectedScore(t, "statefulset-not-configured.yaml", "StatefulSet Volume Claim Templates Storage", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The volumeClaimTemplate data does not request any storage", comments[0].Summary)
+}
+
+func TestDeploymentServiceReplicas(t *testing.T) {
+	t.Parallel()
+	s, err := testScore(config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("deployment-service-replicas.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"deployment-service-replicas": {}},
+	})
+	assert.Nil(t, err)
+
+	grades := make(map[string]scorecard.Grade)
+	for _, o := range s {
+		for _, c := range o.Checks {
+			if c.Check.ID == "deployment-service-replicas" {
+				grades[o.ObjectMeta.Name] = c.Grade
+			}
+		}
+	}
+	assert.Equal(t, map[string]scorecard.Grade{"single": scorecard.GradeWarning, "multiple": scorecard.GradeAllOK}, grades)
+
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("service-target-deployment.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"deployment-service-replicas": {}},
+	}, "Deployment Service Replicas", scorecard.GradeWarning)
+	assert.Equal(t, "The deployment does not set replicas, which defaults to 1, and is exposed by the Service my-service", comments[0].Summary)
+
+	// The replicas of Deployments that are targeted by a HPA are controlled by the HPA
+	comments = testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("deployment-with-hpa-not-has-replicas.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"deployment-service-replicas": {}},
+	}, "Deployment Service Replicas", 0)
+	assert.Equal(t, "Skipped because the deployment is targeted by a HorizontalPodAutoscaler", comments[0].Summary)
+}
@@ -1,9 +1,11 @@
 package checks
 
 import (
+	"sort"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,8 +16,12 @@ import (
 )
 
 func New(cnf config.Configuration) *Checks {
+	// The profiles have already been validated when parsing the flags and the configuration file
+	profileChecks, _ := ProfileChecks(cnf.Profiles, cnf.CustomProfiles)
+
 	return &Checks{
-		cnf: cnf,
+		cnf:           cnf,
+		profileChecks: profileChecks,
 
 		all:                      make([]ks.Check, 0),
 		metas:                    make(map[string]MetaCheck),
@@ -23,11 +29,20 @@ func New(cnf config.Configuration) *Checks {
 		services:                 make(map[string]ServiceCheck),
 		statefulsets:             make(map[string]StatefulSetCheck),
 		deployments:              make(map[string]DeploymentCheck),
+		jobs:                     make(map[string]JobCheck),
 		networkpolicies:          make(map[string]NetworkPolicyCheck),
 		ingresses:                make(map[string]IngressCheck),
 		cronjobs:                 make(map[string]CronJobCheck),
 		horizontalPodAutoscalers: make(map[string]HorizontalPodAutoscalerCheck),
 		poddisruptionbudgets:     make(map[string]PodDisruptionBudgetCheck),
+		roles:                    make(map[string]RoleCheck),
+		roleBindings:             make(map[string]RoleBindingCheck),
+		gateways:                 make(map[string]GatewayCheck),
+		httpRoutes:               make(map[string]HTTPRouteCheck),
+		persistentVolumeClaims:   make(map[string]PersistentVolumeClaimCheck),
+		objects:                  make(map[string]ObjectCheck),
+		docs:                     make(map[string]Documentation),
+		crossObject:              make(map[string]struct{}),
 	}
 }
 
@@ -77,6 +92,12 @@ type DeploymentCheck struct {
 	Fn DeploymentCheckFn
 }
 
+type JobCheckFn = func(batchv1.Job) scorecard.TestScore
+type JobCheck struct {
+	ks.Check
+	Fn JobCheckFn
+}
+
 type NetworkPolicyCheckFn = func(networkingv1.NetworkPolicy) scorecard.TestScore
 type NetworkPolicyCheck struct {
 	ks.Check
@@ -107,6 +128,58 @@ type PodDisruptionBudgetCheck struct {
 	Fn PodDisruptionBudgetCheckFn
 }
 
+type RoleCheckFn = func(ks.Role) scorecard.TestScore
+type RoleCheck struct {
+	ks.Check
+	Fn RoleCheckFn
+}
+
+type RoleBindingCheckFn = func(ks.RoleBinding) scorecard.TestScore
+type RoleBindingCheck struct {
+	ks.Check
+	Fn RoleBindingCheckFn
+}
+
+type GatewayCheckFn = func(ks.Gateway) scorecard.TestScore
+type GatewayCheck struct {
+	ks.Check
+	Fn GatewayCheckFn
+}
+
+type HTTPRouteCheckFn = func(ks.HTTPRoute) scorecard.TestScore
+type HTTPRouteCheck struct {
+	ks.Check
+	Fn HTTPRouteCheckFn
+}
+
+type PersistentVolumeClaimCheckFn = func(corev1.PersistentVolumeClaim) scorecard.TestScore
+type PersistentVolumeClaimCheck struct {
+	ks.Check
+	Fn PersistentVolumeClaimCheckFn
+}
+
+type ObjectCheckFn = func(ks.Object) scorecard.TestScore
+type ObjectCheck struct {
+	ks.Check
+
+	// Kinds are the kinds of objects that the check is run on. If empty, the check is run on all objects.
+	Kinds []string
+	Fn    ObjectCheckFn
+}
+
+// Matches returns true if the check should be run on objects of the kind
+func (c ObjectCheck) Matches(kind string) bool {
+	if len(c.Kinds) == 0 {
+		return true
+	}
+	for _, k := range c.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
 type Checks struct {
 	all                      []ks.Check
 	metas                    map[string]MetaCheck
@@ -114,18 +187,43 @@ type Checks struct {
 	services                 map[string]ServiceCheck
 	statefulsets             map[string]StatefulSetCheck
 	deployments              map[string]DeploymentCheck
+	jobs                     map[string]JobCheck
 	networkpolicies          map[string]NetworkPolicyCheck
 	ingresses                map[string]IngressCheck
 	cronjobs                 map[string]CronJobCheck
 	horizontalPodAutoscalers map[string]HorizontalPodAutoscalerCheck
 	poddisruptionbudgets     map[string]PodDisruptionBudgetCheck
+	roles                    map[string]RoleCheck
+	roleBindings             map[string]RoleBindingCheck
+	gateways                 map[string]GatewayCheck
+	httpRoutes               map[string]HTTPRouteCheck
+	persistentVolumeClaims   map[string]PersistentVolumeClaimCheck
+	objects                  map[string]ObjectCheck
+	docs                     map[string]Documentation
+	crossObject              map[string]struct{}
 
 	cnf config.Configuration
+
+	// profileChecks are the IDs of the checks of the selected profiles, or nil if all checks are run
+	profileChecks map[string]struct{}
 }
 
 func (c Checks) isIgnored(id string) bool {
-	_, ok := c.cnf.IgnoredTests[id]
-	return ok
+	return containsCheck(c.cnf.IgnoredTests, id)
+}
+
+// containsCheck reports whether ids contains the check with the id, or one of the IDs of the check before it was
+// renamed
+func containsCheck(ids map[string]struct{}, id string) bool {
+	if _, ok := ids[id]; ok {
+		return true
+	}
+	for old, renamed := range scorecard.RenamedChecks {
+		if _, ok := ids[old]; ok && renamed == id {
+			return true
+		}
+	}
+	return false
 }
 
 func (c Checks) isEnabled(check ks.Check) bool {
@@ -133,12 +231,21 @@ func (c Checks) isEnabled(check ks.Check) bool {
 		return false
 	}
 
+	if c.profileChecks != nil {
+		if _, ok := c.profileChecks[check.ID]; !ok {
+			return false
+		}
+	}
+
 	if !check.Optional {
 		return true
 	}
 
-	_, ok := c.cnf.EnabledOptionalTests[check.ID]
-	return ok
+	if c.cnf.EnableAllOptionalTests {
+		return true
+	}
+
+	return containsCheck(c.cnf.EnabledOptionalTests, check.ID)
 }
 
 func (c *Checks) RegisterMetaCheck(name, comment string, fn MetaCheckFn) {
@@ -279,6 +386,29 @@ func (c *Checks) Deployments() map[string]DeploymentCheck {
 	return c.deployments
 }
 
+func (c *Checks) RegisterJobCheck(name, comment string, fn JobCheckFn) {
+	ch := NewCheck(name, "Job", comment, false)
+	c.registerJobCheck(JobCheck{ch, fn})
+}
+
+func (c *Checks) RegisterOptionalJobCheck(name, comment string, fn JobCheckFn) {
+	ch := NewCheck(name, "Job", comment, true)
+	c.registerJobCheck(JobCheck{ch, fn})
+}
+
+func (c *Checks) registerJobCheck(ch JobCheck) {
+	c.all = append(c.all, ch.Check)
+
+	if !c.isEnabled(ch.Check) {
+		return
+	}
+	c.jobs[machineFriendlyName(ch.Name)] = ch
+}
+
+func (c *Checks) Jobs() map[string]JobCheck {
+	return c.jobs
+}
+
 func (c *Checks) RegisterIngressCheck(name, comment string, fn IngressCheckFn) {
 	ch := NewCheck(name, "Ingress", comment, false)
 	c.registerIngressCheck(IngressCheck{ch, fn})
@@ -366,6 +496,183 @@ func (c *Checks) Services() map[string]ServiceCheck {
 	return c.services
 }
 
+func (c *Checks) RegisterRoleCheck(name, comment string, fn RoleCheckFn) {
+	ch := NewCheck(name, "Role", comment, false)
+	c.registerRoleCheck(RoleCheck{ch, fn})
+}
+
+func (c *Checks) RegisterOptionalRoleCheck(name, comment string, fn RoleCheckFn) {
+	ch := NewCheck(name, "Role", comment, true)
+	c.registerRoleCheck(RoleCheck{ch, fn})
+}
+
+func (c *Checks) registerRoleCheck(ch RoleCheck) {
+	c.all = append(c.all, ch.Check)
+
+	if !c.isEnabled(ch.Check) {
+		return
+	}
+	c.roles[machineFriendlyName(ch.Name)] = ch
+}
+
+func (c *Checks) Roles() map[string]RoleCheck {
+	return c.roles
+}
+
+func (c *Checks) RegisterRoleBindingCheck(name, comment string, fn RoleBindingCheckFn) {
+	ch := NewCheck(name, "RoleBinding", comment, false)
+	c.registerRoleBindingCheck(RoleBindingCheck{ch, fn})
+}
+
+func (c *Checks) RegisterOptionalRoleBindingCheck(name, comment string, fn RoleBindingCheckFn) {
+	ch := NewCheck(name, "RoleBinding", comment, true)
+	c.registerRoleBindingCheck(RoleBindingCheck{ch, fn})
+}
+
+func (c *Checks) registerRoleBindingCheck(ch RoleBindingCheck) {
+	c.all = append(c.all, ch.Check)
+
+	if !c.isEnabled(ch.Check) {
+		return
+	}
+	c.roleBindings[machineFriendlyName(ch.Name)] = ch
+}
+
+func (c *Checks) RoleBindings() map[string]RoleBindingCheck {
+	return c.roleBindings
+}
+
+func (c *Checks) RegisterGatewayCheck(name, comment string, fn GatewayCheckFn) {
+	ch := NewCheck(name, "Gateway", comment, false)
+	c.registerGatewayCheck(GatewayCheck{ch, fn})
+}
+
+func (c *Checks) RegisterOptionalGatewayCheck(name, comment string, fn GatewayCheckFn) {
+	ch := NewCheck(name, "Gateway", comment, true)
+	c.registerGatewayCheck(GatewayCheck{ch, fn})
+}
+
+func (c *Checks) registerGatewayCheck(ch GatewayCheck) {
+	c.all = append(c.all, ch.Check)
+
+	if !c.isEnabled(ch.Check) {
+		return
+	}
+	c.gateways[machineFriendlyName(ch.Name)] = ch
+}
+
+func (c *Checks) Gateways() map[string]GatewayCheck {
+	return c.gateways
+}
+
+func (c *Checks) RegisterHTTPRouteCheck(name, comment string, fn HTTPRouteCheckFn) {
+	ch := NewCheck(name, "HTTPRoute", comment, false)
+	c.registerHTTPRouteCheck(HTTPRouteCheck{ch, fn})
+}
+
+func (c *Checks) RegisterOptionalHTTPRouteCheck(name, comment string, fn HTTPRouteCheckFn) {
+	ch := NewCheck(name, "HTTPRoute", comment, true)
+	c.registerHTTPRouteCheck(HTTPRouteCheck{ch, fn})
+}
+
+func (c *Checks) registerHTTPRouteCheck(ch HTTPRouteCheck) {
+	c.all = append(c.all, ch.Check)
+
+	if !c.isEnabled(ch.Check) {
+		return
+	}
+	c.httpRoutes[machineFriendlyName(ch.Name)] = ch
+}
+
+func (c *Checks) HTTPRoutes() map[string]HTTPRouteCheck {
+	return c.httpRoutes
+}
+
+func (c *Checks) RegisterPersistentVolumeClaimCheck(name, comment string, fn PersistentVolumeClaimCheckFn) {
+	ch := NewCheck(name, "PersistentVolumeClaim", comment, false)
+	c.registerPersistentVolumeClaimCheck(PersistentVolumeClaimCheck{ch, fn})
+}
+
+func (c *Checks) RegisterOptionalPersistentVolumeClaimCheck(name, comment string, fn PersistentVolumeClaimCheckFn) {
+	ch := NewCheck(name, "PersistentVolumeClaim", comment, true)
+	c.registerPersistentVolumeClaimCheck(PersistentVolumeClaimCheck{ch, fn})
+}
+
+func (c *Checks) registerPersistentVolumeClaimCheck(ch PersistentVolumeClaimCheck) {
+	c.all = append(c.all, ch.Check)
+
+	if !c.isEnabled(ch.Check) {
+		return
+	}
+	c.persistentVolumeClaims[machineFriendlyName(ch.Name)] = ch
+}
+
+func (c *Checks) PersistentVolumeClaims() map[string]PersistentVolumeClaimCheck {
+	return c.persistentVolumeClaims
+}
+
+// RegisterObjectCheck registers a check that is run on all objects of the kinds, including objects of kinds that
+// have no checks of their own. It's used for custom checks and plugins, which have an ID that is not derived from
+// the name, and for checks of fields that are not a part of the supported API version.
+func (c *Checks) RegisterObjectCheck(id, name, comment string, kinds []string, fn ObjectCheckFn) {
+	c.registerObjectCheck(newObjectCheck(id, name, comment, kinds, false, fn))
+}
+
+func (c *Checks) RegisterOptionalObjectCheck(id, name, comment string, kinds []string, fn ObjectCheckFn) {
+	c.registerObjectCheck(newObjectCheck(id, name, comment, kinds, true, fn))
+}
+
+func newObjectCheck(id, name, comment string, kinds []string, optional bool, fn ObjectCheckFn) ObjectCheck {
+	targetType := "All"
+	if len(kinds) > 0 {
+		targetType = strings.Join(kinds, ", ")
+	}
+
+	return ObjectCheck{
+		Check: ks.Check{
+			Name:       name,
+			ID:         id,
+			TargetType: targetType,
+			Comment:    comment,
+			Optional:   optional,
+		},
+		Kinds: kinds,
+		Fn:    fn,
+	}
+}
+
+func (c *Checks) registerObjectCheck(ch ObjectCheck) {
+	c.all = append(c.all, ch.Check)
+
+	if !c.isEnabled(ch.Check) {
+		return
+	}
+	c.objects[ch.ID] = ch
+}
+
+func (c *Checks) Objects() map[string]ObjectCheck {
+	return c.objects
+}
+
 func (c *Checks) All() []ks.Check {
 	return c.all
 }
+
+// MarkCrossObject marks the checks with the ids as checks that look at other objects than the object that is scored,
+// such as the NetworkPolicies that target a Pod. These checks fail when the other objects are not scored at the same
+// time, such as in the webhook, that only sees one object at the time.
+func (c *Checks) MarkCrossObject(ids ...string) {
+	for _, id := range ids {
+		c.crossObject[id] = struct{}{}
+	}
+}
+
+// CrossObject returns the sorted IDs of the checks that have been marked with MarkCrossObject
+func (c *Checks) CrossObject() []string {
+	ids := make([]string, 0, len(c.crossObject))
+	for id := range c.crossObject {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,47 @@
+package checks
+
+import (
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// DocumentationURL is the documentation of all checks, that is used for checks that don't have a more specific URL
+const DocumentationURL = "https://github.com/zegl/kube-score/blob/master/README_CHECKS.md"
+
+// Documentation describes a check in more detail than the comment that it's registered with, and is used to
+// generate documentation of the checks with "kube-score list" and "kube-score explain"
+type Documentation struct {
+	// Grade is the lowest grade that the check gives to an object that fails it
+	Grade scorecard.Grade
+
+	// Remediation describes how to fix an object that fails the check
+	Remediation string
+
+	// URL is a link to more information about the check
+	URL string
+
+	// Details is a longer description of what the check inspects, the comment of the check is used if it's not set
+	Details string
+
+	// Rationale describes why the check matters
+	Rationale string
+
+	// FailingExample and PassingExample are example objects in YAML, that fail and pass the check
+	FailingExample string
+	PassingExample string
+}
+
+// Document attaches documentation to the check with the id, it's used by the Register functions after the check has
+// been registered
+func (c *Checks) Document(id string, doc Documentation) {
+	c.docs[id] = doc
+}
+
+// Documentation returns the documentation of the check with the id. The URL defaults to DocumentationURL, false is
+// returned if the check has not been documented.
+func (c *Checks) Documentation(id string) (Documentation, bool) {
+	doc, ok := c.docs[id]
+	if doc.URL == "" {
+		doc.URL = DocumentationURL
+	}
+	return doc, ok
+}
@@ -0,0 +1,216 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// ProfileAll is the profile that contains all checks
+const ProfileAll = "all"
+
+// Profiles are the built-in profiles, that group the checks by what they audit. A check can be in multiple profiles.
+var Profiles = map[string][]string{
+	"security": {
+		"container-environment-secrets",
+		"container-image-digest",
+		"container-image-pull-policy",
+		"container-image-registry",
+		"container-image-tag",
+		"container-seccomp-profile",
+		"container-security-context-capabilities",
+		"container-security-context-privilege-escalation",
+		"container-security-context-privileged",
+		"container-security-context-readonlyrootfilesystem",
+		"container-security-context-runasnonroot",
+		"container-security-context-user-group-id",
+		"container-volume-mounts-read-only",
+		"gateway-listener-hostname",
+		"gateway-listener-tls",
+		"ingress-tls",
+		"init-container-privileged",
+		"networkpolicy-targets-pod",
+		"pod-automount-service-account-token",
+		"pod-default-service-account",
+		"pod-host-ipc",
+		"pod-host-network",
+		"pod-host-path-volumes",
+		"pod-host-pid",
+		"pod-networkpolicy",
+		"pod-networkpolicy-default-deny",
+		"pod-security-standard",
+		"role-privilege-escalation-permissions",
+		"role-wildcard-permissions",
+		"rolebinding-cluster-admin",
+		"rolebinding-default-service-account",
+		"service-internal-only",
+		"service-type",
+	},
+	"reliability": {
+		"annotation-size",
+		"configmap-secret-immutable",
+		"configmap-secret-size",
+		"container-ephemeral-storage-request-and-limit",
+		"container-image-pull-policy-consistency",
+		"container-image-tag",
+		"container-memory-requests-equal-limits",
+		"container-prestop-hook",
+		"container-ports-named",
+		"container-probe-ports",
+		"container-probe-values",
+		"container-probes-identical",
+		"container-resources",
+		"container-startup-probe",
+		"container-volume-mounts-read-only",
+		"cronjob-concurrency-policy",
+		"cronjob-has-deadline",
+		"cronjob-time-zone",
+		"deployment-has-pod-spread",
+		"deployment-has-poddisruptionbudget",
+		"deployment-min-ready-seconds",
+		"deployment-pod-selector-labels-match-template-metadata-labels",
+		"deployment-progress-deadline",
+		"deployment-rolling-update",
+		"deployment-service-replicas",
+		"deployment-targeted-by-hpa-does-not-have-replicas-configured",
+		"deprecated-api-version",
+		"horizontalpodautoscaler-has-target",
+		"horizontalpodautoscaler-replicas",
+		"horizontalpodautoscaler-target-resource-requests",
+		"httproute-has-parentrefs",
+		"httproute-targets-service",
+		"ingress-class",
+		"ingress-path-type",
+		"ingress-targets-service",
+		"job-active-deadline",
+		"job-backoff-limit",
+		"job-restart-policy",
+		"label-keys",
+		"label-values",
+		"native-sidecar-container",
+		"native-sidecar-migration",
+		"object-name",
+		"object-namespace",
+		"object-unique",
+		"persistentvolumeclaim-access-modes",
+		"persistentvolumeclaim-readwritemany",
+		"persistentvolumeclaim-storage-class",
+		"persistentvolumeclaim-storage-request",
+		"pod-configmap-and-secret-references",
+		"pod-deprecated-node-labels",
+		"pod-dns-config",
+		"pod-dns-policy",
+		"pod-priority-class",
+		"pod-probes",
+		"pod-termination-grace-period",
+		"pod-tolerations",
+		"pod-unique-fields",
+		"poddisruptionbudget-allows-disruption",
+		"poddisruptionbudget-has-policy",
+		"podmonitor-targets-pod",
+		"service-external-traffic-policy",
+		"service-target-port",
+		"service-targets-pod",
+		"servicemonitor-targets-service",
+		"stable-version",
+		"statefulset-has-pod-spread",
+		"statefulset-has-poddisruptionbudget",
+		"statefulset-has-servicename",
+		"statefulset-pod-management-policy",
+		"statefulset-pod-selector-labels-match-template-metadata-labels",
+		"statefulset-service-replicas",
+		"statefulset-targeted-by-hpa-does-not-have-replicas-configured",
+		"statefulset-update-strategy",
+		"statefulset-volume-claim-templates-storage",
+	},
+	"cost": {
+		"container-cpu-requests-equal-limits",
+		"container-ephemeral-storage-request-and-limit",
+		"container-memory-limit-ratio",
+		"container-memory-requests-equal-limits",
+		"container-resource-requests-equal-limits",
+		"container-resources",
+		"cronjob-history-limits",
+		"deployment-revision-history-limit",
+		"deployment-targeted-by-hpa-does-not-have-replicas-configured",
+		"horizontalpodautoscaler-replicas",
+		"horizontalpodautoscaler-target-resource-requests",
+		"job-active-deadline",
+		"job-ttl-after-finished",
+		"pod-emptydir-size-limit",
+		"statefulset-targeted-by-hpa-does-not-have-replicas-configured",
+		"workload-required-labels",
+	},
+}
+
+// ProfileChecks returns the IDs of the checks of the profiles, from the built-in profiles and the custom profiles.
+// nil is returned if no profiles are given, or if one of them is "all", as all checks should be run.
+func ProfileChecks(profiles []string, customProfiles map[string][]string) (map[string]struct{}, error) {
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+
+	res := make(map[string]struct{})
+	for _, name := range profiles {
+		if name == ProfileAll {
+			return nil, nil
+		}
+		ids, ok := customProfiles[name]
+		if !ok {
+			ids, ok = Profiles[name]
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q, must be one of: %s", name, strings.Join(profileNames(customProfiles), ", "))
+		}
+		for _, id := range ids {
+			res[scorecard.CheckID(id)] = struct{}{}
+		}
+	}
+	return res, nil
+}
+
+// ProfilesOf returns the names of the profiles that the check is in, sorted by name
+func ProfilesOf(id string, customProfiles map[string][]string) []string {
+	var res []string
+	for name, ids := range Profiles {
+		// Custom profiles replace the built-in profiles with the same name
+		if _, ok := customProfiles[name]; ok {
+			continue
+		}
+		if contains(ids, id) {
+			res = append(res, name)
+		}
+	}
+	for name, ids := range customProfiles {
+		if contains(ids, id) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func profileNames(customProfiles map[string][]string) []string {
+	names := []string{ProfileAll}
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	for name := range customProfiles {
+		if _, ok := Profiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
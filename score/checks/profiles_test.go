@@ -0,0 +1,38 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileChecks(t *testing.T) {
+	res, err := ProfileChecks(nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+
+	res, err = ProfileChecks([]string{"security", ProfileAll}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+
+	res, err = ProfileChecks([]string{"cost", "mine"}, map[string][]string{"mine": {"pod-probes"}})
+	assert.NoError(t, err)
+	assert.Contains(t, res, "pod-probes")
+	assert.Contains(t, res, "container-resources")
+	assert.NotContains(t, res, "pod-host-pid")
+
+	// Custom profiles replace the built-in profiles with the same name
+	res, err = ProfileChecks([]string{"security"}, map[string][]string{"security": {"pod-probes"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"pod-probes": {}}, res)
+
+	_, err = ProfileChecks([]string{"speed"}, map[string][]string{"mine": {}})
+	assert.Error(t, err)
+	assert.Equal(t, `unknown profile "speed", must be one of: all, cost, mine, reliability, security`, err.Error())
+}
+
+func TestProfilesOf(t *testing.T) {
+	assert.Equal(t, []string{"cost", "reliability"}, ProfilesOf("container-resources", nil))
+	assert.Equal(t, []string{"mine", "reliability"}, ProfilesOf("container-resources", map[string][]string{"mine": {"container-resources"}, "cost": {}}))
+	assert.Nil(t, ProfilesOf("no-such-check", nil))
+}
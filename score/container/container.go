@@ -1,10 +1,12 @@
 package container
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/zegl/kube-score/config"
 	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
 	"github.com/zegl/kube-score/scorecard"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,11 +17,89 @@ func Register(allChecks *checks.Checks, cnf config.Configuration) {
 	allChecks.RegisterOptionalPodCheck("Container Resource Requests Equal Limits", `Makes sure that all pods have the same requests as limits on resources set.`, containerResourceRequestsEqualLimits)
 	allChecks.RegisterOptionalPodCheck("Container CPU Requests Equal Limits", `Makes sure that all pods have the same CPU requests as limits set.`, containerCPURequestsEqualLimits)
 	allChecks.RegisterOptionalPodCheck("Container Memory Requests Equal Limits", `Makes sure that all pods have the same memory requests as limits set.`, containerMemoryRequestsEqualLimits)
+	allChecks.RegisterOptionalPodCheck("Container Memory Limit Ratio", `Makes sure that the memory limit of all containers is not much higher than the memory request. The highest allowed ratio can be configured with --max-memory-limit-ratio`, containerMemoryLimitRatio(cnf.MaxMemoryLimitRatio))
+	allChecks.RegisterOptionalPodCheck("Container Ephemeral Storage Request and Limit", `Makes sure that all containers have an ephemeral-storage request and limit set, to avoid that the node runs out of disk and starts evicting pods`, containerEphemeralStorage)
+	allChecks.RegisterOptionalPodCheck("Pod EmptyDir Size Limit", `Makes sure that all emptyDir volumes have a sizeLimit set`, podEmptyDirSizeLimit)
+	allChecks.RegisterOptionalPodCheck("Container Volume Mounts Read Only", `Makes sure that all ConfigMap, Secret and downwardAPI volumes are mounted with readOnly, and that ConfigMaps are not mounted with subPath, as they are not updated when the ConfigMap changes`, containerVolumeMountsReadOnly)
+	allChecks.RegisterOptionalPodCheck("Container Ports Named", `Makes sure that all ports of all containers have a name, so that they can be referenced by name from Services and probes`, containerPortsNamed)
 	allChecks.RegisterPodCheck("Container Image Tag", `Makes sure that a explicit non-latest tag is used`, containerImageTag)
+	allChecks.RegisterOptionalPodCheck("Container Image Digest", `Makes sure that all images are pinned to a digest`, containerImageDigest)
+	allChecks.RegisterOptionalPodCheck("Container Image Registry", `Makes sure that all images are pulled from one of the allowed registries, configured with allowedImageRegistries in the configuration file or with --allowed-image-registry`, containerImageRegistry(cnf.AllowedImageRegistries))
 	allChecks.RegisterPodCheck("Container Image Pull Policy", `Makes sure that the pullPolicy is set to Always. This makes sure that imagePullSecrets are always validated.`, containerImagePullPolicy)
+	allChecks.RegisterPodCheck("Container Image Pull Policy Consistency", `Makes sure that images pinned to a digest are not always pulled, and that images with a mutable tag are not using IfNotPresent or Never`, containerImagePullPolicyConsistency)
+	allChecks.Document("container-resources", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set resources.requests and resources.limits for cpu and memory on all containers",
+		URL:         "https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/",
+	})
+	allChecks.Document("container-resource-requests-equal-limits", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set the cpu and memory requests of all containers to the same values as the limits",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/pods/pod-qos/#guaranteed",
+	})
+	allChecks.Document("container-cpu-requests-equal-limits", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set the cpu request of all containers to the same value as the cpu limit",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/pods/pod-qos/#guaranteed",
+	})
+	allChecks.Document("container-memory-requests-equal-limits", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set the memory request of all containers to the same value as the memory limit",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/pods/pod-qos/#guaranteed",
+	})
+	allChecks.Document("container-memory-limit-ratio", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Lower the memory limit, or raise the memory request, so that the ratio between them is within the allowed ratio",
+		URL:         "https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/",
+	})
+	allChecks.Document("container-ephemeral-storage-request-and-limit", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set resources.requests and resources.limits for ephemeral-storage on all containers",
+		URL:         "https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/#local-ephemeral-storage",
+	})
+	allChecks.Document("pod-emptydir-size-limit", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set sizeLimit on all emptyDir volumes",
+		URL:         "https://kubernetes.io/docs/concepts/storage/volumes/#emptydir",
+	})
+	allChecks.Document("container-volume-mounts-read-only", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set readOnly to true on all mounts of ConfigMap, Secret and downwardAPI volumes, and mount ConfigMaps as a directory instead of with subPath",
+		URL:         "https://kubernetes.io/docs/concepts/storage/volumes/#using-subpath",
+	})
+	allChecks.Document("container-ports-named", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set name on all ports of all containers, and use the name as the targetPort of Services",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/service/#field-spec-ports",
+	})
+	allChecks.Document("container-image-tag", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Use a specific version tag for all images, instead of latest or no tag",
+		URL:         "https://kubernetes.io/docs/concepts/containers/images/#image-names",
+	})
+	allChecks.Document("container-image-digest", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Pin all images to a digest, such as nginx@sha256:...",
+		URL:         "https://kubernetes.io/docs/concepts/containers/images/#image-names",
+	})
+	allChecks.Document("container-image-registry", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Pull all images from one of the allowed registries",
+	})
+	allChecks.Document("container-image-pull-policy", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set imagePullPolicy to Always on all containers",
+		URL:         "https://kubernetes.io/docs/concepts/containers/images/#updating-images",
+	})
+	allChecks.Document("container-image-pull-policy-consistency", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Use IfNotPresent for images pinned to a digest, and Always for images with a mutable tag",
+		URL:         "https://kubernetes.io/docs/concepts/containers/images/#image-pull-policy",
+	})
 }
 
-// containerResources makes sure that the container has resource requests and limits set
+// containerResources makes sure that the init containers and containers have resource requests and limits set.
+// Ephemeral containers are not checked, as they are not allowed to set resources.
 // The check for a CPU limit requirement can be enabled via the requireCPULimit flag parameter
 func containerResources(requireCPULimit bool, requireMemoryLimit bool) func(corev1.PodTemplateSpec, metav1.TypeMeta) scorecard.TestScore {
 	return func(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
@@ -137,19 +217,193 @@ func containerMemoryRequestsEqualLimits(podTemplate corev1.PodTemplateSpec, type
 	return
 }
 
-// containerImageTag checks that no container is using the ":latest" tag
-func containerImageTag(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+// containerMemoryLimitRatio checks that the memory limit of all containers is at most maxRatio times the memory request
+func containerMemoryLimitRatio(maxRatio float64) func(corev1.PodTemplateSpec, metav1.TypeMeta) scorecard.TestScore {
+	if maxRatio <= 0 {
+		maxRatio = 2
+	}
+
+	return func(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+		pod := podTemplate.Spec
+
+		allContainers := pod.InitContainers
+		allContainers = append(allContainers, pod.Containers...)
+
+		score.Grade = scorecard.GradeAllOK
+
+		for _, container := range allContainers {
+			request := container.Resources.Requests.Memory()
+			limit := container.Resources.Limits.Memory()
+
+			// Missing requests and limits are reported by the container-resources check
+			if request.IsZero() || limit.IsZero() {
+				continue
+			}
+
+			ratio := float64(limit.Value()) / float64(request.Value())
+			if ratio > maxRatio {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(container.Name,
+					fmt.Sprintf("Memory limit is %.1f times the memory request", ratio),
+					fmt.Sprintf("When the node runs out of memory, containers using more memory than they have requested are OOM killed first, also affecting other pods on the node. Set resources.limits.memory to at most %g times resources.requests.memory", maxRatio))
+			}
+		}
+
+		return
+	}
+}
+
+// containerEphemeralStorage checks that all containers have ephemeral-storage requests and limits set
+func containerEphemeralStorage(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
 	pod := podTemplate.Spec
 
 	allContainers := pod.InitContainers
 	allContainers = append(allContainers, pod.Containers...)
 
-	hasTagLatest := false
+	hasMissingLimit := false
+	hasMissingRequest := false
 
 	for _, container := range allContainers {
-		tag := containerTag(container.Image)
-		if tag == "" || tag == "latest" {
-			score.AddComment(container.Name, "Image with latest tag", "Using a fixed tag is recommended to avoid accidental upgrades")
+		if container.Resources.Limits.StorageEphemeral().IsZero() {
+			score.AddComment(container.Name, "Ephemeral storage limit is not set", "Without a limit, a container can fill up the disk of the node and cause other pods to be evicted. Set resources.limits.ephemeral-storage")
+			hasMissingLimit = true
+		}
+		if container.Resources.Requests.StorageEphemeral().IsZero() {
+			score.AddComment(container.Name, "Ephemeral storage request is not set", "Resource requests are recommended to make sure that the pod is scheduled to a node with enough disk. Set resources.requests.ephemeral-storage")
+			hasMissingRequest = true
+		}
+	}
+
+	if hasMissingLimit {
+		score.Grade = scorecard.GradeCritical
+	} else if hasMissingRequest {
+		score.Grade = scorecard.GradeWarning
+	} else {
+		score.Grade = scorecard.GradeAllOK
+	}
+
+	return
+}
+
+// podEmptyDirSizeLimit checks that all emptyDir volumes have a sizeLimit set
+func podEmptyDirSizeLimit(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for _, volume := range podTemplate.Spec.Volumes {
+		if volume.EmptyDir == nil {
+			continue
+		}
+		if volume.EmptyDir.SizeLimit == nil || volume.EmptyDir.SizeLimit.IsZero() {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(volume.Name, "The emptyDir volume has no sizeLimit", "Without a sizeLimit, the volume can fill up the disk of the node and cause other pods to be evicted. Set emptyDir.sizeLimit")
+		}
+	}
+
+	return
+}
+
+// containerVolumeMountsReadOnly checks that ConfigMap, Secret, downwardAPI and projected volumes are mounted with
+// readOnly, and that volumes with ConfigMaps are not mounted with subPath
+func containerVolumeMountsReadOnly(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	volumes := make(map[string]corev1.Volume)
+	for _, volume := range podTemplate.Spec.Volumes {
+		volumes[volume.Name] = volume
+	}
+
+	for _, container := range internal.AllContainers(podTemplate.Spec) {
+		for _, mount := range container.VolumeMounts {
+			volume, ok := volumes[mount.Name]
+			if !ok {
+				continue
+			}
+			kind, ok := configVolumeKind(volume)
+			if !ok {
+				continue
+			}
+
+			if !mount.ReadOnly {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(container.Name, fmt.Sprintf("The %s volume %s is not mounted as readOnly", kind, volume.Name),
+					"Set readOnly to true on the volumeMount, to make it explicit that the container does not write to the volume")
+			}
+			if mount.SubPath != "" && hasConfigMap(volume) {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(container.Name, fmt.Sprintf("The %s volume %s is mounted with subPath", kind, volume.Name),
+					"Files that are mounted with subPath are not updated when the ConfigMap changes, the pod must be restarted to get the new values. Mount the volume as a directory instead")
+			}
+		}
+	}
+
+	return
+}
+
+// configVolumeKind returns the kind of the volume if it's a ConfigMap, Secret, downwardAPI or projected volume
+func configVolumeKind(volume corev1.Volume) (string, bool) {
+	switch {
+	case volume.ConfigMap != nil:
+		return "ConfigMap", true
+	case volume.Secret != nil:
+		return "Secret", true
+	case volume.DownwardAPI != nil:
+		return "downwardAPI", true
+	case volume.Projected != nil:
+		return "projected", true
+	}
+	return "", false
+}
+
+// hasConfigMap returns true if the volume is a ConfigMap volume, or a projected volume with a ConfigMap source
+func hasConfigMap(volume corev1.Volume) bool {
+	if volume.ConfigMap != nil {
+		return true
+	}
+	if volume.Projected == nil {
+		return false
+	}
+	for _, source := range volume.Projected.Sources {
+		if source.ConfigMap != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// containerPortsNamed checks that all container ports have a name
+func containerPortsNamed(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for _, container := range internal.AllContainers(podTemplate.Spec) {
+		for _, port := range container.Ports {
+			if port.Name == "" {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(container.Name, fmt.Sprintf("The port %d does not have a name", port.ContainerPort),
+					"Named ports can be referenced from Services and probes, which keeps them working when the port number changes. Set a name on the port")
+			}
+		}
+	}
+
+	return
+}
+
+// containerImageTag checks that no container is using the ":latest" tag, or no tag at all
+func containerImageTag(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	hasTagLatest := false
+
+	for _, image := range containerImages(podTemplate.Spec) {
+		tag, digest := imageTagAndDigest(image.image)
+
+		// Images that are pinned to a digest are never updated, even if the tag is latest
+		if digest != "" {
+			continue
+		}
+
+		if tag == "" {
+			score.AddComment(image.container, "Image without tag", "Using a fixed tag is recommended to avoid accidental upgrades. Images without a tag uses the latest tag")
+			hasTagLatest = true
+		} else if tag == "latest" {
+			score.AddComment(image.container, "Image with latest tag", "Using a fixed tag is recommended to avoid accidental upgrades")
 			hasTagLatest = true
 		}
 	}
@@ -163,18 +417,79 @@ func containerImageTag(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeM
 	return
 }
 
-// containerImagePullPolicy checks if the containers ImagePullPolicy is set to PullAlways
-func containerImagePullPolicy(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
-	pod := podTemplate.Spec
+// containerImageDigest checks that all images are pinned to a digest
+func containerImageDigest(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
 
-	allContainers := pod.InitContainers
-	allContainers = append(allContainers, pod.Containers...)
+	for _, image := range containerImages(podTemplate.Spec) {
+		if _, digest := imageTagAndDigest(image.image); digest == "" {
+			score.AddComment(image.container, "Image is not pinned to a digest", "Set the image on the format image@sha256:<digest>, to make sure that the same image is always used. Tags can be moved to point to a different image.")
+			score.Grade = scorecard.GradeWarning
+		}
+	}
+
+	return
+}
+
+// containerImageRegistry checks that all images are pulled from one of the allowed registries
+func containerImageRegistry(allowedRegistries []string) func(corev1.PodTemplateSpec, metav1.TypeMeta) scorecard.TestScore {
+	return func(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+		score.Grade = scorecard.GradeAllOK
+
+		for _, image := range containerImages(podTemplate.Spec) {
+			repository := imageRepository(image.image)
+			if isAllowedRegistry(repository, allowedRegistries) {
+				continue
+			}
+			score.AddComment(image.container,
+				fmt.Sprintf("The image %s is not from an allowed registry", repository),
+				fmt.Sprintf("Use an image from one of the allowed registries (%s), or add the registry to allowedImageRegistries in the configuration file", strings.Join(allowedRegistries, ", ")))
+			score.Grade = scorecard.GradeCritical
+		}
+
+		return
+	}
+}
+
+// isAllowedRegistry returns true if the repository is equal to, or below, one of the allowed registries.
+// An allowed registry can be a host (gcr.io), or a host and a path (gcr.io/my-project).
+func isAllowedRegistry(repository string, allowedRegistries []string) bool {
+	for _, allowed := range allowedRegistries {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if repository == allowed || strings.HasPrefix(repository, allowed+"/") {
+			return true
+		}
+	}
+	return false
+}
 
+type containerImage struct {
+	container  string
+	image      string
+	pullPolicy corev1.PullPolicy
+}
+
+// containerImages returns the images of all init containers, containers and ephemeral containers in the pod
+func containerImages(pod corev1.PodSpec) []containerImage {
+	var images []containerImage
+	for _, c := range internal.AllContainers(pod) {
+		images = append(images, containerImage{c.Name, c.Image, c.ImagePullPolicy})
+	}
+	return images
+}
+
+// containerImagePullPolicy checks if the containers ImagePullPolicy is set to PullAlways
+func containerImagePullPolicy(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
 	// Default to AllOK
 	score.Grade = scorecard.GradeAllOK
 
-	for _, container := range allContainers {
-		tag := containerTag(container.Image)
+	for _, container := range internal.AllContainers(podTemplate.Spec) {
+		tag, digest := imageTagAndDigest(container.Image)
+
+		// Images that are pinned to a digest can not change, see containerImagePullPolicyConsistency
+		if digest != "" {
+			continue
+		}
 
 		// If the pull policy is not set, and the tag is either empty or latest
 		// kubernetes will default to always pull the image
@@ -192,13 +507,76 @@ func containerImagePullPolicy(podTemplate corev1.PodTemplateSpec, typeMeta metav
 	return
 }
 
-// containerTag returns the image tag
-// An empty string is returned if the image has no tag
-func containerTag(image string) string {
-	imageParts := strings.Split(image, ":")
-	if len(imageParts) > 1 {
-		imageVersion := imageParts[len(imageParts)-1]
-		return imageVersion
+// containerImagePullPolicyConsistency checks that the ImagePullPolicy matches how the image is referenced.
+// Images that are pinned to a digest never change, and pulling them on every start is pointless.
+// Images referenced by a tag can be changed in the registry, and should not be cached on the node.
+func containerImagePullPolicyConsistency(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for _, image := range containerImages(podTemplate.Spec) {
+		tag, digest := imageTagAndDigest(image.image)
+		pullPolicy := effectivePullPolicy(image.pullPolicy, tag, digest)
+
+		if digest != "" && pullPolicy == corev1.PullAlways {
+			score.AddComment(image.container, "ImagePullPolicy is Always for an image pinned to a digest",
+				"The image can not change, and pulling it on every container start only adds latency and a dependency on the registry. Set the ImagePullPolicy to IfNotPresent")
+			score.Grade = scorecard.GradeWarning
+		}
+
+		if digest == "" && (pullPolicy == corev1.PullIfNotPresent || pullPolicy == corev1.PullNever) {
+			score.AddComment(image.container, fmt.Sprintf("ImagePullPolicy is %s for an image with a mutable tag", pullPolicy),
+				"The tag can be moved to a different image, and nodes that have already pulled the image will keep running a stale version. Pin the image to a digest, or set the ImagePullPolicy to Always")
+			score.Grade = scorecard.GradeWarning
+		}
+	}
+
+	return
+}
+
+// effectivePullPolicy returns the ImagePullPolicy that Kubernetes uses for the image,
+// if the pull policy is not set it's Always for images without a tag or with the latest tag, and IfNotPresent otherwise.
+func effectivePullPolicy(pullPolicy corev1.PullPolicy, tag, digest string) corev1.PullPolicy {
+	if pullPolicy != "" {
+		return pullPolicy
+	}
+	if digest == "" && (tag == "" || tag == "latest") {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
+
+// imageRepository returns the fully qualified repository of the image, without the tag and digest.
+// Images without a registry are pulled from Docker Hub, for example "nginx" is returned as "docker.io/library/nginx".
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+		image = image[:i]
 	}
-	return ""
+
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return image
+	}
+	if len(parts) == 1 {
+		return "docker.io/library/" + image
+	}
+	return "docker.io/" + image
+}
+
+// imageTagAndDigest returns the tag and the digest of the image
+// Empty strings are returned if the image has no tag or no digest
+func imageTagAndDigest(image string) (tag, digest string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		digest = image[i+1:]
+		image = image[:i]
+	}
+
+	// The tag is after the last colon, unless the colon is a part of the registry host (registry:5000/image)
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+		tag = image[i+1:]
+	}
+
+	return tag, digest
 }
@@ -557,3 +557,57 @@ func TestFailInitContainerMemoryRequestsEqualLimits(t *testing.T) {
 	assert.Equal(t, "Memory requests does not match limits", s.Comments[0].Summary)
 	assert.Equal(t, "Having equal requests and limits is recommended to avoid resource DDOS of the node during spikes. Set resources.requests.memory == resources.limits.memory", s.Comments[0].Description)
 }
+
+func TestImageTagAndDigest(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		image  string
+		tag    string
+		digest string
+	}{
+		{"foo/bar", "", ""},
+		{"foo/bar:1.0.0", "1.0.0", ""},
+		{"foo/bar:latest", "latest", ""},
+		{"registry:5000/foo/bar", "", ""},
+		{"registry:5000/foo/bar:1.0.0", "1.0.0", ""},
+		{"foo/bar@sha256:0123abcd", "", "sha256:0123abcd"},
+		{"registry:5000/foo/bar:1.0.0@sha256:0123abcd", "1.0.0", "sha256:0123abcd"},
+	}
+
+	for _, tc := range tests {
+		tag, digest := imageTagAndDigest(tc.image)
+		assert.Equal(t, tc.tag, tag, tc.image)
+		assert.Equal(t, tc.digest, digest, tc.image)
+	}
+}
+
+func TestImageRepository(t *testing.T) {
+	tests := []struct {
+		image      string
+		repository string
+	}{
+		{"nginx", "docker.io/library/nginx"},
+		{"nginx:1.21", "docker.io/library/nginx"},
+		{"foo/bar:1.0.0", "docker.io/foo/bar"},
+		{"gcr.io/my-project/bar:1.0.0", "gcr.io/my-project/bar"},
+		{"registry:5000/foo/bar:1.0.0", "registry:5000/foo/bar"},
+		{"localhost/foo", "localhost/foo"},
+		{"foo/bar@sha256:0123abcd", "docker.io/foo/bar"},
+		{"registry:5000/foo/bar:1.0.0@sha256:0123abcd", "registry:5000/foo/bar"},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.repository, imageRepository(tc.image), tc.image)
+	}
+}
+
+func TestIsAllowedRegistry(t *testing.T) {
+	allowed := []string{"gcr.io/my-project/", "registry:5000", "docker.io/library"}
+
+	assert.True(t, isAllowedRegistry("gcr.io/my-project/bar", allowed))
+	assert.True(t, isAllowedRegistry("registry:5000/foo/bar", allowed))
+	assert.True(t, isAllowedRegistry("docker.io/library/nginx", allowed))
+	assert.False(t, isAllowedRegistry("gcr.io/my-project-2/bar", allowed))
+	assert.False(t, isAllowedRegistry("docker.io/foo/bar", allowed))
+	assert.False(t, isAllowedRegistry("gcr.io/my-project/bar", nil))
+}
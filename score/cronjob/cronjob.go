@@ -1,15 +1,44 @@
 package cronjob
 
 import (
+	"fmt"
+
+	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/score/checks"
 	"github.com/zegl/kube-score/scorecard"
 )
 
-func Register(allChecks *checks.Checks) {
+func Register(allChecks *checks.Checks, kubernetesVersion config.Semver) {
 	allChecks.RegisterCronJobCheck("CronJob has deadline", `Makes sure that all CronJobs has a configured deadline`, cronJobHasDeadline)
+	allChecks.RegisterCronJobCheck("CronJob Concurrency Policy", `Makes sure that all CronJobs explicitly set a concurrencyPolicy`, cronJobConcurrencyPolicy)
+	allChecks.RegisterCronJobCheck("CronJob History Limits", `Makes sure that the successfulJobsHistoryLimit and failedJobsHistoryLimit of CronJobs are not excessively large`, cronJobHistoryLimits)
+	allChecks.RegisterCronJobCheck("CronJob Time Zone", `Makes sure that CronJobs set a timeZone, when targeting Kubernetes v1.25 or later`, cronJobTimeZone(kubernetesVersion))
+	allChecks.Document("cronjob-has-deadline", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set startingDeadlineSeconds to the number of seconds that a missed run can be started late",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/cron-jobs/#job-creation",
+	})
+	allChecks.Document("cronjob-concurrency-policy", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set concurrencyPolicy to Allow, Forbid or Replace",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/cron-jobs/#concurrency-policy",
+	})
+	allChecks.Document("cronjob-history-limits", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Lower successfulJobsHistoryLimit and failedJobsHistoryLimit, or remove them to use the defaults",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/cron-jobs/#jobs-history-limits",
+	})
+	allChecks.Document("cronjob-time-zone", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set timeZone to the name of a time zone, such as Etc/UTC",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/cron-jobs/#time-zones",
+	})
 }
 
+// maxJobsHistoryLimit is the highest number of finished Jobs to keep that is not considered to be excessive
+const maxJobsHistoryLimit = 10
+
 func cronJobHasDeadline(job ks.CronJob) (score scorecard.TestScore) {
 	if job.StartingDeadlineSeconds() == nil {
 		score.Grade = scorecard.GradeCritical
@@ -21,3 +50,57 @@ func cronJobHasDeadline(job ks.CronJob) (score scorecard.TestScore) {
 	score.Grade = scorecard.GradeAllOK
 	return
 }
+
+func cronJobConcurrencyPolicy(job ks.CronJob) (score scorecard.TestScore) {
+	if job.ConcurrencyPolicy() == "" {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "The CronJob does not set a concurrencyPolicy",
+			"The default policy Allow runs multiple Jobs at the same time if a Job takes longer than the schedule interval. Set concurrencyPolicy to Allow, Forbid or Replace")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func cronJobHistoryLimits(job ks.CronJob) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for _, limit := range []struct {
+		name  string
+		value *int32
+	}{
+		{"successfulJobsHistoryLimit", job.SuccessfulJobsHistoryLimit()},
+		{"failedJobsHistoryLimit", job.FailedJobsHistoryLimit()},
+	} {
+		if limit.value == nil || *limit.value <= maxJobsHistoryLimit {
+			continue
+		}
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", fmt.Sprintf("%s is set to %d", limit.name, *limit.value),
+			fmt.Sprintf("Every finished Job and its pods are kept in the cluster. Set %s to %d or less", limit.name, maxJobsHistoryLimit))
+	}
+
+	return
+}
+
+func cronJobTimeZone(kubernetesVersion config.Semver) func(ks.CronJob) scorecard.TestScore {
+	return func(job ks.CronJob) (score scorecard.TestScore) {
+		// timeZone is enabled by default since Kubernetes v1.25
+		if kubernetesVersion.LessThan(config.Semver{1, 25}) {
+			score.Skipped = true
+			score.AddComment("", "Skipped because timeZone is not supported by the targeted Kubernetes version", "")
+			return
+		}
+
+		if job.TimeZone() == nil || *job.TimeZone() == "" {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("", "The CronJob does not set a timeZone",
+				"Without a timeZone, the schedule is interpreted in the time zone of the kube-controller-manager. Set timeZone, for example to Etc/UTC")
+			return
+		}
+
+		score.Grade = scorecard.GradeAllOK
+		return
+	}
+}
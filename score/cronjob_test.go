@@ -3,6 +3,10 @@ package score
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/scorecard"
 )
 
@@ -35,3 +39,67 @@ func TestProbesPodCronMissingReady(t *testing.T) {
 		})
 	}
 }
+
+func TestCronJobConcurrencyPolicy(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "cronjob-batchv1-hygiene.yaml", "CronJob Concurrency Policy", scorecard.GradeAllOK)
+}
+
+func TestCronJobConcurrencyPolicyNotSet(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []string{"batchv1beta1", "batchv1"} {
+		t.Run(v, func(t *testing.T) {
+			testExpectedScore(t, "cronjob-"+v+"-deadline-set.yaml", "CronJob Concurrency Policy", scorecard.GradeWarning)
+		})
+	}
+}
+
+func TestCronJobHistoryLimits(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "cronjob-batchv1-hygiene.yaml", "CronJob History Limits", scorecard.GradeAllOK)
+	testExpectedScore(t, "cronjob-batchv1-deadline-set.yaml", "CronJob History Limits", scorecard.GradeAllOK)
+}
+
+func TestCronJobHistoryLimitsLarge(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "cronjob-batchv1beta1-history-limits.yaml", "CronJob History Limits", scorecard.GradeWarning)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "successfulJobsHistoryLimit is set to 100", comments[0].Summary)
+	assert.Equal(t, "failedJobsHistoryLimit is set to 50", comments[1].Summary)
+}
+
+func TestCronJobTimeZone(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("cronjob-batchv1-hygiene.yaml")},
+		KubernetesVersion: config.Semver{1, 25},
+	}, "CronJob Time Zone", scorecard.GradeAllOK)
+}
+
+func TestCronJobTimeZoneNotSet(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []string{"batchv1beta1", "batchv1"} {
+		t.Run(v, func(t *testing.T) {
+			testExpectedScoreWithConfig(t, config.Configuration{
+				AllFiles:          []ks.NamedReader{testFile("cronjob-" + v + "-deadline-set.yaml")},
+				KubernetesVersion: config.Semver{1, 27},
+			}, "CronJob Time Zone", scorecard.GradeWarning)
+		})
+	}
+}
+
+func TestCronJobTimeZoneOldKubernetesVersion(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "cronjob-batchv1-deadline-set.yaml", "CronJob Time Zone", 0)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Skipped because timeZone is not supported by the targeted Kubernetes version", comments[0].Summary)
+}
+
+func TestCronJobTemplatePodChecks(t *testing.T) {
+	t.Parallel()
+
+	testExpectedScore(t, "cronjob-batchv1-hygiene.yaml", "Container Image Tag", scorecard.GradeAllOK)
+	testExpectedScore(t, "cronjob-batchv1beta1-history-limits.yaml", "Container Image Tag", scorecard.GradeCritical)
+}
@@ -0,0 +1,104 @@
+package custom
+
+import (
+	"fmt"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/internal/cel"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Register registers the custom checks from the configuration file
+func Register(allChecks *checks.Checks, customChecks []config.CustomCheck) {
+	for _, c := range customChecks {
+		name := c.Name
+		if name == "" {
+			name = c.ID
+		}
+		comment := c.Comment
+		if comment == "" {
+			comment = fmt.Sprintf("Makes sure that %s is true", c.Expression)
+		}
+
+		compiled, err := compile(c)
+		allChecks.RegisterObjectCheck(c.ID, name, comment, c.Kinds, customCheck(c, compiled, err))
+		allChecks.Document(c.ID, checks.Documentation{Grade: compiled.grade})
+	}
+}
+
+// compiledCheck is a custom check with its expression and templates compiled, and its grade parsed
+type compiledCheck struct {
+	program              *cel.Program
+	message, description *cel.Template
+	grade                scorecard.Grade
+}
+
+// compile compiles the custom check. The grade is critical if the check is invalid.
+func compile(c config.CustomCheck) (compiledCheck, error) {
+	res := compiledCheck{grade: scorecard.GradeCritical}
+	if err := c.Validate(); err != nil {
+		return res, err
+	}
+
+	grade := scorecard.GradeCritical
+	if c.Grade != "" {
+		var err error
+		if grade, err = scorecard.ParseGrade(c.Grade); err != nil {
+			return res, fmt.Errorf("custom check %s: %w", c.ID, err)
+		}
+	}
+	program, err := cel.Compile(c.Expression)
+	if err != nil {
+		return res, fmt.Errorf("custom check %s: invalid expression: %w", c.ID, err)
+	}
+	message, err := cel.CompileTemplate(c.Message)
+	if err != nil {
+		return res, fmt.Errorf("custom check %s: invalid message: %w", c.ID, err)
+	}
+	description, err := cel.CompileTemplate(c.Description)
+	if err != nil {
+		return res, fmt.Errorf("custom check %s: invalid description: %w", c.ID, err)
+	}
+
+	return compiledCheck{program: program, message: message, description: description, grade: grade}, nil
+}
+
+// customCheck returns a check that fails if the expression is false, or if it can't be evaluated. The configuration
+// file has already been validated, compileErr is reported by the check if it has not.
+func customCheck(c config.CustomCheck, compiled compiledCheck, compileErr error) checks.ObjectCheckFn {
+	return func(object ks.Object) (score scorecard.TestScore) {
+		if compileErr != nil {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment("", "The custom check is invalid", compileErr.Error())
+			return
+		}
+
+		vars := map[string]interface{}{"object": object.Unstructured()}
+
+		ok, evalErr := compiled.program.EvalBool(vars)
+		if evalErr != nil {
+			score.Grade = compiled.grade
+			score.AddComment("", "The expression could not be evaluated", fmt.Sprintf("%s: %s", c.Expression, evalErr))
+			return
+		}
+		if ok {
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+
+		score.Grade = compiled.grade
+
+		summary, err := compiled.message.Execute(vars)
+		if err != nil || summary == "" {
+			summary = fmt.Sprintf("The expression %s is false", c.Expression)
+		}
+		desc, err := compiled.description.Execute(vars)
+		if err != nil {
+			desc = ""
+		}
+		score.AddComment("", summary, desc)
+		return
+	}
+}
@@ -0,0 +1,129 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func findCustomCheck(t *testing.T, sc scorecard.Scorecard, object, id string) *scorecard.TestScore {
+	o, ok := sc[object]
+	if !assert.True(t, ok, object) {
+		return nil
+	}
+	for _, c := range o.Checks {
+		if c.Check.ID == id {
+			return &c
+		}
+	}
+	return nil
+}
+
+func TestCustomCheck(t *testing.T) {
+	t.Parallel()
+
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("custom-checks.yaml")},
+		KubernetesVersion: config.Semver{1, 18},
+		CustomChecks: []config.CustomCheck{
+			{
+				ID:          "deployment-min-replicas",
+				Name:        "Deployment Min Replicas",
+				Kinds:       []string{"Deployment"},
+				Expression:  "object.spec.replicas >= 3",
+				Grade:       "warning",
+				Message:     "The Deployment has {{ object.spec.replicas }} replicas",
+				Description: "Run at least 3 replicas of {{ object.metadata.name }}",
+			},
+			{
+				ID:         "configmap-has-data",
+				Kinds:      []string{"ConfigMap"},
+				Expression: "has(object.data) && size(object.data) > 0",
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	few := findCustomCheck(t, sc, "Deployment/apps/v1//few-replicas", "deployment-min-replicas")
+	assert.Equal(t, scorecard.GradeWarning, few.Grade)
+	assert.Equal(t, "Deployment Min Replicas", few.Check.Name)
+	assert.Equal(t, "Deployment", few.Check.TargetType)
+	assert.Len(t, few.Comments, 1)
+	assert.Equal(t, "The Deployment has 1 replicas", few.Comments[0].Summary)
+	assert.Equal(t, "Run at least 3 replicas of few-replicas", few.Comments[0].Description)
+
+	many := findCustomCheck(t, sc, "Deployment/apps/v1//many-replicas", "deployment-min-replicas")
+	assert.Equal(t, scorecard.GradeAllOK, many.Grade)
+
+	// Objects of kinds without built-in checks are scored by the custom checks that target them
+	configMap := findCustomCheck(t, sc, "ConfigMap/v1//settings", "configmap-has-data")
+	assert.Equal(t, scorecard.GradeAllOK, configMap.Grade)
+	assert.Equal(t, "configmap-has-data", configMap.Check.Name)
+	assert.Nil(t, findCustomCheck(t, sc, "ConfigMap/v1//settings", "deployment-min-replicas"))
+}
+
+func TestCustomCheckEvaluationError(t *testing.T) {
+	t.Parallel()
+
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("custom-checks.yaml")},
+		KubernetesVersion: config.Semver{1, 18},
+		CustomChecks: []config.CustomCheck{{
+			ID:         "deployment-strategy",
+			Kinds:      []string{"Deployment"},
+			Expression: `object.spec.strategy.type == "RollingUpdate"`,
+		}},
+		UnknownKinds: config.UnknownKindsSkip,
+	})
+	assert.Nil(t, err)
+
+	check := findCustomCheck(t, sc, "Deployment/apps/v1//few-replicas", "deployment-strategy")
+	assert.Equal(t, scorecard.GradeCritical, check.Grade)
+	assert.Equal(t, "The expression could not be evaluated", check.Comments[0].Summary)
+	assert.Equal(t, `object.spec.strategy.type == "RollingUpdate": no such key: strategy`, check.Comments[0].Description)
+
+	// Custom checks are only run on the objects of their kinds
+	assert.Nil(t, findCustomCheck(t, sc, "ConfigMap/v1//settings", "deployment-strategy"))
+}
+
+func TestCustomCheckIgnored(t *testing.T) {
+	t.Parallel()
+
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("custom-checks.yaml")},
+		KubernetesVersion: config.Semver{1, 18},
+		IgnoredTests:      map[string]struct{}{"deployment-min-replicas": {}},
+		CustomChecks: []config.CustomCheck{{
+			ID:         "deployment-min-replicas",
+			Expression: "object.spec.replicas >= 3",
+		}},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, findCustomCheck(t, sc, "Deployment/apps/v1//few-replicas", "deployment-min-replicas"))
+}
+
+func TestCustomCheckInvalid(t *testing.T) {
+	t.Parallel()
+
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("custom-checks.yaml")},
+		KubernetesVersion: config.Semver{1, 18},
+		CustomChecks: []config.CustomCheck{{
+			ID:         "deployment-invalid",
+			Kinds:      []string{"Deployment"},
+			Expression: "object.spec.replicas >=",
+			Grade:      "warning",
+		}},
+	})
+	assert.Nil(t, err)
+
+	// Checks that are not validated by the configuration file report why they are invalid
+	check := findCustomCheck(t, sc, "Deployment/apps/v1//few-replicas", "deployment-invalid")
+	assert.Equal(t, scorecard.GradeCritical, check.Grade)
+	assert.Equal(t, "The custom check is invalid", check.Comments[0].Summary)
+	assert.Contains(t, check.Comments[0].Description, "custom check deployment-invalid: invalid expression")
+}
@@ -9,13 +9,37 @@ import (
 	"github.com/zegl/kube-score/scorecard"
 
 	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func Register(allChecks *checks.Checks, budgets ks.PodDisruptionBudgets) {
+func Register(allChecks *checks.Checks, budgets ks.PodDisruptionBudgets, deployments ks.Deployments, statefulsets ks.StatefulSets) {
 	allChecks.RegisterStatefulSetCheck("StatefulSet has PodDisruptionBudget", `Makes sure that all StatefulSets are targeted by a PDB`, statefulSetHas(budgets.PodDisruptionBudgets()))
 	allChecks.RegisterDeploymentCheck("Deployment has PodDisruptionBudget", `Makes sure that all Deployments are targeted by a PDB`, deploymentHas(budgets.PodDisruptionBudgets()))
 	allChecks.RegisterPodDisruptionBudgetCheck("PodDisruptionBudget has policy", `Makes sure that PodDisruptionBudgets specify minAvailable or maxUnavailable`, hasPolicy)
+	allChecks.RegisterPodDisruptionBudgetCheck("PodDisruptionBudget allows disruption", `Makes sure that the minAvailable or maxUnavailable of PodDisruptionBudgets allows at least one pod to be evicted, given the replicas of the targeted Deployments and StatefulSets`, allowsDisruption(deployments.Deployments(), statefulsets.StatefulSets()))
+	allChecks.MarkCrossObject("statefulset-has-poddisruptionbudget", "deployment-has-poddisruptionbudget")
+	allChecks.Document("statefulset-has-poddisruptionbudget", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Create a PodDisruptionBudget that selects the pods of the StatefulSet",
+		URL:         "https://kubernetes.io/docs/tasks/run-application/configure-pdb/",
+	})
+	allChecks.Document("deployment-has-poddisruptionbudget", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Create a PodDisruptionBudget that selects the pods of the Deployment",
+		URL:         "https://kubernetes.io/docs/tasks/run-application/configure-pdb/",
+	})
+	allChecks.Document("poddisruptionbudget-has-policy", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set minAvailable or maxUnavailable",
+		URL:         "https://kubernetes.io/docs/tasks/run-application/configure-pdb/#specifying-a-poddisruptionbudget",
+	})
+	allChecks.Document("poddisruptionbudget-allows-disruption", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Lower minAvailable, or raise maxUnavailable, so that at least one pod can be evicted",
+		URL:         "https://kubernetes.io/docs/tasks/run-application/configure-pdb/#specifying-a-poddisruptionbudget",
+	})
 }
 
 func hasMatching(budgets []ks.PodDisruptionBudget, namespace string, labels map[string]string) (bool, error) {
@@ -98,3 +122,102 @@ func hasPolicy(pdb ks.PodDisruptionBudget) (score scorecard.TestScore) {
 
 	return
 }
+
+type pdbTarget struct {
+	kind     string
+	name     string
+	replicas int
+}
+
+// pdbTargets returns the Deployments and StatefulSets that are targeted by the budget
+func pdbTargets(budget ks.PodDisruptionBudget, deployments []ks.Deployment, statefulsets []ks.StatefulSet) ([]pdbTarget, error) {
+	selector, err := metav1.LabelSelectorAsSelector(budget.PodDisruptionBudgetSelector())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create selector: %v", err)
+	}
+
+	replicas := func(r *int32) int {
+		if r == nil {
+			return 1
+		}
+		return int(*r)
+	}
+
+	var targets []pdbTarget
+	for _, d := range deployments {
+		deployment := d.Deployment()
+		if deployment.Namespace == budget.Namespace() && selector.Matches(internal.MapLables(deployment.Spec.Template.Labels)) {
+			targets = append(targets, pdbTarget{"Deployment", deployment.Name, replicas(deployment.Spec.Replicas)})
+		}
+	}
+	for _, s := range statefulsets {
+		statefulset := s.StatefulSet()
+		if statefulset.Namespace == budget.Namespace() && selector.Matches(internal.MapLables(statefulset.Spec.Template.Labels)) {
+			targets = append(targets, pdbTarget{"StatefulSet", statefulset.Name, replicas(statefulset.Spec.Replicas)})
+		}
+	}
+	return targets, nil
+}
+
+// allowedDisruptions returns the number of pods that can be evicted, out of the given number of replicas.
+// Percentages are rounded up, in the same way as the disruption controller.
+func allowedDisruptions(spec policyv1.PodDisruptionBudgetSpec, replicas int) (int, error) {
+	if spec.MaxUnavailable != nil {
+		return intstr.GetScaledValueFromIntOrPercent(spec.MaxUnavailable, replicas, true)
+	}
+	if spec.MinAvailable != nil {
+		minAvailable, err := intstr.GetScaledValueFromIntOrPercent(spec.MinAvailable, replicas, true)
+		if err != nil {
+			return 0, err
+		}
+		return replicas - minAvailable, nil
+	}
+	return replicas, nil
+}
+
+func allowsDisruption(deployments []ks.Deployment, statefulsets []ks.StatefulSet) func(ks.PodDisruptionBudget) scorecard.TestScore {
+	return func(budget ks.PodDisruptionBudget) (score scorecard.TestScore) {
+		spec := budget.Spec()
+		if spec.MinAvailable == nil && spec.MaxUnavailable == nil {
+			score.Skipped = true
+			score.AddComment("", "Skipped because the PodDisruptionBudget has no policy", "")
+			return
+		}
+
+		targets, err := pdbTargets(budget, deployments, statefulsets)
+		if err != nil {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment("", "Invalid selector", err.Error())
+			return
+		}
+
+		score.Grade = scorecard.GradeAllOK
+
+		// Without any known targets, only the policies that never allow any disruption can be detected
+		if len(targets) == 0 {
+			if allowed, err := allowedDisruptions(spec, 100); err == nil && allowed <= 0 {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment("", "The PodDisruptionBudget does not allow any pods to be evicted",
+					"A PodDisruptionBudget that does not allow any disruption blocks node drains and cluster upgrades. Lower minAvailable, or increase maxUnavailable")
+			}
+			return
+		}
+
+		for _, target := range targets {
+			allowed, err := allowedDisruptions(spec, target.replicas)
+			if err != nil {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment("", "Invalid policy", err.Error())
+				return
+			}
+			if allowed > 0 {
+				continue
+			}
+			score.Grade = scorecard.GradeCritical
+			score.AddComment("", fmt.Sprintf("The PodDisruptionBudget does not allow any pods of the %s %s with %d replicas to be evicted", target.kind, target.name, target.replicas),
+				"A PodDisruptionBudget that does not allow any disruption blocks node drains and cluster upgrades. Lower minAvailable, increase maxUnavailable, or increase the number of replicas")
+		}
+
+		return
+	}
+}
@@ -3,6 +3,8 @@ package disruptionbudget
 import (
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"testing"
 
 	"github.com/zegl/kube-score/scorecard"
@@ -62,6 +64,34 @@ func TestDeploymentReplicas(t *testing.T) {
 	}
 }
 
+func TestAllowedDisruptions(t *testing.T) {
+	t.Parallel()
+	intOrStr := func(s string) *intstr.IntOrString {
+		v := intstr.Parse(s)
+		return &v
+	}
+
+	cases := []struct {
+		spec     policyv1.PodDisruptionBudgetSpec
+		replicas int
+		expected int
+	}{
+		{policyv1.PodDisruptionBudgetSpec{MinAvailable: intOrStr("100%")}, 3, 0},
+		{policyv1.PodDisruptionBudgetSpec{MinAvailable: intOrStr("50%")}, 3, 1},
+		{policyv1.PodDisruptionBudgetSpec{MinAvailable: intOrStr("2")}, 3, 1},
+		{policyv1.PodDisruptionBudgetSpec{MinAvailable: intOrStr("2")}, 1, -1},
+		{policyv1.PodDisruptionBudgetSpec{MaxUnavailable: intOrStr("0")}, 3, 0},
+		{policyv1.PodDisruptionBudgetSpec{MaxUnavailable: intOrStr("10%")}, 3, 1},
+		{policyv1.PodDisruptionBudgetSpec{MaxUnavailable: intOrStr("0%")}, 3, 0},
+	}
+
+	for _, tc := range cases {
+		allowed, err := allowedDisruptions(tc.spec, tc.replicas)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.expected, allowed, "%+v replicas=%d", tc.spec, tc.replicas)
+	}
+}
+
 func intptr(a int32) *int32 {
 	return &a
 }
@@ -0,0 +1,124 @@
+// Package dns checks the DNS configuration of pods
+package dns
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// hostnamePattern matches hostnames in the environment variables, commands and arguments of containers
+var hostnamePattern = regexp.MustCompile(`[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)+`)
+
+func Register(allChecks *checks.Checks, services ks.Services) {
+	allChecks.RegisterPodCheck("Pod DNS Policy", `Makes sure that pods that connect to Services do not use the DNS of the node with dnsPolicy Default, and that pods with hostNetwork use dnsPolicy ClusterFirstWithHostNet`, podDNSPolicy(services.Services()))
+	allChecks.RegisterOptionalPodCheck("Pod DNS Config", `Makes sure that pods that use the DNS of the cluster lower the ndots option with dnsConfig, to avoid multiple DNS lookups of external names`, podDNSConfig)
+	allChecks.Document("pod-dns-policy", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Remove dnsPolicy to use ClusterFirst, or set it to ClusterFirstWithHostNet if the pod uses hostNetwork",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/dns-pod-service/#pod-s-dns-policy",
+	})
+	allChecks.Document("pod-dns-config", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Add the ndots option to dnsConfig.options, for example with the value 2",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/dns-pod-service/#pod-dns-config",
+	})
+}
+
+func podDNSPolicy(services []ks.Service) func(corev1.PodTemplateSpec, metav1.TypeMeta) scorecard.TestScore {
+	return func(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+		score.Grade = scorecard.GradeAllOK
+		spec := podTemplate.Spec
+
+		if spec.DNSPolicy == corev1.DNSDefault {
+			if host, ok := clusterHostname(podTemplate, services); ok {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment("", "The pod uses the DNS of the node",
+					fmt.Sprintf("The pod connects to %s, which can't be resolved with dnsPolicy Default. Remove dnsPolicy to use the DNS of the cluster", host))
+			}
+			return
+		}
+
+		if spec.HostNetwork && (spec.DNSPolicy == "" || spec.DNSPolicy == corev1.DNSClusterFirst) {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("", "The pod uses hostNetwork without dnsPolicy ClusterFirstWithHostNet",
+				"Pods with hostNetwork and dnsPolicy ClusterFirst use the DNS of the node, and can't resolve the names of Services. Set dnsPolicy to ClusterFirstWithHostNet")
+		}
+
+		return
+	}
+}
+
+// clusterHostname returns a hostname in the environment variables, commands or arguments of the containers that
+// can only be resolved by the DNS of the cluster, such as the name of a Service
+func clusterHostname(podTemplate corev1.PodTemplateSpec, services []ks.Service) (string, bool) {
+	serviceNames := make(map[string]struct{})
+	for _, s := range services {
+		service := s.Service()
+		if service.Namespace != podTemplate.Namespace {
+			continue
+		}
+		serviceNames[service.Name] = struct{}{}
+		serviceNames[service.Name+"."+service.Namespace] = struct{}{}
+	}
+
+	for _, container := range internal.AllContainers(podTemplate.Spec) {
+		var values []string
+		values = append(values, container.Command...)
+		values = append(values, container.Args...)
+		for _, env := range container.Env {
+			values = append(values, env.Value)
+		}
+
+		for _, value := range values {
+			for _, host := range hostnamePattern.FindAllString(strings.ToLower(value), -1) {
+				if strings.Contains(host, ".svc") || strings.HasSuffix(host, ".cluster.local") {
+					return host, true
+				}
+			}
+			if u, err := url.Parse(value); err == nil && u.Host != "" {
+				if _, ok := serviceNames[u.Hostname()]; ok {
+					return u.Hostname(), true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+func podDNSConfig(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	spec := podTemplate.Spec
+	if spec.DNSPolicy == corev1.DNSDefault || spec.DNSPolicy == corev1.DNSNone {
+		score.Skipped = true
+		score.AddComment("", "Skipped because the pod does not use the DNS of the cluster", "")
+		return
+	}
+	if spec.HostNetwork && spec.DNSPolicy != corev1.DNSClusterFirstWithHostNet {
+		score.Skipped = true
+		score.AddComment("", "Skipped because the pod does not use the DNS of the cluster", "")
+		return
+	}
+
+	if spec.DNSConfig != nil {
+		for _, option := range spec.DNSConfig.Options {
+			if option.Name == "ndots" {
+				score.Grade = scorecard.GradeAllOK
+				return
+			}
+		}
+	}
+
+	score.Grade = scorecard.GradeWarning
+	score.AddComment("", "The pod uses the default ndots of 5",
+		"Names with less than 5 dots, such as api.example.com, are first looked up in all search domains of the cluster, which adds several DNS lookups to each request. Add the ndots option to dnsConfig.options, for example with the value 2")
+	return
+}
@@ -0,0 +1,54 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestPodDNSPolicyDefault(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-dns-policy-default.yaml", "Pod DNS Policy", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The pod uses the DNS of the node", comments[0].Summary)
+	assert.Contains(t, comments[0].Description, "db")
+}
+
+func TestPodDNSPolicyDefaultWithoutClusterNames(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-dns-policy-default-external.yaml", "Pod DNS Policy", scorecard.GradeAllOK)
+}
+
+func TestPodDNSPolicyHostNetwork(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-dns-host-network.yaml", "Pod DNS Policy", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The pod uses hostNetwork without dnsPolicy ClusterFirstWithHostNet", comments[0].Summary)
+}
+
+func TestPodDNSPolicyHostNetworkClusterFirstWithHostNet(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-dns-configured.yaml", "Pod DNS Policy", scorecard.GradeAllOK)
+}
+
+func TestPodDNSConfig(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-dns-configured.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-dns-config": {}},
+	}, "Pod DNS Config", scorecard.GradeAllOK)
+}
+
+func TestPodDNSConfigMissingNdots(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-probes-all-missing.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-dns-config": {}},
+	}, "Pod DNS Config", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The pod uses the default ndots of 5", comments[0].Summary)
+}
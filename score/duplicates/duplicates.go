@@ -0,0 +1,126 @@
+// Package duplicates checks that objects are only defined once, and that the names in the pod spec are unique
+package duplicates
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks, metas ks.Metas) {
+	allChecks.RegisterMetaCheck("Object Unique", `Makes sure that no two objects have the same kind, namespace and name, as only one of them is applied`, objectUnique(metas.Metas()))
+	allChecks.RegisterPodCheck("Pod Unique Fields", `Makes sure that the names of the containers, volumes, environment variables and ports of the pod, and the numbers of the ports, are unique`, podUniqueFields)
+	allChecks.Document("object-unique", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Remove all but one of the objects, or give them different names",
+	})
+	allChecks.Document("pod-unique-fields", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Remove or rename the duplicated containers, volumes, environment variables and ports",
+	})
+}
+
+// objectKey identifies an object in the cluster. The version is not a part of the key, as objects of different
+// versions of the same kind are the same object.
+type objectKey struct {
+	group, kind, namespace, name string
+}
+
+func keyOf(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) objectKey {
+	gv, _ := schema.ParseGroupVersion(typeMeta.APIVersion)
+	return objectKey{gv.Group, typeMeta.Kind, objectMeta.Namespace, objectMeta.Name}
+}
+
+func objectUnique(metas []ks.BothMeta) func(ks.BothMeta) scorecard.TestScore {
+	locations := make(map[objectKey][]ks.FileLocation)
+	for _, meta := range metas {
+		key := keyOf(meta.TypeMeta, meta.ObjectMeta)
+		locations[key] = append(locations[key], meta.FileLocation())
+	}
+
+	return func(meta ks.BothMeta) (score scorecard.TestScore) {
+		found := locations[keyOf(meta.TypeMeta, meta.ObjectMeta)]
+		if len(found) < 2 {
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+
+		var defined []string
+		for _, location := range found {
+			defined = append(defined, fmt.Sprintf("%s:%d", location.Name, location.Line))
+		}
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", fmt.Sprintf("The %s is defined %d times", meta.TypeMeta.Kind, len(found)),
+			fmt.Sprintf("Only one of the objects is applied, and the other definitions are silently overwritten. The object is defined in %s", strings.Join(defined, ", ")))
+		return
+	}
+}
+
+// podUniqueFields checks that the names of containers, volumes, environment variables and ports are not duplicated
+func podUniqueFields(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+	duplicate := func(path, summary, description string) {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment(path, summary, description)
+	}
+
+	containerNames := make(map[string]struct{})
+	portNames := make(map[string]struct{})
+	portNumbers := make(map[string]struct{})
+	for _, container := range internal.AllContainers(podTemplate.Spec) {
+		if _, ok := containerNames[container.Name]; ok {
+			duplicate(container.Name, fmt.Sprintf("The container name %s is used more than once", container.Name),
+				"The names of all containers, init containers and ephemeral containers of the pod must be unique")
+		}
+		containerNames[container.Name] = struct{}{}
+
+		envNames := make(map[string]struct{})
+		for _, env := range container.Env {
+			if _, ok := envNames[env.Name]; ok {
+				duplicate(container.Name, fmt.Sprintf("The environment variable %s is set more than once", env.Name),
+					"Only the last value of the environment variable is used. Remove the other values")
+			}
+			envNames[env.Name] = struct{}{}
+		}
+
+		for _, port := range container.Ports {
+			if port.Name != "" {
+				if _, ok := portNames[port.Name]; ok {
+					duplicate(container.Name, fmt.Sprintf("The port name %s is used more than once", port.Name),
+						"Services and probes that reference the port by name can target the wrong port. Give the ports unique names")
+				}
+				portNames[port.Name] = struct{}{}
+			}
+
+			protocol := port.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			number := fmt.Sprintf("%d/%s", port.ContainerPort, protocol)
+			if _, ok := portNumbers[number]; ok {
+				duplicate(container.Name, fmt.Sprintf("The port %s is declared more than once", number),
+					"All containers of a pod share the same network namespace, and only one of them can listen on the port. Remove the duplicated port")
+			}
+			portNumbers[number] = struct{}{}
+		}
+	}
+
+	volumeNames := make(map[string]struct{})
+	for _, volume := range podTemplate.Spec.Volumes {
+		if _, ok := volumeNames[volume.Name]; ok {
+			duplicate(volume.Name, fmt.Sprintf("The volume name %s is used more than once", volume.Name),
+				"The names of all volumes of the pod must be unique")
+		}
+		volumeNames[volume.Name] = struct{}{}
+	}
+
+	return
+}
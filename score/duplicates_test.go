@@ -0,0 +1,54 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestObjectUnique(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("duplicate-objects.yaml")},
+	})
+	assert.NoError(t, err)
+
+	grades := make(map[string]scorecard.Grade)
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID == "object-unique" {
+				grades[o.ObjectMeta.Namespace] = c.Grade
+				if c.Grade == scorecard.GradeCritical {
+					assert.Equal(t, "The Deployment is defined 2 times", c.Comments[0].Summary)
+					assert.Equal(t, "Only one of the objects is applied, and the other definitions are silently overwritten. The object is defined in testdata/duplicate-objects.yaml:1, testdata/duplicate-objects.yaml:19", c.Comments[0].Description)
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]scorecard.Grade{"foo": scorecard.GradeCritical, "bar": scorecard.GradeAllOK}, grades)
+}
+
+func TestPodUniqueFields(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-probes-all-missing.yaml", "Pod Unique Fields", scorecard.GradeAllOK)
+}
+
+func TestPodUniqueFieldsDuplicated(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-duplicate-fields.yaml", "Pod Unique Fields", scorecard.GradeCritical)
+	var summaries []string
+	for _, c := range comments {
+		summaries = append(summaries, c.Path+": "+c.Summary)
+	}
+	assert.Equal(t, []string{
+		"app: The environment variable LOG_LEVEL is set more than once",
+		"proxy: The port name http is used more than once",
+		"proxy: The port 8080/TCP is declared more than once",
+		"app: The container name app is used more than once",
+		"data: The volume name data is used more than once",
+	}, summaries)
+}
@@ -33,3 +33,30 @@ func TestFileLocation(t *testing.T) {
 	assert.Equal(t, 2, sc["Deployment/apps/v1//foo"].FileLocation.Line)
 	assert.Equal(t, 12, sc["Deployment/apps/v1//foo2"].FileLocation.Line)
 }
+
+func TestFileLocationComments(t *testing.T) {
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("linenumbers-containers.yaml")},
+		KubernetesVersion: config.Semver{1, 18},
+	})
+	assert.Nil(t, err)
+
+	obj := sc["Pod/v1//second"]
+	assert.Equal(t, 11, obj.FileLocation.Line)
+
+	var locations []ks.FileLocation
+	for _, check := range obj.Checks {
+		if check.Check.Name != "Container Image Tag" {
+			continue
+		}
+		for _, comment := range check.Comments {
+			locations = append(locations, comment.FileLocation)
+		}
+	}
+
+	// The comments point at the containers that they refer to
+	assert.Equal(t, []ks.FileLocation{
+		{Name: "testdata/linenumbers-containers.yaml", Line: 17, Column: 7},
+		{Name: "testdata/linenumbers-containers.yaml", Line: 22, Column: 7},
+	}, locations)
+}
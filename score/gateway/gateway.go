@@ -0,0 +1,136 @@
+package gateway
+
+import (
+	"fmt"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks, services ks.Services) {
+	allChecks.RegisterGatewayCheck("Gateway Listener TLS", `Makes sure that the Gateway has a listener with TLS, and that all HTTPS and TLS listeners have a certificate`, gatewayListenerTLS)
+	allChecks.RegisterOptionalGatewayCheck("Gateway Listener Hostname", `Makes sure that all listeners of the Gateway only accept a specific hostname`, gatewayListenerHostname)
+	allChecks.RegisterHTTPRouteCheck("HTTPRoute has parentRefs", `Makes sure that the HTTPRoute is attached to a Gateway`, httpRouteHasParentRefs)
+	allChecks.RegisterHTTPRouteCheck("HTTPRoute targets Service", `Makes sure that all backendRefs of the HTTPRoute targets a Service`, httpRouteTargetsService(services.Services()))
+	allChecks.MarkCrossObject("httproute-targets-service")
+	allChecks.Document("gateway-listener-tls", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Add a listener with the protocol HTTPS or TLS, and reference a certificate in tls.certificateRefs of all HTTPS and TLS listeners",
+	})
+	allChecks.Document("gateway-listener-hostname", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set hostname on all listeners of the Gateway",
+	})
+	allChecks.Document("httproute-has-parentrefs", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Add a parentRef to the Gateway that the HTTPRoute should be attached to",
+	})
+	allChecks.Document("httproute-targets-service", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set the name and port of all backendRefs to a Service that exists in the same namespace",
+	})
+}
+
+// gatewayListenerTLS checks that HTTPS and TLS listeners have a certificate, and that at least one listener uses TLS
+func gatewayListenerTLS(gateway ks.Gateway) (score scorecard.TestScore) {
+	hasTLSListener := false
+	hasMissingCertificate := false
+
+	for _, listener := range gateway.Listeners() {
+		if listener.Protocol != "HTTPS" && listener.Protocol != "TLS" {
+			continue
+		}
+		hasTLSListener = true
+
+		if listener.TLSMode == "" {
+			hasMissingCertificate = true
+			score.AddComment(listener.Name, "The listener does not have a TLS configuration", fmt.Sprintf("Listeners with the %s protocol must have a TLS configuration", listener.Protocol))
+		} else if listener.TLSMode == "Terminate" && listener.CertificateRefs == 0 {
+			hasMissingCertificate = true
+			score.AddComment(listener.Name, "The listener does not have a certificate", "Listeners that terminate TLS must set tls.certificateRefs")
+		}
+	}
+
+	if hasMissingCertificate {
+		score.Grade = scorecard.GradeCritical
+	} else if !hasTLSListener {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "The Gateway does not have a TLS listener", "Traffic to the Gateway is not encrypted. Add a listener with the HTTPS or TLS protocol")
+	} else {
+		score.Grade = scorecard.GradeAllOK
+	}
+
+	return
+}
+
+// gatewayListenerHostname checks that all HTTP, HTTPS and TLS listeners have a hostname
+func gatewayListenerHostname(gateway ks.Gateway) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for _, listener := range gateway.Listeners() {
+		if listener.Protocol != "HTTP" && listener.Protocol != "HTTPS" && listener.Protocol != "TLS" {
+			continue
+		}
+		if listener.Hostname == "" {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(listener.Name, "The listener accepts all hostnames", "Routes attached to the listener can serve traffic for any hostname. Set the hostname of the listener")
+		}
+	}
+
+	return
+}
+
+func httpRouteHasParentRefs(route ks.HTTPRoute) (score scorecard.TestScore) {
+	if len(route.ParentRefs()) == 0 {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", "The HTTPRoute does not have any parentRefs", "The HTTPRoute is not attached to a Gateway, and does not receive any traffic. Set spec.parentRefs")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+// httpRouteTargetsService checks that all Service backends of the HTTPRoute exist, and have the referenced port
+func httpRouteTargetsService(allServices []ks.Service) func(ks.HTTPRoute) scorecard.TestScore {
+	return func(route ks.HTTPRoute) (score scorecard.TestScore) {
+		score.Grade = scorecard.GradeAllOK
+
+		for _, ref := range route.BackendRefs() {
+			// Other kinds of backends can not be validated
+			if ref.Group != "" || ref.Kind != "Service" {
+				continue
+			}
+
+			if !backendHasService(ref, allServices) {
+				score.Grade = scorecard.GradeCritical
+				if ref.Port != nil {
+					score.AddComment(ref.Name, "No service match was found", fmt.Sprintf("No service with name %s and port number %d was found in the namespace %s", ref.Name, *ref.Port, ref.Namespace))
+				} else {
+					score.AddComment(ref.Name, "No service match was found", fmt.Sprintf("No service with name %s was found in the namespace %s", ref.Name, ref.Namespace))
+				}
+			}
+		}
+
+		return
+	}
+}
+
+func backendHasService(ref ks.GatewayObjectReference, allServices []ks.Service) bool {
+	for _, srv := range allServices {
+		service := srv.Service()
+		if service.Namespace != ref.Namespace || service.Name != ref.Name {
+			continue
+		}
+		if ref.Port == nil {
+			return true
+		}
+		for _, port := range service.Spec.Ports {
+			if port.Port == *ref.Port {
+				return true
+			}
+		}
+	}
+	return false
+}
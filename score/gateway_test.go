@@ -0,0 +1,60 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestGatewayListenerTLS(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "gateway-httproute.yaml", "Gateway Listener TLS", scorecard.GradeAllOK)
+}
+
+func TestGatewayListenerTLSMissingCertificate(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "gateway-httproute-invalid.yaml", "Gateway Listener TLS", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "https", comments[0].Path)
+	assert.Equal(t, "The listener does not have a certificate", comments[0].Summary)
+}
+
+func TestGatewayListenerHostname(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("gateway-httproute.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"gateway-listener-hostname": {}},
+	}, "Gateway Listener Hostname", scorecard.GradeAllOK)
+}
+
+func TestGatewayListenerHostnameMissing(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("gateway-httproute-invalid.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"gateway-listener-hostname": {}},
+	}, "Gateway Listener Hostname", scorecard.GradeWarning)
+	assert.Len(t, comments, 2)
+}
+
+func TestHTTPRouteHasParentRefs(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "gateway-httproute.yaml", "HTTPRoute has parentRefs", scorecard.GradeAllOK)
+	testExpectedScore(t, "gateway-httproute-invalid.yaml", "HTTPRoute has parentRefs", scorecard.GradeCritical)
+}
+
+func TestHTTPRouteTargetsService(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "gateway-httproute.yaml", "HTTPRoute targets Service", scorecard.GradeAllOK)
+}
+
+func TestHTTPRouteTargetsServiceNoMatch(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "gateway-httproute-invalid.yaml", "HTTPRoute targets Service", scorecard.GradeCritical)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "app-service", comments[0].Path)
+	assert.Equal(t, "other-service", comments[1].Path)
+}
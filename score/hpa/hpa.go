@@ -1,13 +1,35 @@
 package hpa
 
 import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/score/checks"
 	"github.com/zegl/kube-score/scorecard"
 )
 
-func Register(allChecks *checks.Checks, allTargetableObjs []domain.BothMeta) {
+func Register(allChecks *checks.Checks, allTargetableObjs []domain.BothMeta, allPodSpeccers []domain.PodSpecer) {
 	allChecks.RegisterHorizontalPodAutoscalerCheck("HorizontalPodAutoscaler has target", `Makes sure that the HPA targets a valid object`, hpaHasTarget(allTargetableObjs))
+	allChecks.RegisterHorizontalPodAutoscalerCheck("HorizontalPodAutoscaler Replicas", `Makes sure that the minReplicas of the HPA is lower than the maxReplicas`, hpaReplicas)
+	allChecks.RegisterHorizontalPodAutoscalerCheck("HorizontalPodAutoscaler Target Resource Requests", `Makes sure that the containers of the HPA target request the resources that the HPA scales on`, hpaTargetResourceRequests(allPodSpeccers))
+	allChecks.MarkCrossObject("horizontalpodautoscaler-has-target", "horizontalpodautoscaler-target-resource-requests")
+	allChecks.Document("horizontalpodautoscaler-has-target", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set scaleTargetRef to the kind, name and apiVersion of an existing Deployment or StatefulSet",
+		URL:         "https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale/",
+	})
+	allChecks.Document("horizontalpodautoscaler-replicas", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set minReplicas to a lower value than maxReplicas",
+		URL:         "https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale/",
+	})
+	allChecks.Document("horizontalpodautoscaler-target-resource-requests", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set requests for the resources that the HorizontalPodAutoscaler scales on, on all containers of the target",
+		URL:         "https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale/#how-does-a-horizontalpodautoscaler-work",
+	})
 }
 
 func hpaHasTarget(allTargetableObjs []domain.BothMeta) func(hpa domain.HpaTargeter) scorecard.TestScore {
@@ -33,3 +55,78 @@ func hpaHasTarget(allTargetableObjs []domain.BothMeta) func(hpa domain.HpaTarget
 		return
 	}
 }
+
+func hpaReplicas(hpa domain.HpaTargeter) (score scorecard.TestScore) {
+	// minReplicas defaults to 1
+	minReplicas := int32(1)
+	if hpa.MinReplicas() != nil {
+		minReplicas = *hpa.MinReplicas()
+	}
+	maxReplicas := hpa.MaxReplicas()
+
+	if minReplicas > maxReplicas {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", fmt.Sprintf("minReplicas (%d) is larger than maxReplicas (%d)", minReplicas, maxReplicas),
+			"Set minReplicas to a lower value than maxReplicas")
+		return
+	}
+
+	if minReplicas == maxReplicas {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", fmt.Sprintf("minReplicas and maxReplicas are both set to %d", minReplicas),
+			"The HPA can not scale the target. Set minReplicas to a lower value than maxReplicas, or remove the HPA and set the replicas of the target")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func hpaTargetResourceRequests(allPodSpeccers []domain.PodSpecer) func(hpa domain.HpaTargeter) scorecard.TestScore {
+	return func(hpa domain.HpaTargeter) (score scorecard.TestScore) {
+		targetRef := hpa.HpaTarget()
+
+		var target domain.PodSpecer
+		for _, p := range allPodSpeccers {
+			if p.GetTypeMeta().APIVersion == targetRef.APIVersion &&
+				p.GetTypeMeta().Kind == targetRef.Kind &&
+				p.GetObjectMeta().Name == targetRef.Name &&
+				p.GetObjectMeta().Namespace == hpa.GetObjectMeta().Namespace {
+				target = p
+				break
+			}
+		}
+
+		if target == nil {
+			score.Skipped = true
+			score.AddComment("", "Skipped because the HPA target could not be found", "")
+			return
+		}
+
+		score.Grade = scorecard.GradeAllOK
+
+		containers := target.GetPodTemplateSpec().Spec.Containers
+		for _, metric := range hpa.UtilizationMetrics() {
+			for _, container := range containers {
+				if metric.Container != "" && metric.Container != container.Name {
+					continue
+				}
+				if hasRequest(container, metric.Resource) {
+					continue
+				}
+
+				score.Grade = scorecard.GradeCritical
+				score.AddComment(container.Name,
+					fmt.Sprintf("The HPA scales on %s utilization, but the container does not request %s", metric.Resource, metric.Resource),
+					fmt.Sprintf("The utilization is calculated relative to the requested resources, and the HPA can not scale the target without it. Set resources.requests.%s", metric.Resource))
+			}
+		}
+
+		return
+	}
+}
+
+func hasRequest(container corev1.Container, resource corev1.ResourceName) bool {
+	q, ok := container.Resources.Requests[resource]
+	return ok && !q.IsZero()
+}
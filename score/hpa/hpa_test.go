@@ -180,6 +180,18 @@ func (d hpav1) HpaTarget() v1.CrossVersionObjectReference {
 	return d.Spec.ScaleTargetRef
 }
 
+func (d hpav1) MinReplicas() *int32 {
+	return d.Spec.MinReplicas
+}
+
+func (d hpav1) MaxReplicas() int32 {
+	return d.Spec.MaxReplicas
+}
+
+func (d hpav1) UtilizationMetrics() []domain.HpaUtilizationMetric {
+	return nil
+}
+
 func (d hpav1) FileLocation() domain.FileLocation {
 	return domain.FileLocation{}
 }
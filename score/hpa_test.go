@@ -3,6 +3,8 @@ package score
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/zegl/kube-score/scorecard"
 )
 
@@ -15,3 +17,48 @@ func TestHorizontalPodAutoscalerHasNoTarget(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(t, "hpa-has-no-target.yaml", "HorizontalPodAutoscaler has target", scorecard.GradeCritical)
 }
+
+func TestHorizontalPodAutoscalerReplicas(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "hpa-targets-deployment.yaml", "HorizontalPodAutoscaler Replicas", scorecard.GradeAllOK)
+}
+
+func TestHorizontalPodAutoscalerReplicasEqual(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "hpa-statefulset-min-max-replicas.yaml", "HorizontalPodAutoscaler Replicas", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "minReplicas and maxReplicas are both set to 5", comments[0].Summary)
+}
+
+func TestHorizontalPodAutoscalerTargetResourceRequestsV1(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "hpa-targets-deployment.yaml", "HorizontalPodAutoscaler Target Resource Requests", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "foo", comments[0].Path)
+	assert.Equal(t, "The HPA scales on cpu utilization, but the container does not request cpu", comments[0].Summary)
+}
+
+func TestHorizontalPodAutoscalerTargetResourceRequestsV2beta2(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "hpa-v2beta2-resource-requests.yaml", "HorizontalPodAutoscaler Target Resource Requests", scorecard.GradeCritical)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "sidecar", comments[0].Path)
+	assert.Equal(t, "The HPA scales on cpu utilization, but the container does not request cpu", comments[0].Summary)
+	assert.Equal(t, "app", comments[1].Path)
+	assert.Equal(t, "The HPA scales on memory utilization, but the container does not request memory", comments[1].Summary)
+}
+
+func TestHorizontalPodAutoscalerTargetResourceRequestsStatefulSet(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "hpa-statefulset-min-max-replicas.yaml", "HorizontalPodAutoscaler Target Resource Requests", scorecard.GradeAllOK)
+}
+
+func TestHorizontalPodAutoscalerTargetResourceRequestsNoTarget(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "hpa-has-no-target.yaml", "HorizontalPodAutoscaler Target Resource Requests", 0)
+}
+
+func TestStatefulSetTargetedByHPAHasReplicas(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "hpa-statefulset-min-max-replicas.yaml", "StatefulSet targeted by HPA does not have replicas configured", scorecard.GradeCritical)
+}
@@ -2,14 +2,43 @@ package ingress
 
 import (
 	"fmt"
+	"strings"
 
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/score/checks"
 	"github.com/zegl/kube-score/scorecard"
 )
 
-func Register(allChecks *checks.Checks, services ks.Services) {
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+func Register(allChecks *checks.Checks, services ks.Services, kubernetesVersion config.Semver) {
 	allChecks.RegisterIngressCheck("Ingress targets Service", `Makes sure that the Ingress targets a Service`, ingressTargetsService(services.Services()))
+	allChecks.RegisterIngressCheck("Ingress TLS", `Makes sure that all hosts of the Ingress are covered by the TLS configuration`, ingressTLS)
+	allChecks.RegisterIngressCheck("Ingress Class", `Makes sure that the Ingress sets ingressClassName, or the kubernetes.io/ingress.class annotation on Kubernetes versions older than v1.18`, ingressClass(kubernetesVersion))
+	allChecks.RegisterIngressCheck("Ingress Path Type", `Makes sure that all paths of the Ingress have an explicit pathType of Exact or Prefix`, ingressPathType)
+	allChecks.MarkCrossObject("ingress-targets-service")
+	allChecks.Document("ingress-targets-service", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set the backend service of all paths to the name and port of a Service that exists in the same namespace",
+	})
+	allChecks.Document("ingress-tls", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Add a tls configuration with a certificate Secret, that covers all hosts of the rules of the Ingress",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/ingress/#tls",
+	})
+	allChecks.Document("ingress-class", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set spec.ingressClassName to the IngressClass of the ingress controller that should handle the Ingress",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/ingress/#ingress-class",
+	})
+	allChecks.Document("ingress-path-type", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set pathType to Exact or Prefix on all paths of the Ingress",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/ingress/#path-types",
+	})
 }
 
 func ingressTargetsService(allServices []ks.Service) func(ks.Ingress) scorecard.TestScore {
@@ -74,3 +103,101 @@ func ingressTargetsServiceCommon(ingress ks.Ingress, allServices []ks.Service) (
 
 	return
 }
+
+// ingressTLS checks that all hosts in the rules of the Ingress are listed in the TLS configuration
+func ingressTLS(ingress ks.Ingress) (score scorecard.TestScore) {
+	tls := ingress.TLS()
+
+	if len(tls) == 0 {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "The Ingress does not have TLS configured", "Traffic to the Ingress is not encrypted. Add a TLS configuration with a certificate for the hosts of the Ingress")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+
+	for _, rule := range ingress.Rules() {
+		// Rules without a host are served with the certificate of the TLS configuration
+		if rule.Host == "" {
+			continue
+		}
+		if !tlsCoversHost(tls, rule.Host) {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(rule.Host, "The host is not covered by TLS", fmt.Sprintf("Add %s to the hosts of a TLS configuration of the Ingress", rule.Host))
+		}
+	}
+
+	return
+}
+
+// tlsCoversHost returns true if the host is listed in the TLS configuration, either explicitly or with a wildcard
+func tlsCoversHost(tls []networkingv1.IngressTLS, host string) bool {
+	for _, t := range tls {
+		for _, h := range t.Hosts {
+			if h == host {
+				return true
+			}
+			// A wildcard matches exactly one DNS label
+			if strings.HasPrefix(h, "*.") {
+				if i := strings.Index(host, "."); i > 0 && host[i:] == h[1:] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ingressClass checks that the Ingress selects an IngressClass. The ingressClassName field was added in
+// Kubernetes v1.18, on older versions the kubernetes.io/ingress.class annotation must be used instead.
+func ingressClass(kubernetesVersion config.Semver) func(ks.Ingress) scorecard.TestScore {
+	return func(ingress ks.Ingress) (score scorecard.TestScore) {
+		className := ingress.IngressClassName()
+		_, hasAnnotation := ingress.GetObjectMeta().Annotations[ingressClassAnnotation]
+
+		if kubernetesVersion.LessThan(config.Semver{Major: 1, Minor: 18}) {
+			if hasAnnotation {
+				score.Grade = scorecard.GradeAllOK
+			} else {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment("", "The Ingress does not select an IngressClass", "The Ingress will be handled by the default ingress controller, or by no controller at all. Set the "+ingressClassAnnotation+" annotation")
+			}
+			return
+		}
+
+		if className != nil && *className != "" {
+			score.Grade = scorecard.GradeAllOK
+		} else if hasAnnotation {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("", "The Ingress uses the deprecated "+ingressClassAnnotation+" annotation", "Set spec.ingressClassName instead")
+		} else {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("", "The Ingress does not select an IngressClass", "The Ingress will be handled by the default IngressClass, or by no controller at all if the cluster has no default. Set spec.ingressClassName")
+		}
+
+		return
+	}
+}
+
+// ingressPathType checks that all paths of the Ingress have a pathType with well defined matching rules
+func ingressPathType(ingress ks.Ingress) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for _, rule := range ingress.Rules() {
+		if rule.IngressRuleValue.HTTP == nil {
+			continue
+		}
+
+		for _, path := range rule.IngressRuleValue.HTTP.Paths {
+			if path.PathType == nil {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(path.Path, "The path does not have a pathType", "Paths without a pathType are matched in a way that depends on the ingress controller. Set pathType to Exact or Prefix")
+			} else if *path.PathType == networkingv1.PathTypeImplementationSpecific {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(path.Path, "The path has the pathType ImplementationSpecific", "Paths with the pathType ImplementationSpecific are matched in a way that depends on the ingress controller. Set pathType to Exact or Prefix")
+			}
+		}
+	}
+
+	return
+}
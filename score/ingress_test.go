@@ -3,6 +3,10 @@ package score
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/scorecard"
 )
 
@@ -50,3 +54,63 @@ func TestIngressNoPanicIssue388(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(t, "ingress_issue388.yaml", "Ingress targets Service", scorecard.GradeAllOK)
 }
+
+func TestIngressTLS(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "ingress-tls.yaml", "Ingress TLS", scorecard.GradeAllOK)
+}
+
+func TestIngressTLSMissing(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "ingress-networkingv1-targets-service.yaml", "Ingress TLS", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The Ingress does not have TLS configured", comments[0].Summary)
+}
+
+func TestIngressTLSMissingHost(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "ingress-tls-missing-host.yaml", "Ingress TLS", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "foo.app.example.com", comments[0].Path)
+}
+
+func TestIngressClass(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "ingress-tls.yaml", "Ingress Class", scorecard.GradeAllOK)
+}
+
+func TestIngressClassMissing(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "ingress-networkingv1-targets-service.yaml", "Ingress Class", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The Ingress does not select an IngressClass", comments[0].Summary)
+}
+
+func TestIngressClassAnnotation(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "ingress-class-annotation.yaml", "Ingress Class", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The Ingress uses the deprecated kubernetes.io/ingress.class annotation", comments[0].Summary)
+}
+
+func TestIngressClassAnnotationOldKubernetes(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("ingress-class-annotation.yaml")},
+		KubernetesVersion: config.Semver{Major: 1, Minor: 17},
+	}, "Ingress Class", scorecard.GradeAllOK)
+}
+
+func TestIngressPathType(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "ingress-tls.yaml", "Ingress Path Type", scorecard.GradeAllOK)
+	testExpectedScore(t, "ingress-tls-missing-host.yaml", "Ingress Path Type", scorecard.GradeAllOK)
+}
+
+func TestIngressPathTypeMissing(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "ingress-class-annotation.yaml", "Ingress Path Type", scorecard.GradeWarning)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "The path does not have a pathType", comments[0].Summary)
+	assert.Equal(t, "The path has the pathType ImplementationSpecific", comments[1].Summary)
+}
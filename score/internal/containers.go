@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AllContainers returns the init containers, the containers and the ephemeral containers of the pod. Ephemeral
+// containers are converted to containers, the fields that are not allowed on ephemeral containers, such as resources
+// and probes, are always empty.
+func AllContainers(spec corev1.PodSpec) []corev1.Container {
+	var containers []corev1.Container
+	containers = append(containers, spec.InitContainers...)
+	containers = append(containers, spec.Containers...)
+	for _, c := range spec.EphemeralContainers {
+		containers = append(containers, corev1.Container(c.EphemeralContainerCommon))
+	}
+	return containers
+}
+
+// UnstructuredPodSpec returns the pod spec of the object, as decoded from JSON. The second return value is false if
+// the object has no pod spec. It's used to read fields that are not a part of the supported API version.
+func UnstructuredPodSpec(typeMeta metav1.TypeMeta, object map[string]interface{}) (map[string]interface{}, bool) {
+	templatePath, ok := podTemplatePath(typeMeta)
+	if !ok {
+		return nil, false
+	}
+
+	value := object
+	for _, key := range strings.Split(strings.TrimPrefix(templatePath+"/spec", "/"), "/") {
+		next, ok := value[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value = next
+	}
+	return value, true
+}
@@ -14,6 +14,11 @@ func (l MapLables) Get(key string) string {
 	return l[key]
 }
 
+func (l MapLables) Lookup(key string) (string, bool) {
+	value, ok := l[key]
+	return value, ok
+}
+
 func LabelSelectorMatchesLabels(selectorLabels map[string]string, labels map[string]string) bool {
 	labelSelector := &metav1.LabelSelector{
 		MatchLabels: selectorLabels,
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// podTemplatePath returns the JSON pointer to the pod template in an object of the kind, where the pod itself is
+// the template of a Pod. The second return value is false if the kind is unknown.
+func podTemplatePath(typeMeta metav1.TypeMeta) (string, bool) {
+	switch typeMeta.Kind {
+	case "Pod":
+		return "", true
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		return "/spec/template", true
+	case "CronJob":
+		return "/spec/jobTemplate/spec/template", true
+	default:
+		return "", false
+	}
+}
+
+// nestedPatch wraps the value in maps with the keys of the JSON pointer
+func nestedPatch(pointer string, value map[string]interface{}) map[string]interface{} {
+	if pointer == "" {
+		return value
+	}
+	keys := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i := len(keys) - 1; i >= 0; i-- {
+		value = map[string]interface{}{keys[i]: value}
+	}
+	return value
+}
+
+// PodSpecRemediation returns a remediation that sets the field of the pod spec to the value
+func PodSpecRemediation(typeMeta metav1.TypeMeta, field string, value interface{}) *scorecard.Remediation {
+	templatePath, ok := podTemplatePath(typeMeta)
+	if !ok {
+		return nil
+	}
+
+	return &scorecard.Remediation{
+		JSONPatch: []scorecard.JSONPatchOperation{
+			{Op: "add", Path: templatePath + "/spec/" + field, Value: value},
+		},
+		StrategicMergePatch: nestedPatch(templatePath+"/spec", map[string]interface{}{field: value}),
+	}
+}
+
+// ContainerSecurityContextRemediation returns a remediation that sets the field of the security context of the
+// container with the name to the value
+func ContainerSecurityContextRemediation(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta, containerName, field string, value interface{}) *scorecard.Remediation {
+	templatePath, ok := podTemplatePath(typeMeta)
+	if !ok {
+		return nil
+	}
+
+	containersField, index, container, ok := findContainer(podTemplate.Spec, containerName)
+	if !ok {
+		return nil
+	}
+
+	containerPath := templatePath + "/spec/" + containersField + "/" + strconv.Itoa(index)
+
+	// The security context must be added as a whole if the container doesn't have one
+	var op scorecard.JSONPatchOperation
+	if container.SecurityContext == nil {
+		op = scorecard.JSONPatchOperation{Op: "add", Path: containerPath + "/securityContext", Value: map[string]interface{}{field: value}}
+	} else {
+		op = scorecard.JSONPatchOperation{Op: "add", Path: containerPath + "/securityContext/" + field, Value: value}
+	}
+
+	return &scorecard.Remediation{
+		JSONPatch: []scorecard.JSONPatchOperation{op},
+		StrategicMergePatch: nestedPatch(templatePath+"/spec", map[string]interface{}{
+			containersField: []interface{}{
+				map[string]interface{}{
+					"name":            containerName,
+					"securityContext": map[string]interface{}{field: value},
+				},
+			},
+		}),
+	}
+}
+
+// findContainer returns the name of the list that the container is in, and its index in the list
+func findContainer(spec corev1.PodSpec, name string) (string, int, corev1.Container, bool) {
+	for i, c := range spec.InitContainers {
+		if c.Name == name {
+			return "initContainers", i, c, true
+		}
+	}
+	for i, c := range spec.Containers {
+		if c.Name == name {
+			return "containers", i, c, true
+		}
+	}
+	return "", 0, corev1.Container{}, false
+}
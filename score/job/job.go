@@ -0,0 +1,87 @@
+package job
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks) {
+	allChecks.RegisterJobCheck("Job Backoff Limit", `Makes sure that Jobs explicitly set a backoffLimit`, jobBackoffLimit)
+	allChecks.RegisterJobCheck("Job Active Deadline", `Makes sure that Jobs set activeDeadlineSeconds, to bound the runtime of the Job`, jobActiveDeadline)
+	allChecks.RegisterJobCheck("Job TTL After Finished", `Makes sure that Jobs set ttlSecondsAfterFinished, so that finished Jobs are cleaned up`, jobTTLAfterFinished)
+	allChecks.RegisterJobCheck("Job Restart Policy", `Makes sure that the restartPolicy of Jobs is set to OnFailure or Never`, jobRestartPolicy)
+	allChecks.Document("job-backoff-limit", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set backoffLimit to the number of retries before the Job is considered failed",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/job/#pod-backoff-failure-policy",
+	})
+	allChecks.Document("job-active-deadline", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set activeDeadlineSeconds to the longest time that the Job is allowed to run",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/job/#job-termination-and-cleanup",
+	})
+	allChecks.Document("job-ttl-after-finished", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set ttlSecondsAfterFinished to the number of seconds that a finished Job is kept",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/controllers/ttlafterfinished/",
+	})
+	allChecks.Document("job-restart-policy", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set the restartPolicy of the pod template to OnFailure or Never",
+	})
+}
+
+func jobBackoffLimit(job batchv1.Job) (score scorecard.TestScore) {
+	if job.Spec.BackoffLimit == nil {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "The Job does not set a backoffLimit",
+			"A failing Job is retried 6 times by default, with an exponential back-off delay. Set backoffLimit to the number of retries that makes sense for the Job")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func jobActiveDeadline(job batchv1.Job) (score scorecard.TestScore) {
+	if job.Spec.ActiveDeadlineSeconds == nil {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "The Job does not set activeDeadlineSeconds",
+			"A Job that hangs runs forever. Set activeDeadlineSeconds to the longest time that the Job is expected to run")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func jobTTLAfterFinished(job batchv1.Job) (score scorecard.TestScore) {
+	if job.Spec.TTLSecondsAfterFinished == nil {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "The Job does not set ttlSecondsAfterFinished",
+			"Finished Jobs and their pods are kept in the cluster until they are deleted. Set ttlSecondsAfterFinished to clean them up automatically")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func jobRestartPolicy(job batchv1.Job) (score scorecard.TestScore) {
+	switch job.Spec.Template.Spec.RestartPolicy {
+	case corev1.RestartPolicyOnFailure, corev1.RestartPolicyNever:
+		score.Grade = scorecard.GradeAllOK
+	case "":
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", "The Job does not set a restartPolicy",
+			"The restartPolicy of pods defaults to Always, which is not allowed for Jobs. Set restartPolicy to OnFailure or Never")
+	default:
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", "The restartPolicy of the Job is set to "+string(job.Spec.Template.Spec.RestartPolicy),
+			"Only OnFailure and Never are allowed for Jobs. Set restartPolicy to OnFailure or Never")
+	}
+	return
+}
@@ -0,0 +1,40 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestJobBackoffLimit(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "job-batchv1.yaml", "Job Backoff Limit", scorecard.GradeAllOK)
+	testExpectedScore(t, "job-batchv1-restart-policy-always.yaml", "Job Backoff Limit", scorecard.GradeWarning)
+}
+
+func TestJobActiveDeadline(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "job-batchv1-robust.yaml", "Job Active Deadline", scorecard.GradeAllOK)
+	testExpectedScore(t, "job-batchv1.yaml", "Job Active Deadline", scorecard.GradeWarning)
+}
+
+func TestJobTTLAfterFinished(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "job-batchv1-robust.yaml", "Job TTL After Finished", scorecard.GradeAllOK)
+	testExpectedScore(t, "job-batchv1.yaml", "Job TTL After Finished", scorecard.GradeWarning)
+}
+
+func TestJobRestartPolicy(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "job-batchv1.yaml", "Job Restart Policy", scorecard.GradeAllOK)
+	testExpectedScore(t, "job-batchv1-robust.yaml", "Job Restart Policy", scorecard.GradeAllOK)
+}
+
+func TestJobRestartPolicyAlways(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "job-batchv1-restart-policy-always.yaml", "Job Restart Policy", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The restartPolicy of the Job is set to Always", comments[0].Summary)
+}
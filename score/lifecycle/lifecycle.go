@@ -0,0 +1,115 @@
+package lifecycle
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks, services ks.Services) {
+	allChecks.RegisterPodCheck("Pod Termination Grace Period", `Makes sure that terminationGracePeriodSeconds is not set to 0, or to an excessively large value`, podTerminationGracePeriod)
+	allChecks.RegisterOptionalPodCheck("Container PreStop Hook", `Makes sure that containers that are exposed through a Service have a preStop hook, to finish in-flight requests during shutdown`, containerPreStopHook(services.Services()))
+	allChecks.Document("pod-termination-grace-period", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set terminationGracePeriodSeconds to the time that the containers need to shut down, or remove it to use the default of 30 seconds",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle/#pod-termination",
+	})
+	allChecks.Document("container-prestop-hook", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Add a preStop hook, such as a short sleep, that delays the shutdown until the pod has been removed from the endpoints of the Service",
+		URL:         "https://kubernetes.io/docs/concepts/containers/container-lifecycle-hooks/",
+	})
+}
+
+// maxTerminationGracePeriodSeconds is the longest terminationGracePeriodSeconds that is not considered to be excessive
+const maxTerminationGracePeriodSeconds = 3600
+
+func podTerminationGracePeriod(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	period := podTemplate.Spec.TerminationGracePeriodSeconds
+
+	if period != nil && *period == 0 {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", "terminationGracePeriodSeconds is set to 0",
+			"The containers are killed immediately, without a chance to finish in-flight requests or to shut down gracefully. Remove terminationGracePeriodSeconds to use the default of 30 seconds")
+		return
+	}
+
+	if period != nil && *period > maxTerminationGracePeriodSeconds {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", fmt.Sprintf("terminationGracePeriodSeconds is set to %d", *period),
+			fmt.Sprintf("A pod that does not exit can block rollouts and node drains for the entire period. Set terminationGracePeriodSeconds to %d or less", maxTerminationGracePeriodSeconds))
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func containerPreStopHook(allServices []ks.Service) func(corev1.PodTemplateSpec, metav1.TypeMeta) scorecard.TestScore {
+	return func(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+		var services []corev1.Service
+		for _, s := range allServices {
+			service := s.Service()
+			if service.Namespace == podTemplate.Namespace && internal.LabelSelectorMatchesLabels(service.Spec.Selector, podTemplate.GetObjectMeta().GetLabels()) {
+				services = append(services, service)
+			}
+		}
+
+		if len(services) == 0 {
+			score.Skipped = true
+			score.AddComment("", "Skipped because the pod is not targeted by a service", "")
+			return
+		}
+
+		score.Grade = scorecard.GradeAllOK
+
+		for _, container := range podTemplate.Spec.Containers {
+			// Container ports are informational, a pod with a single container is always considered to be exposed
+			if len(podTemplate.Spec.Containers) > 1 && !isExposed(container, services) {
+				continue
+			}
+			if container.Lifecycle != nil && container.Lifecycle.PreStop != nil {
+				continue
+			}
+
+			score.Grade = scorecard.GradeWarning
+			score.AddCommentWithURL(container.Name, "The container is exposed through a Service, but has no preStop hook",
+				"The pod can receive new connections for a short while after it has been asked to stop, until it's removed from all endpoints. Add a preStop hook, for example a short sleep, to avoid dropped connections during rollouts",
+				"https://kubernetes.io/docs/concepts/containers/container-lifecycle-hooks/",
+			)
+		}
+
+		return
+	}
+}
+
+// isExposed returns true if any of the services has a targetPort that matches a port of the container
+func isExposed(container corev1.Container, services []corev1.Service) bool {
+	for _, service := range services {
+		for _, servicePort := range service.Spec.Ports {
+			target := servicePort.TargetPort
+			for _, containerPort := range container.Ports {
+				if target.Type == intstr.String && target.StrVal == containerPort.Name {
+					return true
+				}
+
+				// The targetPort defaults to the same value as the port
+				port := target.IntVal
+				if port == 0 {
+					port = servicePort.Port
+				}
+				if target.Type == intstr.Int && port == containerPort.ContainerPort {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
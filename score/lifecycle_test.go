@@ -0,0 +1,48 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestPodTerminationGracePeriodZero(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-termination-grace-period-zero.yaml", "Pod Termination Grace Period", scorecard.GradeCritical)
+}
+
+func TestPodTerminationGracePeriodLarge(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-termination-grace-period-large.yaml", "Pod Termination Grace Period", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "terminationGracePeriodSeconds is set to 86400", comments[0].Summary)
+}
+
+func TestPodTerminationGracePeriodDefault(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-probes-startup.yaml", "Pod Termination Grace Period", scorecard.GradeAllOK)
+}
+
+func TestContainerPreStopHook(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("deployment-prestop-hook.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-prestop-hook": {}},
+	}, "Container PreStop Hook", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "without-hook", comments[0].Path)
+}
+
+func TestContainerPreStopHookNotTargeted(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-termination-grace-period-zero.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-prestop-hook": {}},
+	}, "Container PreStop Hook", 0)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Skipped because the pod is not targeted by a service", comments[0].Summary)
+}
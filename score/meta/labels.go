@@ -3,13 +3,57 @@ package meta
 import (
 	"regexp"
 
+	"github.com/zegl/kube-score/config"
 	"github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/score/checks"
 	"github.com/zegl/kube-score/scorecard"
 )
 
-func Register(allChecks *checks.Checks) {
+// DefaultRequiredLabels are the recommended labels from https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/
+var DefaultRequiredLabels = []string{
+	"app.kubernetes.io/name",
+	"app.kubernetes.io/instance",
+	"app.kubernetes.io/version",
+	"app.kubernetes.io/part-of",
+}
+
+func Register(allChecks *checks.Checks, cnf config.Configuration) {
 	allChecks.RegisterMetaCheck("Label values", "Validates label values", validateLabelValues)
+	allChecks.RegisterMetaCheck("Label Keys", "Validates label keys", validateLabelKeys)
+	allChecks.RegisterMetaCheck("Object Name", "Makes sure that the name of the object is valid, and not too long", objectName)
+	allChecks.RegisterMetaCheck("Annotation Size", "Makes sure that the annotation keys are valid, and that the annotations are not larger than the 256 kB that Kubernetes accepts", annotationSize)
+	allChecks.RegisterOptionalMetaCheck("Object Namespace", "Makes sure that all namespaced objects have a namespace set", objectNamespace)
+	allChecks.RegisterOptionalMetaCheck("Workload Required Labels", "Makes sure that all workloads have the required labels set. The required labels can be configured with --required-label, and defaults to the recommended app.kubernetes.io labels", workloadRequiredLabels(cnf.RequiredLabels))
+	allChecks.Document("label-values", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Change the label values to at most 63 characters, that begin and end with an alphanumeric character, and only contain alphanumerics, dashes, underscores and dots",
+		URL:         "https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#syntax-and-character-set",
+	})
+	allChecks.Document("label-keys", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Change the label keys to a name of at most 63 characters, with an optional DNS subdomain prefix and a slash",
+		URL:         "https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#syntax-and-character-set",
+	})
+	allChecks.Document("object-name", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Change the name to a lowercase DNS subdomain of at most 253 characters, or a DNS label of at most 63 characters for Services and Namespaces",
+		URL:         "https://kubernetes.io/docs/concepts/overview/working-with-objects/names/",
+	})
+	allChecks.Document("annotation-size", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Fix the invalid annotation keys, and move large annotation values to a ConfigMap",
+		URL:         "https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations/#syntax-and-character-set",
+	})
+	allChecks.Document("object-namespace", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set metadata.namespace on all namespaced objects",
+		URL:         "https://kubernetes.io/docs/concepts/overview/working-with-objects/namespaces/",
+	})
+	allChecks.Document("workload-required-labels", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set all required labels on the workload",
+		URL:         "https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/",
+	})
 }
 
 func validateLabelValues(meta domain.BothMeta) (score scorecard.TestScore) {
@@ -23,3 +67,30 @@ func validateLabelValues(meta domain.BothMeta) (score scorecard.TestScore) {
 	}
 	return
 }
+
+// workloadRequiredLabels checks that all Pods and objects with a pod template have all of the required labels.
+// If requiredLabels is empty, the DefaultRequiredLabels are required.
+func workloadRequiredLabels(requiredLabels []string) func(domain.BothMeta) scorecard.TestScore {
+	if len(requiredLabels) == 0 {
+		requiredLabels = DefaultRequiredLabels
+	}
+
+	return func(meta domain.BothMeta) (score scorecard.TestScore) {
+		switch meta.FileLocationer.(type) {
+		case domain.Pod, domain.PodSpecer:
+		default:
+			score.Skipped = true
+			score.AddComment("", "Skipped because the object is not a workload", "")
+			return
+		}
+
+		score.Grade = scorecard.GradeAllOK
+		for _, label := range requiredLabels {
+			if meta.ObjectMeta.Labels[label] == "" {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(label, "The label is not set", "Set the label "+label+" to make it possible to identify and group the workload")
+			}
+		}
+		return
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/zegl/kube-score/domain"
@@ -37,3 +38,50 @@ func TestOKLabel(t *testing.T) {
 	})
 	assert.Equal(t, scorecard.GradeAllOK, s.Grade)
 }
+
+func TestWorkloadRequiredLabelsDefault(t *testing.T) {
+	t.Parallel()
+	s := workloadRequiredLabels(nil)(domain.BothMeta{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"app.kubernetes.io/name":     "foo",
+				"app.kubernetes.io/instance": "foo-prod",
+				"app.kubernetes.io/version":  "1.2.3",
+			},
+		},
+		FileLocationer: pod{},
+	})
+	assert.Equal(t, scorecard.GradeWarning, s.Grade)
+	assert.Len(t, s.Comments, 1)
+	assert.Equal(t, "app.kubernetes.io/part-of", s.Comments[0].Path)
+}
+
+func TestWorkloadRequiredLabelsConfigured(t *testing.T) {
+	t.Parallel()
+	s := workloadRequiredLabels([]string{"team", "cost-center"})(domain.BothMeta{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"team":        "platform",
+				"cost-center": "1234",
+			},
+		},
+		FileLocationer: pod{},
+	})
+	assert.Equal(t, scorecard.GradeAllOK, s.Grade)
+}
+
+func TestWorkloadRequiredLabelsNotWorkload(t *testing.T) {
+	t.Parallel()
+	s := workloadRequiredLabels(nil)(domain.BothMeta{})
+	assert.True(t, s.Skipped)
+}
+
+type pod struct{}
+
+func (pod) Pod() corev1.Pod {
+	return corev1.Pod{}
+}
+
+func (pod) FileLocation() domain.FileLocation {
+	return domain.FileLocation{}
+}
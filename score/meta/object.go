@@ -0,0 +1,147 @@
+package meta
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/api/validation/path"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// clusterScopedKinds are the built-in kinds, and the kinds of common add-ons, that are not namespaced
+var clusterScopedKinds = map[string]struct{}{
+	"APIService":                       {},
+	"CertificateSigningRequest":        {},
+	"ClusterIssuer":                    {},
+	"ClusterPolicy":                    {},
+	"ClusterRole":                      {},
+	"ClusterRoleBinding":               {},
+	"ConstraintTemplate":               {},
+	"CSIDriver":                        {},
+	"CSINode":                          {},
+	"CustomResourceDefinition":         {},
+	"FlowSchema":                       {},
+	"GatewayClass":                     {},
+	"IngressClass":                     {},
+	"MutatingWebhookConfiguration":     {},
+	"Namespace":                        {},
+	"Node":                             {},
+	"PersistentVolume":                 {},
+	"PodSecurityPolicy":                {},
+	"PriorityClass":                    {},
+	"PriorityLevelConfiguration":       {},
+	"RuntimeClass":                     {},
+	"StorageClass":                     {},
+	"ValidatingAdmissionPolicy":        {},
+	"ValidatingAdmissionPolicyBinding": {},
+	"ValidatingWebhookConfiguration":   {},
+	"VolumeAttachment":                 {},
+}
+
+// dnsLabelNameKinds are the kinds that have names that must be DNS labels, instead of DNS subdomains
+var dnsLabelNameKinds = map[string]struct{}{
+	"Namespace": {},
+	"Service":   {},
+}
+
+// objectName checks that the name of the object is accepted by the API server. Kubernetes only requires that the names
+// of all kinds are valid path segments, the built-in kinds with stricter requirements are validated by their rules.
+func objectName(meta domain.BothMeta) (score scorecard.TestScore) {
+	name := meta.ObjectMeta.Name
+	if name == "" {
+		if meta.ObjectMeta.GenerateName != "" {
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("metadata.name", "The object has no name", "Set metadata.name, or metadata.generateName for objects that are created with a generated name")
+		return
+	}
+
+	var errs []string
+	if _, ok := dnsLabelNameKinds[meta.TypeMeta.Kind]; ok {
+		errs = utilvalidation.IsDNS1123Label(name)
+	} else if len(name) > utilvalidation.DNS1123SubdomainMaxLength {
+		errs = []string{utilvalidation.MaxLenError(utilvalidation.DNS1123SubdomainMaxLength)}
+	} else {
+		errs = path.IsValidPathSegmentName(name)
+	}
+
+	if len(errs) > 0 {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("metadata.name", "The name is invalid, and will not be accepted by Kubernetes", strings.Join(errs, ", "))
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+// objectNamespace checks that all namespaced objects have a namespace set, so that they are not created in the
+// namespace of the current context of the user that applies them
+func objectNamespace(meta domain.BothMeta) (score scorecard.TestScore) {
+	if _, ok := clusterScopedKinds[meta.TypeMeta.Kind]; ok {
+		score.Skipped = true
+		score.AddComment("", "Skipped because the kind is not namespaced", "")
+		return
+	}
+
+	if meta.ObjectMeta.Namespace == "" {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("metadata.namespace", "The object has no namespace", "Set metadata.namespace, so that the object is created in the same namespace no matter which namespace the kubectl context is using")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+// validateLabelKeys checks that all label keys are qualified names, with an optional DNS subdomain prefix
+func validateLabelKeys(meta domain.BothMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+	for _, key := range sortedKeys(meta.ObjectMeta.Labels) {
+		if errs := utilvalidation.IsQualifiedName(key); len(errs) > 0 {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(key, "Invalid label key", "The label key is invalid, and will not be accepted by Kubernetes: "+strings.Join(errs, ", "))
+		}
+	}
+	return
+}
+
+// annotationSize checks that the annotation keys are valid, and that the annotations are not larger than what the
+// API server accepts
+func annotationSize(meta domain.BothMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	var size int
+	for _, key := range sortedKeys(meta.ObjectMeta.Annotations) {
+		size += len(key) + len(meta.ObjectMeta.Annotations[key])
+		if errs := utilvalidation.IsQualifiedName(strings.ToLower(key)); len(errs) > 0 {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(key, "Invalid annotation key", "The annotation key is invalid, and will not be accepted by Kubernetes: "+strings.Join(errs, ", "))
+		}
+	}
+
+	if size > validation.TotalAnnotationSizeLimitB {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("metadata.annotations",
+			"The annotations are too large",
+			fmt.Sprintf("The annotations are %d bytes, the API server accepts at most %d bytes. Move the large values to a ConfigMap.", size, validation.TotalAnnotationSizeLimitB),
+		)
+	}
+	return
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,126 @@
+package meta
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestObjectName(t *testing.T) {
+	t.Parallel()
+	s := objectName(domain.BothMeta{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: "system:controller:foo"},
+	})
+	assert.Equal(t, scorecard.GradeAllOK, s.Grade)
+
+	s = objectName(domain.BothMeta{
+		TypeMeta:   metav1.TypeMeta{Kind: "Rollout"},
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "foo-"},
+	})
+	assert.Equal(t, scorecard.GradeAllOK, s.Grade)
+}
+
+func TestObjectNameInvalid(t *testing.T) {
+	t.Parallel()
+	s := objectName(domain.BothMeta{TypeMeta: metav1.TypeMeta{Kind: "Rollout"}})
+	assert.Equal(t, scorecard.GradeCritical, s.Grade)
+	assert.Equal(t, "The object has no name", s.Comments[0].Summary)
+
+	s = objectName(domain.BothMeta{
+		TypeMeta:   metav1.TypeMeta{Kind: "Rollout"},
+		ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 254)},
+	})
+	assert.Equal(t, scorecard.GradeCritical, s.Grade)
+
+	s = objectName(domain.BothMeta{
+		TypeMeta:   metav1.TypeMeta{Kind: "Rollout"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo/bar"},
+	})
+	assert.Equal(t, scorecard.GradeCritical, s.Grade)
+
+	// Service names are DNS labels
+	s = objectName(domain.BothMeta{
+		TypeMeta:   metav1.TypeMeta{Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo.bar"},
+	})
+	assert.Equal(t, scorecard.GradeCritical, s.Grade)
+	assert.Equal(t, "metadata.name", s.Comments[0].Path)
+}
+
+func TestObjectNamespace(t *testing.T) {
+	t.Parallel()
+	s := objectNamespace(domain.BothMeta{
+		TypeMeta:   metav1.TypeMeta{Kind: "Rollout"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+	})
+	assert.Equal(t, scorecard.GradeWarning, s.Grade)
+
+	s = objectNamespace(domain.BothMeta{
+		TypeMeta:   metav1.TypeMeta{Kind: "Rollout"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+	})
+	assert.Equal(t, scorecard.GradeAllOK, s.Grade)
+
+	s = objectNamespace(domain.BothMeta{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+	})
+	assert.True(t, s.Skipped)
+}
+
+func TestLabelKeys(t *testing.T) {
+	t.Parallel()
+	s := validateLabelKeys(domain.BothMeta{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"app.kubernetes.io/name": "foo",
+				"team":                   "bar",
+			},
+		},
+	})
+	assert.Equal(t, scorecard.GradeAllOK, s.Grade)
+
+	s = validateLabelKeys(domain.BothMeta{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"example.com/team/name": "foo",
+				"team":                  "bar",
+			},
+		},
+	})
+	assert.Equal(t, scorecard.GradeCritical, s.Grade)
+	assert.Len(t, s.Comments, 1)
+	assert.Equal(t, "example.com/team/name", s.Comments[0].Path)
+}
+
+func TestAnnotationSize(t *testing.T) {
+	t.Parallel()
+	s := annotationSize(domain.BothMeta{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{"example.com/config": strings.Repeat("a", 1024)},
+		},
+	})
+	assert.Equal(t, scorecard.GradeAllOK, s.Grade)
+
+	s = annotationSize(domain.BothMeta{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{"example.com/config": strings.Repeat("a", 300*1024)},
+		},
+	})
+	assert.Equal(t, scorecard.GradeCritical, s.Grade)
+	assert.Equal(t, "The annotations are too large", s.Comments[0].Summary)
+
+	s = annotationSize(domain.BothMeta{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{"example.com/": "foo"},
+		},
+	})
+	assert.Equal(t, scorecard.GradeCritical, s.Grade)
+	assert.Equal(t, "Invalid annotation key", s.Comments[0].Summary)
+}
@@ -0,0 +1,197 @@
+// Package monitoring checks the ServiceMonitors and PodMonitors of the Prometheus Operator. They are custom resources,
+// so the checks read them from the objects as decoded from JSON.
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks, services ks.Services, pods ks.Pods, podspecers ks.PodSpeccers) {
+	allChecks.RegisterObjectCheck("servicemonitor-targets-service", "ServiceMonitor targets Service", `Makes sure that the ServiceMonitor selects a Service, and that the Services have the ports of all endpoints`, []string{"ServiceMonitor"}, serviceMonitorTargetsService(services.Services()))
+	allChecks.RegisterObjectCheck("podmonitor-targets-pod", "PodMonitor targets Pod", `Makes sure that the PodMonitor selects a pod, and that the containers of the pods have the ports of all endpoints`, []string{"PodMonitor"}, podMonitorTargetsPod(podTemplates(pods.Pods(), podspecers.PodSpeccers())))
+	allChecks.MarkCrossObject("servicemonitor-targets-service", "podmonitor-targets-pod")
+	allChecks.Document("servicemonitor-targets-service", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Make the selector and namespaceSelector match the labels and namespace of a Service, and set the port of all endpoints to the name of a port of the Service",
+		URL:         "https://prometheus-operator.dev/docs/developer/getting-started/#using-servicemonitors",
+	})
+	allChecks.Document("podmonitor-targets-pod", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Make the selector and namespaceSelector match the labels and namespace of the pods, and set the port of all podMetricsEndpoints to the name of a port of the containers",
+		URL:         "https://prometheus-operator.dev/docs/developer/getting-started/#using-podmonitors",
+	})
+}
+
+// monitorSpec is the spec of a ServiceMonitor or a PodMonitor
+type monitorSpec struct {
+	Selector          metav1.LabelSelector `json:"selector"`
+	NamespaceSelector struct {
+		Any        bool     `json:"any"`
+		MatchNames []string `json:"matchNames"`
+	} `json:"namespaceSelector"`
+	Endpoints           []monitorEndpoint `json:"endpoints"`
+	PodMetricsEndpoints []monitorEndpoint `json:"podMetricsEndpoints"`
+}
+
+type monitorEndpoint struct {
+	Port string `json:"port"`
+}
+
+// decodeMonitorSpec returns the spec of the ServiceMonitor or PodMonitor
+func decodeMonitorSpec(object ks.Object) (monitorSpec, error) {
+	var spec monitorSpec
+	b, err := json.Marshal(object.Unstructured()["spec"])
+	if err != nil {
+		return spec, err
+	}
+	err = json.Unmarshal(b, &spec)
+	return spec, err
+}
+
+// matchesNamespace reports whether the namespaceSelector of the monitor selects the namespace. By default, only
+// objects in the namespace of the monitor are selected.
+func (s monitorSpec) matchesNamespace(monitorNamespace, namespace string) bool {
+	if s.NamespaceSelector.Any {
+		return true
+	}
+	if len(s.NamespaceSelector.MatchNames) == 0 {
+		return namespace == monitorNamespace
+	}
+	for _, name := range s.NamespaceSelector.MatchNames {
+		if name == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// targets runs the check of a monitor with the selector of the monitor, and adds a comment if the selector is invalid
+func targets(kind string, fn func(monitorSpec, labels.Selector) scorecard.TestScore) func(ks.Object) scorecard.TestScore {
+	return func(object ks.Object) (score scorecard.TestScore) {
+		spec, err := decodeMonitorSpec(object)
+		if err != nil {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment("spec", fmt.Sprintf("The %s can not be decoded", kind), err.Error())
+			return
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&spec.Selector)
+		if err != nil {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment("selector", fmt.Sprintf("The %s has an invalid selector", kind), err.Error())
+			return
+		}
+		return fn(spec, selector)
+	}
+}
+
+func serviceMonitorTargetsService(allServices []ks.Service) func(ks.Object) scorecard.TestScore {
+	return func(object ks.Object) scorecard.TestScore {
+		namespace := object.GetObjectMeta().Namespace
+		return targets("ServiceMonitor", func(spec monitorSpec, selector labels.Selector) (score scorecard.TestScore) {
+			ports := make(map[string]struct{})
+			var selected []string
+			for _, s := range allServices {
+				service := s.Service()
+				if !spec.matchesNamespace(namespace, service.Namespace) || !selector.Matches(labels.Set(service.Labels)) {
+					continue
+				}
+				selected = append(selected, service.Name)
+				for _, port := range service.Spec.Ports {
+					ports[port.Name] = struct{}{}
+				}
+			}
+
+			if len(selected) == 0 {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment("selector", "The ServiceMonitor does not select any Service",
+					"Prometheus does not scrape any targets of the ServiceMonitor. Make the selector match the labels of the Service, and the namespaceSelector match its namespace")
+				return
+			}
+
+			score.Grade = scorecard.GradeAllOK
+			for _, endpoint := range spec.Endpoints {
+				if endpoint.Port == "" {
+					continue
+				}
+				if _, ok := ports[endpoint.Port]; !ok {
+					score.Grade = scorecard.GradeCritical
+					score.AddComment("endpoints", fmt.Sprintf("The port %s is not a port of the selected Services: %s", endpoint.Port, strings.Join(selected, ", ")),
+						"The endpoint is not scraped. The port of the endpoints of a ServiceMonitor is the name of a port of the Service")
+				}
+			}
+			return
+		})(object)
+	}
+}
+
+// podTemplate is the pod template of a pod or a workload, with the namespace of the object
+type podTemplate struct {
+	namespace string
+	template  corev1.PodTemplateSpec
+}
+
+func podTemplates(pods []ks.Pod, podspecers []ks.PodSpecer) []podTemplate {
+	var res []podTemplate
+	for _, pod := range pods {
+		res = append(res, podTemplate{
+			namespace: pod.Pod().Namespace,
+			template:  corev1.PodTemplateSpec{ObjectMeta: pod.Pod().ObjectMeta, Spec: pod.Pod().Spec},
+		})
+	}
+	for _, podspecer := range podspecers {
+		res = append(res, podTemplate{namespace: podspecer.GetObjectMeta().Namespace, template: podspecer.GetPodTemplateSpec()})
+	}
+	return res
+}
+
+func podMonitorTargetsPod(allTemplates []podTemplate) func(ks.Object) scorecard.TestScore {
+	return func(object ks.Object) scorecard.TestScore {
+		namespace := object.GetObjectMeta().Namespace
+		return targets("PodMonitor", func(spec monitorSpec, selector labels.Selector) (score scorecard.TestScore) {
+			ports := make(map[string]struct{})
+			selected := false
+			for _, t := range allTemplates {
+				if !spec.matchesNamespace(namespace, t.namespace) || !selector.Matches(labels.Set(t.template.Labels)) {
+					continue
+				}
+				selected = true
+				for _, container := range internal.AllContainers(t.template.Spec) {
+					for _, port := range container.Ports {
+						ports[port.Name] = struct{}{}
+					}
+				}
+			}
+
+			if !selected {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment("selector", "The PodMonitor does not select any pods",
+					"Prometheus does not scrape any targets of the PodMonitor. Make the selector match the labels of the pod template, and the namespaceSelector match its namespace")
+				return
+			}
+
+			score.Grade = scorecard.GradeAllOK
+			for _, endpoint := range spec.PodMetricsEndpoints {
+				if endpoint.Port == "" {
+					continue
+				}
+				if _, ok := ports[endpoint.Port]; !ok {
+					score.Grade = scorecard.GradeCritical
+					score.AddComment("podMetricsEndpoints", fmt.Sprintf("The port %s is not a port of the containers of the selected pods", endpoint.Port),
+						"The endpoint is not scraped. The port of the podMetricsEndpoints of a PodMonitor is the name of a port of a container")
+				}
+			}
+			return
+		})(object)
+	}
+}
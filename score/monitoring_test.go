@@ -0,0 +1,40 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+)
+
+func TestMonitorTargets(t *testing.T) {
+	t.Parallel()
+	s, err := testScore(config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("servicemonitor.yaml")},
+	})
+	assert.Nil(t, err)
+
+	summaries := make(map[string][]string)
+	for _, o := range s {
+		for _, c := range o.Checks {
+			if c.Check.ID != "servicemonitor-targets-service" && c.Check.ID != "podmonitor-targets-pod" {
+				continue
+			}
+			key := o.TypeMeta.Kind + "/" + o.ObjectMeta.Name
+			summaries[key] = append(summaries[key], c.Grade.String())
+			for _, comment := range c.Comments {
+				summaries[key] = append(summaries[key], comment.Summary)
+			}
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"ServiceMonitor/valid":           {"OK"},
+		"ServiceMonitor/wrong-namespace": {"CRITICAL", "The ServiceMonitor does not select any Service"},
+		"ServiceMonitor/wrong-port":      {"CRITICAL", "The port 9090 is not a port of the selected Services: app"},
+		"PodMonitor/valid":               {"OK"},
+		"PodMonitor/wrong-port":          {"CRITICAL", "The port prometheus is not a port of the containers of the selected pods"},
+		"PodMonitor/wrong-selector":      {"CRITICAL", "The PodMonitor does not select any pods"},
+	}, summaries)
+}
@@ -13,7 +13,24 @@ import (
 
 func Register(allChecks *checks.Checks, netpols ks.NetworkPolicies, pods ks.Pods, podspecers ks.PodSpeccers) {
 	allChecks.RegisterPodCheck("Pod NetworkPolicy", `Makes sure that all Pods are targeted by a NetworkPolicy`, podHasNetworkPolicy(netpols.NetworkPolicies()))
+	allChecks.RegisterOptionalPodCheck("Pod NetworkPolicy Default Deny", `Makes sure that the namespace of all Pods has a default deny NetworkPolicy, that selects all pods and denies all ingress and egress traffic that is not allowed by other policies`, podNamespaceHasDefaultDeny(netpols.NetworkPolicies()))
 	allChecks.RegisterNetworkPolicyCheck("NetworkPolicy targets Pod", `Makes sure that all NetworkPolicies targets at least one Pod`, networkPolicyTargetsPod(pods.Pods(), podspecers.PodSpeccers()))
+	allChecks.MarkCrossObject("pod-networkpolicy", "pod-networkpolicy-default-deny", "networkpolicy-targets-pod")
+	allChecks.Document("pod-networkpolicy", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Create a NetworkPolicy that selects the pod, and that has both the Ingress and Egress policyTypes",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/network-policies/",
+	})
+	allChecks.Document("pod-networkpolicy-default-deny", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Create a NetworkPolicy with an empty podSelector and the Ingress and Egress policyTypes, but no rules, in the namespace",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/network-policies/#default-policies",
+	})
+	allChecks.Document("networkpolicy-targets-pod", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Change the podSelector of the NetworkPolicy to match the labels of the pods that it should apply to",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/network-policies/",
+	})
 }
 
 // podHasNetworkPolicy returns a function that tests that all pods have matching NetworkPolicies
@@ -33,33 +50,12 @@ func podHasNetworkPolicy(allNetpols []ks.NetworkPolicy) func(spec corev1.PodTemp
 
 			if selector, err := metav1.LabelSelectorAsSelector(&netPol.Spec.PodSelector); err == nil {
 				if selector.Matches(internal.MapLables(podSpec.Labels)) {
-
-					// Documentation of PolicyTypes
-					//
-					// List of rule types that the NetworkPolicy relates to.
-					// Valid options are "Ingress", "Egress", or "Ingress,Egress".
-					// If this field is not specified, it will default based on the existence of Ingress or Egress rules;
-					// policies that contain an Egress section are assumed to affect Egress, and all policies
-					// (whether or not they contain an Ingress section) are assumed to affect Ingress.
-					// If you want to write an egress-only policy, you must explicitly specify policyTypes [ "Egress" ].
-					// Likewise, if you want to write a policy that specifies that no egress is allowed,
-					// you must specify a policyTypes value that include "Egress" (since such a policy would not include
-					// an Egress section and would otherwise default to just [ "Ingress" ]).
-
-					if netPol.Spec.PolicyTypes == nil || len(netPol.Spec.PolicyTypes) == 0 {
+					ingress, egress := policyTypes(netPol)
+					if ingress {
 						hasMatchingIngressNetpol = true
-						if len(netPol.Spec.Egress) > 0 {
-							hasMatchingEgressNetpol = true
-						}
-					} else {
-						for _, policyType := range netPol.Spec.PolicyTypes {
-							if policyType == networkingv1.PolicyTypeIngress {
-								hasMatchingIngressNetpol = true
-							}
-							if policyType == networkingv1.PolicyTypeEgress {
-								hasMatchingEgressNetpol = true
-							}
-						}
+					}
+					if egress {
+						hasMatchingEgressNetpol = true
 					}
 				}
 			}
@@ -82,6 +78,79 @@ func podHasNetworkPolicy(allNetpols []ks.NetworkPolicy) func(spec corev1.PodTemp
 	}
 }
 
+// podNamespaceHasDefaultDeny returns a function that tests that the namespace of the pod has NetworkPolicies that
+// deny all ingress and egress traffic by default. The ingress and egress traffic can be denied by the same policy, or
+// by two separate policies.
+func podNamespaceHasDefaultDeny(allNetpols []ks.NetworkPolicy) func(spec corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) scorecard.TestScore {
+	return func(podSpec corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+		hasDefaultDenyIngress := false
+		hasDefaultDenyEgress := false
+
+		for _, n := range allNetpols {
+			netPol := n.NetworkPolicy()
+			if podSpec.Namespace != netPol.Namespace {
+				continue
+			}
+
+			// A default deny policy selects all pods in the namespace, and does not allow any traffic
+			if len(netPol.Spec.PodSelector.MatchLabels) > 0 || len(netPol.Spec.PodSelector.MatchExpressions) > 0 {
+				continue
+			}
+
+			ingress, egress := policyTypes(netPol)
+			if ingress && len(netPol.Spec.Ingress) == 0 {
+				hasDefaultDenyIngress = true
+			}
+			if egress && len(netPol.Spec.Egress) == 0 {
+				hasDefaultDenyEgress = true
+			}
+		}
+
+		score.Grade = scorecard.GradeAllOK
+
+		if !hasDefaultDenyIngress {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("", "The namespace does not have a default deny ingress NetworkPolicy", "Create a NetworkPolicy with an empty podSelector, the Ingress policyType and no ingress rules, to deny all ingress traffic that is not explicitly allowed")
+		}
+		if !hasDefaultDenyEgress {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("", "The namespace does not have a default deny egress NetworkPolicy", "Create a NetworkPolicy with an empty podSelector, the Egress policyType and no egress rules, to deny all egress traffic that is not explicitly allowed")
+		}
+
+		return
+	}
+}
+
+// policyTypes returns if the NetworkPolicy affects ingress and egress traffic
+func policyTypes(netPol networkingv1.NetworkPolicy) (ingress, egress bool) {
+	// Documentation of PolicyTypes
+	//
+	// List of rule types that the NetworkPolicy relates to.
+	// Valid options are "Ingress", "Egress", or "Ingress,Egress".
+	// If this field is not specified, it will default based on the existence of Ingress or Egress rules;
+	// policies that contain an Egress section are assumed to affect Egress, and all policies
+	// (whether or not they contain an Ingress section) are assumed to affect Ingress.
+	// If you want to write an egress-only policy, you must explicitly specify policyTypes [ "Egress" ].
+	// Likewise, if you want to write a policy that specifies that no egress is allowed,
+	// you must specify a policyTypes value that include "Egress" (since such a policy would not include
+	// an Egress section and would otherwise default to just [ "Ingress" ]).
+
+	if len(netPol.Spec.PolicyTypes) == 0 {
+		return true, len(netPol.Spec.Egress) > 0
+	}
+
+	for _, policyType := range netPol.Spec.PolicyTypes {
+		if policyType == networkingv1.PolicyTypeIngress {
+			ingress = true
+		}
+		if policyType == networkingv1.PolicyTypeEgress {
+			egress = true
+		}
+	}
+
+	return
+}
+
 func networkPolicyTargetsPod(pods []ks.Pod, podspecers []ks.PodSpecer) func(networkingv1.NetworkPolicy) scorecard.TestScore {
 	return func(netpol networkingv1.NetworkPolicy) (score scorecard.TestScore) {
 		hasMatch := false
@@ -3,6 +3,10 @@ package score
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/scorecard"
 )
 
@@ -82,3 +86,29 @@ func TestNetworkPolicyEmptyPodSelector(t *testing.T) {
 	testExpectedScore(t, "networkpolicy-targets-all-pods.yaml", "NetworkPolicy targets Pod", scorecard.GradeAllOK)
 	testExpectedScore(t, "networkpolicy-targets-all-pods.yaml", "Pod NetworkPolicy", scorecard.GradeAllOK)
 }
+
+func TestPodNetworkPolicyDefaultDeny(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("networkpolicy-default-deny.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-networkpolicy-default-deny": {}},
+	}, "Pod NetworkPolicy Default Deny", scorecard.GradeAllOK)
+}
+
+func TestPodNetworkPolicyDefaultDenyOnlyIngress(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("networkpolicy-default-deny-only-ingress.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-networkpolicy-default-deny": {}},
+	}, "Pod NetworkPolicy Default Deny", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The namespace does not have a default deny egress NetworkPolicy", comments[0].Summary)
+}
+
+func TestPodNetworkPolicyDefaultDenyNotEmptySelector(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("networkpolicy-matching.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-networkpolicy-default-deny": {}},
+	}, "Pod NetworkPolicy Default Deny", scorecard.GradeWarning)
+}
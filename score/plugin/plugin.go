@@ -0,0 +1,43 @@
+package plugin
+
+import (
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/plugins"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Register registers the checks of all loaded plugins
+func Register(allChecks *checks.Checks, loaded []*plugins.Plugin) {
+	for _, p := range loaded {
+		for _, c := range p.Checks {
+			name := c.Name
+			if name == "" {
+				name = c.ID
+			}
+			fn := pluginCheck(allChecks, p, c.ID)
+			if c.Optional {
+				allChecks.RegisterOptionalObjectCheck(c.ID, name, c.Comment, c.Kinds, fn)
+			} else {
+				allChecks.RegisterObjectCheck(c.ID, name, c.Comment, c.Kinds, fn)
+			}
+		}
+	}
+}
+
+func pluginCheck(allChecks *checks.Checks, p *plugins.Plugin, id string) checks.ObjectCheckFn {
+	return func(object ks.Object) scorecard.TestScore {
+		return p.Score(object, id, enabledChecks(allChecks, p, object.GetTypeMeta().Kind))
+	}
+}
+
+// enabledChecks returns the IDs of the checks in the plugin that are run on objects of the kind
+func enabledChecks(allChecks *checks.Checks, p *plugins.Plugin, kind string) []string {
+	var ids []string
+	for _, c := range p.Checks {
+		if check, ok := allChecks.Objects()[c.ID]; ok && check.Matches(kind) {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,57 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/plugins"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+type pluginRuntime struct{}
+
+func (pluginRuntime) Run(module string, args []string, stdin []byte) ([]byte, error) {
+	if args[0] == "describe" {
+		return []byte(`{"abiVersion": 1, "checks": [
+			{"id": "vendor-replicas", "kinds": ["Deployment"]},
+			{"id": "vendor-optional", "optional": true}
+		]}`), nil
+	}
+	return []byte(`{"results": [{"check": "vendor-replicas", "grade": "warning"}, {"check": "vendor-optional", "grade": "ok"}]}`), nil
+}
+
+func TestPluginChecks(t *testing.T) {
+	t.Parallel()
+
+	p, err := plugins.Load("vendor.wasm", pluginRuntime{})
+	assert.Nil(t, err)
+
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("custom-checks.yaml")},
+		KubernetesVersion: config.Semver{1, 18},
+		Plugins:           []*plugins.Plugin{p},
+		UnknownKinds:      config.UnknownKindsSkip,
+	})
+	assert.Nil(t, err)
+
+	replicas := findCustomCheck(t, sc, "Deployment/apps/v1//few-replicas", "vendor-replicas")
+	assert.Equal(t, scorecard.GradeWarning, replicas.Grade)
+	assert.Equal(t, "vendor-replicas", replicas.Check.Name)
+
+	// Optional checks are only run when they are enabled
+	assert.Nil(t, findCustomCheck(t, sc, "Deployment/apps/v1//few-replicas", "vendor-optional"))
+	assert.Nil(t, findCustomCheck(t, sc, "ConfigMap/v1//settings", "vendor-replicas"))
+
+	sc, err = testScore(config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("custom-checks.yaml")},
+		KubernetesVersion:    config.Semver{1, 18},
+		Plugins:              []*plugins.Plugin{p},
+		EnabledOptionalTests: map[string]struct{}{"vendor-optional": {}},
+	})
+	assert.Nil(t, err)
+	optional := findCustomCheck(t, sc, "ConfigMap/v1//settings", "vendor-optional")
+	assert.Equal(t, scorecard.GradeAllOK, optional.Grade)
+}
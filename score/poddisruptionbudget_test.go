@@ -3,6 +3,8 @@ package score
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/zegl/kube-score/scorecard"
 )
 
@@ -65,3 +67,34 @@ func TestDeploymentPodDisruptionBudgetV1NoMatch(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(t, "deployment-poddisruptionbudget-v1-no-match.yaml", "Deployment has PodDisruptionBudget", scorecard.GradeCritical)
 }
+
+func TestPodDisruptionBudgetMinAvailable100Percent(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "poddisruptionbudget-min-available-100-percent.yaml", "PodDisruptionBudget allows disruption", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The PodDisruptionBudget does not allow any pods of the Deployment deployment-test-1 with 3 replicas to be evicted", comments[0].Summary)
+}
+
+func TestPodDisruptionBudgetMinAvailableReplicas(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "poddisruptionbudget-min-available-replicas.yaml", "PodDisruptionBudget allows disruption", scorecard.GradeCritical)
+}
+
+func TestPodDisruptionBudgetMaxUnavailablePercent(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "poddisruptionbudget-max-unavailable-percent.yaml", "PodDisruptionBudget allows disruption", scorecard.GradeAllOK)
+}
+
+func TestPodDisruptionBudgetMaxUnavailableZeroNoTarget(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "poddisruptionbudget-max-unavailable-zero.yaml", "PodDisruptionBudget allows disruption", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The PodDisruptionBudget does not allow any pods to be evicted", comments[0].Summary)
+}
+
+func TestPodDisruptionBudgetAllowsDisruptionNoPolicy(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "deployment-poddisruptionbudget-v1-no-policy.yaml", "PodDisruptionBudget allows disruption", 0)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Skipped because the PodDisruptionBudget has no policy", comments[0].Summary)
+}
@@ -0,0 +1,61 @@
+// Package podsecurity evaluates pods against the Pod Security Standards
+// https://kubernetes.io/docs/concepts/security/pod-security-standards/
+package podsecurity
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+const documentationURL = "https://kubernetes.io/docs/concepts/security/pod-security-standards/"
+
+// Register registers the Pod Security Standard check. The check is optional, unless a profile to enforce has been
+// configured with --pod-security-standard.
+func Register(allChecks *checks.Checks, cnf config.Configuration) {
+	const name = "Pod Security Standard"
+	const comment = "Reports which of the Pod Security Standards (privileged, baseline or restricted) the pod satisfies. Use --pod-security-standard to require a profile"
+	allChecks.Document("pod-security-standard", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Fix the violations of the enforced Pod Security Standard that are reported by the check",
+		URL:         "https://kubernetes.io/docs/concepts/security/pod-security-standards/",
+	})
+
+	if cnf.PodSecurityStandard == "" {
+		allChecks.RegisterOptionalPodCheck(name, comment, podSecurityStandard(LevelPrivileged))
+		return
+	}
+
+	// The level has already been validated when parsing the flags
+	enforce, _ := ParseLevel(cnf.PodSecurityStandard)
+	allChecks.RegisterPodCheck(name, comment, podSecurityStandard(enforce))
+}
+
+// podSecurityStandard reports the most restrictive profile that the pod satisfies, and fails if it does not satisfy
+// the enforced profile
+func podSecurityStandard(enforce Level) func(corev1.PodTemplateSpec, metav1.TypeMeta) scorecard.TestScore {
+	return func(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+		level, violations := Evaluate(podTemplate)
+
+		if level >= enforce {
+			score.Grade = scorecard.GradeAllOK
+			score.AddCommentWithURL("", fmt.Sprintf("The pod satisfies the %s Pod Security Standard", level), "", documentationURL)
+			return
+		}
+
+		score.Grade = scorecard.GradeCritical
+		for _, v := range violations {
+			if v.Level > enforce {
+				continue
+			}
+			score.AddCommentWithURL(v.Path, v.Summary, fmt.Sprintf("%s. Required by the %s Pod Security Standard.", v.Description, v.Level), documentationURL)
+		}
+
+		return
+	}
+}
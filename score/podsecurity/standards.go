@@ -0,0 +1,333 @@
+package podsecurity
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zegl/kube-score/score/internal"
+)
+
+// Level is one of the profiles in the Pod Security Standards
+type Level int
+
+const (
+	LevelPrivileged Level = iota
+	LevelBaseline
+	LevelRestricted
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelPrivileged:
+		return "privileged"
+	case LevelBaseline:
+		return "baseline"
+	case LevelRestricted:
+		return "restricted"
+	default:
+		panic("Unknown level")
+	}
+}
+
+// ParseLevel parses the name of a Pod Security Standards profile
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "privileged":
+		return LevelPrivileged, nil
+	case "baseline":
+		return LevelBaseline, nil
+	case "restricted":
+		return LevelRestricted, nil
+	default:
+		return 0, fmt.Errorf("unknown pod security standard %q, must be one of privileged, baseline or restricted", s)
+	}
+}
+
+// Violation is a part of a pod that does not satisfy a profile
+type Violation struct {
+	// Level is the profile that is not satisfied
+	Level Level
+
+	// Path is the name of the container, or empty if the violation is in the pod spec
+	Path        string
+	Summary     string
+	Description string
+}
+
+// baselineCapabilities are the capabilities that are allowed to be added by the baseline profile
+var baselineCapabilities = map[string]struct{}{
+	"AUDIT_WRITE":      {},
+	"CHOWN":            {},
+	"DAC_OVERRIDE":     {},
+	"FOWNER":           {},
+	"FSETID":           {},
+	"KILL":             {},
+	"MKNOD":            {},
+	"NET_BIND_SERVICE": {},
+	"SETFCAP":          {},
+	"SETGID":           {},
+	"SETPCAP":          {},
+	"SETUID":           {},
+	"SYS_CHROOT":       {},
+}
+
+// safeSysctls are the sysctls that are allowed by the baseline profile
+var safeSysctls = map[string]struct{}{
+	"kernel.shm_rmid_forced":              {},
+	"net.ipv4.ip_local_port_range":        {},
+	"net.ipv4.ip_unprivileged_port_start": {},
+	"net.ipv4.tcp_syncookies":             {},
+	"net.ipv4.ping_group_range":           {},
+}
+
+// baselineSELinuxTypes are the SELinux types that are allowed by the baseline profile
+var baselineSELinuxTypes = map[string]struct{}{
+	"":                 {},
+	"container_t":      {},
+	"container_init_t": {},
+	"container_kvm_t":  {},
+}
+
+// restrictedVolumeTypes are the volume types that are allowed by the restricted profile
+var restrictedVolumeTypes = map[string]struct{}{
+	"configMap":             {},
+	"csi":                   {},
+	"downwardAPI":           {},
+	"emptyDir":              {},
+	"ephemeral":             {},
+	"persistentVolumeClaim": {},
+	"projected":             {},
+	"secret":                {},
+}
+
+const appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+// Evaluate returns the most restrictive profile that the pod satisfies, together with the violations of the more
+// restrictive profiles.
+func Evaluate(podTemplate corev1.PodTemplateSpec) (Level, []Violation) {
+	violations := baselineViolations(podTemplate)
+	violations = append(violations, restrictedViolations(podTemplate)...)
+
+	level := LevelRestricted
+	for _, v := range violations {
+		if v.Level <= level {
+			level = v.Level - 1
+		}
+	}
+
+	return level, violations
+}
+
+func baselineViolations(podTemplate corev1.PodTemplateSpec) []Violation {
+	spec := podTemplate.Spec
+	var violations []Violation
+
+	add := func(path, summary, description string) {
+		violations = append(violations, Violation{
+			Level:       LevelBaseline,
+			Path:        path,
+			Summary:     summary,
+			Description: description,
+		})
+	}
+
+	if spec.HostNetwork {
+		add("", "The pod uses the host network", "Set hostNetwork to false")
+	}
+	if spec.HostPID {
+		add("", "The pod uses the host PID namespace", "Set hostPID to false")
+	}
+	if spec.HostIPC {
+		add("", "The pod uses the host IPC namespace", "Set hostIPC to false")
+	}
+
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			add("", fmt.Sprintf("The volume %s is a hostPath volume", volume.Name), "Remove the hostPath volume")
+		}
+	}
+
+	var annotations []string
+	for key := range podTemplate.ObjectMeta.Annotations {
+		annotations = append(annotations, key)
+	}
+	sort.Strings(annotations)
+
+	for _, key := range annotations {
+		if !strings.HasPrefix(key, appArmorAnnotationPrefix) {
+			continue
+		}
+		if value := podTemplate.ObjectMeta.Annotations[key]; value != "runtime/default" && !strings.HasPrefix(value, "localhost/") {
+			add(strings.TrimPrefix(key, appArmorAnnotationPrefix), fmt.Sprintf("The container uses the AppArmor profile %s", value), "Set the AppArmor profile to runtime/default or localhost/<profile>")
+		}
+	}
+
+	if sec := spec.SecurityContext; sec != nil {
+		if sec.WindowsOptions != nil && sec.WindowsOptions.HostProcess != nil && *sec.WindowsOptions.HostProcess {
+			add("", "The pod runs as a Windows HostProcess", "Set securityContext.windowsOptions.hostProcess to false")
+		}
+		if sec.SELinuxOptions != nil && !isBaselineSELinuxOptions(*sec.SELinuxOptions) {
+			add("", "The pod has custom SELinux options", "Remove securityContext.seLinuxOptions.user and role, and set type to container_t, container_init_t or container_kvm_t")
+		}
+		if sec.SeccompProfile != nil && sec.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			add("", "The pod uses the Unconfined Seccomp profile", "Set securityContext.seccompProfile.type to RuntimeDefault or Localhost")
+		}
+		for _, sysctl := range sec.Sysctls {
+			if _, ok := safeSysctls[sysctl.Name]; !ok {
+				add("", fmt.Sprintf("The pod sets the unsafe sysctl %s", sysctl.Name), "Remove the sysctl from securityContext.sysctls")
+			}
+		}
+	}
+
+	for _, container := range internal.AllContainers(spec) {
+		for _, port := range container.Ports {
+			if port.HostPort != 0 {
+				add(container.Name, fmt.Sprintf("The container uses the host port %d", port.HostPort), "Remove hostPort from the container ports")
+			}
+		}
+
+		sec := container.SecurityContext
+		if sec == nil {
+			continue
+		}
+
+		if sec.Privileged != nil && *sec.Privileged {
+			add(container.Name, "The container is privileged", "Set securityContext.privileged to false")
+		}
+		if sec.WindowsOptions != nil && sec.WindowsOptions.HostProcess != nil && *sec.WindowsOptions.HostProcess {
+			add(container.Name, "The container runs as a Windows HostProcess", "Set securityContext.windowsOptions.hostProcess to false")
+		}
+		if sec.Capabilities != nil {
+			for _, c := range sec.Capabilities.Add {
+				if _, ok := baselineCapabilities[normalizeCapability(c)]; !ok {
+					add(container.Name, fmt.Sprintf("The container adds the capability %s", c), "Remove the capability from securityContext.capabilities.add")
+				}
+			}
+		}
+		if sec.SELinuxOptions != nil && !isBaselineSELinuxOptions(*sec.SELinuxOptions) {
+			add(container.Name, "The container has custom SELinux options", "Remove securityContext.seLinuxOptions.user and role, and set type to container_t, container_init_t or container_kvm_t")
+		}
+		if sec.ProcMount != nil && *sec.ProcMount != corev1.DefaultProcMount {
+			add(container.Name, "The container uses an unmasked /proc mount", "Set securityContext.procMount to Default")
+		}
+		if sec.SeccompProfile != nil && sec.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			add(container.Name, "The container uses the Unconfined Seccomp profile", "Set securityContext.seccompProfile.type to RuntimeDefault or Localhost")
+		}
+	}
+
+	return violations
+}
+
+func restrictedViolations(podTemplate corev1.PodTemplateSpec) []Violation {
+	spec := podTemplate.Spec
+	var violations []Violation
+
+	add := func(path, summary, description string) {
+		violations = append(violations, Violation{
+			Level:       LevelRestricted,
+			Path:        path,
+			Summary:     summary,
+			Description: description,
+		})
+	}
+
+	for _, volume := range spec.Volumes {
+		t := volumeType(volume)
+		// Volumes without a source are defaulted to emptyDir
+		if t == "" {
+			continue
+		}
+		if _, ok := restrictedVolumeTypes[t]; !ok {
+			add("", fmt.Sprintf("The volume %s is of the type %s", volume.Name, t), "Only configMap, csi, downwardAPI, emptyDir, ephemeral, persistentVolumeClaim, projected and secret volumes are allowed")
+		}
+	}
+
+	podSec := spec.SecurityContext
+	if podSec == nil {
+		podSec = &corev1.PodSecurityContext{}
+	}
+
+	if podSec.RunAsUser != nil && *podSec.RunAsUser == 0 {
+		add("", "The pod runs as the root user", "Set securityContext.runAsUser to a non-zero value")
+	}
+
+	for _, container := range internal.AllContainers(spec) {
+		sec := container.SecurityContext
+		if sec == nil {
+			sec = &corev1.SecurityContext{}
+		}
+
+		if sec.AllowPrivilegeEscalation == nil || *sec.AllowPrivilegeEscalation {
+			add(container.Name, "The container allows privilege escalation", "Set securityContext.allowPrivilegeEscalation to false")
+		}
+
+		runAsNonRoot := podSec.RunAsNonRoot
+		if sec.RunAsNonRoot != nil {
+			runAsNonRoot = sec.RunAsNonRoot
+		}
+		if runAsNonRoot == nil || !*runAsNonRoot {
+			add(container.Name, "The container does not set runAsNonRoot", "Set securityContext.runAsNonRoot to true")
+		}
+
+		if sec.RunAsUser != nil && *sec.RunAsUser == 0 {
+			add(container.Name, "The container runs as the root user", "Set securityContext.runAsUser to a non-zero value")
+		}
+
+		seccompProfile := podSec.SeccompProfile
+		if sec.SeccompProfile != nil {
+			seccompProfile = sec.SeccompProfile
+		}
+		if seccompProfile == nil || (seccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault && seccompProfile.Type != corev1.SeccompProfileTypeLocalhost) {
+			add(container.Name, "The container does not have a RuntimeDefault or Localhost Seccomp profile", "Set securityContext.seccompProfile.type to RuntimeDefault or Localhost")
+		}
+
+		var capabilities corev1.Capabilities
+		if sec.Capabilities != nil {
+			capabilities = *sec.Capabilities
+		}
+		dropsAll := false
+		for _, c := range capabilities.Drop {
+			if normalizeCapability(c) == "ALL" {
+				dropsAll = true
+			}
+		}
+		if !dropsAll {
+			add(container.Name, "The container does not drop ALL capabilities", "Set securityContext.capabilities.drop to [ALL]")
+		}
+		for _, c := range capabilities.Add {
+			if normalizeCapability(c) != "NET_BIND_SERVICE" {
+				add(container.Name, fmt.Sprintf("The container adds the capability %s", c), "Only NET_BIND_SERVICE can be added to securityContext.capabilities.add")
+			}
+		}
+	}
+
+	return violations
+}
+
+func isBaselineSELinuxOptions(opts corev1.SELinuxOptions) bool {
+	if opts.User != "" || opts.Role != "" {
+		return false
+	}
+	_, ok := baselineSELinuxTypes[opts.Type]
+	return ok
+}
+
+// normalizeCapability returns the name of the capability in upper case, without the optional CAP_ prefix
+func normalizeCapability(c corev1.Capability) string {
+	return strings.TrimPrefix(strings.ToUpper(string(c)), "CAP_")
+}
+
+// volumeType returns the name of the volume source that is set on the volume, for example "emptyDir"
+func volumeType(volume corev1.Volume) string {
+	v := reflect.ValueOf(volume.VolumeSource)
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Field(i).IsNil() {
+			return strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		}
+	}
+	return ""
+}
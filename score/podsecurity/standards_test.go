@@ -0,0 +1,131 @@
+package podsecurity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func restrictedContainer(name string) corev1.Container {
+	return corev1.Container{
+		Name: name,
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: boolPtr(false),
+			RunAsNonRoot:             boolPtr(true),
+			SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+				Add:  []corev1.Capability{"NET_BIND_SERVICE"},
+			},
+		},
+	}
+}
+
+func TestEvaluateRestricted(t *testing.T) {
+	t.Parallel()
+	level, violations := Evaluate(corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{restrictedContainer("init")},
+			Containers:     []corev1.Container{restrictedContainer("foo")},
+			Volumes: []corev1.Volume{
+				{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+		},
+	})
+	assert.Equal(t, LevelRestricted, level)
+	assert.Empty(t, violations)
+}
+
+func TestEvaluateBaseline(t *testing.T) {
+	t.Parallel()
+	level, violations := Evaluate(corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "foo"}},
+		},
+	})
+	assert.Equal(t, LevelBaseline, level)
+	assert.Contains(t, violations, Violation{
+		Level:       LevelRestricted,
+		Path:        "foo",
+		Summary:     "The container does not drop ALL capabilities",
+		Description: "Set securityContext.capabilities.drop to [ALL]",
+	})
+}
+
+func TestEvaluatePrivileged(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		spec    corev1.PodSpec
+		summary string
+	}{
+		{
+			name:    "host network",
+			spec:    corev1.PodSpec{HostNetwork: true, Containers: []corev1.Container{restrictedContainer("foo")}},
+			summary: "The pod uses the host network",
+		},
+		{
+			name: "host path",
+			spec: corev1.PodSpec{
+				Containers: []corev1.Container{restrictedContainer("foo")},
+				Volumes: []corev1.Volume{
+					{Name: "logs", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/log"}}},
+				},
+			},
+			summary: "The volume logs is a hostPath volume",
+		},
+		{
+			name: "dangerous capability",
+			spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:            "foo",
+				SecurityContext: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"SYS_ADMIN"}}},
+			}}},
+			summary: "The container adds the capability SYS_ADMIN",
+		},
+		{
+			name: "host port",
+			spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  "foo",
+				Ports: []corev1.ContainerPort{{ContainerPort: 80, HostPort: 80}},
+			}}},
+			summary: "The container uses the host port 80",
+		},
+		{
+			name: "unsafe sysctl",
+			spec: corev1.PodSpec{
+				SecurityContext: &corev1.PodSecurityContext{Sysctls: []corev1.Sysctl{{Name: "kernel.msgmax", Value: "65536"}}},
+				Containers:      []corev1.Container{restrictedContainer("foo")},
+			},
+			summary: "The pod sets the unsafe sysctl kernel.msgmax",
+		},
+	}
+
+	for _, tc := range tests {
+		level, violations := Evaluate(corev1.PodTemplateSpec{Spec: tc.spec})
+		assert.Equal(t, LevelPrivileged, level, tc.name)
+
+		found := false
+		for _, v := range violations {
+			if v.Level == LevelBaseline && v.Summary == tc.summary {
+				found = true
+			}
+		}
+		assert.True(t, found, tc.name)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+	l, err := ParseLevel("Restricted")
+	assert.Nil(t, err)
+	assert.Equal(t, LevelRestricted, l)
+
+	_, err = ParseLevel("strict")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,45 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestPodSecurityStandardReport(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("deployment-pod-security-standard-privileged.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-security-standard": {}},
+	}, "Pod Security Standard", scorecard.GradeAllOK)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The pod satisfies the privileged Pod Security Standard", comments[0].Summary)
+}
+
+func TestPodSecurityStandardEnforceRestricted(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:            []ks.NamedReader{testFile("deployment-pod-security-standard-restricted.yaml")},
+		PodSecurityStandard: "restricted",
+	}, "Pod Security Standard", scorecard.GradeAllOK)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The pod satisfies the restricted Pod Security Standard", comments[0].Summary)
+}
+
+func TestPodSecurityStandardEnforceBaselineViolated(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:            []ks.NamedReader{testFile("deployment-pod-security-standard-privileged.yaml")},
+		PodSecurityStandard: "baseline",
+	}, "Pod Security Standard", scorecard.GradeCritical)
+	assert.Equal(t, []scorecard.TestScoreComment{{
+		Path:             "",
+		Summary:          "The pod uses the host network",
+		Description:      "Set hostNetwork to false. Required by the baseline Pod Security Standard.",
+		DocumentationURL: "https://kubernetes.io/docs/concepts/security/pod-security-standards/",
+	}}, comments)
+}
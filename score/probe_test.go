@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/scorecard"
 )
 
@@ -96,3 +98,65 @@ func TestProbesMultipleContainersInit(t *testing.T) {
 	comments := testExpectedScore(t, "pod-probes-on-different-containers-init.yaml", "Pod Probes", scorecard.GradeAllOK)
 	assert.Len(t, comments, 0)
 }
+
+func TestProbesValues(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-probes-values.yaml", "Container Probe Values", scorecard.GradeWarning)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "The readinessProbe timeoutSeconds (10) is not lower than periodSeconds (5)", comments[0].Summary)
+	assert.Equal(t, "The livenessProbe failureThreshold is -1", comments[1].Summary)
+}
+
+func TestProbesValuesOK(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-probes-startup.yaml", "Container Probe Values", scorecard.GradeAllOK)
+}
+
+func TestProbesStartupProbeMissing(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-probes-values.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-startup-probe": {}},
+	}, "Container Startup Probe", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The livenessProbe has an initialDelaySeconds of 300, but no startupProbe is configured", comments[0].Summary)
+}
+
+func TestProbesStartupProbe(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-probes-startup.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-startup-probe": {}},
+	}, "Container Startup Probe", scorecard.GradeAllOK)
+}
+
+func TestContainerProbePorts(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "container-ports-valid.yaml", "Container Probe Ports", scorecard.GradeAllOK)
+}
+
+func TestContainerProbePortsNotDeclared(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "container-ports-invalid.yaml", "Container Probe Ports", scorecard.GradeCritical)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "The readinessProbe uses the undeclared port htp", comments[0].Summary)
+	assert.Equal(t, "The livenessProbe uses the undeclared port 8081", comments[1].Summary)
+}
+
+func TestContainerProbesIdentical(t *testing.T) {
+	t.Parallel()
+	cnf := func(file string) config.Configuration {
+		return config.Configuration{
+			AllFiles:             []ks.NamedReader{testFile(file)},
+			EnabledOptionalTests: map[string]struct{}{"container-probes-identical": {}},
+		}
+	}
+
+	comments := testExpectedScoreWithConfig(t, cnf("pod-probes-identical-named-port.yaml"), "Container Probes Identical", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "named", comments[0].Path)
+	assert.Equal(t, "The livenessProbe and the readinessProbe are identical", comments[0].Summary)
+
+	testExpectedScoreWithConfig(t, cnf("pod-probes-identical-exec.yaml"), "Container Probes Identical", scorecard.GradeWarning)
+	testExpectedScoreWithConfig(t, cnf("pod-probes-values.yaml"), "Container Probes Identical", scorecard.GradeAllOK)
+}
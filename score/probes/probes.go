@@ -1,8 +1,12 @@
 package probes
 
 import (
+	"fmt"
+	"reflect"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/score/checks"
@@ -12,8 +16,135 @@ import (
 
 func Register(allChecks *checks.Checks, services ks.Services) {
 	allChecks.RegisterPodCheck("Pod Probes", `Makes sure that all Pods have safe probe configurations`, containerProbes(services.Services()))
+	allChecks.RegisterPodCheck("Container Probe Values", `Makes sure that the timeoutSeconds of all probes is lower than the periodSeconds, and that the thresholds are positive`, containerProbeValues)
+	allChecks.RegisterPodCheck("Container Probe Ports", `Makes sure that the ports of the httpGet and tcpSocket probes of all containers are declared as ports of the container`, containerProbePorts)
+	allChecks.RegisterOptionalPodCheck("Container Probes Identical", `Makes sure that the livenessProbe and the readinessProbe of all containers are not identical, with the same httpGet request, tcpSocket port or exec command`, containerProbesIdentical)
+	allChecks.RegisterOptionalPodCheck("Container Startup Probe", `Makes sure that containers with a livenessProbe with a long initialDelaySeconds use a startupProbe instead`, containerStartupProbe)
+	allChecks.Document("pod-probes", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Add a readinessProbe to the pods that are targeted by a Service, and make sure that the livenessProbe is not identical to the readinessProbe",
+		URL:         "https://github.com/zegl/kube-score/blob/master/README_PROBES.md",
+		Details: "Inspects the readinessProbe and livenessProbe of all containers of the pod. " +
+			"Pods that are targeted by a Service must have a readinessProbe, and should have a livenessProbe. " +
+			"A container that has a readinessProbe and a livenessProbe with the same httpGet path and port, the same tcpSocket port, or the same exec command fails the check, also when the pod is not targeted by a Service. " +
+			"Jobs and CronJobs are not checked.",
+		Rationale: "Without a readinessProbe, traffic is sent to the pod before the application has started, and is still sent to it while it's shutting down. " +
+			"The readinessProbe is also used during rollouts, and stops a rollout of a version of the application that is failing. " +
+			"A livenessProbe that is identical to the readinessProbe restarts all containers when the application is temporarily unable to serve traffic, for example when a dependency is unavailable, which turns a partial outage into a full one.",
+		FailingExample: podProbesFailingExample,
+		PassingExample: podProbesPassingExample,
+	})
+	allChecks.Document("container-probe-values", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set timeoutSeconds to a lower value than periodSeconds, and successThreshold and failureThreshold to positive values",
+		URL:         "https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-startup-probes/#configure-probes",
+	})
+	allChecks.Document("container-probe-ports", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Change the port of the probe to a port that is declared in the ports of the container, or add the port to the container",
+		URL:         "https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-startup-probes/#use-a-named-port",
+	})
+	allChecks.Document("container-probes-identical", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Use a livenessProbe that only checks that the process is not deadlocked, and a readinessProbe that checks that it can serve traffic, or remove the livenessProbe",
+		URL:         "https://github.com/zegl/kube-score/blob/master/README_PROBES.md",
+		Details: "Compares the httpGet, tcpSocket and exec handlers of the livenessProbe and the readinessProbe of each container. " +
+			"Named ports are resolved to the ports of the container, and the timing and thresholds of the probes are not compared. " +
+			"Unlike pod-probes, all fields of the httpGet request are compared, including the host, scheme and headers.",
+		Rationale: "The readinessProbe stops sending traffic to a container that is temporarily unable to serve it, and the livenessProbe restarts a container that can not recover by itself. " +
+			"Identical probes can not tell the two apart, so a container that is only overloaded is restarted as well.",
+	})
+	allChecks.Document("container-startup-probe", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Add a startupProbe, and lower the initialDelaySeconds of the livenessProbe",
+		URL:         "https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-startup-probes/#define-startup-probes",
+	})
 }
 
+const podProbesFailingExample = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  selector:
+    app: web
+  ports:
+  - port: 80
+    targetPort: 8080
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: example/web:1.0.0
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: 8080
+        readinessProbe:
+          httpGet:
+            path: /healthz
+            port: 8080
+`
+
+const podProbesPassingExample = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  selector:
+    app: web
+  ports:
+  - port: 80
+    targetPort: 8080
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: example/web:1.0.0
+        livenessProbe:
+          httpGet:
+            path: /livez
+            port: 8080
+        readinessProbe:
+          httpGet:
+            path: /readyz
+            port: 8080
+`
+
+// startupProbeInitialDelaySeconds is the longest initialDelaySeconds of a livenessProbe that is accepted
+// without a startupProbe
+const startupProbeInitialDelaySeconds = 60
+
+// Default values of probes, used when the field is not set
+const (
+	defaultProbePeriodSeconds  = 10
+	defaultProbeTimeoutSeconds = 1
+)
+
 // containerProbes returns a function that checks if all probes are defined correctly in the Pod.
 // Only one probe of each type is required on the entire pod.
 // ReadinessProbes are not required if the pod is not targeted by a Service.
@@ -26,8 +157,7 @@ func containerProbes(allServices []ks.Service) func(corev1.PodTemplateSpec, meta
 			return score
 		}
 
-		allContainers := podTemplate.Spec.InitContainers
-		allContainers = append(allContainers, podTemplate.Spec.Containers...)
+		allContainers := internal.AllContainers(podTemplate.Spec)
 
 		hasReadinessProbe := false
 		hasLivenessProbe := false
@@ -140,3 +270,217 @@ func podIsTargetedByService(pod corev1.PodTemplateSpec, service corev1.Service)
 		pod.GetObjectMeta().GetLabels(),
 	)
 }
+
+// containerProbeValues checks that the numeric values of all probes are sane
+func containerProbeValues(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	allContainers := internal.AllContainers(podTemplate.Spec)
+
+	score.Grade = scorecard.GradeAllOK
+
+	for _, container := range allContainers {
+		for _, p := range []struct {
+			name  string
+			probe *corev1.Probe
+		}{
+			{"readinessProbe", container.ReadinessProbe},
+			{"livenessProbe", container.LivenessProbe},
+			{"startupProbe", container.StartupProbe},
+		} {
+			if p.probe == nil {
+				continue
+			}
+
+			period := p.probe.PeriodSeconds
+			if period == 0 {
+				period = defaultProbePeriodSeconds
+			}
+			timeout := p.probe.TimeoutSeconds
+			if timeout == 0 {
+				timeout = defaultProbeTimeoutSeconds
+			}
+
+			if timeout >= period {
+				score.Grade = scorecard.GradeWarning
+				score.AddCommentWithURL(container.Name,
+					fmt.Sprintf("The %s timeoutSeconds (%d) is not lower than periodSeconds (%d)", p.name, timeout, period),
+					"A probe that times out is not retried until the next period. Set timeoutSeconds to a lower value than periodSeconds",
+					"https://github.com/zegl/kube-score/blob/master/README_PROBES.md",
+				)
+			}
+
+			if p.probe.FailureThreshold < 0 {
+				score.Grade = scorecard.GradeWarning
+				score.AddCommentWithURL(container.Name,
+					fmt.Sprintf("The %s failureThreshold is %d", p.name, p.probe.FailureThreshold),
+					"Set failureThreshold to a value greater than 0",
+					"https://github.com/zegl/kube-score/blob/master/README_PROBES.md",
+				)
+			}
+
+			if p.probe.SuccessThreshold < 0 {
+				score.Grade = scorecard.GradeWarning
+				score.AddCommentWithURL(container.Name,
+					fmt.Sprintf("The %s successThreshold is %d", p.name, p.probe.SuccessThreshold),
+					"Set successThreshold to a value greater than 0",
+					"https://github.com/zegl/kube-score/blob/master/README_PROBES.md",
+				)
+			}
+		}
+	}
+
+	return
+}
+
+// containerStartupProbe recommends a startupProbe for containers that delay their livenessProbe for a long time
+func containerStartupProbe(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for _, container := range podTemplate.Spec.Containers {
+		if container.LivenessProbe == nil || container.StartupProbe != nil {
+			continue
+		}
+		if container.LivenessProbe.InitialDelaySeconds <= startupProbeInitialDelaySeconds {
+			continue
+		}
+
+		score.Grade = scorecard.GradeWarning
+		score.AddCommentWithURL(container.Name,
+			fmt.Sprintf("The livenessProbe has an initialDelaySeconds of %d, but no startupProbe is configured", container.LivenessProbe.InitialDelaySeconds),
+			"A long initialDelaySeconds delays the detection of deadlocks after every start. Configure a startupProbe for slow-starting containers, and lower the initialDelaySeconds of the livenessProbe",
+			"https://github.com/zegl/kube-score/blob/master/README_PROBES.md",
+		)
+	}
+
+	return
+}
+
+// containerProbePorts checks that the ports of the probes are declared by the container. Probes that use a named port
+// that is not declared always fail. Probes that use a numeric port are only reported if the container declares other
+// ports, as declaring the ports is optional.
+func containerProbePorts(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for _, container := range internal.AllContainers(podTemplate.Spec) {
+		probes := []struct {
+			name  string
+			probe *corev1.Probe
+		}{
+			{"readinessProbe", container.ReadinessProbe},
+			{"livenessProbe", container.LivenessProbe},
+			{"startupProbe", container.StartupProbe},
+		}
+
+		for _, p := range probes {
+			port, ok := probePort(p.probe)
+			if !ok {
+				continue
+			}
+
+			if port.Type == intstr.String {
+				if !hasNamedPort(container, port.StrVal) {
+					score.Grade = scorecard.GradeCritical
+					score.AddComment(container.Name, fmt.Sprintf("The %s uses the undeclared port %s", p.name, port.StrVal),
+						"Probes with a named port that is not declared in the ports of the container always fail. Fix the name of the port, or add it to the ports of the container")
+				}
+				continue
+			}
+
+			if len(container.Ports) > 0 && !hasPortNumber(container, port.IntVal) {
+				if score.Grade > scorecard.GradeWarning {
+					score.Grade = scorecard.GradeWarning
+				}
+				score.AddComment(container.Name, fmt.Sprintf("The %s uses the undeclared port %d", p.name, port.IntVal),
+					"The port of the probe is not one of the ports of the container. Check the port for typos, or add it to the ports of the container")
+			}
+		}
+	}
+
+	return
+}
+
+// probePort returns the port of a httpGet or tcpSocket probe
+func probePort(probe *corev1.Probe) (intstr.IntOrString, bool) {
+	switch {
+	case probe == nil:
+		return intstr.IntOrString{}, false
+	case probe.HTTPGet != nil:
+		return probe.HTTPGet.Port, true
+	case probe.TCPSocket != nil:
+		return probe.TCPSocket.Port, true
+	}
+	return intstr.IntOrString{}, false
+}
+
+func hasNamedPort(container corev1.Container, name string) bool {
+	for _, port := range container.Ports {
+		if port.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPortNumber(container corev1.Container, number int32) bool {
+	for _, port := range container.Ports {
+		if port.ContainerPort == number {
+			return true
+		}
+	}
+	return false
+}
+
+// containerProbesIdentical warns about containers with a livenessProbe and a readinessProbe with the same handler
+func containerProbesIdentical(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for _, container := range internal.AllContainers(podTemplate.Spec) {
+		if container.LivenessProbe == nil || container.ReadinessProbe == nil {
+			continue
+		}
+
+		liveness := resolvedProbeHandler(container, container.LivenessProbe.ProbeHandler)
+		readiness := resolvedProbeHandler(container, container.ReadinessProbe.ProbeHandler)
+		if !reflect.DeepEqual(liveness, readiness) {
+			continue
+		}
+
+		score.Grade = scorecard.GradeWarning
+		score.AddCommentWithURL(container.Name, "The livenessProbe and the readinessProbe are identical",
+			"A container that is temporarily unable to serve traffic is restarted instead of only being removed from the endpoints of its Services. Use a livenessProbe that only checks that the process is not deadlocked, or remove it",
+			"https://github.com/zegl/kube-score/blob/master/README_PROBES.md",
+		)
+	}
+
+	return
+}
+
+// resolvedProbeHandler returns a copy of the handler with named ports replaced by the numbers of the ports of the
+// container, so that probes that use the same port by name and by number are equal
+func resolvedProbeHandler(container corev1.Container, handler corev1.ProbeHandler) corev1.ProbeHandler {
+	resolve := func(port intstr.IntOrString) intstr.IntOrString {
+		if port.Type != intstr.String {
+			return port
+		}
+		for _, p := range container.Ports {
+			if p.Name == port.StrVal {
+				return intstr.FromInt(int(p.ContainerPort))
+			}
+		}
+		return port
+	}
+
+	if handler.HTTPGet != nil {
+		httpGet := *handler.HTTPGet
+		httpGet.Port = resolve(httpGet.Port)
+		if httpGet.Scheme == "" {
+			httpGet.Scheme = corev1.URISchemeHTTP
+		}
+		handler.HTTPGet = &httpGet
+	}
+	if handler.TCPSocket != nil {
+		tcpSocket := *handler.TCPSocket
+		tcpSocket.Port = resolve(tcpSocket.Port)
+		handler.TCPSocket = &tcpSocket
+	}
+	return handler
+}
@@ -0,0 +1,135 @@
+package pvc
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// defaultReadWriteOnceStorageClasses are the default StorageClasses of GKE, EKS and AKS, that are backed by block
+// storage and can't be mounted by multiple nodes
+var defaultReadWriteOnceStorageClasses = []string{
+	"standard", "standard-rwo", "premium-rwo",
+	"gp2", "gp3",
+	"default", "managed", "managed-csi", "managed-premium", "managed-csi-premium",
+}
+
+// betaStorageClassAnnotation is the deprecated annotation that was used before storageClassName was added
+const betaStorageClassAnnotation = "volume.beta.kubernetes.io/storage-class"
+
+func Register(allChecks *checks.Checks, cnf config.Configuration) {
+	readWriteOnceClasses := cnf.ReadWriteOnceStorageClasses
+	if len(readWriteOnceClasses) == 0 {
+		readWriteOnceClasses = defaultReadWriteOnceStorageClasses
+	}
+
+	allChecks.RegisterPersistentVolumeClaimCheck("PersistentVolumeClaim Storage Request", "Makes sure that the PersistentVolumeClaim requests storage", pvcStorageRequest)
+	allChecks.RegisterPersistentVolumeClaimCheck("PersistentVolumeClaim Access Modes", "Makes sure that the PersistentVolumeClaim has accessModes set", pvcAccessModes)
+	allChecks.RegisterPersistentVolumeClaimCheck("PersistentVolumeClaim Storage Class", "Makes sure that the PersistentVolumeClaim has storageClassName set, and does not rely on the default StorageClass of the cluster", pvcStorageClass)
+	allChecks.RegisterOptionalPersistentVolumeClaimCheck("PersistentVolumeClaim ReadWriteMany", "Makes sure that PersistentVolumeClaims with the ReadWriteMany access mode do not use a StorageClass that only supports ReadWriteOnce. The StorageClasses can be changed with --read-write-once-storage-class", pvcReadWriteMany(readWriteOnceClasses))
+
+	allChecks.Document("persistentvolumeclaim-storage-request", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set resources.requests.storage to the size of the volume",
+		URL:         "https://kubernetes.io/docs/concepts/storage/persistent-volumes/#persistentvolumeclaims",
+	})
+	allChecks.Document("persistentvolumeclaim-access-modes", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set accessModes, for example to ReadWriteOnce",
+		URL:         "https://kubernetes.io/docs/concepts/storage/persistent-volumes/#access-modes",
+	})
+	allChecks.Document("persistentvolumeclaim-storage-class", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set storageClassName to the StorageClass that the volume should be provisioned with, or to an empty string to bind to a pre-provisioned volume without a class",
+		URL:         "https://kubernetes.io/docs/concepts/storage/persistent-volumes/#class-1",
+	})
+	allChecks.Document("persistentvolumeclaim-readwritemany", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Use a StorageClass that supports ReadWriteMany, such as one backed by NFS, or change the access mode to ReadWriteOnce",
+		URL:         "https://kubernetes.io/docs/concepts/storage/persistent-volumes/#access-modes",
+	})
+}
+
+func pvcStorageRequest(claim corev1.PersistentVolumeClaim) (score scorecard.TestScore) {
+	if storage, ok := claim.Spec.Resources.Requests[corev1.ResourceStorage]; ok && !storage.IsZero() {
+		score.Grade = scorecard.GradeAllOK
+		return
+	}
+
+	score.Grade = scorecard.GradeCritical
+	score.AddComment("", "The PersistentVolumeClaim does not request any storage", "Set resources.requests.storage to the size of the volume")
+	return
+}
+
+func pvcAccessModes(claim corev1.PersistentVolumeClaim) (score scorecard.TestScore) {
+	if len(claim.Spec.AccessModes) > 0 {
+		score.Grade = scorecard.GradeAllOK
+		return
+	}
+
+	score.Grade = scorecard.GradeCritical
+	score.AddComment("", "The PersistentVolumeClaim does not have any accessModes", "Set accessModes to how the volume is mounted, for example ReadWriteOnce if it's only used by a single node")
+	return
+}
+
+func pvcStorageClass(claim corev1.PersistentVolumeClaim) (score scorecard.TestScore) {
+	if _, ok := storageClassName(claim); ok {
+		score.Grade = scorecard.GradeAllOK
+		return
+	}
+
+	score.Grade = scorecard.GradeWarning
+	score.AddComment("", "The PersistentVolumeClaim does not have a storageClassName",
+		"The default StorageClass of the cluster is used, which can be different between clusters, and can be changed after the volume is created. Set storageClassName explicitly")
+	return
+}
+
+func pvcReadWriteMany(readWriteOnceClasses []string) func(corev1.PersistentVolumeClaim) scorecard.TestScore {
+	readWriteOnce := make(map[string]struct{}, len(readWriteOnceClasses))
+	for _, class := range readWriteOnceClasses {
+		readWriteOnce[class] = struct{}{}
+	}
+
+	return func(claim corev1.PersistentVolumeClaim) (score scorecard.TestScore) {
+		score.Grade = scorecard.GradeAllOK
+
+		if !hasAccessMode(claim, corev1.ReadWriteMany) {
+			return
+		}
+
+		// Claims without a StorageClass are reported by the persistentvolumeclaim-storage-class check
+		class, ok := storageClassName(claim)
+		if !ok {
+			return
+		}
+		if _, ok := readWriteOnce[class]; ok {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("", fmt.Sprintf("The StorageClass %s does not support ReadWriteMany", class),
+				"The volume can't be mounted by pods on multiple nodes, and the PersistentVolumeClaim will not be bound. Use a StorageClass that supports ReadWriteMany, or use ReadWriteOnce")
+		}
+		return
+	}
+}
+
+// storageClassName returns the StorageClass of the claim, from storageClassName or from the deprecated annotation.
+// ok is false if the StorageClass is not set, and the default StorageClass of the cluster is used.
+func storageClassName(claim corev1.PersistentVolumeClaim) (class string, ok bool) {
+	if claim.Spec.StorageClassName != nil {
+		return *claim.Spec.StorageClassName, true
+	}
+	class, ok = claim.Annotations[betaStorageClassAnnotation]
+	return
+}
+
+func hasAccessMode(claim corev1.PersistentVolumeClaim, mode corev1.PersistentVolumeAccessMode) bool {
+	for _, m := range claim.Spec.AccessModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestPersistentVolumeClaimConfigured(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pvc-configured.yaml", "PersistentVolumeClaim Storage Request", scorecard.GradeAllOK)
+	testExpectedScore(t, "pvc-configured.yaml", "PersistentVolumeClaim Access Modes", scorecard.GradeAllOK)
+	testExpectedScore(t, "pvc-configured.yaml", "PersistentVolumeClaim Storage Class", scorecard.GradeAllOK)
+}
+
+func TestPersistentVolumeClaimNotConfigured(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pvc-not-configured.yaml", "PersistentVolumeClaim Storage Request", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The PersistentVolumeClaim does not request any storage", comments[0].Summary)
+
+	comments = testExpectedScore(t, "pvc-not-configured.yaml", "PersistentVolumeClaim Access Modes", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The PersistentVolumeClaim does not have any accessModes", comments[0].Summary)
+
+	comments = testExpectedScore(t, "pvc-not-configured.yaml", "PersistentVolumeClaim Storage Class", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The PersistentVolumeClaim does not have a storageClassName", comments[0].Summary)
+}
+
+func TestPersistentVolumeClaimReadWriteMany(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pvc-configured.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"persistentvolumeclaim-readwritemany": {}},
+	}, "PersistentVolumeClaim ReadWriteMany", scorecard.GradeAllOK)
+
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pvc-readwritemany-block-storage.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"persistentvolumeclaim-readwritemany": {}},
+	}, "PersistentVolumeClaim ReadWriteMany", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The StorageClass gp3 does not support ReadWriteMany", comments[0].Summary)
+}
+
+func TestPersistentVolumeClaimReadWriteManyConfiguredStorageClasses(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:                    []ks.NamedReader{testFile("pvc-readwritemany-block-storage.yaml")},
+		EnabledOptionalTests:        map[string]struct{}{"persistentvolumeclaim-readwritemany": {}},
+		ReadWriteOnceStorageClasses: []string{"local-path"},
+	}, "PersistentVolumeClaim ReadWriteMany", scorecard.GradeAllOK)
+
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:                    []ks.NamedReader{testFile("pvc-configured.yaml")},
+		EnabledOptionalTests:        map[string]struct{}{"persistentvolumeclaim-readwritemany": {}},
+		ReadWriteOnceStorageClasses: []string{"nfs"},
+	}, "PersistentVolumeClaim ReadWriteMany", scorecard.GradeWarning)
+}
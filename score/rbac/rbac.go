@@ -0,0 +1,134 @@
+package rbac
+
+import (
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks) {
+	allChecks.RegisterRoleCheck("Role Wildcard Permissions", `Makes sure that Roles and ClusterRoles do not use wildcards in verbs, resources or apiGroups`, roleWildcardPermissions)
+	allChecks.RegisterRoleCheck("Role Privilege Escalation Permissions", `Makes sure that Roles and ClusterRoles do not grant the escalate, bind or impersonate verbs`, rolePrivilegeEscalationPermissions)
+	allChecks.RegisterRoleBindingCheck("RoleBinding Cluster Admin", `Makes sure that RoleBindings and ClusterRoleBindings do not bind to the cluster-admin ClusterRole`, roleBindingClusterAdmin)
+	allChecks.RegisterRoleBindingCheck("RoleBinding Default Service Account", `Makes sure that RoleBindings and ClusterRoleBindings do not grant permissions to the default ServiceAccount`, roleBindingDefaultServiceAccount)
+	allChecks.Document("role-wildcard-permissions", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Replace the wildcards with the verbs, resources and apiGroups that are needed",
+		URL:         "https://kubernetes.io/docs/concepts/security/rbac-good-practices/",
+	})
+	allChecks.Document("role-privilege-escalation-permissions", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Remove the escalate, bind and impersonate verbs from the rules of the Role",
+		URL:         "https://kubernetes.io/docs/concepts/security/rbac-good-practices/#escalate-verb",
+	})
+	allChecks.Document("rolebinding-cluster-admin", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Bind to a Role or ClusterRole with only the permissions that are needed",
+		URL:         "https://kubernetes.io/docs/concepts/security/rbac-good-practices/",
+	})
+	allChecks.Document("rolebinding-default-service-account", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Create a ServiceAccount for the workload, and bind the Role to it instead",
+		URL:         "https://kubernetes.io/docs/concepts/security/rbac-good-practices/",
+	})
+}
+
+// escalationVerbs are the verbs that allow a subject to gain more permissions than it has been granted
+var escalationVerbs = []string{"escalate", "bind", "impersonate"}
+
+func roleWildcardPermissions(role ks.Role) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for i, rule := range role.Rules() {
+		for _, field := range []struct {
+			name   string
+			values []string
+		}{
+			{"verbs", rule.Verbs},
+			{"resources", rule.Resources},
+			{"apiGroups", rule.APIGroups},
+		} {
+			if !contains(field.values, rbacv1.VerbAll) {
+				continue
+			}
+			score.AddComment("",
+				fmt.Sprintf("rules[%d] has a wildcard in %s", i, field.name),
+				"Wildcards grant access to everything, including resources and verbs that are added in the future. List the required "+field.name+" explicitly")
+			if score.Grade > scorecard.GradeHigh {
+				score.Grade = scorecard.GradeHigh
+			}
+		}
+
+		// A rule with wildcards in both verbs and resources grants the same access as cluster-admin
+		if contains(rule.Verbs, rbacv1.VerbAll) && contains(rule.Resources, rbacv1.ResourceAll) {
+			score.Grade = scorecard.GradeCritical
+		}
+	}
+
+	return
+}
+
+func rolePrivilegeEscalationPermissions(role ks.Role) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for i, rule := range role.Rules() {
+		for _, verb := range escalationVerbs {
+			if !contains(rule.Verbs, verb) {
+				continue
+			}
+			score.AddComment("",
+				fmt.Sprintf("rules[%d] grants the %s verb", i, verb),
+				"The escalate, bind and impersonate verbs allow the subject to gain permissions that it has not been granted. Remove the verb from the rule")
+			score.Grade = scorecard.GradeCritical
+		}
+	}
+
+	return
+}
+
+func roleBindingClusterAdmin(binding ks.RoleBinding) (score scorecard.TestScore) {
+	ref := binding.RoleRef()
+	if ref.Kind == "ClusterRole" && ref.Name == "cluster-admin" {
+		score.AddComment("", "The binding grants the cluster-admin ClusterRole",
+			"cluster-admin grants full control over the cluster. Create a Role or ClusterRole with only the permissions that are needed")
+		score.Grade = scorecard.GradeCritical
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func roleBindingDefaultServiceAccount(binding ks.RoleBinding) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for _, subject := range binding.Subjects() {
+		if subject.Kind != rbacv1.ServiceAccountKind || subject.Name != "default" {
+			continue
+		}
+
+		summary := "The binding grants permissions to the default ServiceAccount"
+		if subject.Namespace != "" {
+			summary = fmt.Sprintf("%s in the namespace %s", summary, subject.Namespace)
+		}
+
+		score.AddComment("", summary,
+			"The default ServiceAccount is used by all pods that do not set a ServiceAccount. Create a dedicated ServiceAccount and bind the permissions to it")
+		score.Grade = scorecard.GradeWarning
+	}
+
+	return
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestRoleWildcardPermissions(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "rbac-role-wildcard.yaml", "Role Wildcard Permissions", scorecard.GradeCritical)
+	assert.Len(t, comments, 3)
+	assert.Equal(t, "rules[0] has a wildcard in verbs", comments[0].Summary)
+	assert.Equal(t, "rules[0] has a wildcard in resources", comments[1].Summary)
+	assert.Equal(t, "rules[0] has a wildcard in apiGroups", comments[2].Summary)
+}
+
+func TestRoleWildcardPermissionsResources(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "rbac-role-wildcard-resources.yaml", "Role Wildcard Permissions", scorecard.GradeHigh)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "rules[0] has a wildcard in resources", comments[0].Summary)
+}
+
+func TestRoleWildcardPermissionsOK(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "rbac-role-ok.yaml", "Role Wildcard Permissions", scorecard.GradeAllOK)
+}
+
+func TestRolePrivilegeEscalationPermissions(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "rbac-role-escalate.yaml", "Role Privilege Escalation Permissions", scorecard.GradeCritical)
+	assert.Len(t, comments, 3)
+	assert.Equal(t, "rules[0] grants the escalate verb", comments[0].Summary)
+	assert.Equal(t, "rules[0] grants the bind verb", comments[1].Summary)
+	assert.Equal(t, "rules[1] grants the impersonate verb", comments[2].Summary)
+}
+
+func TestRolePrivilegeEscalationPermissionsOK(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "rbac-role-ok.yaml", "Role Privilege Escalation Permissions", scorecard.GradeAllOK)
+}
+
+func TestRoleBindingClusterAdmin(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "rbac-clusterrolebinding-cluster-admin.yaml", "RoleBinding Cluster Admin", scorecard.GradeCritical)
+}
+
+func TestRoleBindingClusterAdminOK(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "rbac-rolebinding-ok.yaml", "RoleBinding Cluster Admin", scorecard.GradeAllOK)
+}
+
+func TestRoleBindingDefaultServiceAccount(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "rbac-rolebinding-default-service-account.yaml", "RoleBinding Default Service Account", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The binding grants permissions to the default ServiceAccount", comments[0].Summary)
+}
+
+func TestClusterRoleBindingDefaultServiceAccount(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "rbac-clusterrolebinding-cluster-admin.yaml", "RoleBinding Default Service Account", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The binding grants permissions to the default ServiceAccount in the namespace foo", comments[0].Summary)
+}
+
+func TestRoleBindingDefaultServiceAccountOK(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "rbac-rolebinding-ok.yaml", "RoleBinding Default Service Account", scorecard.GradeAllOK)
+}
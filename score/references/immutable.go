@@ -0,0 +1,179 @@
+package references
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/internal"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// maxConfigSize is the largest total size of the data in a ConfigMap or Secret that the API server accepts
+const maxConfigSize = 1024 * 1024
+
+// largeConfigSize is the total size of the data in a ConfigMap or Secret above which it is considered large. Large
+// objects are sent in full to all watchers, such as the kubelets of the nodes that run pods that use them, on every
+// change.
+const largeConfigSize = maxConfigSize / 2
+
+// versionSuffix matches names that end with a version, such as "-v2" or "-1.2.3", or with the hash suffix of the
+// ConfigMap and Secret generators of Kustomize
+var versionSuffix = regexp.MustCompile(`-(v?[0-9]+(\.[0-9]+)*|[bcdfghjklmnpqrstvwxz2456789]{10})$`)
+
+// workloadReferences returns the kind and name of the pods and workloads that use each ConfigMap and Secret
+func workloadReferences(pods []ks.Pod, podspecers []ks.PodSpecer) map[provided][]string {
+	res := make(map[provided][]string)
+	add := func(kind, name, namespace string, spec corev1.PodSpec) {
+		seen := make(map[provided]struct{})
+		for _, ref := range podSpecReferences(spec) {
+			ref.namespace = namespace
+			if _, ok := seen[ref]; ok {
+				continue
+			}
+			seen[ref] = struct{}{}
+			res[ref] = append(res[ref], kind+"/"+name)
+		}
+	}
+	for _, pod := range pods {
+		add("Pod", pod.Pod().Name, pod.Pod().Namespace, pod.Pod().Spec)
+	}
+	for _, podspecer := range podspecers {
+		template := podspecer.GetPodTemplateSpec()
+		add(podspecer.GetTypeMeta().Kind, podspecer.GetObjectMeta().Name, podspecer.GetObjectMeta().Namespace, template.Spec)
+	}
+	return res
+}
+
+// podSpecReferences returns the ConfigMaps and Secrets that the pod uses in env, envFrom and volumes, without their
+// namespace
+func podSpecReferences(spec corev1.PodSpec) []provided {
+	var res []provided
+	for _, container := range internal.AllContainers(spec) {
+		for _, envFrom := range container.EnvFrom {
+			if ref := envFrom.ConfigMapRef; ref != nil {
+				res = append(res, provided{kind: "ConfigMap", name: ref.Name})
+			}
+			if ref := envFrom.SecretRef; ref != nil {
+				res = append(res, provided{kind: "Secret", name: ref.Name})
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				res = append(res, provided{kind: "ConfigMap", name: ref.Name})
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				res = append(res, provided{kind: "Secret", name: ref.Name})
+			}
+		}
+	}
+	for _, volume := range spec.Volumes {
+		if cm := volume.ConfigMap; cm != nil {
+			res = append(res, provided{kind: "ConfigMap", name: cm.Name})
+		}
+		if secret := volume.Secret; secret != nil {
+			res = append(res, provided{kind: "Secret", name: secret.SecretName})
+		}
+		if volume.Projected == nil {
+			continue
+		}
+		for _, source := range volume.Projected.Sources {
+			if cm := source.ConfigMap; cm != nil {
+				res = append(res, provided{kind: "ConfigMap", name: cm.Name})
+			}
+			if secret := source.Secret; secret != nil {
+				res = append(res, provided{kind: "Secret", name: secret.Name})
+			}
+		}
+	}
+	return res
+}
+
+// configImmutable recommends immutable on ConfigMaps and Secrets with a version suffix, as a new object is created
+// for every change, and on the ConfigMaps and Secrets that are used by a single workload, as they can be replaced
+// together with the workload
+func configImmutable(references map[provided][]string) func(ks.Object) scorecard.TestScore {
+	return func(o ks.Object) (score scorecard.TestScore) {
+		kind := o.GetTypeMeta().Kind
+		meta := o.GetObjectMeta()
+
+		if immutable, _ := o.Unstructured()["immutable"].(bool); immutable {
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+		if secretType, _ := o.Unstructured()["type"].(string); secretType == string(corev1.SecretTypeServiceAccountToken) {
+			score.Skipped = true
+			score.AddComment("", "Skipped because the Secret is a service account token", "")
+			return
+		}
+
+		if versionSuffix.MatchString(meta.Name) {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("", fmt.Sprintf("The %s has a version suffix, but is not immutable", kind),
+				fmt.Sprintf("A new %s is created for every change, set immutable to true to protect it from accidental updates, and to stop the kubelets from watching it for changes", kind))
+			return
+		}
+
+		users := references[provided{kind, meta.Namespace, meta.Name}]
+		if len(users) != 1 {
+			score.Skipped = true
+			score.AddComment("", fmt.Sprintf("Skipped because the %s is used by %d workloads", kind, len(users)), "")
+			return
+		}
+
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", fmt.Sprintf("The %s is only used by %s, but is not immutable", kind, users[0]),
+			fmt.Sprintf("Changes to a mutable %s are not rolled out to the pods in a controlled way. Set immutable to true, and create a %s with a new name when the data changes, such as with a generator of Kustomize", kind, kind))
+		return
+	}
+}
+
+// configSize checks that the total size of the data in a ConfigMap or Secret is not larger than the API server
+// accepts, and warns if it's large
+func configSize(o ks.Object) (score scorecard.TestScore) {
+	kind := o.GetTypeMeta().Kind
+
+	// The data of Secrets and the binaryData of ConfigMaps are base64 encoded. The comments point to the field with
+	// the most data.
+	var size, largest int
+	var largestField string
+	for _, field := range []string{"data", "binaryData", "stringData"} {
+		encoded := field == "binaryData" || field == "data" && kind == "Secret"
+		data, _ := o.Unstructured()[field].(map[string]interface{})
+		var fieldSize int
+		for _, v := range data {
+			value, _ := v.(string)
+			if encoded {
+				if decoded, err := base64.StdEncoding.DecodeString(value); err == nil {
+					fieldSize += len(decoded)
+					continue
+				}
+			}
+			fieldSize += len(value)
+		}
+		size += fieldSize
+		if fieldSize > largest {
+			largest, largestField = fieldSize, field
+		}
+	}
+
+	switch {
+	case size > maxConfigSize:
+		score.Grade = scorecard.GradeCritical
+		score.AddComment(largestField, fmt.Sprintf("The data of the %s is %d KiB, larger than the limit of %d KiB", kind, size/1024, maxConfigSize/1024),
+			fmt.Sprintf("The API server rejects a %s with more than 1 MiB of data. Split the data into multiple objects, or mount it from a volume", kind))
+	case size > largeConfigSize:
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(largestField, fmt.Sprintf("The data of the %s is %d KiB", kind, size/1024),
+			fmt.Sprintf("The %s is sent in full to every watcher, such as the kubelets of the nodes of the pods that use it, on every change. Split the data into multiple objects, or mount it from a volume", kind))
+	default:
+		score.Grade = scorecard.GradeAllOK
+	}
+	return
+}
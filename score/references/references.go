@@ -0,0 +1,166 @@
+// Package references checks that the ConfigMaps and Secrets that are used by pods are a part of the scored objects
+package references
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks, objects ks.Objects, pods ks.Pods, podspecers ks.PodSpeccers) {
+	allChecks.RegisterOptionalPodCheck("Pod ConfigMap And Secret References", `Makes sure that all ConfigMaps and Secrets that are used by the pod, in env, envFrom and volumes, are a part of the scored objects, unless they are optional`, podReferences(providedObjects(objects.Objects())))
+	allChecks.RegisterOptionalObjectCheck("configmap-secret-immutable", "ConfigMap And Secret Immutable", `Makes sure that ConfigMaps and Secrets that have a version suffix in their name, or that are only used by a single workload, are immutable`, []string{"ConfigMap", "Secret"}, configImmutable(workloadReferences(pods.Pods(), podspecers.PodSpeccers())))
+	allChecks.RegisterOptionalObjectCheck("configmap-secret-size", "ConfigMap And Secret Size", `Makes sure that the data of ConfigMaps and Secrets is not larger than the 1 MiB that the API server accepts, and warns if it's larger than 512 KiB`, []string{"ConfigMap", "Secret"}, configSize)
+	allChecks.MarkCrossObject("pod-configmap-and-secret-references")
+	allChecks.Document("pod-configmap-and-secret-references", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Fix the name of the ConfigMap or Secret, add it to the scored files, or set optional to true if the pod can run without it",
+		URL:         "https://kubernetes.io/docs/concepts/configuration/configmap/#optional-configmaps",
+	})
+	allChecks.Document("configmap-secret-immutable", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set immutable to true, and create a new ConfigMap or Secret with a new name when the data changes",
+		URL:         "https://kubernetes.io/docs/concepts/configuration/configmap/#configmap-immutable",
+	})
+	allChecks.Document("configmap-secret-size", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Split the data into multiple ConfigMaps or Secrets, or mount it from a volume",
+		URL:         "https://kubernetes.io/docs/concepts/configuration/configmap/#motivation",
+	})
+}
+
+// provided is a ConfigMap or a Secret in the scored objects
+type provided struct {
+	kind, namespace, name string
+}
+
+// providedObjects returns the ConfigMaps and Secrets in the objects, with their keys. The keys are nil if they are
+// unknown, which is the case for Secrets that are created from SealedSecrets and ExternalSecrets.
+func providedObjects(objects []ks.Object) map[provided]map[string]struct{} {
+	res := make(map[provided]map[string]struct{})
+	for _, o := range objects {
+		meta := o.GetObjectMeta()
+		switch o.GetTypeMeta().Kind {
+		case "ConfigMap":
+			res[provided{"ConfigMap", meta.Namespace, meta.Name}] = dataKeys(o.Unstructured(), "data", "binaryData")
+		case "Secret":
+			res[provided{"Secret", meta.Namespace, meta.Name}] = dataKeys(o.Unstructured(), "data", "stringData")
+		case "SealedSecret":
+			res[provided{"Secret", meta.Namespace, meta.Name}] = nil
+		case "ExternalSecret":
+			name := meta.Name
+			if spec, ok := o.Unstructured()["spec"].(map[string]interface{}); ok {
+				if target, ok := spec["target"].(map[string]interface{}); ok {
+					if n, ok := target["name"].(string); ok && n != "" {
+						name = n
+					}
+				}
+			}
+			res[provided{"Secret", meta.Namespace, name}] = nil
+		}
+	}
+	return res
+}
+
+func dataKeys(object map[string]interface{}, fields ...string) map[string]struct{} {
+	keys := make(map[string]struct{})
+	for _, field := range fields {
+		data, _ := object[field].(map[string]interface{})
+		for key := range data {
+			keys[key] = struct{}{}
+		}
+	}
+	return keys
+}
+
+// podReferences checks that the ConfigMaps and Secrets that the pod uses are provided, and that they have the keys
+// that are used
+func podReferences(allProvided map[provided]map[string]struct{}) func(corev1.PodTemplateSpec, metav1.TypeMeta) scorecard.TestScore {
+	return func(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+		score.Grade = scorecard.GradeAllOK
+
+		// check adds a comment to the path if the ConfigMap or Secret, or one of the keys in it, is missing
+		check := func(path, kind, name string, optional *bool, keys ...string) {
+			if optional != nil && *optional {
+				return
+			}
+
+			providedKeys, ok := allProvided[provided{kind, podTemplate.Namespace, name}]
+			if !ok {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment(path, fmt.Sprintf("The %s %s is not found", kind, name),
+					fmt.Sprintf("The pod can't start if the %s does not exist. Check the name for typos, add the %s to the scored files, or set optional to true if the pod can run without it", kind, kind))
+				return
+			}
+
+			if providedKeys == nil {
+				return
+			}
+			for _, key := range keys {
+				if _, ok := providedKeys[key]; !ok {
+					score.Grade = scorecard.GradeCritical
+					score.AddComment(path, fmt.Sprintf("The key %s is not found in the %s %s", key, kind, name),
+						fmt.Sprintf("The pod can't start if the key does not exist. Check the key for typos, or add it to the %s", kind))
+				}
+			}
+		}
+
+		for _, container := range internal.AllContainers(podTemplate.Spec) {
+			for _, envFrom := range container.EnvFrom {
+				if ref := envFrom.ConfigMapRef; ref != nil {
+					check(container.Name, "ConfigMap", ref.Name, ref.Optional)
+				}
+				if ref := envFrom.SecretRef; ref != nil {
+					check(container.Name, "Secret", ref.Name, ref.Optional)
+				}
+			}
+			for _, env := range container.Env {
+				if env.ValueFrom == nil {
+					continue
+				}
+				if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+					check(container.Name, "ConfigMap", ref.Name, ref.Optional, ref.Key)
+				}
+				if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+					check(container.Name, "Secret", ref.Name, ref.Optional, ref.Key)
+				}
+			}
+		}
+
+		for _, volume := range podTemplate.Spec.Volumes {
+			if cm := volume.ConfigMap; cm != nil {
+				check(volume.Name, "ConfigMap", cm.Name, cm.Optional, itemKeys(cm.Items)...)
+			}
+			if secret := volume.Secret; secret != nil {
+				check(volume.Name, "Secret", secret.SecretName, secret.Optional, itemKeys(secret.Items)...)
+			}
+			if volume.Projected == nil {
+				continue
+			}
+			for _, source := range volume.Projected.Sources {
+				if cm := source.ConfigMap; cm != nil {
+					check(volume.Name, "ConfigMap", cm.Name, cm.Optional, itemKeys(cm.Items)...)
+				}
+				if secret := source.Secret; secret != nil {
+					check(volume.Name, "Secret", secret.Name, secret.Optional, itemKeys(secret.Items)...)
+				}
+			}
+		}
+
+		return
+	}
+}
+
+func itemKeys(items []corev1.KeyToPath) []string {
+	var keys []string
+	for _, item := range items {
+		keys = append(keys, item.Key)
+	}
+	return keys
+}
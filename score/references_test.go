@@ -0,0 +1,98 @@
+package score
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestPodConfigMapAndSecretReferences(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("deployment-configmap-and-secret-references.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-configmap-and-secret-references": {}},
+	}, "Pod ConfigMap And Secret References", scorecard.GradeCritical)
+
+	var summaries []string
+	for _, c := range comments {
+		summaries = append(summaries, c.Path+": "+c.Summary)
+	}
+	assert.Equal(t, []string{
+		"app: The ConfigMap other-namespace is not found",
+		"app: The key pasword is not found in the Secret db",
+		"certs: The Secret certs is not found",
+	}, summaries)
+}
+
+func TestPodConfigMapAndSecretReferencesAllGood(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-probes-all-missing.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-configmap-and-secret-references": {}},
+	}, "Pod ConfigMap And Secret References", scorecard.GradeAllOK)
+}
+
+func TestConfigMapAndSecretImmutable(t *testing.T) {
+	t.Parallel()
+	s, err := testScore(config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("configmap-immutable.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"configmap-secret-immutable": {}},
+	})
+	assert.Nil(t, err)
+
+	summaries := make(map[string]string)
+	for _, o := range s {
+		for _, c := range o.Checks {
+			if c.Check.ID != "configmap-secret-immutable" {
+				continue
+			}
+			summary := "skipped"
+			if !c.Skipped {
+				summary = c.Grade.String()
+			}
+			if len(c.Comments) > 0 {
+				summary += ": " + c.Comments[0].Summary
+			}
+			summaries[o.ObjectMeta.Name] = summary
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"app-config":             "WARNING: The ConfigMap is only used by Deployment/app, but is not immutable",
+		"shared":                 "skipped: Skipped because the ConfigMap is used by 2 workloads",
+		"settings-v2":            "WARNING: The ConfigMap has a version suffix, but is not immutable",
+		"credentials-5tmc2h9gk7": "OK",
+	}, summaries)
+}
+
+func TestConfigMapAndSecretSize(t *testing.T) {
+	t.Parallel()
+	configMap := func(size int) ks.NamedReader {
+		return unnamedReader{strings.NewReader(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: large
+binaryData:
+  data.bin: ` + base64.StdEncoding.EncodeToString(make([]byte, size)) + `
+`)}
+	}
+	cnf := func(size int) config.Configuration {
+		return config.Configuration{
+			AllFiles:             []ks.NamedReader{configMap(size)},
+			EnabledOptionalTests: map[string]struct{}{"configmap-secret-size": {}},
+		}
+	}
+
+	testExpectedScoreWithConfig(t, cnf(1024), "ConfigMap And Secret Size", scorecard.GradeAllOK)
+	comments := testExpectedScoreWithConfig(t, cnf(600*1024), "ConfigMap And Secret Size", scorecard.GradeWarning)
+	assert.Equal(t, "The data of the ConfigMap is 600 KiB", comments[0].Summary)
+	comments = testExpectedScoreWithConfig(t, cnf(2*1024*1024), "ConfigMap And Secret Size", scorecard.GradeCritical)
+	assert.Equal(t, "binaryData", comments[0].Path)
+	assert.Equal(t, "The data of the ConfigMap is 2048 KiB, larger than the limit of 1024 KiB", comments[0].Summary)
+}
@@ -0,0 +1,82 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func testRemediations(t *testing.T, filename, testcase string) []*scorecard.Remediation {
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile(filename)},
+		KubernetesVersion: config.Semver{1, 18},
+	})
+	assert.NoError(t, err)
+
+	var res []*scorecard.Remediation
+	for _, objectScore := range sc {
+		for _, s := range objectScore.Checks {
+			if s.Check.Name != testcase {
+				continue
+			}
+			for _, c := range s.Comments {
+				res = append(res, c.Remediation)
+			}
+		}
+	}
+	return res
+}
+
+func TestRemediationContainerSecurityContext(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []*scorecard.Remediation{{
+		JSONPatch: []scorecard.JSONPatchOperation{
+			{Op: "add", Path: "/spec/containers/0/securityContext/privileged", Value: false},
+		},
+		StrategicMergePatch: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "foobar", "securityContext": map[string]interface{}{"privileged": false}},
+				},
+			},
+		},
+	}}, testRemediations(t, "pod-security-context-privileged.yaml", "Container Security Context Privileged"))
+}
+
+func TestRemediationContainerWithoutSecurityContext(t *testing.T) {
+	t.Parallel()
+	remediations := testRemediations(t, "pod-security-context-nosecuritycontext.yaml", "Container Security Context Privilege Escalation")
+	assert.Len(t, remediations, 1)
+	assert.Equal(t, []scorecard.JSONPatchOperation{
+		{Op: "add", Path: "/spec/containers/0/securityContext", Value: map[string]interface{}{"allowPrivilegeEscalation": false}},
+	}, remediations[0].JSONPatch)
+}
+
+func TestRemediationCronJob(t *testing.T) {
+	t.Parallel()
+	remediations := testRemediations(t, "cronjob-privilege-escalation-allowed.yaml", "Container Security Context Privilege Escalation")
+	assert.Len(t, remediations, 1)
+	assert.Equal(t, []scorecard.JSONPatchOperation{
+		{Op: "add", Path: "/spec/jobTemplate/spec/template/spec/containers/0/securityContext/allowPrivilegeEscalation", Value: false},
+	}, remediations[0].JSONPatch)
+}
+
+func TestRemediationPodSpec(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []*scorecard.Remediation{{
+		JSONPatch: []scorecard.JSONPatchOperation{
+			{Op: "add", Path: "/spec/template/spec/hostNetwork", Value: false},
+		},
+		StrategicMergePatch: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{"hostNetwork": false},
+				},
+			},
+		},
+	}}, testRemediations(t, "deployment-pod-security-standard-privileged.yaml", "Pod Host Network"))
+}
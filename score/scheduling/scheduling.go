@@ -0,0 +1,219 @@
+// Package scheduling checks the fields of the pod spec that control how pods are scheduled and evicted
+package scheduling
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// deprecatedNodeLabels are the deprecated well-known labels of nodes, and the labels that replace them
+var deprecatedNodeLabels = map[string]string{
+	"beta.kubernetes.io/arch":                  "kubernetes.io/arch",
+	"beta.kubernetes.io/os":                    "kubernetes.io/os",
+	"beta.kubernetes.io/instance-type":         "node.kubernetes.io/instance-type",
+	"failure-domain.beta.kubernetes.io/region": "topology.kubernetes.io/region",
+	"failure-domain.beta.kubernetes.io/zone":   "topology.kubernetes.io/zone",
+}
+
+// builtinPriorityClasses are the PriorityClasses that exist in all clusters
+var builtinPriorityClasses = []string{"system-cluster-critical", "system-node-critical"}
+
+func Register(allChecks *checks.Checks, cnf config.Configuration, objects ks.Objects) {
+	allChecks.RegisterOptionalPodCheck("Pod Priority Class", `Makes sure that all pods have a priorityClassName set, that it's one of the classes allowed with --allowed-priority-class, and that the PriorityClass is a part of the scored objects if any PriorityClasses are scored`, podPriorityClass(cnf.AllowedPriorityClasses, providedPriorityClasses(objects.Objects())))
+	allChecks.RegisterPodCheck("Pod Tolerations", `Makes sure that pods, other than DaemonSets, do not tolerate all taints, and that tolerations of NoExecute taints have tolerationSeconds set`, podTolerations)
+	allChecks.RegisterPodCheck("Pod Deprecated Node Labels", `Makes sure that the nodeSelector and affinity of pods do not use deprecated node labels, such as beta.kubernetes.io/os`, podDeprecatedNodeLabels)
+	allChecks.Document("pod-tolerations", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set key on all tolerations with the Exists operator, and set tolerationSeconds on tolerations of NoExecute taints",
+		URL:         "https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration/",
+	})
+	allChecks.Document("pod-deprecated-node-labels", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Replace the deprecated labels with the labels that replace them, such as kubernetes.io/os instead of beta.kubernetes.io/os",
+		URL:         "https://kubernetes.io/docs/reference/labels-annotations-taints/",
+	})
+	allChecks.Document("pod-priority-class", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set priorityClassName to a PriorityClass that exists in the cluster, and that is allowed with --allowed-priority-class",
+		URL:         "https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/",
+	})
+}
+
+// priorityClasses are the PriorityClasses in the scored objects
+type priorityClasses struct {
+	// names is nil if there are no PriorityClasses in the scored objects, and they can't be checked
+	names map[string]struct{}
+
+	// globalDefault is the name of the PriorityClass that is used by pods without a priorityClassName, or empty
+	globalDefault string
+}
+
+func providedPriorityClasses(objects []ks.Object) priorityClasses {
+	var res priorityClasses
+	for _, o := range objects {
+		if o.GetTypeMeta().Kind != "PriorityClass" {
+			continue
+		}
+		if res.names == nil {
+			res.names = make(map[string]struct{})
+			for _, name := range builtinPriorityClasses {
+				res.names[name] = struct{}{}
+			}
+		}
+		name := o.GetObjectMeta().Name
+		res.names[name] = struct{}{}
+		if globalDefault, _ := o.Unstructured()["globalDefault"].(bool); globalDefault {
+			res.globalDefault = name
+		}
+	}
+	return res
+}
+
+func podPriorityClass(allowed []string, provided priorityClasses) func(corev1.PodTemplateSpec, metav1.TypeMeta) scorecard.TestScore {
+	allowedNames := make(map[string]struct{}, len(allowed))
+	for _, name := range allowed {
+		allowedNames[name] = struct{}{}
+	}
+
+	return func(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+		name := podTemplate.Spec.PriorityClassName
+		if name == "" {
+			name = provided.globalDefault
+		}
+
+		if name == "" {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("", "The pod does not have a priorityClassName",
+				"Pods without a PriorityClass have the lowest priority, and are the first to be preempted when the cluster is out of resources. Set priorityClassName to a class that matches how critical the workload is")
+			return
+		}
+
+		if provided.names != nil {
+			if _, ok := provided.names[name]; !ok {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment("", fmt.Sprintf("The PriorityClass %s is not found", name),
+					"Pods with a PriorityClass that does not exist are rejected. Check the name for typos, or add the PriorityClass to the scored files")
+				return
+			}
+		}
+
+		if len(allowedNames) > 0 {
+			if _, ok := allowedNames[name]; !ok {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment("", fmt.Sprintf("The PriorityClass %s is not allowed", name),
+					fmt.Sprintf("Use one of the allowed PriorityClasses: %s", strings.Join(allowed, ", ")))
+				return
+			}
+		}
+
+		score.Grade = scorecard.GradeAllOK
+		return
+	}
+}
+
+// podTolerations checks that pods do not tolerate all taints, and that they are evicted from nodes with NoExecute
+// taints after some time
+func podTolerations(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	for _, toleration := range podTemplate.Spec.Tolerations {
+		// DaemonSets run on all nodes, and commonly tolerate all taints
+		if toleration.Key == "" && toleration.Operator == corev1.TolerationOpExists && typeMeta.Kind != "DaemonSet" {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("", "The pod tolerates all taints",
+				"A toleration with the Exists operator and no key tolerates all taints, and the pod can be scheduled on nodes that are reserved for other workloads, or that are not ready. Set key to the taint that should be tolerated")
+			continue
+		}
+
+		if toleration.Effect == corev1.TaintEffectNoExecute && toleration.TolerationSeconds == nil {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(toleration.Key, "The toleration of a NoExecute taint has no tolerationSeconds",
+				"The pod is never evicted from nodes with the taint, such as nodes that are not ready or unreachable. Set tolerationSeconds to how long the pod can stay on the node")
+		}
+	}
+
+	return
+}
+
+// podDeprecatedNodeLabels checks that the nodeSelector, node affinity and pod affinity of the pod do not use
+// deprecated node labels
+func podDeprecatedNodeLabels(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	seen := make(map[string]struct{})
+	check := func(key string) {
+		replacement, ok := deprecatedNodeLabels[key]
+		if !ok {
+			return
+		}
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(key, fmt.Sprintf("The node label %s is deprecated", key), fmt.Sprintf("Use %s instead", replacement))
+	}
+
+	spec := podTemplate.Spec
+	var nodeSelectorKeys []string
+	for key := range spec.NodeSelector {
+		nodeSelectorKeys = append(nodeSelectorKeys, key)
+	}
+	sort.Strings(nodeSelectorKeys)
+	for _, key := range nodeSelectorKeys {
+		check(key)
+	}
+
+	if spec.Affinity != nil && spec.Affinity.NodeAffinity != nil {
+		var terms []corev1.NodeSelectorTerm
+		if required := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			terms = append(terms, required.NodeSelectorTerms...)
+		}
+		for _, preferred := range spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, preferred.Preference)
+		}
+		for _, term := range terms {
+			for _, expression := range term.MatchExpressions {
+				check(expression.Key)
+			}
+		}
+	}
+
+	if spec.Affinity != nil {
+		for _, term := range podAffinityTerms(spec.Affinity) {
+			check(term.TopologyKey)
+		}
+	}
+	for _, constraint := range spec.TopologySpreadConstraints {
+		check(constraint.TopologyKey)
+	}
+
+	return
+}
+
+// podAffinityTerms returns all terms of the podAffinity and podAntiAffinity
+func podAffinityTerms(affinity *corev1.Affinity) []corev1.PodAffinityTerm {
+	var terms []corev1.PodAffinityTerm
+	if affinity.PodAffinity != nil {
+		terms = append(terms, affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+		for _, weighted := range affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, weighted.PodAffinityTerm)
+		}
+	}
+	if affinity.PodAntiAffinity != nil {
+		terms = append(terms, affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+		for _, weighted := range affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, weighted.PodAffinityTerm)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,111 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestPodPriorityClass(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-priority-class.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-priority-class": {}},
+	}, "Pod Priority Class", scorecard.GradeAllOK)
+}
+
+func TestPodPriorityClassNotSet(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-probes-all-missing.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-priority-class": {}},
+	}, "Pod Priority Class", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The pod does not have a priorityClassName", comments[0].Summary)
+}
+
+func TestPodPriorityClassGlobalDefault(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-priority-class-global-default.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-priority-class": {}},
+	}, "Pod Priority Class", scorecard.GradeAllOK)
+}
+
+func TestPodPriorityClassNotFound(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-priority-class-missing.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-priority-class": {}},
+	}, "Pod Priority Class", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The PriorityClass high-priorty is not found", comments[0].Summary)
+}
+
+func TestPodPriorityClassNotAllowed(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:               []ks.NamedReader{testFile("pod-priority-class.yaml")},
+		EnabledOptionalTests:   map[string]struct{}{"pod-priority-class": {}},
+		AllowedPriorityClasses: []string{"production", "batch"},
+	}, "Pod Priority Class", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The PriorityClass high-priority is not allowed", comments[0].Summary)
+}
+
+func TestPodTolerations(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-scheduling-valid.yaml", "Pod Tolerations", scorecard.GradeAllOK)
+}
+
+func TestPodTolerationsDaemonSet(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("pod-scheduling-valid.yaml")},
+	})
+	assert.NoError(t, err)
+	for _, o := range sc {
+		if o.TypeMeta.Kind != "DaemonSet" {
+			continue
+		}
+		for _, c := range o.Checks {
+			if c.Check.Name == "Pod Tolerations" {
+				assert.Equal(t, scorecard.GradeAllOK, c.Grade)
+				return
+			}
+		}
+	}
+	t.Error("Was not tested")
+}
+
+func TestPodTolerationsInvalid(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-scheduling-invalid.yaml", "Pod Tolerations", scorecard.GradeWarning)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "The pod tolerates all taints", comments[0].Summary)
+	assert.Equal(t, "node.kubernetes.io/unreachable", comments[1].Path)
+	assert.Equal(t, "The toleration of a NoExecute taint has no tolerationSeconds", comments[1].Summary)
+}
+
+func TestPodDeprecatedNodeLabels(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-scheduling-valid.yaml", "Pod Deprecated Node Labels", scorecard.GradeAllOK)
+}
+
+func TestPodDeprecatedNodeLabelsInvalid(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-scheduling-invalid.yaml", "Pod Deprecated Node Labels", scorecard.GradeWarning)
+	var summaries []string
+	for _, c := range comments {
+		summaries = append(summaries, c.Summary)
+	}
+	assert.Equal(t, []string{
+		"The node label beta.kubernetes.io/os is deprecated",
+		"The node label beta.kubernetes.io/instance-type is deprecated",
+		"The node label failure-domain.beta.kubernetes.io/zone is deprecated",
+	}, summaries)
+}
@@ -1,20 +1,42 @@
 package score
 
 import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
 	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/internal/logger"
 	"github.com/zegl/kube-score/score/apps"
 	"github.com/zegl/kube-score/score/checks"
 	"github.com/zegl/kube-score/score/container"
 	"github.com/zegl/kube-score/score/cronjob"
+	"github.com/zegl/kube-score/score/custom"
 	"github.com/zegl/kube-score/score/disruptionbudget"
+	"github.com/zegl/kube-score/score/dns"
+	"github.com/zegl/kube-score/score/duplicates"
+	"github.com/zegl/kube-score/score/gateway"
 	"github.com/zegl/kube-score/score/hpa"
 	"github.com/zegl/kube-score/score/ingress"
+	"github.com/zegl/kube-score/score/job"
+	"github.com/zegl/kube-score/score/lifecycle"
 	"github.com/zegl/kube-score/score/meta"
+	"github.com/zegl/kube-score/score/monitoring"
 	"github.com/zegl/kube-score/score/networkpolicy"
+	"github.com/zegl/kube-score/score/plugin"
+	"github.com/zegl/kube-score/score/podsecurity"
 	"github.com/zegl/kube-score/score/probes"
+	"github.com/zegl/kube-score/score/pvc"
+	"github.com/zegl/kube-score/score/rbac"
+	"github.com/zegl/kube-score/score/references"
+	"github.com/zegl/kube-score/score/scheduling"
 	"github.com/zegl/kube-score/score/security"
 	"github.com/zegl/kube-score/score/service"
+	"github.com/zegl/kube-score/score/sidecar"
 	"github.com/zegl/kube-score/score/stable"
 	"github.com/zegl/kube-score/scorecard"
 
@@ -25,121 +47,584 @@ import (
 func RegisterAllChecks(allObjects ks.AllTypes, cnf config.Configuration) *checks.Checks {
 	allChecks := checks.New(cnf)
 
-	ingress.Register(allChecks, allObjects)
-	cronjob.Register(allChecks)
+	ingress.Register(allChecks, allObjects, cnf.KubernetesVersion)
+	gateway.Register(allChecks, allObjects)
+	cronjob.Register(allChecks, cnf.KubernetesVersion)
+	job.Register(allChecks)
 	container.Register(allChecks, cnf)
-	disruptionbudget.Register(allChecks, allObjects)
+	disruptionbudget.Register(allChecks, allObjects, allObjects, allObjects)
 	networkpolicy.Register(allChecks, allObjects, allObjects, allObjects)
 	probes.Register(allChecks, allObjects)
-	security.Register(allChecks)
-	service.Register(allChecks, allObjects, allObjects)
+	lifecycle.Register(allChecks, allObjects)
+	security.Register(allChecks, cnf, allObjects)
+	sidecar.Register(allChecks, cnf)
+	references.Register(allChecks, allObjects, allObjects, allObjects)
+	scheduling.Register(allChecks, cnf, allObjects)
+	dns.Register(allChecks, allObjects)
+	monitoring.Register(allChecks, allObjects, allObjects, allObjects)
+	duplicates.Register(allChecks, allObjects)
+	podsecurity.Register(allChecks, cnf)
+	service.Register(allChecks, allObjects, allObjects, allObjects)
 	stable.Register(cnf.KubernetesVersion, allChecks)
 	apps.Register(allChecks, allObjects.HorizontalPodAutoscalers(), allObjects.Services())
-	meta.Register(allChecks)
-	hpa.Register(allChecks, allObjects.Metas())
+	meta.Register(allChecks, cnf)
+	hpa.Register(allChecks, allObjects.Metas(), allObjects.PodSpeccers())
+	rbac.Register(allChecks)
+	pvc.Register(allChecks, cnf)
+	custom.Register(allChecks, cnf.CustomChecks)
+	plugin.Register(allChecks, cnf.Plugins)
 
 	return allChecks
 }
 
 // Score runs a pre-configured list of tests against the files defined in the configuration, and returns a scorecard.
-// Additional configuration and tuning parameters can be provided via the config.
-func Score(allObjects ks.AllTypes, cnf config.Configuration) (*scorecard.Scorecard, error) {
+// Additional configuration and tuning parameters can be provided via the config. Scoring is stopped, and the error of
+// the context is returned, if the context is done.
+func Score(ctx context.Context, allObjects ks.AllTypes, cnf config.Configuration) (*scorecard.Scorecard, error) {
+	scoreCard := scorecard.New()
+	err := ScoreEach(ctx, allObjects, cnf, func(key string, o *scorecard.ScoredObject) error {
+		scoreCard[key] = o
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &scoreCard, nil
+}
+
+// ObjectFunc is called with each scored object, and the key of the object in the scorecard. Scoring is stopped if it
+// returns an error.
+type ObjectFunc func(key string, o *scorecard.ScoredObject) error
+
+// ScoreEach scores the objects in the same way as Score, but calls fn with each object as soon as all checks have been
+// run on it, instead of returning a scorecard. It's used by the output formats that are written while the objects are
+// scored, so that the scorecard is never held in memory. The objects are passed to fn in an order that depends on
+// when their checks finish, and fn is called from the goroutine that called ScoreEach.
+func ScoreEach(ctx context.Context, allObjects ks.AllTypes, cnf config.Configuration, fn ObjectFunc) error {
 	allChecks := RegisterAllChecks(allObjects, cnf)
 	scoreCard := scorecard.New()
+	scoredKind := kindFilter(cnf.IgnoredKinds, cnf.OnlyKinds)
 
+	// newObject returns nil for the objects of the kinds that are not scored, and no checks are run on them
 	newObject := func(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) *scorecard.ScoredObject {
+		if !scoredKind(typeMeta.Kind) {
+			return nil
+		}
 		return scoreCard.NewObject(typeMeta, objectMeta, cnf.UseIgnoreChecksAnnotation)
 	}
 
+	// The objects are created, and the checks are collected, in a fixed order. The checks are then run concurrently,
+	// and the results are added to the objects in the same order as they were collected.
+	var tasks []scoreTask
+	add := func(o *scorecard.ScoredObject, check ks.Check, locationer ks.FileLocationer, fn func() (scorecard.TestScore, error)) {
+		if o == nil {
+			return
+		}
+		tasks = append(tasks, scoreTask{object: o, check: check, locationer: locationer, fn: fn})
+	}
+
 	for _, ingress := range allObjects.Ingresses() {
+		ingress := ingress
 		o := newObject(ingress.GetTypeMeta(), ingress.GetObjectMeta())
 		for _, test := range allChecks.Ingresses() {
-			o.Add(test.Fn(ingress), test.Check, ingress)
+			test := test
+			add(o, test.Check, ingress, func() (scorecard.TestScore, error) {
+				return test.Fn(ingress), nil
+			})
 		}
 	}
 
 	for _, meta := range allObjects.Metas() {
+		meta := meta
 		o := newObject(meta.TypeMeta, meta.ObjectMeta)
 		for _, test := range allChecks.Metas() {
-			o.Add(test.Fn(meta), test.Check, meta)
+			test := test
+			add(o, test.Check, meta, func() (scorecard.TestScore, error) {
+				return test.Fn(meta), nil
+			})
 		}
 	}
 
 	for _, pod := range allObjects.Pods() {
+		pod := pod
 		o := newObject(pod.Pod().TypeMeta, pod.Pod().ObjectMeta)
 		for _, test := range allChecks.Pods() {
-			score := test.Fn(corev1.PodTemplateSpec{
-				ObjectMeta: pod.Pod().ObjectMeta,
-				Spec:       pod.Pod().Spec,
-			}, pod.Pod().TypeMeta)
-			o.Add(score, test.Check, pod)
+			test := test
+			add(o, test.Check, pod, func() (scorecard.TestScore, error) {
+				return test.Fn(corev1.PodTemplateSpec{
+					ObjectMeta: pod.Pod().ObjectMeta,
+					Spec:       pod.Pod().Spec,
+				}, pod.Pod().TypeMeta), nil
+			})
 		}
 	}
 
 	for _, podspecer := range allObjects.PodSpeccers() {
+		podspecer := podspecer
 		o := newObject(podspecer.GetTypeMeta(), podspecer.GetObjectMeta())
 		for _, test := range allChecks.Pods() {
-			score := test.Fn(podspecer.GetPodTemplateSpec(), podspecer.GetTypeMeta())
-			o.Add(score, test.Check, podspecer)
+			test := test
+			add(o, test.Check, podspecer, func() (scorecard.TestScore, error) {
+				return test.Fn(podspecer.GetPodTemplateSpec(), podspecer.GetTypeMeta()), nil
+			})
 		}
 	}
 
 	for _, service := range allObjects.Services() {
+		service := service
 		o := newObject(service.Service().TypeMeta, service.Service().ObjectMeta)
 		for _, test := range allChecks.Services() {
-			o.Add(test.Fn(service.Service()), test.Check, service)
+			test := test
+			add(o, test.Check, service, func() (scorecard.TestScore, error) {
+				return test.Fn(service.Service()), nil
+			})
 		}
 	}
 
 	for _, statefulset := range allObjects.StatefulSets() {
+		statefulset := statefulset
 		o := newObject(statefulset.StatefulSet().TypeMeta, statefulset.StatefulSet().ObjectMeta)
 		for _, test := range allChecks.StatefulSets() {
-			res, err := test.Fn(statefulset.StatefulSet())
-			if err != nil {
-				return nil, err
-			}
-			o.Add(res, test.Check, statefulset)
+			test := test
+			add(o, test.Check, statefulset, func() (scorecard.TestScore, error) {
+				return test.Fn(statefulset.StatefulSet())
+			})
 		}
 	}
 
 	for _, deployment := range allObjects.Deployments() {
+		deployment := deployment
 		o := newObject(deployment.Deployment().TypeMeta, deployment.Deployment().ObjectMeta)
 		for _, test := range allChecks.Deployments() {
-			res, err := test.Fn(deployment.Deployment())
-			if err != nil {
-				return nil, err
-			}
-			o.Add(res, test.Check, deployment)
+			test := test
+			add(o, test.Check, deployment, func() (scorecard.TestScore, error) {
+				return test.Fn(deployment.Deployment())
+			})
+		}
+	}
+
+	for _, job := range allObjects.Jobs() {
+		job := job
+		o := newObject(job.Job().TypeMeta, job.Job().ObjectMeta)
+		for _, test := range allChecks.Jobs() {
+			test := test
+			add(o, test.Check, job, func() (scorecard.TestScore, error) {
+				return test.Fn(job.Job()), nil
+			})
 		}
 	}
 
 	for _, netpol := range allObjects.NetworkPolicies() {
+		netpol := netpol
 		o := newObject(netpol.NetworkPolicy().TypeMeta, netpol.NetworkPolicy().ObjectMeta)
 		for _, test := range allChecks.NetworkPolicies() {
-			o.Add(test.Fn(netpol.NetworkPolicy()), test.Check, netpol)
+			test := test
+			add(o, test.Check, netpol, func() (scorecard.TestScore, error) {
+				return test.Fn(netpol.NetworkPolicy()), nil
+			})
 		}
 	}
 
 	for _, cjob := range allObjects.CronJobs() {
+		cjob := cjob
 		o := newObject(cjob.GetTypeMeta(), cjob.GetObjectMeta())
 		for _, test := range allChecks.CronJobs() {
-			o.Add(test.Fn(cjob), test.Check, cjob)
+			test := test
+			add(o, test.Check, cjob, func() (scorecard.TestScore, error) {
+				return test.Fn(cjob), nil
+			})
 		}
 	}
 
 	for _, hpa := range allObjects.HorizontalPodAutoscalers() {
+		hpa := hpa
 		o := newObject(hpa.GetTypeMeta(), hpa.GetObjectMeta())
 		for _, test := range allChecks.HorizontalPodAutoscalers() {
-			o.Add(test.Fn(hpa), test.Check, hpa)
+			test := test
+			add(o, test.Check, hpa, func() (scorecard.TestScore, error) {
+				return test.Fn(hpa), nil
+			})
 		}
 	}
 
 	for _, pdb := range allObjects.PodDisruptionBudgets() {
+		pdb := pdb
 		o := newObject(pdb.GetTypeMeta(), pdb.GetObjectMeta())
 		for _, test := range allChecks.PodDisruptionBudgets() {
-			o.Add(test.Fn(pdb), test.Check, pdb)
+			test := test
+			add(o, test.Check, pdb, func() (scorecard.TestScore, error) {
+				return test.Fn(pdb), nil
+			})
 		}
 	}
 
-	return &scoreCard, nil
+	for _, role := range allObjects.Roles() {
+		role := role
+		o := newObject(role.GetTypeMeta(), role.GetObjectMeta())
+		for _, test := range allChecks.Roles() {
+			test := test
+			add(o, test.Check, role, func() (scorecard.TestScore, error) {
+				return test.Fn(role), nil
+			})
+		}
+	}
+
+	for _, binding := range allObjects.RoleBindings() {
+		binding := binding
+		o := newObject(binding.GetTypeMeta(), binding.GetObjectMeta())
+		for _, test := range allChecks.RoleBindings() {
+			test := test
+			add(o, test.Check, binding, func() (scorecard.TestScore, error) {
+				return test.Fn(binding), nil
+			})
+		}
+	}
+
+	for _, gateway := range allObjects.Gateways() {
+		gateway := gateway
+		o := newObject(gateway.GetTypeMeta(), gateway.GetObjectMeta())
+		for _, test := range allChecks.Gateways() {
+			test := test
+			add(o, test.Check, gateway, func() (scorecard.TestScore, error) {
+				return test.Fn(gateway), nil
+			})
+		}
+	}
+
+	for _, route := range allObjects.HTTPRoutes() {
+		route := route
+		o := newObject(route.GetTypeMeta(), route.GetObjectMeta())
+		for _, test := range allChecks.HTTPRoutes() {
+			test := test
+			add(o, test.Check, route, func() (scorecard.TestScore, error) {
+				return test.Fn(route), nil
+			})
+		}
+	}
+
+	for _, claim := range allObjects.PersistentVolumeClaims() {
+		claim := claim
+		o := newObject(claim.PersistentVolumeClaim().TypeMeta, claim.PersistentVolumeClaim().ObjectMeta)
+		for _, test := range allChecks.PersistentVolumeClaims() {
+			test := test
+			add(o, test.Check, claim, func() (scorecard.TestScore, error) {
+				return test.Fn(claim.PersistentVolumeClaim()), nil
+			})
+		}
+	}
+
+	// Objects are only added to the scorecard if a check is run on them, as all objects are parsed
+	for _, object := range allObjects.Objects() {
+		object := object
+		var o *scorecard.ScoredObject
+		for _, test := range allChecks.Objects() {
+			test := test
+			if !test.Matches(object.GetTypeMeta().Kind) {
+				continue
+			}
+			if o == nil {
+				o = newObject(object.GetTypeMeta(), object.GetObjectMeta())
+			}
+			add(o, test.Check, object, func() (scorecard.TestScore, error) {
+				return test.Fn(object), nil
+			})
+		}
+	}
+
+	if err := addUnknownObjects(allObjects.UnknownObjects(), allChecks, cnf.UnknownKinds, scoredKind, newObject, add); err != nil {
+		return err
+	}
+
+	// The tasks of each object, in the order that they were collected
+	objectTasks := make(map[*scorecard.ScoredObject][]int)
+	for i, task := range tasks {
+		objectTasks[task.object] = append(objectTasks[task.object], i)
+	}
+
+	// finish adds the results of the checks to the object once all of them have been run, and passes it to fn
+	debug := logger.Default().Enabled(logger.LevelDebug)
+	results := make([]scorecard.TestScore, len(tasks))
+	finish := func(key string, o *scorecard.ScoredObject) error {
+		for _, i := range objectTasks[o] {
+			o.Add(results[i], tasks[i].check, tasks[i].locationer)
+			if debug {
+				logFailedCheck(o)
+			}
+			// The results are not needed anymore, and are released together with the object
+			results[i] = scorecard.TestScore{}
+		}
+		delete(objectTasks, o)
+		delete(scoreCard, key)
+
+		// The checks are registered in maps, sort them to make the output deterministic
+		sort.SliceStable(o.Checks, func(i, j int) bool {
+			return o.Checks[i].Check.ID < o.Checks[j].Check.ID
+		})
+
+		single := scorecard.Scorecard{key: o}
+		applyIgnoreRules(single, cnf.IgnoreRules)
+		applyGradeOverrides(single, cnf.GradeOverrides)
+		if _, ok := single[key]; !ok {
+			return nil
+		}
+		return fn(key, o)
+	}
+
+	keys := make(map[*scorecard.ScoredObject]string)
+	pending := make(map[*scorecard.ScoredObject]int)
+	var keysWithoutTasks []string
+	for key, o := range scoreCard {
+		keys[o] = key
+		pending[o] = len(objectTasks[o])
+		if pending[o] == 0 {
+			keysWithoutTasks = append(keysWithoutTasks, key)
+		}
+	}
+
+	// Objects without any enabled checks are finished first
+	sort.Strings(keysWithoutTasks)
+	for _, key := range keysWithoutTasks {
+		if err := finish(key, scoreCard[key]); err != nil {
+			return err
+		}
+	}
+
+	return runTasks(ctx, tasks, cnf.Parallelism, func(i int, result scorecard.TestScore) error {
+		results[i] = result
+		o := tasks[i].object
+		pending[o]--
+		if pending[o] > 0 {
+			return nil
+		}
+		key := keys[o]
+		delete(pending, o)
+		delete(keys, o)
+		return finish(key, o)
+	})
+}
+
+// unknownKindCheck is reported as skipped on the objects of unknown kinds that no checks other than the meta checks are
+// run on
+var unknownKindCheck = checks.NewCheck("Unknown Kind", "all", "Reports the objects of kinds that kube-score does not support, that only the metadata of is checked", false)
+
+// addUnknownObjects handles the objects of unknown kinds that no checks are run on, as configured by mode. With
+// UnknownKindsWarn, the objects are added to the scorecard with a skipped check, so that it's visible that they have
+// not been inspected. The objects of the kinds that are not scored are left out.
+func addUnknownObjects(
+	objects []ks.Object,
+	allChecks *checks.Checks,
+	mode string,
+	scoredKind func(kind string) bool,
+	newObject func(metav1.TypeMeta, metav1.ObjectMeta) *scorecard.ScoredObject,
+	add func(*scorecard.ScoredObject, ks.Check, ks.FileLocationer, func() (scorecard.TestScore, error)),
+) error {
+	if mode == config.UnknownKindsSkip {
+		return nil
+	}
+
+	// Checks without kinds, such as custom checks and the checks of plugins that don't set any kinds, are run on all
+	// objects, and don't make a kind supported
+	hasCheck := func(kind string) bool {
+		for _, check := range allChecks.Objects() {
+			if len(check.Kinds) > 0 && check.Matches(kind) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var kinds []string
+	seenKinds := make(map[string]struct{})
+	for _, object := range objects {
+		typeMeta := object.GetTypeMeta()
+		if typeMeta.Kind == "" || !scoredKind(typeMeta.Kind) || hasCheck(typeMeta.Kind) {
+			continue
+		}
+
+		if _, ok := seenKinds[typeMeta.Kind]; !ok {
+			seenKinds[typeMeta.Kind] = struct{}{}
+			kinds = append(kinds, typeMeta.Kind)
+		}
+
+		if mode == config.UnknownKindsFail {
+			continue
+		}
+		summary := fmt.Sprintf("Skipped because the kind %s is not supported, only the metadata of the object is checked", typeMeta.Kind)
+		add(newObject(typeMeta, object.GetObjectMeta()), unknownKindCheck, object, func() (scorecard.TestScore, error) {
+			return scorecard.TestScore{Skipped: true, Comments: []scorecard.TestScoreComment{{Summary: summary}}}, nil
+		})
+	}
+
+	if len(kinds) == 0 {
+		return nil
+	}
+	if mode == config.UnknownKindsFail {
+		return fmt.Errorf("objects of unsupported kinds can not be scored: %s", strings.Join(kinds, ", "))
+	}
+	logger.Warn("Objects of unsupported kinds are not scored", "kinds", kinds)
+	return nil
+}
+
+// scoreTask is a check that is run on an object
+type scoreTask struct {
+	object     *scorecard.ScoredObject
+	check      ks.Check
+	locationer ks.FileLocationer
+	fn         func() (scorecard.TestScore, error)
+}
+
+// runTasks runs the tasks on a pool of parallelism workers, or one worker per CPU if parallelism is not positive.
+// done is called with the index and the result of each task as soon as it has been run, from the goroutine that
+// called runTasks. If any of the tasks fails, or done returns an error, no more tasks are started, and the error of
+// the first failed task is returned. No more tasks are started once the context is done, and the error of the
+// context is returned.
+func runTasks(ctx context.Context, tasks []scoreTask, parallelism int, done func(i int, result scorecard.TestScore) error) error {
+	if parallelism < 1 {
+		parallelism = runtime.NumCPU()
+	}
+
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+	stopTasks := func() {
+		stopOnce.Do(func() { close(stop) })
+	}
+
+	type taskResult struct {
+		i      int
+		result scorecard.TestScore
+		err    error
+	}
+	indexes := make(chan int)
+	results := make(chan taskResult)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result, err := tasks[i].fn()
+				results <- taskResult{i: i, result: result, err: err}
+			}
+		}()
+	}
+	go func() {
+		defer close(indexes)
+		for i := range tasks {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			case <-stop:
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// The tasks are started in order, so the tasks before a failed task have all been run when the results are
+	// drained, and the error of the task with the lowest index is returned
+	errs := make(map[int]error)
+	firstErr := -1
+	for r := range results {
+		err := r.err
+		if err == nil && len(errs) == 0 && ctx.Err() == nil {
+			err = done(r.i, r.result)
+		}
+		if err != nil {
+			errs[r.i] = err
+			if firstErr < 0 || r.i < firstErr {
+				firstErr = r.i
+			}
+			stopTasks()
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if firstErr >= 0 {
+		return errs[firstErr]
+	}
+	return nil
+}
+
+// logFailedCheck writes a debug message with the comments of the last check of the object, if it failed
+func logFailedCheck(o *scorecard.ScoredObject) {
+	c := o.Checks[len(o.Checks)-1]
+	if c.Skipped || c.Grade >= scorecard.GradeAllOK {
+		return
+	}
+	var comments []string
+	for _, comment := range c.Comments {
+		comments = append(comments, comment.Summary)
+	}
+	logger.Debug("Check failed", "object", o.HumanFriendlyRef(), "check", c.Check.ID, "grade", c.Grade.String(), "comments", comments)
+}
+
+// kindFilter returns a function that returns true if the objects of a kind are scored, which they are unless the kind
+// is one of the ignored kinds, or there are only kinds and the kind is not one of them. The objects of the other kinds
+// are still used by the checks of other objects, such as services that target pods.
+func kindFilter(ignoredKinds, onlyKinds []string) func(kind string) bool {
+	hasKind := func(kinds []string, kind string) bool {
+		for _, k := range kinds {
+			if strings.EqualFold(k, kind) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(kind string) bool {
+		return !hasKind(ignoredKinds, kind) && (len(onlyKinds) == 0 || hasKind(onlyKinds, kind))
+	}
+}
+
+// applyIgnoreRules skips all checks that are ignored by a rule for the path or the namespace of the object
+func applyIgnoreRules(scoreCard scorecard.Scorecard, rules []config.IgnoreRule) {
+	if len(rules) == 0 {
+		return
+	}
+
+	for _, o := range scoreCard {
+		for i, c := range o.Checks {
+			if c.Skipped {
+				continue
+			}
+			for _, rule := range rules {
+				if rule.Matches(c.Check.ID, o.FileLocation.Name, o.ObjectMeta.Namespace) {
+					o.Checks[i].Skipped = true
+					o.Checks[i].Comments = []scorecard.TestScoreComment{{Summary: fmt.Sprintf("Skipped because %s is ignored in this path or namespace", c.Check.ID)}}
+					break
+				}
+			}
+		}
+	}
+}
+
+// applyGradeOverrides changes the grade of all failing checks that have a configured grade override. Checks that have
+// been downgraded on the object with the kube-score/downgrade annotation are not changed.
+func applyGradeOverrides(scoreCard scorecard.Scorecard, overrides map[string]scorecard.Grade) {
+	if len(overrides) == 0 {
+		return
+	}
+
+	// The overrides of renamed checks are applied with the current ID
+	current := make(map[string]scorecard.Grade)
+	for id, grade := range overrides {
+		current[scorecard.CheckID(id)] = grade
+	}
+	overrides = current
+
+	for _, o := range scoreCard {
+		for i, c := range o.Checks {
+			grade, ok := overrides[c.Check.ID]
+			if !ok || c.Skipped || c.Grade >= scorecard.GradeAllOK || o.IsDowngraded(c.Check.ID) {
+				continue
+			}
+			o.Checks[i].Grade = grade
+		}
+	}
 }
@@ -1,16 +1,23 @@
 package score
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score/checks"
 	"github.com/zegl/kube-score/scorecard"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func testFile(name string) *os.File {
@@ -31,7 +38,16 @@ func testExpectedScoreWithConfig(t *testing.T, config config.Configuration, test
 		for _, s := range objectScore.Checks {
 			if s.Check.Name == testcase {
 				assert.Equal(t, expectedScore, s.Grade)
-				return s.Comments
+
+				// The locations and remediations of the comments are tested in filelocation_test.go and
+				// remediation_test.go
+				var comments []scorecard.TestScoreComment
+				for _, c := range s.Comments {
+					c.FileLocation = ks.FileLocation{}
+					c.Remediation = nil
+					comments = append(comments, c)
+				}
+				return comments
 			}
 		}
 	}
@@ -41,12 +57,12 @@ func testExpectedScoreWithConfig(t *testing.T, config config.Configuration, test
 }
 
 func testScore(config config.Configuration) (scorecard.Scorecard, error) {
-	parsed, err := parser.ParseFiles(config)
+	parsed, err := parser.ParseFiles(context.Background(), config)
 	if err != nil {
 		return nil, err
 	}
 
-	card, err := Score(parsed, config)
+	card, err := Score(context.Background(), parsed, config)
 	if err != nil {
 		return nil, err
 	}
@@ -109,6 +125,16 @@ func TestPodContainerResourceNoLimitRequired(t *testing.T) {
 	}, "Container Resources", scorecard.GradeAllOK)
 }
 
+func TestPodContainerResourcesInitContainer(t *testing.T) {
+	t.Parallel()
+	// Ephemeral containers can not set resources, and are not checked
+	comments := testExpectedScore(t, "pod-init-and-ephemeral-containers.yaml", "Container Resources", scorecard.GradeCritical)
+	assert.Len(t, comments, 4)
+	for _, c := range comments {
+		assert.Equal(t, "migrate", c.Path)
+	}
+}
+
 func TestPodContainerResourceRequestsEqualLimits(t *testing.T) {
 	t.Parallel()
 
@@ -181,6 +207,114 @@ func TestPodContainerCPURequestsEqualLimitsNoLimits(t *testing.T) {
 	}, "Container CPU Requests Equal Limits", scorecard.GradeCritical)
 }
 
+func TestPodContainerMemoryLimitRatio(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-test-resources-limits-and-requests.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-memory-limit-ratio": {}},
+	}, "Container Memory Limit Ratio", scorecard.GradeAllOK)
+}
+
+func TestPodContainerMemoryLimitRatioTooHigh(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-memory-limit-ratio.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-memory-limit-ratio": {}},
+	}, "Container Memory Limit Ratio", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Memory limit is 4.0 times the memory request", comments[0].Summary)
+}
+
+func TestPodContainerMemoryLimitRatioConfigured(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-memory-limit-ratio.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-memory-limit-ratio": {}},
+		MaxMemoryLimitRatio:  4,
+	}, "Container Memory Limit Ratio", scorecard.GradeAllOK)
+}
+
+func TestPodContainerEphemeralStorage(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-ephemeral-storage.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-ephemeral-storage-request-and-limit": {}},
+	}, "Container Ephemeral Storage Request and Limit", scorecard.GradeAllOK)
+}
+
+func TestPodContainerEphemeralStorageMissingLimit(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-ephemeral-storage-missing.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-ephemeral-storage-request-and-limit": {}},
+	}, "Container Ephemeral Storage Request and Limit", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Ephemeral storage limit is not set", comments[0].Summary)
+}
+
+func TestPodEmptyDirSizeLimit(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-ephemeral-storage.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-emptydir-size-limit": {}},
+	}, "Pod EmptyDir Size Limit", scorecard.GradeAllOK)
+}
+
+func TestPodEmptyDirSizeLimitMissing(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-ephemeral-storage-missing.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"pod-emptydir-size-limit": {}},
+	}, "Pod EmptyDir Size Limit", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "cache", comments[0].Path)
+}
+
+func TestContainerVolumeMountsReadOnly(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-volume-mounts-read-only.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-volume-mounts-read-only": {}},
+	}, "Container Volume Mounts Read Only", scorecard.GradeAllOK)
+}
+
+func TestContainerVolumeMountsNotReadOnly(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-volume-mounts-not-read-only.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-volume-mounts-read-only": {}},
+	}, "Container Volume Mounts Read Only", scorecard.GradeWarning)
+
+	var summaries []string
+	for _, c := range comments {
+		summaries = append(summaries, c.Path+": "+c.Summary)
+	}
+	assert.Equal(t, []string{
+		"migrate: The Secret volume credentials is not mounted as readOnly",
+		"app: The ConfigMap volume config is mounted with subPath",
+		"app: The projected volume projected is not mounted as readOnly",
+	}, summaries)
+}
+
+func TestContainerPortsNamed(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("container-ports-valid.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-ports-named": {}},
+	}, "Container Ports Named", scorecard.GradeAllOK)
+}
+
+func TestContainerPortsNotNamed(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("container-ports-invalid.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-ports-named": {}},
+	}, "Container Ports Named", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "app", comments[0].Path)
+	assert.Equal(t, "The port 9090 does not have a name", comments[0].Summary)
+}
+
 func TestDeploymentResources(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(t, "deployment-test-resources.yaml", "Container Resources", scorecard.GradeWarning)
@@ -201,6 +335,61 @@ func TestPodContainerTagFixed(t *testing.T) {
 	testExpectedScore(t, "pod-image-tag-fixed.yaml", "Container Image Tag", scorecard.GradeAllOK)
 }
 
+func TestPodContainerTagNone(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-image-tag-none.yaml", "Container Image Tag", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "init", comments[0].Path)
+	assert.Equal(t, "Image without tag", comments[0].Summary)
+}
+
+func TestPodContainerTagEphemeralContainer(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-image-digest.yaml", "Container Image Tag", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "debugger", comments[0].Path)
+	assert.Equal(t, "Image with latest tag", comments[0].Summary)
+}
+
+func TestPodContainerImageDigest(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-image-digest.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-image-digest": {}},
+	}, "Container Image Digest", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "debugger", comments[0].Path)
+}
+
+func TestPodContainerImageDigestTag(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-image-tag-fixed.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-image-digest": {}},
+	}, "Container Image Digest", scorecard.GradeWarning)
+}
+
+func TestPodContainerImageRegistry(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:               []ks.NamedReader{testFile("pod-image-digest.yaml")},
+		EnabledOptionalTests:   map[string]struct{}{"container-image-registry": {}},
+		AllowedImageRegistries: []string{"docker.io/foo"},
+	}, "Container Image Registry", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "debugger", comments[0].Path)
+	assert.Equal(t, "The image docker.io/library/busybox is not from an allowed registry", comments[0].Summary)
+}
+
+func TestPodContainerImageRegistryAllowed(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:               []ks.NamedReader{testFile("pod-image-digest.yaml")},
+		EnabledOptionalTests:   map[string]struct{}{"container-image-registry": {}},
+		AllowedImageRegistries: []string{"docker.io/foo", "docker.io/library"},
+	}, "Container Image Registry", scorecard.GradeAllOK)
+}
+
 func TestPodContainerPullPolicyUndefined(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(t, "pod-image-pullpolicy-undefined.yaml", "Container Image Pull Policy", scorecard.GradeCritical)
@@ -226,6 +415,40 @@ func TestPodContainerPullPolicyAlways(t *testing.T) {
 	testExpectedScore(t, "pod-image-pullpolicy-always.yaml", "Container Image Pull Policy", scorecard.GradeAllOK)
 }
 
+func TestPodContainerPullPolicyDigest(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-image-pullpolicy-ifnotpresent-digest.yaml", "Container Image Pull Policy", scorecard.GradeAllOK)
+}
+
+func TestPodContainerPullPolicyConsistencyAlwaysDigest(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-image-pullpolicy-always-digest.yaml", "Container Image Pull Policy Consistency", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "ImagePullPolicy is Always for an image pinned to a digest", comments[0].Summary)
+}
+
+func TestPodContainerPullPolicyConsistencyIfNotPresentDigest(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-image-pullpolicy-ifnotpresent-digest.yaml", "Container Image Pull Policy Consistency", scorecard.GradeAllOK)
+}
+
+func TestPodContainerPullPolicyConsistencyNeverTag(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-image-pullpolicy-never.yaml", "Container Image Pull Policy Consistency", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "ImagePullPolicy is Never for an image with a mutable tag", comments[0].Summary)
+}
+
+func TestPodContainerPullPolicyConsistencyUndefinedTag(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-image-pullpolicy-undefined.yaml", "Container Image Pull Policy Consistency", scorecard.GradeWarning)
+}
+
+func TestPodContainerPullPolicyConsistencyAlwaysTag(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-image-pullpolicy-always.yaml", "Container Image Pull Policy Consistency", scorecard.GradeAllOK)
+}
+
 func TestConfigMapMultiDash(t *testing.T) {
 	t.Parallel()
 	_, err := testScore(config.Configuration{
@@ -307,3 +530,552 @@ func TestList(t *testing.T) {
 	assert.True(t, hasService)
 	assert.True(t, hasDeployment)
 }
+
+func TestAnnotationDowngrade(t *testing.T) {
+	t.Parallel()
+	cnf := func(overrides map[string]scorecard.Grade) config.Configuration {
+		return config.Configuration{
+			AllFiles:                  []ks.NamedReader{testFile("downgrade-annotation-service.yaml")},
+			UseIgnoreChecksAnnotation: true,
+			GradeOverrides:            overrides,
+		}
+	}
+	comments := testExpectedScoreWithConfig(t, cnf(nil), "Service Type", scorecard.GradeInfo)
+	assert.Equal(t, "The service is of type NodePort", comments[0].Summary)
+	testExpectedScoreWithConfig(t, cnf(nil), "Service Targets Pod", scorecard.GradeWarning)
+
+	// Checks are only downgraded, never upgraded
+	testExpectedScoreWithConfig(t, cnf(nil), "Stable version", scorecard.GradeAllOK)
+
+	// The annotation takes precedence over the grade overrides of the configuration
+	overrides := map[string]scorecard.Grade{"service-targets-pod": scorecard.GradeCritical}
+	testExpectedScoreWithConfig(t, cnf(overrides), "Service Targets Pod", scorecard.GradeWarning)
+}
+
+func TestAnnotationDowngradeDisabled(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:                  []ks.NamedReader{testFile("downgrade-annotation-service.yaml")},
+		UseIgnoreChecksAnnotation: false,
+	}, "Service Type", scorecard.GradeWarning)
+}
+
+func TestIgnoreRuleNamespace(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:    []ks.NamedReader{testFile("service-type-nodeport.yaml")},
+		IgnoreRules: []config.IgnoreRule{{Checks: []string{"service-type"}, Namespaces: []string{"foo*"}}},
+	}, "Service Type", scorecard.GradeWarning)
+	assert.Equal(t, "Skipped because service-type is ignored in this path or namespace", comments[0].Summary)
+}
+
+func TestIgnoreRuleOtherPath(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:    []ks.NamedReader{testFile("service-type-nodeport.yaml")},
+		IgnoreRules: []config.IgnoreRule{{Checks: []string{"service-type"}, Paths: []string{"legacy/**"}}},
+	}, "Service Type", scorecard.GradeWarning)
+	assert.Equal(t, "The service is of type NodePort", comments[0].Summary)
+}
+
+func TestIgnoreRulePath(t *testing.T) {
+	t.Parallel()
+	s, err := testScore(config.Configuration{
+		AllFiles:    []ks.NamedReader{testFile("service-type-nodeport.yaml")},
+		IgnoreRules: []config.IgnoreRule{{Paths: []string{"testdata/**"}}},
+	})
+	assert.Nil(t, err)
+	for _, o := range s {
+		for _, c := range o.Checks {
+			assert.True(t, c.Skipped, c.Check.ID)
+		}
+	}
+}
+
+func TestOnlyKind(t *testing.T) {
+	t.Parallel()
+	s, err := testScore(config.Configuration{
+		AllFiles:  []ks.NamedReader{testFile("all-ok.yaml")},
+		OnlyKinds: []string{"networkpolicy"},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, s, 1)
+	for _, o := range s {
+		assert.Equal(t, "NetworkPolicy", o.TypeMeta.Kind)
+	}
+}
+
+func TestIgnoreKind(t *testing.T) {
+	t.Parallel()
+	s, err := testScore(config.Configuration{
+		AllFiles:     []ks.NamedReader{testFile("service-target-deployment.yaml")},
+		IgnoredKinds: []string{"Deployment"},
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, s)
+	for _, o := range s {
+		assert.NotEqual(t, "Deployment", o.TypeMeta.Kind)
+		// The ignored objects are still used by the checks of other objects
+		for _, c := range o.Checks {
+			if c.Check.ID == "service-targets-pod" {
+				assert.Equal(t, scorecard.GradeAllOK, c.Grade)
+			}
+		}
+	}
+}
+
+func TestUnknownKinds(t *testing.T) {
+	t.Parallel()
+	s, err := testScore(config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("unknown-kinds.yaml")},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, s, 2)
+
+	rollout, ok := s["Rollout/argoproj.io/v1alpha1//app"]
+	assert.True(t, ok)
+	var ids []string
+	for _, c := range rollout.Checks {
+		ids = append(ids, c.Check.ID)
+	}
+	assert.Contains(t, ids, "label-values")
+	assert.Contains(t, ids, "object-name")
+
+	unknown := rollout.Checks[len(rollout.Checks)-1]
+	assert.Equal(t, "unknown-kind", unknown.Check.ID)
+	assert.True(t, unknown.Skipped)
+	assert.Equal(t, "Skipped because the kind Rollout is not supported, only the metadata of the object is checked", unknown.Comments[0].Summary)
+}
+
+func TestUnknownKindsSkip(t *testing.T) {
+	t.Parallel()
+	s, err := testScore(config.Configuration{
+		AllFiles:     []ks.NamedReader{testFile("unknown-kinds.yaml")},
+		UnknownKinds: config.UnknownKindsSkip,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, s, 2)
+	for _, c := range s["Rollout/argoproj.io/v1alpha1//app"].Checks {
+		assert.NotEqual(t, "unknown-kind", c.Check.ID)
+	}
+}
+
+func TestUnknownKindsFail(t *testing.T) {
+	t.Parallel()
+	_, err := testScore(config.Configuration{
+		AllFiles:     []ks.NamedReader{testFile("unknown-kinds.yaml")},
+		UnknownKinds: config.UnknownKindsFail,
+	})
+	assert.EqualError(t, err, "objects of unsupported kinds can not be scored: Rollout")
+
+	// Objects of unknown kinds that are targeted by a custom check are scored
+	_, err = testScore(config.Configuration{
+		AllFiles:     []ks.NamedReader{testFile("unknown-kinds.yaml")},
+		UnknownKinds: config.UnknownKindsFail,
+		CustomChecks: []config.CustomCheck{{
+			ID:         "rollout-replicas",
+			Kinds:      []string{"Rollout"},
+			Expression: `object.spec.replicas > 1`,
+		}},
+	})
+	assert.Nil(t, err)
+
+	// Custom checks without kinds are run on all objects, and don't make any kind supported
+	_, err = testScore(config.Configuration{
+		AllFiles:     []ks.NamedReader{testFile("unknown-kinds.yaml")},
+		UnknownKinds: config.UnknownKindsFail,
+		CustomChecks: []config.CustomCheck{{
+			ID:         "has-labels",
+			Expression: `has(object.metadata.labels)`,
+		}},
+	})
+	assert.EqualError(t, err, "objects of unsupported kinds can not be scored: Rollout")
+
+	// Objects of kinds that are not scored are not reported as an unsupported kind
+	_, err = testScore(config.Configuration{
+		AllFiles:     []ks.NamedReader{testFile("unknown-kinds.yaml")},
+		UnknownKinds: config.UnknownKindsFail,
+		IgnoredKinds: []string{"Rollout"},
+	})
+	assert.Nil(t, err)
+
+	// Objects without a kind are not reported as an unsupported kind
+	_, err = testScore(config.Configuration{
+		AllFiles: []ks.NamedReader{unnamedReader{strings.NewReader(`apiVersion: v1
+metadata:
+  name: app
+`)}},
+		UnknownKinds: config.UnknownKindsFail,
+	})
+	assert.Nil(t, err)
+}
+
+func TestWorkloadKinds(t *testing.T) {
+	t.Parallel()
+	checkIDs := func(s scorecard.Scorecard, key string) []string {
+		var ids []string
+		for _, c := range s[key].Checks {
+			ids = append(ids, c.Check.ID)
+		}
+		return ids
+	}
+
+	s, err := testScore(config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("workload-kinds.yaml")},
+	})
+	assert.Nil(t, err)
+	// Rollouts are workloads by default, the pod checks are run on the pod template
+	assert.Contains(t, checkIDs(s, "Rollout/argoproj.io/v1alpha1//app"), "container-resources")
+	assert.NotContains(t, checkIDs(s, "Rollout/argoproj.io/v1alpha1//app"), "unknown-kind")
+	assert.Contains(t, checkIDs(s, "Worker/example.com/v1//worker"), "unknown-kind")
+
+	s, err = testScore(config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("workload-kinds.yaml")},
+		WorkloadKinds: []config.WorkloadKind{
+			{Group: "example.com", Kind: "Worker", PodTemplatePath: "spec.pod.template"},
+			// An empty path disables the default
+			{Group: "argoproj.io", Kind: "Rollout"},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Contains(t, checkIDs(s, "Worker/example.com/v1//worker"), "container-resources")
+	assert.Contains(t, checkIDs(s, "Rollout/argoproj.io/v1alpha1//app"), "unknown-kind")
+}
+
+func TestNamespaceAndSelector(t *testing.T) {
+	t.Parallel()
+	objects := func(namespace string, selector labels.Selector) []string {
+		s, err := testScore(config.Configuration{
+			AllFiles: []ks.NamedReader{unnamedReader{strings.NewReader(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+  namespace: payments
+  labels:
+    app: checkout
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: b
+  namespace: payments
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: c
+  namespace: other
+  labels:
+    app: checkout
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: d
+`)}},
+			Namespace: namespace,
+			Selector:  selector,
+		})
+		assert.Nil(t, err)
+		var names []string
+		for _, o := range s {
+			names = append(names, o.ObjectMeta.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	checkout, err := labels.Parse("app=checkout")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, objects("", nil))
+	assert.Equal(t, []string{"a", "b", "d"}, objects("payments", nil))
+	assert.Equal(t, []string{"a", "c"}, objects("", checkout))
+	assert.Equal(t, []string{"a"}, objects("payments", checkout))
+}
+
+func TestSelectorExcludedObjectsAreNotUsed(t *testing.T) {
+	t.Parallel()
+	grade := func(selector labels.Selector) scorecard.Grade {
+		s, err := testScore(config.Configuration{
+			AllFiles: []ks.NamedReader{unnamedReader{strings.NewReader(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: checkout
+  labels:
+    app: checkout
+spec:
+  selector:
+    app: checkout-pods
+  ports:
+  - port: 80
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: checkout
+  labels:
+    app: checkout-pods
+spec:
+  containers:
+  - name: foo
+    image: foo:1.0
+`)}},
+			Selector: selector,
+		})
+		assert.Nil(t, err)
+		for _, c := range s["Service/v1//checkout"].Checks {
+			if c.Check.ID == "service-targets-pod" {
+				return c.Grade
+			}
+		}
+		t.Fatal("service-targets-pod was not run")
+		return 0
+	}
+
+	checkout, err := labels.Parse("app=checkout")
+	assert.Nil(t, err)
+
+	assert.Equal(t, scorecard.GradeAllOK, grade(nil))
+	// The Pod doesn't match the selector, and is not used by the checks of the Service
+	assert.Equal(t, scorecard.GradeCritical, grade(checkout))
+}
+
+func TestParallelism(t *testing.T) {
+	t.Parallel()
+	cnf := func(parallelism int) config.Configuration {
+		return config.Configuration{
+			AllFiles:          []ks.NamedReader{testFile("all-ok.yaml"), testFile("deployment-test-resources.yaml"), testFile("service-type-nodeport.yaml")},
+			KubernetesVersion: config.Semver{1, 18},
+			Parallelism:       parallelism,
+		}
+	}
+
+	sequential, err := testScore(cnf(1))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, sequential)
+
+	// The result, including the order of the checks of each object, does not depend on the parallelism
+	for _, parallelism := range []int{0, 4, 100} {
+		parallel, err := testScore(cnf(parallelism))
+		assert.Nil(t, err)
+		assert.Equal(t, sequential, parallel, parallelism)
+	}
+}
+
+func TestRunTasksError(t *testing.T) {
+	t.Parallel()
+	var tasks []scoreTask
+	for i := 0; i < 10; i++ {
+		i := i
+		tasks = append(tasks, scoreTask{fn: func() (scorecard.TestScore, error) {
+			if i >= 5 {
+				return scorecard.TestScore{}, fmt.Errorf("task %d failed", i)
+			}
+			return scorecard.TestScore{Grade: scorecard.Grade(i)}, nil
+		}})
+	}
+
+	ignore := func(int, scorecard.TestScore) error { return nil }
+	err := runTasks(context.Background(), tasks, 3, ignore)
+	assert.Equal(t, errors.New("task 5 failed"), err)
+
+	results := make(map[int]scorecard.Grade)
+	err = runTasks(context.Background(), tasks[:5], 3, func(i int, r scorecard.TestScore) error {
+		results[i] = r.Grade
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Len(t, results, 5)
+	for i, grade := range results {
+		assert.Equal(t, scorecard.Grade(i), grade)
+	}
+
+	// Errors of done stop the tasks
+	err = runTasks(context.Background(), tasks[:5], 1, func(i int, r scorecard.TestScore) error {
+		return fmt.Errorf("done %d failed", i)
+	})
+	assert.Equal(t, errors.New("done 0 failed"), err)
+}
+
+func TestRunTasksCancelled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var tasks []scoreTask
+	for i := 0; i < 10; i++ {
+		tasks = append(tasks, scoreTask{fn: func() (scorecard.TestScore, error) {
+			cancel()
+			return scorecard.TestScore{}, nil
+		}})
+	}
+
+	err := runTasks(ctx, tasks, 1, func(int, scorecard.TestScore) error { return nil })
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestScoreCancelled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cnf := config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("pod-probes-all-missing.yaml")},
+		KubernetesVersion: config.Semver{1, 18},
+	}
+	_, err := parser.ParseFiles(ctx, cnf)
+	assert.Equal(t, context.Canceled, err)
+
+	parsed, err := parser.ParseFiles(context.Background(), config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("pod-probes-all-missing.yaml")},
+		KubernetesVersion: config.Semver{1, 18},
+	})
+	assert.Nil(t, err)
+	_, err = Score(ctx, parsed, cnf)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestAllChecksDocumented(t *testing.T) {
+	t.Parallel()
+	allChecks := RegisterAllChecks(parser.Empty(), config.Configuration{PodSecurityStandard: "restricted"})
+	for _, c := range allChecks.All() {
+		doc, ok := allChecks.Documentation(c.ID)
+		assert.True(t, ok, c.ID)
+		assert.NotEmpty(t, doc.Remediation, c.ID)
+		assert.NotEmpty(t, doc.URL, c.ID)
+		assert.True(t, doc.Grade < scorecard.GradeAllOK, c.ID)
+	}
+}
+
+func TestAllChecksInProfile(t *testing.T) {
+	t.Parallel()
+	allChecks := RegisterAllChecks(parser.Empty(), config.Configuration{PodSecurityStandard: "restricted"})
+	registered := make(map[string]struct{})
+	for _, c := range allChecks.All() {
+		registered[c.ID] = struct{}{}
+		assert.NotEmpty(t, checks.ProfilesOf(c.ID, nil), c.ID)
+	}
+	for name, ids := range checks.Profiles {
+		for _, id := range ids {
+			_, ok := registered[id]
+			assert.True(t, ok, "%s in profile %s is not a check", id, name)
+		}
+	}
+}
+
+func TestCrossObjectChecks(t *testing.T) {
+	t.Parallel()
+	allChecks := RegisterAllChecks(parser.Empty(), config.Configuration{})
+	registered := make(map[string]struct{})
+	for _, c := range allChecks.All() {
+		registered[c.ID] = struct{}{}
+	}
+	crossObject := allChecks.CrossObject()
+	for _, id := range crossObject {
+		_, ok := registered[id]
+		assert.True(t, ok, "%s is not a check", id)
+	}
+	assert.Contains(t, crossObject, "servicemonitor-targets-service")
+	assert.Contains(t, crossObject, "podmonitor-targets-pod")
+
+	// A single object passes when the cross-object checks are ignored, such as in the webhook
+	ignored := make(map[string]struct{})
+	for _, id := range crossObject {
+		ignored[id] = struct{}{}
+	}
+	sc, err := testScore(config.Configuration{
+		AllFiles: []ks.NamedReader{unnamedReader{strings.NewReader(`apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: app
+spec:
+  selector:
+    matchLabels:
+      app: app
+  endpoints:
+  - port: metrics
+`)}},
+		IgnoredTests: ignored,
+	})
+	assert.NoError(t, err)
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			assert.NotEqual(t, scorecard.GradeCritical, c.Grade, c.Check.ID)
+		}
+	}
+}
+
+func TestProfile(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("all-ok.yaml")},
+		KubernetesVersion: config.Semver{1, 18},
+		Profiles:          []string{"security"},
+	})
+	assert.NoError(t, err)
+
+	security, _ := checks.ProfileChecks([]string{"security"}, nil)
+	var tested int
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			_, ok := security[c.Check.ID]
+			assert.True(t, ok, c.Check.ID)
+			tested++
+		}
+	}
+	assert.True(t, tested > 0)
+}
+
+func TestCustomProfile(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("all-ok.yaml")},
+		KubernetesVersion: config.Semver{1, 18},
+		Profiles:          []string{"probes"},
+		CustomProfiles:    map[string][]string{"probes": {"pod-probes"}},
+	})
+	assert.NoError(t, err)
+
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			assert.Equal(t, "pod-probes", c.Check.ID)
+		}
+	}
+}
+
+func TestDocumentationExamples(t *testing.T) {
+	t.Parallel()
+	allChecks := RegisterAllChecks(parser.Empty(), config.Configuration{})
+	for _, c := range allChecks.All() {
+		doc, _ := allChecks.Documentation(c.ID)
+		if doc.FailingExample == "" && doc.PassingExample == "" {
+			continue
+		}
+
+		grades := func(example string) []scorecard.Grade {
+			sc, err := testScore(config.Configuration{
+				AllFiles:          []ks.NamedReader{unnamedReader{strings.NewReader(example)}},
+				KubernetesVersion: config.Semver{1, 18},
+			})
+			assert.NoError(t, err, c.ID)
+			var res []scorecard.Grade
+			for _, o := range sc {
+				for _, s := range o.Checks {
+					if s.Check.ID == c.ID && !s.Skipped {
+						res = append(res, s.Grade)
+					}
+				}
+			}
+			return res
+		}
+
+		assert.Contains(t, grades(doc.FailingExample), doc.Grade, c.ID)
+		passing := grades(doc.PassingExample)
+		assert.NotEmpty(t, passing, c.ID)
+		for _, g := range passing {
+			assert.Equal(t, scorecard.GradeAllOK, g, c.ID)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package security
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zegl/kube-score/score/internal"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// secretPatterns are values of environment variables that look like secrets, by the kind of secret
+var secretPatterns = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{"an AWS access key", regexp.MustCompile(`^(AKIA|ASIA)[0-9A-Z]{16}$`)},
+	{"a GitHub token", regexp.MustCompile(`^(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{40,})$`)},
+	{"a Slack token", regexp.MustCompile(`^xox[abposr]-[A-Za-z0-9-]{10,}$`)},
+	{"a JSON Web Token", regexp.MustCompile(`^eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*$`)},
+	{"a private key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY-----`)},
+}
+
+// base64Pattern matches base64 encoded values, which are only reported if they are long and look random
+var base64Pattern = regexp.MustCompile(`^[A-Za-z0-9+/_-]{40,}={0,2}$`)
+
+// containerEnvironmentSecrets checks that no container reads Secrets into environment variables, and that no
+// environment variable has a value that looks like a secret
+func containerEnvironmentSecrets(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	score.Grade = scorecard.GradeAllOK
+
+	const mountDescription = "Environment variables are easily leaked, such as to the logs, crash reports and child processes of the container. Mount the Secret as a volume, and read the value from the file"
+
+	for _, container := range internal.AllContainers(podTemplate.Spec) {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef == nil {
+				continue
+			}
+			setWarning(&score)
+			score.AddComment(container.Name, fmt.Sprintf("The container reads the Secret %s into environment variables", envFrom.SecretRef.Name), mountDescription)
+		}
+
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				setWarning(&score)
+				score.AddComment(container.Name, fmt.Sprintf("The environment variable %s is read from the Secret %s", env.Name, env.ValueFrom.SecretKeyRef.Name), mountDescription)
+				continue
+			}
+
+			if kind, ok := secretLiteral(env.Value); ok {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment(container.Name, fmt.Sprintf("The environment variable %s looks like it contains %s", env.Name, kind),
+					"The value can be read by everyone who can read the object, and is often stored in version control. Rotate the secret, store it in a Secret, and mount the Secret as a volume")
+			}
+		}
+	}
+
+	return
+}
+
+// secretLiteral returns the kind of secret that the value looks like, the second return value is false if the value
+// does not look like a secret
+func secretLiteral(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	for _, p := range secretPatterns {
+		if p.pattern.MatchString(value) {
+			return p.kind, true
+		}
+	}
+	if base64Pattern.MatchString(value) && looksRandom(value) {
+		return "a base64 encoded secret", true
+	}
+	return "", false
+}
+
+// looksRandom returns true if the value has upper case letters, lower case letters and digits, which makes it unlikely
+// to be a word, a path or a hex encoded hash
+func looksRandom(value string) bool {
+	var upper, lower, digit bool
+	for _, r := range value {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= '0' && r <= '9':
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+// setWarning sets the grade to warning, unless the grade is already worse
+func setWarning(score *scorecard.TestScore) {
+	if score.Grade > scorecard.GradeWarning {
+		score.Grade = scorecard.GradeWarning
+	}
+}
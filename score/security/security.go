@@ -1,25 +1,133 @@
 package security
 
 import (
+	"fmt"
+	"path"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
 	"github.com/zegl/kube-score/scorecard"
 )
 
-func Register(allChecks *checks.Checks) {
+// dangerousCapabilities are capabilities that should never be added to a container, as they can be used to escape
+// the container or to attack other workloads on the same node
+var dangerousCapabilities = []string{
+	"ALL",
+	"BPF",
+	"DAC_READ_SEARCH",
+	"NET_ADMIN",
+	"NET_RAW",
+	"SYS_ADMIN",
+	"SYS_MODULE",
+	"SYS_PTRACE",
+}
+
+// dockerSocketPaths are the paths of the Docker socket on the node
+var dockerSocketPaths = []string{"/var/run/docker.sock", "/run/docker.sock"}
+
+func Register(allChecks *checks.Checks, cnf config.Configuration, serviceAccounts ks.ServiceAccounts) {
 	allChecks.RegisterPodCheck("Container Security Context User Group ID", `Makes sure that all pods have a security context with valid UID and GID set `, containerSecurityContextUserGroupID)
 	allChecks.RegisterPodCheck("Container Security Context Privileged", "Makes sure that all pods have a unprivileged security context set", containerSecurityContextPrivileged)
+	allChecks.RegisterPodCheck("Init Container Privileged", "Makes sure that no init containers are privileged, or mount the Docker socket of the node", initContainerPrivileged)
 	allChecks.RegisterPodCheck("Container Security Context ReadOnlyRootFilesystem", "Makes sure that all pods have a security context with read only filesystem set", containerSecurityContextReadOnlyRootFilesystem)
+	allChecks.RegisterPodCheck("Container Security Context RunAsNonRoot", "Makes sure that all containers are configured to run as a non-root user, with runAsNonRoot or a non-zero runAsUser", containerSecurityContextRunAsNonRoot)
 
-	allChecks.RegisterOptionalPodCheck("Container Seccomp Profile", `Makes sure that all pods have at a seccomp policy configured.`, podSeccompProfile)
+	allChecks.RegisterPodCheck("Container Security Context Privilege Escalation", "Makes sure that all containers have allowPrivilegeEscalation set to false", containerSecurityContextPrivilegeEscalation)
+	allChecks.RegisterPodCheck("Container Security Context Capabilities", "Makes sure that all containers drop all capabilities, and that no dangerous capabilities are added. The required dropped capabilities can be changed with --required-dropped-capabilities", containerSecurityContextCapabilities(cnf.RequiredDroppedCapabilities))
+	allChecks.RegisterPodCheck("Pod Host Network", "Makes sure that no pods use the network namespace of the host", podHostNetwork)
+	allChecks.RegisterPodCheck("Pod Host PID", "Makes sure that no pods use the process ID namespace of the host", podHostPID)
+	allChecks.RegisterPodCheck("Pod Host IPC", "Makes sure that no pods use the IPC namespace of the host", podHostIPC)
+	allChecks.RegisterPodCheck("Pod Host Path Volumes", "Makes sure that no pods mount hostPath volumes. Paths can be allowed with --allowed-host-path", podHostPathVolumes(cnf.AllowedHostPaths))
+	allChecks.RegisterPodCheck("Pod Automount Service Account Token", "Makes sure that pods, or the ServiceAccounts that they use, have disabled automounting of the service account token", podAutomountServiceAccountToken(serviceAccounts.ServiceAccounts()))
+	allChecks.RegisterPodCheck("Pod Default Service Account", "Makes sure that pods do not use the default ServiceAccount", podDefaultServiceAccount)
+	allChecks.RegisterOptionalPodCheck("Container Seccomp Profile", `Makes sure that all pods have at a seccomp policy configured. With --kubernetes-version v1.19 and later, securityContext.seccompProfile must be set to RuntimeDefault or Localhost, for older versions the seccomp annotation is used.`, podSeccompProfile(cnf.KubernetesVersion))
+	allChecks.RegisterOptionalPodCheck("Container Environment Secrets", "Makes sure that no containers read Secrets into environment variables, and that no environment variables have values that look like secrets, such as AWS keys, tokens and base64 encoded values", containerEnvironmentSecrets)
+	allChecks.Document("container-security-context-user-group-id", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set securityContext.runAsUser and securityContext.runAsGroup to values above 10000",
+	})
+	allChecks.Document("container-security-context-privileged", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set securityContext.privileged to false, or remove it",
+		URL:         "https://kubernetes.io/docs/tasks/configure-pod-container/security-context/",
+	})
+	allChecks.Document("init-container-privileged", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set securityContext.privileged to false on all init containers, and do not mount the Docker socket into them",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/pods/init-containers/",
+	})
+	allChecks.Document("container-security-context-readonlyrootfilesystem", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Details:     "Makes sure that all containers, including init containers, have a securityContext with readOnlyRootFilesystem set to true. Containers without a securityContext, or where readOnlyRootFilesystem is not set, have a writable root filesystem and fail the check.",
+		Remediation: "Set securityContext.readOnlyRootFilesystem to true, and mount volumes for the paths that need to be writable",
+		URL:         "https://kubernetes.io/docs/tasks/configure-pod-container/security-context/",
+	})
+	allChecks.Document("container-security-context-runasnonroot", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Set securityContext.runAsNonRoot to true, or securityContext.runAsUser to a non-zero value",
+		URL:         "https://kubernetes.io/docs/tasks/configure-pod-container/security-context/",
+	})
+	allChecks.Document("container-security-context-privilege-escalation", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set securityContext.allowPrivilegeEscalation to false",
+		URL:         "https://kubernetes.io/docs/tasks/configure-pod-container/security-context/",
+	})
+	allChecks.Document("container-security-context-capabilities", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Drop ALL in securityContext.capabilities.drop, and only add the capabilities that the container needs",
+		URL:         "https://kubernetes.io/docs/tasks/configure-pod-container/security-context/#set-capabilities-for-a-container",
+	})
+	allChecks.Document("pod-host-network", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Remove hostNetwork, or set it to false",
+		URL:         "https://kubernetes.io/docs/concepts/security/pod-security-standards/",
+	})
+	allChecks.Document("pod-host-pid", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Remove hostPID, or set it to false",
+		URL:         "https://kubernetes.io/docs/concepts/security/pod-security-standards/",
+	})
+	allChecks.Document("pod-host-ipc", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Remove hostIPC, or set it to false",
+		URL:         "https://kubernetes.io/docs/concepts/security/pod-security-standards/",
+	})
+	allChecks.Document("pod-host-path-volumes", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Replace hostPath volumes with other volume types, or allow the path with --allowed-host-path",
+		URL:         "https://kubernetes.io/docs/concepts/storage/volumes/#hostpath",
+	})
+	allChecks.Document("pod-automount-service-account-token", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set automountServiceAccountToken to false on the pod or on its ServiceAccount",
+		URL:         "https://kubernetes.io/docs/tasks/configure-pod-container/configure-service-account/#opt-out-of-api-credential-automounting",
+	})
+	allChecks.Document("pod-default-service-account", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Create a ServiceAccount for the workload, and set serviceAccountName to it",
+		URL:         "https://kubernetes.io/docs/tasks/configure-pod-container/configure-service-account/",
+	})
+	allChecks.Document("container-environment-secrets", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Mount Secrets as volumes instead of reading them into environment variables, and move secret values from env to Secrets",
+		URL:         "https://kubernetes.io/docs/concepts/security/secrets-good-practices/",
+	})
+	allChecks.Document("container-seccomp-profile", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set securityContext.seccompProfile.type to RuntimeDefault or Localhost",
+		URL:         "https://kubernetes.io/docs/tutorials/security/seccomp/",
+	})
 }
 
 // containerSecurityContextReadOnlyRootFilesystem checks for pods using writeable root filesystems
 func containerSecurityContextReadOnlyRootFilesystem(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
-	allContainers := podTemplate.Spec.InitContainers
-	allContainers = append(allContainers, podTemplate.Spec.Containers...)
+	allContainers := internal.AllContainers(podTemplate.Spec)
 
 	noContextSet := false
 	hasWritableRootFS := false
@@ -27,13 +135,15 @@ func containerSecurityContextReadOnlyRootFilesystem(podTemplate corev1.PodTempla
 	for _, container := range allContainers {
 		if container.SecurityContext == nil {
 			noContextSet = true
-			score.AddComment(container.Name, "Container has no configured security context", "Set securityContext to run the container in a more secure context.")
+			score.AddCommentWithRemediation(container.Name, "Container has no configured security context", "Set securityContext to run the container in a more secure context.",
+				internal.ContainerSecurityContextRemediation(podTemplate, typeMeta, container.Name, "readOnlyRootFilesystem", true))
 			continue
 		}
 		sec := container.SecurityContext
 		if sec.ReadOnlyRootFilesystem == nil || *sec.ReadOnlyRootFilesystem == false {
 			hasWritableRootFS = true
-			score.AddComment(container.Name, "The pod has a container with a writable root filesystem", "Set securityContext.readOnlyRootFilesystem to true")
+			score.AddCommentWithRemediation(container.Name, "The pod has a container with a writable root filesystem", "Set securityContext.readOnlyRootFilesystem to true",
+				internal.ContainerSecurityContextRemediation(podTemplate, typeMeta, container.Name, "readOnlyRootFilesystem", true))
 		}
 	}
 
@@ -46,15 +156,92 @@ func containerSecurityContextReadOnlyRootFilesystem(podTemplate corev1.PodTempla
 	return
 }
 
+// containerSecurityContextCapabilities checks that all containers drop the required capabilities (ALL by default),
+// and that no dangerous capabilities are added
+func containerSecurityContextCapabilities(requiredDrop []string) func(corev1.PodTemplateSpec, metav1.TypeMeta) scorecard.TestScore {
+	if len(requiredDrop) == 0 {
+		requiredDrop = []string{"ALL"}
+	}
+
+	return func(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+		allContainers := internal.AllContainers(podTemplate.Spec)
+
+		hasMissingDrop := false
+		hasDangerousAdd := false
+
+		for _, container := range allContainers {
+			var capabilities corev1.Capabilities
+			if container.SecurityContext != nil && container.SecurityContext.Capabilities != nil {
+				capabilities = *container.SecurityContext.Capabilities
+			}
+
+			dropped := make(map[string]struct{})
+			for _, c := range capabilities.Drop {
+				dropped[normalizeCapability(c)] = struct{}{}
+			}
+
+			if _, ok := dropped["ALL"]; !ok {
+				var missing []string
+				for _, c := range requiredDrop {
+					if _, ok := dropped[normalizeCapability(corev1.Capability(c))]; !ok {
+						missing = append(missing, normalizeCapability(corev1.Capability(c)))
+					}
+				}
+				if len(missing) > 0 {
+					hasMissingDrop = true
+					score.AddComment(container.Name,
+						fmt.Sprintf("The container does not drop %s", strings.Join(missing, ", ")),
+						fmt.Sprintf("Set securityContext.capabilities.drop to [%s], and only add back the capabilities that the container needs", strings.Join(requiredDrop, ", ")))
+				}
+			}
+
+			for _, c := range capabilities.Add {
+				name := normalizeCapability(c)
+				if isDangerousCapability(name) {
+					hasDangerousAdd = true
+					score.AddComment(container.Name,
+						fmt.Sprintf("The container adds the dangerous capability %s", name),
+						"Remove the capability from securityContext.capabilities.add. This capability can be used to escape the container, or to attack other workloads on the same node.")
+				}
+			}
+		}
+
+		switch {
+		case hasDangerousAdd:
+			score.Grade = scorecard.GradeCritical
+		case hasMissingDrop:
+			score.Grade = scorecard.GradeWarning
+		default:
+			score.Grade = scorecard.GradeAllOK
+		}
+
+		return
+	}
+}
+
+// normalizeCapability returns the name of the capability in upper case, without the optional CAP_ prefix
+func normalizeCapability(c corev1.Capability) string {
+	return strings.TrimPrefix(strings.ToUpper(string(c)), "CAP_")
+}
+
+func isDangerousCapability(name string) bool {
+	for _, c := range dangerousCapabilities {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
 // containerSecurityContextPrivileged checks for privileged containers
 func containerSecurityContextPrivileged(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
-	allContainers := podTemplate.Spec.InitContainers
-	allContainers = append(allContainers, podTemplate.Spec.Containers...)
+	allContainers := internal.AllContainers(podTemplate.Spec)
 	hasPrivileged := false
 	for _, container := range allContainers {
 		if container.SecurityContext != nil && container.SecurityContext.Privileged != nil && *container.SecurityContext.Privileged {
 			hasPrivileged = true
-			score.AddComment(container.Name, "The container is privileged", "Set securityContext.privileged to false. Privileged containers can access all devices on the host, and grants almost the same access as non-containerized processes on the host.")
+			score.AddCommentWithRemediation(container.Name, "The container is privileged", "Set securityContext.privileged to false. Privileged containers can access all devices on the host, and grants almost the same access as non-containerized processes on the host.",
+				internal.ContainerSecurityContextRemediation(podTemplate, typeMeta, container.Name, "privileged", false))
 		}
 	}
 	if hasPrivileged {
@@ -65,10 +252,211 @@ func containerSecurityContextPrivileged(podTemplate corev1.PodTemplateSpec, type
 	return
 }
 
+// initContainerPrivileged checks that no init container is privileged, or mounts the Docker socket from the node.
+// Init containers are often used to prepare the node or the volumes of the pod, and are easily given more access than
+// they need, as they only run for a short time.
+func initContainerPrivileged(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	hostPaths := make(map[string]string)
+	for _, volume := range podTemplate.Spec.Volumes {
+		if volume.HostPath != nil {
+			hostPaths[volume.Name] = volume.HostPath.Path
+		}
+	}
+
+	score.Grade = scorecard.GradeAllOK
+
+	for _, container := range podTemplate.Spec.InitContainers {
+		if container.SecurityContext != nil && container.SecurityContext.Privileged != nil && *container.SecurityContext.Privileged {
+			score.Grade = scorecard.GradeCritical
+			score.AddCommentWithRemediation(container.Name, "The init container is privileged", "Set securityContext.privileged to false. A privileged init container can access all devices on the host, and can change the node before the other containers are started.",
+				internal.ContainerSecurityContextRemediation(podTemplate, typeMeta, container.Name, "privileged", false))
+		}
+
+		for _, mount := range container.VolumeMounts {
+			hostPath, ok := hostPaths[mount.Name]
+			if !ok || !mountsDockerSocket(hostPath, mount.SubPath) {
+				continue
+			}
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(container.Name, fmt.Sprintf("The init container mounts the Docker socket with the volume %s", mount.Name), "Remove the volume mount. The Docker socket can be used to start privileged containers, and to take over the node.")
+		}
+	}
+
+	return
+}
+
+// mountsDockerSocket returns true if the Docker socket is the mounted path of the node, or is in the mounted directory
+func mountsDockerSocket(hostPath, subPath string) bool {
+	mounted := path.Join("/", hostPath, subPath)
+	for _, socket := range dockerSocketPaths {
+		if socket == mounted || strings.HasPrefix(socket, strings.TrimSuffix(mounted, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// containerSecurityContextPrivilegeEscalation checks that no container can gain more privileges than its parent process
+func containerSecurityContextPrivilegeEscalation(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	allContainers := internal.AllContainers(podTemplate.Spec)
+
+	score.Grade = scorecard.GradeAllOK
+
+	for _, container := range allContainers {
+		sec := container.SecurityContext
+		if sec == nil || sec.AllowPrivilegeEscalation == nil {
+			score.Grade = scorecard.GradeWarning
+			score.AddCommentWithRemediation(container.Name, "The container does not disallow privilege escalation", "Set securityContext.allowPrivilegeEscalation to false. Privilege escalation is allowed by default, and makes it possible for a process to gain more privileges than its parent process, for example with setuid binaries.",
+				internal.ContainerSecurityContextRemediation(podTemplate, typeMeta, container.Name, "allowPrivilegeEscalation", false))
+			continue
+		}
+		if *sec.AllowPrivilegeEscalation {
+			score.Grade = scorecard.GradeWarning
+			score.AddCommentWithRemediation(container.Name, "The container allows privilege escalation", "Set securityContext.allowPrivilegeEscalation to false. Privilege escalation makes it possible for a process to gain more privileges than its parent process, for example with setuid binaries.",
+				internal.ContainerSecurityContextRemediation(podTemplate, typeMeta, container.Name, "allowPrivilegeEscalation", false))
+		}
+	}
+
+	return
+}
+
+// podHostNetwork checks that the pod does not use the network namespace of the host
+func podHostNetwork(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	if podTemplate.Spec.HostNetwork {
+		score.Grade = scorecard.GradeCritical
+		score.AddCommentWithRemediation("", "The pod uses the host network", "Set hostNetwork to false. Pods in the host network can access the network interfaces of the node, including services listening on localhost, and can sniff the traffic of other pods.",
+			internal.PodSpecRemediation(typeMeta, "hostNetwork", false))
+		return
+	}
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+// podHostPID checks that the pod does not use the process ID namespace of the host
+func podHostPID(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	if podTemplate.Spec.HostPID {
+		score.Grade = scorecard.GradeCritical
+		score.AddCommentWithRemediation("", "The pod uses the host PID namespace", "Set hostPID to false. Pods in the host PID namespace can see all processes on the node, and can read their environment variables and files.",
+			internal.PodSpecRemediation(typeMeta, "hostPID", false))
+		return
+	}
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+// podHostIPC checks that the pod does not use the IPC namespace of the host
+func podHostIPC(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	if podTemplate.Spec.HostIPC {
+		score.Grade = scorecard.GradeCritical
+		score.AddCommentWithRemediation("", "The pod uses the host IPC namespace", "Set hostIPC to false. Pods in the host IPC namespace can access the shared memory of all processes on the node.",
+			internal.PodSpecRemediation(typeMeta, "hostIPC", false))
+		return
+	}
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+// podHostPathVolumes checks that the pod does not mount any hostPath volumes, except for volumes that are below one
+// of the allowed paths
+func podHostPathVolumes(allowedPaths []string) func(corev1.PodTemplateSpec, metav1.TypeMeta) scorecard.TestScore {
+	return func(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+		score.Grade = scorecard.GradeAllOK
+
+		for _, volume := range podTemplate.Spec.Volumes {
+			if volume.HostPath == nil || isAllowedHostPath(volume.HostPath.Path, allowedPaths) {
+				continue
+			}
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(volume.Name,
+				fmt.Sprintf("The pod mounts the host path %s", volume.HostPath.Path),
+				"Use a different volume type. hostPath volumes gives the pod access to the filesystem of the node, which can be used to escape the container. If the path is needed, for example by a log collector, allow it with --allowed-host-path.")
+		}
+
+		return
+	}
+}
+
+// podAutomountServiceAccountToken checks that the service account token is not mounted in the pod. The token is not
+// mounted if automountServiceAccountToken is false in the pod, or if it's not set in the pod and is false in the
+// ServiceAccount that the pod uses.
+func podAutomountServiceAccountToken(allServiceAccounts []ks.ServiceAccount) func(corev1.PodTemplateSpec, metav1.TypeMeta) scorecard.TestScore {
+	return func(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+		spec := podTemplate.Spec
+
+		if spec.AutomountServiceAccountToken != nil {
+			if *spec.AutomountServiceAccountToken {
+				score.Grade = scorecard.GradeWarning
+				score.AddCommentWithRemediation("", "The pod automounts the service account token", "Set automountServiceAccountToken to false, unless the pod needs to access the Kubernetes API",
+					internal.PodSpecRemediation(typeMeta, "automountServiceAccountToken", false))
+			} else {
+				score.Grade = scorecard.GradeAllOK
+			}
+			return
+		}
+
+		serviceAccountName := spec.ServiceAccountName
+		if serviceAccountName == "" {
+			serviceAccountName = spec.DeprecatedServiceAccount
+		}
+		if serviceAccountName == "" {
+			serviceAccountName = "default"
+		}
+
+		for _, s := range allServiceAccounts {
+			sa := s.ServiceAccount()
+			if sa.Name != serviceAccountName || sa.Namespace != podTemplate.Namespace {
+				continue
+			}
+			if sa.AutomountServiceAccountToken != nil && !*sa.AutomountServiceAccountToken {
+				score.Grade = scorecard.GradeAllOK
+				return
+			}
+		}
+
+		score.Grade = scorecard.GradeWarning
+		score.AddCommentWithRemediation("", "The pod automounts the service account token",
+			fmt.Sprintf("Set automountServiceAccountToken to false in the pod, or in the ServiceAccount %s, unless the pod needs to access the Kubernetes API", serviceAccountName),
+			internal.PodSpecRemediation(typeMeta, "automountServiceAccountToken", false))
+		return
+	}
+}
+
+// podDefaultServiceAccount checks that the pod uses a dedicated ServiceAccount
+func podDefaultServiceAccount(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	serviceAccountName := podTemplate.Spec.ServiceAccountName
+	if serviceAccountName == "" {
+		serviceAccountName = podTemplate.Spec.DeprecatedServiceAccount
+	}
+
+	switch serviceAccountName {
+	case "":
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "The pod does not set a ServiceAccount", "Set serviceAccountName to a ServiceAccount that is dedicated to this workload. Pods without a serviceAccountName use the default ServiceAccount, which is shared with all other pods in the namespace, and makes it impossible to audit which workload has which RBAC permissions.")
+	case "default":
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "The pod uses the default ServiceAccount", "Set serviceAccountName to a ServiceAccount that is dedicated to this workload. The default ServiceAccount is shared with all other pods in the namespace, and makes it impossible to audit which workload has which RBAC permissions.")
+	default:
+		score.Grade = scorecard.GradeAllOK
+	}
+
+	return
+}
+
+// isAllowedHostPath returns true if p is equal to, or below, one of the allowed paths
+func isAllowedHostPath(p string, allowedPaths []string) bool {
+	p = path.Clean(p)
+	for _, allowed := range allowedPaths {
+		allowed = path.Clean(allowed)
+		if p == allowed || strings.HasPrefix(p, strings.TrimSuffix(allowed, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // containerSecurityContextUserGroupID checks that the user and group are valid ( > 10000) in the security context
 func containerSecurityContextUserGroupID(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
-	allContainers := podTemplate.Spec.InitContainers
-	allContainers = append(allContainers, podTemplate.Spec.Containers...)
+	allContainers := internal.AllContainers(podTemplate.Spec)
 	podSecurityContext := podTemplate.Spec.SecurityContext
 	noContextSet := false
 	hasLowUserID := false
@@ -79,7 +467,9 @@ func containerSecurityContextUserGroupID(podTemplate corev1.PodTemplateSpec, typ
 			score.AddComment(container.Name, "Container has no configured security context", "Set securityContext to run the container in a more secure context.")
 			continue
 		}
-		sec := container.SecurityContext
+		// The security context is copied before the values of the pod are merged into it, as the pod template is
+		// shared with the checks that run at the same time
+		sec := container.SecurityContext.DeepCopy()
 		if sec == nil {
 			sec = &corev1.SecurityContext{}
 		}
@@ -110,8 +500,104 @@ func containerSecurityContextUserGroupID(podTemplate corev1.PodTemplateSpec, typ
 	return
 }
 
-// podSeccompProfile checks if the any Seccommp profile is configured for the pod
-func podSeccompProfile(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+// containerSecurityContextRunAsNonRoot checks that all containers are running as a non-root user. Values set in the
+// container level security context overrides the values set in the pod level security context.
+func containerSecurityContextRunAsNonRoot(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) (score scorecard.TestScore) {
+	allContainers := internal.AllContainers(podTemplate.Spec)
+	podSecurityContext := podTemplate.Spec.SecurityContext
+
+	hasRoot := false
+
+	for _, container := range allContainers {
+		var runAsNonRoot *bool
+		var runAsUser *int64
+
+		if podSecurityContext != nil {
+			runAsNonRoot = podSecurityContext.RunAsNonRoot
+			runAsUser = podSecurityContext.RunAsUser
+		}
+		if sec := container.SecurityContext; sec != nil {
+			if sec.RunAsNonRoot != nil {
+				runAsNonRoot = sec.RunAsNonRoot
+			}
+			if sec.RunAsUser != nil {
+				runAsUser = sec.RunAsUser
+			}
+		}
+
+		if runAsUser != nil && *runAsUser == 0 {
+			hasRoot = true
+			score.AddComment(container.Name, "The container is running as root", "Set securityContext.runAsUser to a non-zero value, the root user has user ID 0")
+			continue
+		}
+
+		if runAsNonRoot != nil && *runAsNonRoot {
+			continue
+		}
+
+		if runAsUser != nil {
+			continue
+		}
+
+		hasRoot = true
+		score.AddComment(container.Name, "The container can run as root", "Set securityContext.runAsNonRoot to true, or set securityContext.runAsUser to a non-zero value. Without it the container runs as the user set in the image, which is often root.")
+	}
+
+	if hasRoot {
+		score.Grade = scorecard.GradeCritical
+	} else {
+		score.Grade = scorecard.GradeAllOK
+	}
+
+	return
+}
+
+// podSeccompProfile checks if the any Seccomp profile is configured for the pod. The seccompProfile field is
+// available since Kubernetes v1.19, for older versions the seccomp annotation is checked instead.
+func podSeccompProfile(kubernetesVersion config.Semver) func(corev1.PodTemplateSpec, metav1.TypeMeta) scorecard.TestScore {
+	return func(podTemplate corev1.PodTemplateSpec, typeMeta metav1.TypeMeta) scorecard.TestScore {
+		if kubernetesVersion.LessThan(config.Semver{1, 19}) {
+			return podSeccompProfileAnnotation(podTemplate)
+		}
+		return podSeccompProfileField(podTemplate)
+	}
+}
+
+// podSeccompProfileField checks that all containers have a RuntimeDefault or Localhost seccomp profile, either set
+// directly on the container or inherited from the pod security context
+func podSeccompProfileField(podTemplate corev1.PodTemplateSpec) (score scorecard.TestScore) {
+	allContainers := internal.AllContainers(podTemplate.Spec)
+
+	var podProfile *corev1.SeccompProfile
+	if podTemplate.Spec.SecurityContext != nil {
+		podProfile = podTemplate.Spec.SecurityContext.SeccompProfile
+	}
+
+	score.Grade = scorecard.GradeAllOK
+
+	for _, container := range allContainers {
+		profile := podProfile
+		if container.SecurityContext != nil && container.SecurityContext.SeccompProfile != nil {
+			profile = container.SecurityContext.SeccompProfile
+		}
+
+		if profile == nil {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(container.Name, "The container has not configured a Seccomp profile", "Set securityContext.seccompProfile.type to RuntimeDefault or Localhost. Running containers with Seccomp is recommended to reduce the kernel attack surface")
+			continue
+		}
+
+		if profile.Type != corev1.SeccompProfileTypeRuntimeDefault && profile.Type != corev1.SeccompProfileTypeLocalhost {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(container.Name, fmt.Sprintf("The container is running with the %s Seccomp profile", profile.Type), "Set securityContext.seccompProfile.type to RuntimeDefault or Localhost. Running containers with Seccomp is recommended to reduce the kernel attack surface")
+		}
+	}
+
+	return
+}
+
+// podSeccompProfileAnnotation checks if the Seccomp annotation is set on the pod
+func podSeccompProfileAnnotation(podTemplate corev1.PodTemplateSpec) (score scorecard.TestScore) {
 	metadata := podTemplate.ObjectMeta
 
 	seccompAnnotated := false
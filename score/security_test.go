@@ -1,11 +1,13 @@
 package score
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser"
 	"github.com/zegl/kube-score/scorecard"
 )
 
@@ -89,6 +91,39 @@ func TestContainerSecurityContextUserGroupIDNoSecurityContext(t *testing.T) {
 	})
 }
 
+// The checks of a pod run at the same time, and must not change the pod. Run with -race to detect data races.
+func TestContainerSecurityContextUserGroupIDInheritedParallel(t *testing.T) {
+	t.Parallel()
+	optionalChecks := make(map[string]struct{})
+	optionalChecks["container-security-context-user-group-id"] = struct{}{}
+	cnf := config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("security-inherit-pod-security-context.yaml")},
+		EnabledOptionalTests: optionalChecks,
+		Parallelism:          8,
+	}
+
+	parsed, err := parser.ParseFiles(context.Background(), cnf)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		card, err := Score(context.Background(), parsed, cnf)
+		assert.NoError(t, err)
+		for _, o := range *card {
+			for _, c := range o.Checks {
+				if c.Check.ID == "container-security-context-user-group-id" {
+					assert.Equal(t, scorecard.GradeAllOK, c.Grade)
+				}
+			}
+		}
+	}
+
+	// The values of the pod security context are not copied to the containers of the parsed object
+	for _, podspecer := range parsed.PodSpeccers() {
+		sec := podspecer.GetPodTemplateSpec().Spec.Containers[0].SecurityContext
+		assert.Nil(t, sec.RunAsUser)
+		assert.Nil(t, sec.RunAsGroup)
+	}
+}
+
 func TestContainerSecurityContextPrivilegedAllGood(t *testing.T) {
 	t.Parallel()
 	structMap := make(map[string]struct{})
@@ -115,6 +150,58 @@ func TestContainerSecurityContextPrivilegedPrivileged(t *testing.T) {
 	})
 }
 
+func TestContainerSecurityContextPrivilegedEphemeralContainer(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-init-and-ephemeral-containers.yaml", "Container Security Context Privileged", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "debugger", comments[0].Path)
+}
+
+func TestInitContainerPrivileged(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-init-container-privileged.yaml", "Init Container Privileged", scorecard.GradeCritical)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "setup", comments[0].Path)
+	assert.Equal(t, "The init container is privileged", comments[0].Summary)
+	assert.Equal(t, "build", comments[1].Path)
+	assert.Equal(t, "The init container mounts the Docker socket with the volume run", comments[1].Summary)
+}
+
+func TestInitContainerPrivilegedOnlyInitContainers(t *testing.T) {
+	t.Parallel()
+	// The privileged ephemeral container, and the docker socket of the app container, are not init containers
+	testExpectedScore(t, "pod-init-and-ephemeral-containers.yaml", "Init Container Privileged", scorecard.GradeAllOK)
+	testExpectedScore(t, "daemonset-host-path.yaml", "Init Container Privileged", scorecard.GradeAllOK)
+}
+
+func TestContainerEnvironmentSecrets(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-env-secrets.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-environment-secrets": {}},
+	}, "Container Environment Secrets", scorecard.GradeCritical)
+
+	var summaries []string
+	for _, c := range comments {
+		summaries = append(summaries, c.Path+": "+c.Summary)
+	}
+	assert.Equal(t, []string{
+		"init: The environment variable GITHUB_TOKEN looks like it contains a GitHub token",
+		"app: The container reads the Secret db into environment variables",
+		"app: The environment variable API_KEY is read from the Secret api",
+		"app: The environment variable AWS_ACCESS_KEY_ID looks like it contains an AWS access key",
+		"app: The environment variable SESSION_KEY looks like it contains a base64 encoded secret",
+	}, summaries)
+}
+
+func TestContainerEnvironmentSecretsAllGood(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-security-context-all-good.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-environment-secrets": {}},
+	}, "Container Environment Secrets", scorecard.GradeAllOK)
+}
+
 func TestContainerSecurityContextReadOnlyRootFilesystemAllGood(t *testing.T) {
 	t.Parallel()
 	structMap := make(map[string]struct{})
@@ -155,3 +242,274 @@ func TestContainerSecurityContextReadOnlyRootFilesystemNoSecurityContext(t *test
 		Description: "Set securityContext to run the container in a more secure context.",
 	})
 }
+
+func TestContainerSecurityContextRunAsNonRootAllGood(t *testing.T) {
+	t.Parallel()
+	c := testExpectedScore(t, "pod-security-context-all-good.yaml", "Container Security Context RunAsNonRoot", scorecard.GradeAllOK)
+	assert.Empty(t, c)
+}
+
+func TestContainerSecurityContextRunAsNonRootPodLevel(t *testing.T) {
+	t.Parallel()
+	c := testExpectedScore(t, "pod-security-context-run-as-non-root.yaml", "Container Security Context RunAsNonRoot", scorecard.GradeAllOK)
+	assert.Empty(t, c)
+}
+
+func TestContainerSecurityContextRunAsNonRootNoSecurityContext(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-security-context-nosecuritycontext.yaml", "Container Security Context RunAsNonRoot", scorecard.GradeCritical)
+	assert.Contains(t, comments, scorecard.TestScoreComment{
+		Path:        "foobar",
+		Summary:     "The container can run as root",
+		Description: "Set securityContext.runAsNonRoot to true, or set securityContext.runAsUser to a non-zero value. Without it the container runs as the user set in the image, which is often root.",
+	})
+}
+
+func TestContainerSecurityContextRunAsNonRootContainerOverride(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-security-context-run-as-non-root-container-override.yaml", "Container Security Context RunAsNonRoot", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The container can run as root", comments[0].Summary)
+}
+
+func TestContainerSecurityContextRunAsNonRootRootUser(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-security-context-run-as-root-user.yaml", "Container Security Context RunAsNonRoot", scorecard.GradeCritical)
+	assert.Contains(t, comments, scorecard.TestScoreComment{
+		Path:        "foobar",
+		Summary:     "The container is running as root",
+		Description: "Set securityContext.runAsUser to a non-zero value, the root user has user ID 0",
+	})
+}
+
+func TestContainerSecurityContextReadOnlyRootFilesystemIgnoredByAnnotation(t *testing.T) {
+	t.Parallel()
+	s, err := testScore(config.Configuration{
+		AllFiles:                  []ks.NamedReader{testFile("pod-read-only-root-filesystem-ignored.yaml")},
+		UseIgnoreChecksAnnotation: true,
+	})
+	assert.Nil(t, err)
+
+	tested := false
+	for _, o := range s {
+		for _, c := range o.Checks {
+			if c.Check.ID == "container-security-context-readonlyrootfilesystem" {
+				assert.True(t, c.Skipped)
+				tested = true
+			}
+		}
+	}
+	assert.True(t, tested)
+}
+
+func TestContainerSecurityContextCapabilitiesDropAll(t *testing.T) {
+	t.Parallel()
+	c := testExpectedScore(t, "pod-capabilities-drop-all.yaml", "Container Security Context Capabilities", scorecard.GradeAllOK)
+	assert.Empty(t, c)
+}
+
+func TestContainerSecurityContextCapabilitiesNoDrop(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-capabilities-no-drop.yaml", "Container Security Context Capabilities", scorecard.GradeWarning)
+	assert.Contains(t, comments, scorecard.TestScoreComment{
+		Path:        "foobar",
+		Summary:     "The container does not drop ALL",
+		Description: "Set securityContext.capabilities.drop to [ALL], and only add back the capabilities that the container needs",
+	})
+}
+
+func TestContainerSecurityContextCapabilitiesRequiredDrop(t *testing.T) {
+	t.Parallel()
+	c := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:                    []ks.NamedReader{testFile("pod-capabilities-no-drop.yaml")},
+		RequiredDroppedCapabilities: []string{"NET_RAW"},
+	}, "Container Security Context Capabilities", scorecard.GradeAllOK)
+	assert.Empty(t, c)
+}
+
+func TestContainerSecurityContextCapabilitiesDangerousAdd(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-capabilities-dangerous-add.yaml", "Container Security Context Capabilities", scorecard.GradeCritical)
+	assert.Contains(t, comments, scorecard.TestScoreComment{
+		Path:        "init",
+		Summary:     "The container adds the dangerous capability SYS_ADMIN",
+		Description: "Remove the capability from securityContext.capabilities.add. This capability can be used to escape the container, or to attack other workloads on the same node.",
+	})
+}
+
+func TestContainerSecurityContextPrivilegeEscalationDisallowed(t *testing.T) {
+	t.Parallel()
+	c := testExpectedScore(t, "deployment-privilege-escalation-disallowed.yaml", "Container Security Context Privilege Escalation", scorecard.GradeAllOK)
+	assert.Empty(t, c)
+}
+
+func TestContainerSecurityContextPrivilegeEscalationNotSet(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-security-context-nosecuritycontext.yaml", "Container Security Context Privilege Escalation", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The container does not disallow privilege escalation", comments[0].Summary)
+}
+
+func TestContainerSecurityContextPrivilegeEscalationAllowed(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "cronjob-privilege-escalation-allowed.yaml", "Container Security Context Privilege Escalation", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The container allows privilege escalation", comments[0].Summary)
+}
+
+func TestContainerSeccompProfileField(t *testing.T) {
+	t.Parallel()
+	c := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-seccomp-profile-runtime-default.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-seccomp-profile": {}},
+		KubernetesVersion:    config.Semver{1, 19},
+	}, "Container Seccomp Profile", scorecard.GradeAllOK)
+	assert.Empty(t, c)
+}
+
+func TestContainerSeccompProfileFieldOldKubernetesVersion(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-seccomp-profile-runtime-default.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-seccomp-profile": {}},
+		KubernetesVersion:    config.Semver{1, 18},
+	}, "Container Seccomp Profile", scorecard.GradeWarning)
+}
+
+func TestContainerSeccompProfileFieldUnconfined(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-seccomp-profile-unconfined.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-seccomp-profile": {}},
+		KubernetesVersion:    config.Semver{1, 19},
+	}, "Container Seccomp Profile", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The container is running with the Unconfined Seccomp profile", comments[0].Summary)
+}
+
+func TestContainerSeccompProfileFieldMissing(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-seccomp-annotated.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-seccomp-profile": {}},
+		KubernetesVersion:    config.Semver{1, 22},
+	}, "Container Seccomp Profile", scorecard.GradeWarning)
+	assert.Contains(t, comments, scorecard.TestScoreComment{
+		Path:        "foobar",
+		Summary:     "The container has not configured a Seccomp profile",
+		Description: "Set securityContext.seccompProfile.type to RuntimeDefault or Localhost. Running containers with Seccomp is recommended to reduce the kernel attack surface",
+	})
+}
+
+func TestPodHostNamespacesNotUsed(t *testing.T) {
+	t.Parallel()
+	for _, check := range []string{"Pod Host Network", "Pod Host PID", "Pod Host IPC"} {
+		c := testExpectedScore(t, "pod-security-context-all-good.yaml", check, scorecard.GradeAllOK)
+		assert.Empty(t, c)
+	}
+}
+
+func TestPodHostNamespacesUsed(t *testing.T) {
+	t.Parallel()
+	c := testExpectedScore(t, "daemonset-host-namespaces.yaml", "Pod Host Network", scorecard.GradeCritical)
+	assert.Equal(t, "The pod uses the host network", c[0].Summary)
+	c = testExpectedScore(t, "daemonset-host-namespaces.yaml", "Pod Host PID", scorecard.GradeCritical)
+	assert.Equal(t, "The pod uses the host PID namespace", c[0].Summary)
+	c = testExpectedScore(t, "daemonset-host-namespaces.yaml", "Pod Host IPC", scorecard.GradeCritical)
+	assert.Equal(t, "The pod uses the host IPC namespace", c[0].Summary)
+}
+
+func TestPodHostNetworkIgnored(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:     []ks.NamedReader{testFile("daemonset-host-namespaces.yaml")},
+		IgnoredTests: map[string]struct{}{"pod-host-network": {}},
+	}, "Pod Host PID", scorecard.GradeCritical)
+
+	s, err := testScore(config.Configuration{
+		AllFiles:     []ks.NamedReader{testFile("daemonset-host-namespaces.yaml")},
+		IgnoredTests: map[string]struct{}{"pod-host-network": {}},
+	})
+	assert.Nil(t, err)
+	for _, o := range s {
+		for _, c := range o.Checks {
+			assert.NotEqual(t, "pod-host-network", c.Check.ID)
+		}
+	}
+}
+
+func TestPodHostPathVolumes(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "daemonset-host-path.yaml", "Pod Host Path Volumes", scorecard.GradeCritical)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "pods", comments[0].Path)
+	assert.Equal(t, "The pod mounts the host path /var/log/pods", comments[0].Summary)
+	assert.Equal(t, "docker", comments[1].Path)
+}
+
+func TestPodHostPathVolumesAllowed(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:         []ks.NamedReader{testFile("daemonset-host-path.yaml")},
+		AllowedHostPaths: []string{"/var/log/"},
+	}, "Pod Host Path Volumes", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The pod mounts the host path /var/run/docker.sock", comments[0].Summary)
+
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:         []ks.NamedReader{testFile("daemonset-host-path.yaml")},
+		AllowedHostPaths: []string{"/var/log", "/var/run/docker.sock"},
+	}, "Pod Host Path Volumes", scorecard.GradeAllOK)
+}
+
+func TestPodHostPathVolumesAllowedPrefix(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:         []ks.NamedReader{testFile("daemonset-host-path.yaml")},
+		AllowedHostPaths: []string{"/var/lo", "/var/run/docker"},
+	}, "Pod Host Path Volumes", scorecard.GradeCritical)
+}
+
+func TestPodAutomountServiceAccountTokenDisabledInPod(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-automount-service-account-token-disabled.yaml", "Pod Automount Service Account Token", scorecard.GradeAllOK)
+}
+
+func TestPodAutomountServiceAccountTokenNotSet(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-security-context-all-good.yaml", "Pod Automount Service Account Token", scorecard.GradeWarning)
+	assert.Equal(t, []scorecard.TestScoreComment{{
+		Summary:     "The pod automounts the service account token",
+		Description: "Set automountServiceAccountToken to false in the pod, or in the ServiceAccount default, unless the pod needs to access the Kubernetes API",
+	}}, comments)
+}
+
+func TestPodAutomountServiceAccountTokenDisabledInServiceAccount(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "deployment-service-account-automount-disabled.yaml", "Pod Automount Service Account Token", scorecard.GradeAllOK)
+}
+
+func TestPodAutomountServiceAccountTokenServiceAccountOtherNamespace(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "deployment-service-account-automount-other-namespace.yaml", "Pod Automount Service Account Token", scorecard.GradeWarning)
+}
+
+func TestPodDefaultServiceAccountDedicated(t *testing.T) {
+	t.Parallel()
+	c := testExpectedScore(t, "deployment-service-account-automount-disabled.yaml", "Pod Default Service Account", scorecard.GradeAllOK)
+	assert.Empty(t, c)
+}
+
+func TestPodDefaultServiceAccountNotSet(t *testing.T) {
+	t.Parallel()
+	c := testExpectedScore(t, "pod-security-context-all-good.yaml", "Pod Default Service Account", scorecard.GradeWarning)
+	assert.Len(t, c, 1)
+	assert.Equal(t, "The pod does not set a ServiceAccount", c[0].Summary)
+}
+
+func TestPodDefaultServiceAccountExplicitDefault(t *testing.T) {
+	t.Parallel()
+	c := testExpectedScore(t, "pod-service-account-default.yaml", "Pod Default Service Account", scorecard.GradeWarning)
+	assert.Len(t, c, 1)
+	assert.Equal(t, "The pod uses the default ServiceAccount", c[0].Summary)
+}
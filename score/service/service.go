@@ -1,7 +1,10 @@
 package service
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/score/checks"
@@ -9,14 +12,43 @@ import (
 	"github.com/zegl/kube-score/scorecard"
 )
 
-func Register(allChecks *checks.Checks, pods ks.Pods, podspeccers ks.PodSpeccers) {
-	allChecks.RegisterServiceCheck("Service Targets Pod", `Makes sure that all Services targets a Pod`, serviceTargetsPod(pods.Pods(), podspeccers.PodSpeccers()))
+func Register(allChecks *checks.Checks, pods ks.Pods, podspeccers ks.PodSpeccers, endpointSlices ks.EndpointSlices) {
+	allChecks.RegisterServiceCheck("Service Targets Pod", `Makes sure that all Services targets a Pod, or have an EndpointSlice if the Service has no selector`, serviceTargetsPod(pods.Pods(), podspeccers.PodSpeccers(), endpointSlices.EndpointSlices()))
+	allChecks.RegisterServiceCheck("Service Target Port", `Makes sure that the targetPort of all ports of the Service is declared as a port of the containers of the pods that the Service targets`, serviceTargetPort(pods.Pods(), podspeccers.PodSpeccers()))
 	allChecks.RegisterServiceCheck("Service Type", `Makes sure that the Service type is not NodePort`, serviceType)
+	allChecks.RegisterOptionalServiceCheck("Service Internal Only", `Makes sure that the Service is not exposed outside of the cluster with the NodePort or LoadBalancer type`, serviceInternalOnly)
+	allChecks.RegisterOptionalServiceCheck("Service External Traffic Policy", `Makes sure that LoadBalancer Services have the externalTrafficPolicy Local, which preserves the source IP of the client`, serviceExternalTrafficPolicy)
+	allChecks.MarkCrossObject("service-targets-pod", "service-target-port")
+	allChecks.Document("service-targets-pod", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Change the selector of the Service to match the labels of the pods, or create an EndpointSlice for Services without a selector",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/service/",
+	})
+	allChecks.Document("service-target-port", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Change the targetPort of the Service to the name or number of a port of the containers, or add the port to the containers",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/service/#field-spec-ports",
+	})
+	allChecks.Document("service-type", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Use the type ClusterIP together with an Ingress, or the type LoadBalancer",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/service/#publishing-services-service-types",
+	})
+	allChecks.Document("service-internal-only", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Use the type ClusterIP for Services that should only be reachable from inside of the cluster",
+		URL:         "https://kubernetes.io/docs/concepts/services-networking/service/#publishing-services-service-types",
+	})
+	allChecks.Document("service-external-traffic-policy", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Set externalTrafficPolicy to Local",
+		URL:         "https://kubernetes.io/docs/tasks/access-application-cluster/create-external-load-balancer/#preserving-the-client-source-ip",
+	})
 }
 
 // serviceTargetsPod checks if a Service targets a pod and issues a critical warning if no matching pod
-// could be found
-func serviceTargetsPod(pods []ks.Pod, podspecers []ks.PodSpecer) func(corev1.Service) scorecard.TestScore {
+// could be found. Services without a selector must have an EndpointSlice instead.
+func serviceTargetsPod(pods []ks.Pod, podspecers []ks.PodSpecer, endpointSlices []ks.EndpointSlice) func(corev1.Service) scorecard.TestScore {
 	podsInNamespace := make(map[string][]map[string]string)
 	for _, p := range pods {
 		pod := p.Pod()
@@ -39,6 +71,20 @@ func serviceTargetsPod(pods []ks.Pod, podspecers []ks.PodSpecer) func(corev1.Ser
 			return
 		}
 
+		// The endpoints of Services without a selector are managed manually
+		if len(service.Spec.Selector) == 0 {
+			for _, slice := range endpointSlices {
+				if slice.GetObjectMeta().Namespace == service.Namespace && slice.ServiceName() == service.Name {
+					score.Grade = scorecard.GradeAllOK
+					return
+				}
+			}
+
+			score.Grade = scorecard.GradeCritical
+			score.AddComment("", "The service has no selector and no EndpointSlice", "Services without a selector do not get any endpoints automatically. Set a selector, or create an EndpointSlice for the service")
+			return
+		}
+
 		hasMatch := false
 
 		for _, podLables := range podsInNamespace[service.Namespace] {
@@ -59,6 +105,85 @@ func serviceTargetsPod(pods []ks.Pod, podspecers []ks.PodSpecer) func(corev1.Ser
 	}
 }
 
+// serviceTargetPort checks that the targetPorts of the Service are declared by the pods that it targets. A named
+// targetPort that is not declared by any of the pods never gets any endpoints. Numeric targetPorts are only reported
+// if the pods declare other ports, as declaring the ports is optional.
+func serviceTargetPort(pods []ks.Pod, podspecers []ks.PodSpecer) func(corev1.Service) scorecard.TestScore {
+	var templates []corev1.PodTemplateSpec
+	for _, p := range pods {
+		pod := p.Pod()
+		templates = append(templates, corev1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec})
+	}
+	for _, podSpec := range podspecers {
+		template := podSpec.GetPodTemplateSpec()
+		template.Namespace = podSpec.GetObjectMeta().Namespace
+		templates = append(templates, template)
+	}
+
+	return func(service corev1.Service) (score scorecard.TestScore) {
+		if service.Spec.Type == corev1.ServiceTypeExternalName || len(service.Spec.Selector) == 0 {
+			score.Skipped = true
+			score.AddComment("", "Skipped because the service does not have a selector", "")
+			return
+		}
+
+		var ports []corev1.ContainerPort
+		hasMatch := false
+		for _, template := range templates {
+			if template.Namespace != service.Namespace || !internal.LabelSelectorMatchesLabels(service.Spec.Selector, template.Labels) {
+				continue
+			}
+			hasMatch = true
+			for _, container := range template.Spec.Containers {
+				ports = append(ports, container.Ports...)
+			}
+		}
+
+		// Services that do not target any pods are reported by the service-targets-pod check
+		if !hasMatch {
+			score.Skipped = true
+			score.AddComment("", "Skipped because the service does not target any pods", "")
+			return
+		}
+
+		score.Grade = scorecard.GradeAllOK
+		for _, servicePort := range service.Spec.Ports {
+			targetPort := servicePort.TargetPort
+			if targetPort.Type == intstr.Int && targetPort.IntVal == 0 {
+				targetPort = intstr.FromInt(int(servicePort.Port))
+			}
+
+			if targetPort.Type == intstr.String {
+				if !hasContainerPort(ports, func(p corev1.ContainerPort) bool { return p.Name == targetPort.StrVal }) {
+					score.Grade = scorecard.GradeCritical
+					score.AddComment(servicePort.Name, fmt.Sprintf("The targetPort %s is not a port of the pods", targetPort.StrVal),
+						"The Service does not get any endpoints for a named targetPort that is not declared by the pods. Fix the name of the port, or add it to the ports of the containers")
+				}
+				continue
+			}
+
+			if len(ports) > 0 && !hasContainerPort(ports, func(p corev1.ContainerPort) bool { return p.ContainerPort == targetPort.IntVal }) {
+				if score.Grade > scorecard.GradeWarning {
+					score.Grade = scorecard.GradeWarning
+				}
+				score.AddComment(servicePort.Name, fmt.Sprintf("The targetPort %d is not a port of the pods", targetPort.IntVal),
+					"The targetPort is not one of the ports of the containers. Check the port for typos, or add it to the ports of the containers")
+			}
+		}
+
+		return
+	}
+}
+
+func hasContainerPort(ports []corev1.ContainerPort, match func(corev1.ContainerPort) bool) bool {
+	for _, port := range ports {
+		if match(port) {
+			return true
+		}
+	}
+	return false
+}
+
 func serviceType(service corev1.Service) (score scorecard.TestScore) {
 	if service.Spec.Type == corev1.ServiceTypeNodePort {
 		score.Grade = scorecard.GradeWarning
@@ -69,3 +194,31 @@ func serviceType(service corev1.Service) (score scorecard.TestScore) {
 	score.Grade = scorecard.GradeAllOK
 	return
 }
+
+func serviceInternalOnly(service corev1.Service) (score scorecard.TestScore) {
+	if service.Spec.Type == corev1.ServiceTypeNodePort || service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "The service is of type "+string(service.Spec.Type), "The service is exposed outside of the cluster. Use the type ClusterIP for services that should only be reachable from inside of the cluster.")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func serviceExternalTrafficPolicy(service corev1.Service) (score scorecard.TestScore) {
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		score.Skipped = true
+		score.AddComment("", "Skipped because the service is not of type LoadBalancer", "")
+		return
+	}
+
+	if service.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyTypeLocal {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment("", "The externalTrafficPolicy is Cluster", "Traffic can be forwarded between nodes, and the source IP of the client is replaced with the IP of the node. Set externalTrafficPolicy to Local if the application needs the IP of the client.")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
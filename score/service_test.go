@@ -3,6 +3,10 @@ package score
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/scorecard"
 )
 
@@ -75,3 +79,70 @@ func TestServiceTypeDefault(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(t, "service-type-default.yaml", "Service Type", scorecard.GradeAllOK)
 }
+
+func TestServiceTypeNodePortGradeOverride(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:       []ks.NamedReader{testFile("service-type-nodeport.yaml")},
+		GradeOverrides: map[string]scorecard.Grade{"service-type": scorecard.GradeCritical},
+	}, "Service Type", scorecard.GradeCritical)
+}
+
+func TestServiceNoSelectorEndpointSlice(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "service-no-selector-endpointslice.yaml", "Service Targets Pod", scorecard.GradeAllOK)
+}
+
+func TestServiceNoSelectorNoEndpointSlice(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "service-no-selector.yaml", "Service Targets Pod", scorecard.GradeCritical)
+}
+
+func TestServiceInternalOnly(t *testing.T) {
+	t.Parallel()
+	enabled := map[string]struct{}{"service-internal-only": {}}
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("service-type-clusterip.yaml")},
+		EnabledOptionalTests: enabled,
+	}, "Service Internal Only", scorecard.GradeAllOK)
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("service-type-nodeport.yaml")},
+		EnabledOptionalTests: enabled,
+	}, "Service Internal Only", scorecard.GradeWarning)
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("service-type-loadbalancer.yaml")},
+		EnabledOptionalTests: enabled,
+	}, "Service Internal Only", scorecard.GradeWarning)
+}
+
+func TestServiceExternalTrafficPolicy(t *testing.T) {
+	t.Parallel()
+	enabled := map[string]struct{}{"service-external-traffic-policy": {}}
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("service-type-loadbalancer.yaml")},
+		EnabledOptionalTests: enabled,
+	}, "Service External Traffic Policy", scorecard.GradeWarning)
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("service-type-loadbalancer-local.yaml")},
+		EnabledOptionalTests: enabled,
+	}, "Service External Traffic Policy", scorecard.GradeAllOK)
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("service-type-clusterip.yaml")},
+		EnabledOptionalTests: enabled,
+	}, "Service External Traffic Policy", 0)
+}
+
+func TestServiceTargetPort(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "container-ports-valid.yaml", "Service Target Port", scorecard.GradeAllOK)
+}
+
+func TestServiceTargetPortNotDeclared(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "container-ports-invalid.yaml", "Service Target Port", scorecard.GradeCritical)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "http", comments[0].Path)
+	assert.Equal(t, "The targetPort htp is not a port of the pods", comments[0].Summary)
+	assert.Equal(t, "admin", comments[1].Path)
+	assert.Equal(t, "The targetPort 8081 is not a port of the pods", comments[1].Summary)
+}
@@ -0,0 +1,217 @@
+// Package sidecar checks native sidecar containers, which are init containers with restartPolicy Always. Native
+// sidecars are supported since Kubernetes v1.28, and the restartPolicy of init containers is not a part of the
+// supported API version, so the checks read the pod spec from the object as decoded from JSON.
+package sidecar
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// kinds are the kinds of objects that have a pod spec
+var kinds = []string{"Pod", "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job", "CronJob"}
+
+// nativeSidecarsVersion is the first Kubernetes version that supports native sidecar containers
+var nativeSidecarsVersion = config.Semver{Major: 1, Minor: 28}
+
+// knownSidecars are the names of containers, and of their images, that are commonly run as sidecars
+var knownSidecars = []string{
+	"cloud-sql-proxy",
+	"cloudsql-proxy",
+	"envoy",
+	"fluent-bit",
+	"fluentd",
+	"istio-proxy",
+	"linkerd-proxy",
+	"oauth2-proxy",
+	"proxyv2",
+	"vault-agent",
+}
+
+func Register(allChecks *checks.Checks, cnf config.Configuration) {
+	allChecks.RegisterObjectCheck("native-sidecar-container", "Native Sidecar Container", `Makes sure that init containers with restartPolicy Always (native sidecars) are supported by the targeted Kubernetes version, and that they have probes and resources set`, kinds, nativeSidecarContainer(cnf))
+	allChecks.RegisterObjectCheck("native-sidecar-migration", "Native Sidecar Migration", `Suggests running containers that look like sidecars, such as proxies and log shippers, as native sidecar containers when the targeted Kubernetes version supports them`, kinds, nativeSidecarMigration(cnf.KubernetesVersion))
+	allChecks.Document("native-sidecar-container", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Target Kubernetes v1.28 or later with --kubernetes-version, and set probes and resources on all init containers with restartPolicy Always",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/pods/sidecar-containers/",
+	})
+	allChecks.Document("native-sidecar-migration", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Move the sidecar to initContainers, and set restartPolicy to Always on it",
+		URL:         "https://kubernetes.io/docs/concepts/workloads/pods/sidecar-containers/",
+	})
+}
+
+type podSpec struct {
+	InitContainers []initContainer    `json:"initContainers"`
+	Containers     []corev1.Container `json:"containers"`
+}
+
+// initContainer is an init container with the restartPolicy field
+type initContainer struct {
+	corev1.Container
+	RestartPolicy string `json:"restartPolicy"`
+}
+
+// decodePodSpec returns the pod spec of the object, the second return value is false if the object has no pod spec
+func decodePodSpec(object ks.Object) (podSpec, bool) {
+	var spec podSpec
+	unstructured, ok := internal.UnstructuredPodSpec(object.GetTypeMeta(), object.Unstructured())
+	if !ok {
+		return spec, false
+	}
+	b, err := json.Marshal(unstructured)
+	if err != nil {
+		return spec, false
+	}
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return spec, false
+	}
+	return spec, true
+}
+
+// nativeSidecarContainer checks that native sidecar containers are supported by the Kubernetes version, and that
+// they have probes and resources, as they are running next to the containers for the whole life of the pod
+func nativeSidecarContainer(cnf config.Configuration) checks.ObjectCheckFn {
+	return func(object ks.Object) (score scorecard.TestScore) {
+		score.Grade = scorecard.GradeAllOK
+
+		spec, ok := decodePodSpec(object)
+		if !ok {
+			return
+		}
+
+		for _, container := range spec.InitContainers {
+			if container.RestartPolicy != "Always" {
+				continue
+			}
+
+			if cnf.KubernetesVersion.LessThan(nativeSidecarsVersion) {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment(container.Name, "Native sidecar containers are not supported by the targeted Kubernetes version",
+					fmt.Sprintf("restartPolicy on init containers is supported since Kubernetes %s, the targeted version is %s. On older versions the field is dropped, and the pod never starts as the init container never completes. Set --kubernetes-version if the cluster is already running %s or later", nativeSidecarsVersion, cnf.KubernetesVersion, nativeSidecarsVersion))
+				continue
+			}
+
+			if container.StartupProbe == nil && container.ReadinessProbe == nil && container.LivenessProbe == nil {
+				setWarning(&score)
+				score.AddComment(container.Name, "The sidecar container has no probes",
+					"Set a startupProbe, so that the containers after the sidecar are not started before it's ready, and a readinessProbe or livenessProbe to detect when it stops working")
+			}
+
+			if missing := missingResources(container.Resources, cnf); len(missing) > 0 {
+				setWarning(&score)
+				score.AddComment(container.Name, fmt.Sprintf("The sidecar container does not set %s", strings.Join(missing, ", ")),
+					"Unlike other init containers, sidecar containers run next to the containers of the pod, and their resources are added to the resources of the pod. Set resources.requests and resources.limits on the sidecar")
+			}
+		}
+
+		return
+	}
+}
+
+// nativeSidecarMigration suggests running containers that look like sidecars as native sidecars. Sidecars in Jobs
+// are graded as warnings, as the pod keeps running after the other containers have completed.
+func nativeSidecarMigration(kubernetesVersion config.Semver) checks.ObjectCheckFn {
+	return func(object ks.Object) (score scorecard.TestScore) {
+		if kubernetesVersion.LessThan(nativeSidecarsVersion) {
+			score.Skipped = true
+			score.AddComment("", "Skipped because native sidecar containers are not supported by the targeted Kubernetes version", "")
+			return
+		}
+
+		score.Grade = scorecard.GradeAllOK
+
+		spec, ok := decodePodSpec(object)
+		if !ok {
+			return
+		}
+
+		var sidecars []corev1.Container
+		for _, container := range spec.Containers {
+			if isKnownSidecar(container) {
+				sidecars = append(sidecars, container)
+			}
+		}
+
+		// A pod where all containers look like sidecars, such as a Deployment of a proxy, has no sidecars
+		if len(sidecars) == 0 || len(sidecars) == len(spec.Containers) {
+			return
+		}
+
+		kind := object.GetTypeMeta().Kind
+		runsToCompletion := kind == "Job" || kind == "CronJob"
+
+		for _, container := range sidecars {
+			if runsToCompletion {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(container.Name, "The container looks like a sidecar, and keeps the pod running after the other containers have completed",
+					"Move the container to initContainers, and set restartPolicy to Always on it. Native sidecar containers are stopped when the other containers of the Job have completed")
+				continue
+			}
+			// The sidecar works as it is, migrating it is only a recommendation
+			if score.Grade > scorecard.GradeInfo {
+				score.Grade = scorecard.GradeInfo
+			}
+			score.AddComment(container.Name, "The container looks like a sidecar",
+				"Move the container to initContainers, and set restartPolicy to Always on it. Native sidecar containers are started before, and stopped after, the other containers of the pod")
+		}
+
+		return
+	}
+}
+
+// setWarning sets the grade to warning, unless the grade is already worse
+func setWarning(score *scorecard.TestScore) {
+	if score.Grade > scorecard.GradeWarning {
+		score.Grade = scorecard.GradeWarning
+	}
+}
+
+// missingResources returns the resources that are not set, the limits that are not required are ignored
+func missingResources(resources corev1.ResourceRequirements, cnf config.Configuration) []string {
+	var missing []string
+	if resources.Requests.Cpu().IsZero() {
+		missing = append(missing, "resources.requests.cpu")
+	}
+	if resources.Requests.Memory().IsZero() {
+		missing = append(missing, "resources.requests.memory")
+	}
+	if resources.Limits.Cpu().IsZero() && !cnf.IgnoreContainerCpuLimitRequirement {
+		missing = append(missing, "resources.limits.cpu")
+	}
+	if resources.Limits.Memory().IsZero() && !cnf.IgnoreContainerMemoryLimitRequirement {
+		missing = append(missing, "resources.limits.memory")
+	}
+	return missing
+}
+
+// isKnownSidecar returns true if the name of the container, or the name of its image, is a known sidecar
+func isKnownSidecar(container corev1.Container) bool {
+	image := container.Image
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	image = path.Base(image)
+	if i := strings.Index(image, ":"); i >= 0 {
+		image = image[:i]
+	}
+
+	for _, name := range knownSidecars {
+		if container.Name == name || image == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestNativeSidecarContainer(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("deployment-native-sidecar.yaml")},
+		KubernetesVersion: config.Semver{1, 29},
+	}, "Native Sidecar Container", scorecard.GradeAllOK)
+}
+
+func TestNativeSidecarContainerOldKubernetesVersion(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "deployment-native-sidecar.yaml", "Native Sidecar Container", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "proxy", comments[0].Path)
+	assert.Equal(t, "Native sidecar containers are not supported by the targeted Kubernetes version", comments[0].Summary)
+}
+
+func TestNativeSidecarContainerMissingProbesAndResources(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:                           []ks.NamedReader{testFile("pod-native-sidecar-missing-probes-and-resources.yaml")},
+		KubernetesVersion:                  config.Semver{1, 28},
+		IgnoreContainerCpuLimitRequirement: true,
+	}, "Native Sidecar Container", scorecard.GradeWarning)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "proxy", comments[0].Path)
+	assert.Equal(t, "The sidecar container has no probes", comments[0].Summary)
+	assert.Equal(t, "proxy", comments[1].Path)
+	assert.Equal(t, "The sidecar container does not set resources.requests.memory, resources.limits.memory", comments[1].Summary)
+}
+
+func TestNativeSidecarMigrationJob(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("job-fake-sidecar.yaml")},
+		KubernetesVersion: config.Semver{1, 28},
+	}, "Native Sidecar Migration", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "proxy", comments[0].Path)
+}
+
+func TestNativeSidecarMigrationDeployment(t *testing.T) {
+	t.Parallel()
+	card, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("deployment-fake-sidecar.yaml")},
+		KubernetesVersion: config.Semver{1, 28},
+	})
+	assert.Nil(t, err)
+
+	grades := make(map[string]scorecard.Grade)
+	for _, o := range card {
+		for _, c := range o.Checks {
+			if c.Check.ID == "native-sidecar-migration" {
+				grades[o.ObjectMeta.Name] = c.Grade
+			}
+		}
+	}
+
+	// A Deployment of only a proxy does not have a sidecar
+	assert.Equal(t, map[string]scorecard.Grade{
+		"foo":   scorecard.GradeInfo,
+		"envoy": scorecard.GradeAllOK,
+	}, grades)
+}
+
+func TestNativeSidecarMigrationGradeOverride(t *testing.T) {
+	t.Parallel()
+	card, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("deployment-fake-sidecar.yaml")},
+		KubernetesVersion: config.Semver{1, 28},
+		GradeOverrides:    map[string]scorecard.Grade{"native-sidecar-migration": scorecard.GradeWarning},
+	})
+	assert.Nil(t, err)
+
+	grades := make(map[string]scorecard.Grade)
+	for _, o := range card {
+		for _, c := range o.Checks {
+			if c.Check.ID == "native-sidecar-migration" {
+				grades[o.ObjectMeta.Name] = c.Grade
+			}
+		}
+	}
+
+	// The info finding is raised, and the passing result is not changed
+	assert.Equal(t, map[string]scorecard.Grade{
+		"foo":   scorecard.GradeWarning,
+		"envoy": scorecard.GradeAllOK,
+	}, grades)
+}
+
+func TestNativeSidecarMigrationOldKubernetesVersion(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "job-fake-sidecar.yaml", "Native Sidecar Migration", 0)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Skipped because native sidecar containers are not supported by the targeted Kubernetes version", comments[0].Summary)
+}
@@ -0,0 +1,132 @@
+package stable
+
+import (
+	"fmt"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+type deprecatedAPI struct {
+	replacement  string
+	deprecatedIn config.Semver
+	removedIn    config.Semver
+}
+
+// deprecatedAPIs are the apiVersions and kinds that have been deprecated, and the version of Kubernetes that they are
+// removed in. See https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var deprecatedAPIs = map[string]map[string]deprecatedAPI{
+	"extensions/v1beta1": {
+		"Deployment":        {"apps/v1", config.Semver{1, 9}, config.Semver{1, 16}},
+		"DaemonSet":         {"apps/v1", config.Semver{1, 9}, config.Semver{1, 16}},
+		"ReplicaSet":        {"apps/v1", config.Semver{1, 9}, config.Semver{1, 16}},
+		"NetworkPolicy":     {"networking.k8s.io/v1", config.Semver{1, 9}, config.Semver{1, 16}},
+		"PodSecurityPolicy": {"policy/v1beta1", config.Semver{1, 10}, config.Semver{1, 16}},
+		"Ingress":           {"networking.k8s.io/v1", config.Semver{1, 14}, config.Semver{1, 22}},
+	},
+	"apps/v1beta1": {
+		"Deployment":  {"apps/v1", config.Semver{1, 9}, config.Semver{1, 16}},
+		"StatefulSet": {"apps/v1", config.Semver{1, 9}, config.Semver{1, 16}},
+		"ReplicaSet":  {"apps/v1", config.Semver{1, 9}, config.Semver{1, 16}},
+	},
+	"apps/v1beta2": {
+		"Deployment":  {"apps/v1", config.Semver{1, 9}, config.Semver{1, 16}},
+		"StatefulSet": {"apps/v1", config.Semver{1, 9}, config.Semver{1, 16}},
+		"DaemonSet":   {"apps/v1", config.Semver{1, 9}, config.Semver{1, 16}},
+		"ReplicaSet":  {"apps/v1", config.Semver{1, 9}, config.Semver{1, 16}},
+	},
+	"apiextensions.k8s.io/v1beta1": {
+		"CustomResourceDefinition": {"apiextensions.k8s.io/v1", config.Semver{1, 16}, config.Semver{1, 22}},
+	},
+	"admissionregistration.k8s.io/v1beta1": {
+		"MutatingWebhookConfiguration":   {"admissionregistration.k8s.io/v1", config.Semver{1, 16}, config.Semver{1, 22}},
+		"ValidatingWebhookConfiguration": {"admissionregistration.k8s.io/v1", config.Semver{1, 16}, config.Semver{1, 22}},
+	},
+	"apiregistration.k8s.io/v1beta1": {
+		"APIService": {"apiregistration.k8s.io/v1", config.Semver{1, 19}, config.Semver{1, 22}},
+	},
+	"certificates.k8s.io/v1beta1": {
+		"CertificateSigningRequest": {"certificates.k8s.io/v1", config.Semver{1, 19}, config.Semver{1, 22}},
+	},
+	"coordination.k8s.io/v1beta1": {
+		"Lease": {"coordination.k8s.io/v1", config.Semver{1, 19}, config.Semver{1, 22}},
+	},
+	"storage.k8s.io/v1beta1": {
+		"CSIDriver":        {"storage.k8s.io/v1", config.Semver{1, 19}, config.Semver{1, 22}},
+		"CSINode":          {"storage.k8s.io/v1", config.Semver{1, 17}, config.Semver{1, 22}},
+		"StorageClass":     {"storage.k8s.io/v1", config.Semver{1, 19}, config.Semver{1, 22}},
+		"VolumeAttachment": {"storage.k8s.io/v1", config.Semver{1, 19}, config.Semver{1, 22}},
+	},
+	"networking.k8s.io/v1beta1": {
+		"Ingress":      {"networking.k8s.io/v1", config.Semver{1, 19}, config.Semver{1, 22}},
+		"IngressClass": {"networking.k8s.io/v1", config.Semver{1, 19}, config.Semver{1, 22}},
+	},
+	"rbac.authorization.k8s.io/v1beta1": {
+		"Role":               {"rbac.authorization.k8s.io/v1", config.Semver{1, 17}, config.Semver{1, 22}},
+		"ClusterRole":        {"rbac.authorization.k8s.io/v1", config.Semver{1, 17}, config.Semver{1, 22}},
+		"RoleBinding":        {"rbac.authorization.k8s.io/v1", config.Semver{1, 17}, config.Semver{1, 22}},
+		"ClusterRoleBinding": {"rbac.authorization.k8s.io/v1", config.Semver{1, 17}, config.Semver{1, 22}},
+	},
+	"scheduling.k8s.io/v1beta1": {
+		"PriorityClass": {"scheduling.k8s.io/v1", config.Semver{1, 14}, config.Semver{1, 22}},
+	},
+	"batch/v1beta1": {
+		"CronJob": {"batch/v1", config.Semver{1, 21}, config.Semver{1, 25}},
+	},
+	"discovery.k8s.io/v1beta1": {
+		"EndpointSlice": {"discovery.k8s.io/v1", config.Semver{1, 21}, config.Semver{1, 25}},
+	},
+	"node.k8s.io/v1beta1": {
+		"RuntimeClass": {"node.k8s.io/v1", config.Semver{1, 20}, config.Semver{1, 25}},
+	},
+	"policy/v1beta1": {
+		"PodDisruptionBudget": {"policy/v1", config.Semver{1, 21}, config.Semver{1, 25}},
+		// PodSecurityPolicy has no replacement, it's replaced by the Pod Security Admission controller
+		"PodSecurityPolicy": {"", config.Semver{1, 21}, config.Semver{1, 25}},
+	},
+	"autoscaling/v2beta1": {
+		"HorizontalPodAutoscaler": {"autoscaling/v2", config.Semver{1, 22}, config.Semver{1, 25}},
+	},
+	"autoscaling/v2beta2": {
+		"HorizontalPodAutoscaler": {"autoscaling/v2", config.Semver{1, 23}, config.Semver{1, 26}},
+	},
+	"flowcontrol.apiserver.k8s.io/v1beta1": {
+		"FlowSchema":                 {"flowcontrol.apiserver.k8s.io/v1beta2", config.Semver{1, 23}, config.Semver{1, 26}},
+		"PriorityLevelConfiguration": {"flowcontrol.apiserver.k8s.io/v1beta2", config.Semver{1, 23}, config.Semver{1, 26}},
+	},
+}
+
+// metaDeprecatedAPI checks if the apiVersion of the object is deprecated or removed in the version of Kubernetes
+// that the user is using
+func metaDeprecatedAPI(kubernetesVersion config.Semver) func(meta domain.BothMeta) scorecard.TestScore {
+	return func(meta domain.BothMeta) (score scorecard.TestScore) {
+		score.Grade = scorecard.GradeAllOK
+
+		api, ok := deprecatedAPIs[meta.TypeMeta.APIVersion][meta.TypeMeta.Kind]
+		if !ok || kubernetesVersion.LessThan(api.deprecatedIn) {
+			return
+		}
+
+		description := fmt.Sprintf("Migrate to %s", api.replacement)
+		if api.replacement == "" {
+			description = "The API has no replacement"
+		}
+
+		if kubernetesVersion.LessThan(api.removedIn) {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("",
+				fmt.Sprintf("The apiVersion %s of %s is deprecated since Kubernetes %s, and will be removed in %s", meta.TypeMeta.APIVersion, meta.TypeMeta.Kind, api.deprecatedIn, api.removedIn),
+				description,
+			)
+			return
+		}
+
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("",
+			fmt.Sprintf("The apiVersion %s of %s has been removed in Kubernetes %s", meta.TypeMeta.APIVersion, meta.TypeMeta.Kind, api.removedIn),
+			description,
+		)
+		return
+	}
+}
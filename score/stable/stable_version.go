@@ -11,6 +11,17 @@ import (
 
 func Register(kubernetesVersion config.Semver, allChecks *checks.Checks) {
 	allChecks.RegisterMetaCheck("Stable version", `Checks if the object is using a deprecated apiVersion`, metaStableAvailable(kubernetesVersion))
+	allChecks.RegisterMetaCheck("Deprecated API version", `Checks if the apiVersion of the object is deprecated in the configured --kubernetes-version, and critical if it has been removed`, metaDeprecatedAPI(kubernetesVersion))
+	allChecks.Document("stable-version", checks.Documentation{
+		Grade:       scorecard.GradeWarning,
+		Remediation: "Change the apiVersion of the object to the stable version",
+		URL:         "https://kubernetes.io/docs/reference/using-api/deprecation-guide/",
+	})
+	allChecks.Document("deprecated-api-version", checks.Documentation{
+		Grade:       scorecard.GradeCritical,
+		Remediation: "Change the apiVersion of the object to the replacement that is reported by the check",
+		URL:         "https://kubernetes.io/docs/reference/using-api/deprecation-guide/",
+	})
 }
 
 // ScoreMetaStableAvailable checks if the supplied TypeMeta is an unstable object type, that has a stable(r) replacement
@@ -37,3 +37,17 @@ func TestStableVersionPodDisruptionBudget(t *testing.T) {
 	assert.Equal(t, scorecard.GradeWarning, scoreNew.Grade)
 	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind policy/v1beta1/PodDisruptionBudget is deprecated", Description: "It's recommended to use policy/v1 instead which has been available since Kubernetes v1.21", DocumentationURL: ""}}, scoreNew.Comments)
 }
+
+func TestDeprecatedAPIUnknownKind(t *testing.T) {
+	fn := metaDeprecatedAPI(config.Semver{1, 25})
+	score := fn(ks.BothMeta{TypeMeta: v1.TypeMeta{Kind: "Service", APIVersion: "v1"}})
+	assert.Equal(t, scorecard.GradeAllOK, score.Grade)
+	assert.Empty(t, score.Comments)
+}
+
+func TestDeprecatedAPIIngress(t *testing.T) {
+	fn := metaDeprecatedAPI(config.Semver{1, 22})
+	score := fn(ks.BothMeta{TypeMeta: v1.TypeMeta{Kind: "Ingress", APIVersion: "networking.k8s.io/v1beta1"}})
+	assert.Equal(t, scorecard.GradeCritical, score.Grade)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion networking.k8s.io/v1beta1 of Ingress has been removed in Kubernetes v1.22", Description: "Migrate to networking.k8s.io/v1", DocumentationURL: ""}}, score.Comments)
+}
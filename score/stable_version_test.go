@@ -3,6 +3,8 @@ package score
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/scorecard"
@@ -86,3 +88,49 @@ func TestJobBatchv1(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(t, "job-batchv1.yaml", "Stable version", scorecard.GradeAllOK)
 }
+
+func TestDeprecatedAPIVersionNotDeprecated(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("cronjob-batchv1beta1-deadline-set.yaml")},
+		KubernetesVersion: config.Semver{1, 20},
+	}, "Deprecated API version", scorecard.GradeAllOK)
+}
+
+func TestDeprecatedAPIVersionDeprecated(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("cronjob-batchv1beta1-deadline-set.yaml")},
+		KubernetesVersion: config.Semver{1, 21},
+	}, "Deprecated API version", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The apiVersion batch/v1beta1 of CronJob is deprecated since Kubernetes v1.21, and will be removed in v1.25", comments[0].Summary)
+}
+
+func TestDeprecatedAPIVersionRemoved(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("cronjob-batchv1beta1-deadline-set.yaml")},
+		KubernetesVersion: config.Semver{1, 25},
+	}, "Deprecated API version", scorecard.GradeCritical)
+}
+
+func TestDeprecatedAPIVersionPodSecurityPolicy(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("podsecuritypolicy-v1beta1.yaml")},
+		KubernetesVersion: config.Semver{1, 25},
+	}, "Deprecated API version", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The API has no replacement", comments[0].Description)
+}
+
+func TestDeprecatedAPIVersionCustomResourceDefinition(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("crd-deprecated-api.yaml")},
+		KubernetesVersion: config.Semver{1, 22},
+	}, "Deprecated API version", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Migrate to apiextensions.k8s.io/v1", comments[0].Description)
+}
@@ -3,15 +3,35 @@ package scorecard
 import (
 	"fmt"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"math"
 	"strings"
+	"time"
 
 	ks "github.com/zegl/kube-score/domain"
 )
 
 const (
-	ignoredChecksAnnotation = "kube-score/ignore"
+	ignoredChecksAnnotation    = "kube-score/ignore"
+	ignoreUntilAnnotation      = "kube-score/ignore-until"
+	ignoreReasonAnnotation     = "kube-score/ignore-reason"
+	downgradedChecksAnnotation = "kube-score/downgrade"
 )
 
+// RenamedChecks maps the old IDs of the checks that have been renamed to their current IDs. The old IDs are accepted
+// everywhere a check is referenced by its ID, such as in the ignore annotation, in --ignore-test and in baselines.
+var RenamedChecks = map[string]string{
+	"deployment-has-host-podantiaffinity":  "deployment-has-pod-spread",
+	"statefulset-has-host-podantiaffinity": "statefulset-has-pod-spread",
+}
+
+// CheckID returns the current ID of the check with the id, which is the id itself unless the check has been renamed
+func CheckID(id string) string {
+	if renamed, ok := RenamedChecks[id]; ok {
+		return renamed
+	}
+	return id
+}
+
 type Scorecard map[string]*ScoredObject
 
 // New creates and initializes a new Scorecard
@@ -33,6 +53,7 @@ func (s Scorecard) NewObject(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectM
 
 	if useIgnoreChecksAnnotation {
 		o.setIgnoredTests()
+		o.setDowngradedChecks()
 	}
 
 	s[o.resourceRefKey()] = o
@@ -48,13 +69,105 @@ func (s Scorecard) AnyBelowOrEqualToGrade(threshold Grade) bool {
 	return false
 }
 
+// Score is the mean of the scores of all objects, rounded to one decimal. An empty scorecard has a score of 10.
+func (s Scorecard) Score() float64 {
+	if len(s) == 0 {
+		return float64(GradeAllOK)
+	}
+	var sum float64
+	for _, o := range s {
+		sum += o.score()
+	}
+	return roundScore(sum / float64(len(s)))
+}
+
+// Totals is the score of objects that are added one at a time, and if any of their checks are below a grade. It's
+// used by the output that is written while the objects are scored, where the scorecard is not kept.
+type Totals struct {
+	objects int
+	sum     float64
+	lowest  Grade
+}
+
+// Add adds the object to the totals
+func (t *Totals) Add(so *ScoredObject) {
+	if t.objects == 0 {
+		t.lowest = GradeAllOK
+	}
+	t.objects++
+	t.sum += so.score()
+	for _, c := range so.Checks {
+		if !c.Skipped && c.Grade < t.lowest {
+			t.lowest = c.Grade
+		}
+	}
+}
+
+// Score is the mean of the scores of the objects, in the same way as Scorecard.Score
+func (t Totals) Score() float64 {
+	if t.objects == 0 {
+		return float64(GradeAllOK)
+	}
+	return roundScore(t.sum / float64(t.objects))
+}
+
+// AnyBelowOrEqualToGrade reports whether any check of the objects that is not skipped is at or below the threshold, in
+// the same way as Scorecard.AnyBelowOrEqualToGrade
+func (t Totals) AnyBelowOrEqualToGrade(threshold Grade) bool {
+	return t.objects > 0 && t.lowest <= threshold
+}
+
+// Filter selects the checks that are written to the output. Checks that have failed are always selected.
+type Filter struct {
+	// ShowPassed selects the checks that have passed, with GradeAllOK
+	ShowPassed bool
+
+	// ShowSkipped selects the checks that have been skipped
+	ShowSkipped bool
+}
+
+// Shows reports whether the check is selected by the filter
+func (f Filter) Shows(ts TestScore) bool {
+	if ts.Skipped {
+		return f.ShowSkipped
+	}
+	if ts.Grade >= GradeAllOK {
+		return f.ShowPassed
+	}
+	return true
+}
+
+// Filter returns a copy of the scorecard with only the checks that are selected by the filter. The objects keep the
+// score of all of their checks, so that the scores of the copy are the same as the scores of the scorecard.
+func (s Scorecard) Filter(f Filter) Scorecard {
+	res := make(Scorecard, len(s))
+	for key, o := range s {
+		filtered := *o
+		filtered.Checks = make([]TestScore, 0, len(o.Checks))
+		for _, c := range o.Checks {
+			if f.Shows(c) {
+				filtered.Checks = append(filtered.Checks, c)
+			}
+		}
+		score := o.score()
+		filtered.unfilteredScore = &score
+		res[key] = &filtered
+	}
+	return res
+}
+
 type ScoredObject struct {
 	TypeMeta     metav1.TypeMeta
 	ObjectMeta   metav1.ObjectMeta
 	FileLocation ks.FileLocation
 	Checks       []TestScore
 
-	ignoredChecks map[string]struct{}
+	ignoredChecks    map[string]struct{}
+	ignoreReason     string
+	downgradedChecks map[string]Grade
+
+	// unfilteredScore is the score of the object before the checks were filtered
+	unfilteredScore *float64
 }
 
 func (s ScoredObject) AnyBelowOrEqualToGrade(threshold Grade) bool {
@@ -66,14 +179,83 @@ func (s ScoredObject) AnyBelowOrEqualToGrade(threshold Grade) bool {
 	return false
 }
 
+// Score is the mean of the grades of all checks that are not skipped, from 1 (all checks are critical) to 10 (all
+// checks are ok), rounded to one decimal. An object without any checks has a score of 10.
+func (so ScoredObject) Score() float64 {
+	return roundScore(so.score())
+}
+
+func (so ScoredObject) score() float64 {
+	if so.unfilteredScore != nil {
+		return *so.unfilteredScore
+	}
+	var sum, count float64
+	for _, c := range so.Checks {
+		if c.Skipped {
+			continue
+		}
+		sum += float64(c.Grade)
+		count++
+	}
+	if count == 0 {
+		return float64(GradeAllOK)
+	}
+	return sum / count
+}
+
+func roundScore(score float64) float64 {
+	return math.Round(score*10) / 10
+}
+
+// ignoreDateFormat is the format of the date in the ignore-until annotation
+const ignoreDateFormat = "2006-01-02"
+
+// setIgnoredTests reads the ignored checks from the ignore annotation. If the ignore-until annotation is set, the
+// checks are only ignored until and including that date, and are reported again after it. Invalid dates are treated
+// as expired.
 func (so *ScoredObject) setIgnoredTests() {
 	ignoredMap := make(map[string]struct{})
+	so.ignoredChecks = ignoredMap
+
+	if until, ok := so.ObjectMeta.Annotations[ignoreUntilAnnotation]; ok {
+		expires, err := time.Parse(ignoreDateFormat, strings.TrimSpace(until))
+		if err != nil || !time.Now().Before(expires.AddDate(0, 0, 1)) {
+			return
+		}
+	}
+	so.ignoreReason = strings.TrimSpace(so.ObjectMeta.Annotations[ignoreReasonAnnotation])
+
 	if ignoredCSV, ok := so.ObjectMeta.Annotations[ignoredChecksAnnotation]; ok {
 		for _, ignored := range strings.Split(ignoredCSV, ",") {
-			ignoredMap[strings.TrimSpace(ignored)] = struct{}{}
+			ignoredMap[CheckID(strings.TrimSpace(ignored))] = struct{}{}
 		}
 	}
-	so.ignoredChecks = ignoredMap
+}
+
+// setDowngradedChecks reads the grades from the downgrade annotation, on the format "check-id=grade,other-id=grade".
+// Entries with an unknown grade are not used.
+func (so *ScoredObject) setDowngradedChecks() {
+	downgradedMap := make(map[string]Grade)
+	if downgradedCSV, ok := so.ObjectMeta.Annotations[downgradedChecksAnnotation]; ok {
+		for _, downgraded := range strings.Split(downgradedCSV, ",") {
+			parts := strings.SplitN(downgraded, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			grade, err := ParseGrade(strings.TrimSpace(parts[1]))
+			if err != nil {
+				continue
+			}
+			downgradedMap[CheckID(strings.TrimSpace(parts[0]))] = grade
+		}
+	}
+	so.downgradedChecks = downgradedMap
+}
+
+// IsDowngraded returns true if the grade of the check has been downgraded with the kube-score/downgrade annotation
+func (so ScoredObject) IsDowngraded(checkID string) bool {
+	_, ok := so.downgradedChecks[checkID]
+	return ok
 }
 
 func (so ScoredObject) resourceRefKey() string {
@@ -89,6 +271,14 @@ func (so ScoredObject) HumanFriendlyRef() string {
 	return s
 }
 
+// CommentFileLocation returns the location of the comment, or the location of the object if the comment has no location
+func (so ScoredObject) CommentFileLocation(comment TestScoreComment) ks.FileLocation {
+	if comment.FileLocation.Name == "" && comment.FileLocation.Line == 0 {
+		return so.FileLocation.Lookup("")
+	}
+	return comment.FileLocation
+}
+
 func (so *ScoredObject) Add(ts TestScore, check ks.Check, locationer ks.FileLocationer) {
 	ts.Check = check
 	so.FileLocation = locationer.FileLocation()
@@ -96,7 +286,27 @@ func (so *ScoredObject) Add(ts TestScore, check ks.Check, locationer ks.FileLoca
 	// This test is ignored (via annotations), don't save the score
 	if _, ok := so.ignoredChecks[check.ID]; ok {
 		ts.Skipped = true
-		ts.Comments = []TestScoreComment{{Summary: fmt.Sprintf("Skipped because %s is ignored", check.ID)}}
+		summary := fmt.Sprintf("Skipped because %s is ignored", check.ID)
+		if so.ignoreReason != "" {
+			summary += ": " + so.ignoreReason
+		}
+		ts.Comments = []TestScoreComment{{Summary: summary}}
+	}
+
+	// The grade of this test is downgraded (via annotations), the comments are kept so that the finding is still
+	// visible
+	if grade, ok := so.downgradedChecks[check.ID]; ok && !ts.Skipped && ts.Grade < grade {
+		ts.Grade = grade
+	}
+
+	// Point each comment at the field that it refers to, or at the object if the field is unknown
+	comments := make([]TestScoreComment, len(ts.Comments))
+	for i, comment := range ts.Comments {
+		comment.FileLocation = so.FileLocation.Lookup(comment.Path)
+		comments[i] = comment
+	}
+	if ts.Comments != nil {
+		ts.Comments = comments
 	}
 
 	so.Checks = append(so.Checks, ts)
@@ -109,12 +319,24 @@ type TestScore struct {
 	Comments []TestScoreComment
 }
 
+// Severity returns the severity of the grade of the check, skipped checks have SeverityNone
+func (ts TestScore) Severity() Severity {
+	if ts.Skipped {
+		return SeverityNone
+	}
+	return ts.Grade.Severity()
+}
+
+// Grade is the result of a check, from 1 (GradeCritical) to 10 (GradeAllOK). Higher is better. Each grade maps to a
+// severity, that is used to prioritize the findings of failing checks.
 type Grade int
 
 const (
 	GradeCritical Grade = 1
+	GradeHigh     Grade = 3
 	GradeWarning  Grade = 5
 	GradeAlmostOK Grade = 7
+	GradeInfo     Grade = 9
 	GradeAllOK    Grade = 10
 )
 
@@ -122,8 +344,12 @@ func (g Grade) String() string {
 	switch g {
 	case GradeCritical:
 		return "CRITICAL"
+	case GradeHigh:
+		return "HIGH"
 	case GradeWarning:
 		return "WARNING"
+	case GradeInfo:
+		return "INFO"
 	case GradeAlmostOK, GradeAllOK:
 		return "OK"
 	default:
@@ -131,11 +357,121 @@ func (g Grade) String() string {
 	}
 }
 
+// Severity returns the severity of the grade. Grades between the named grades have the severity of the next
+// named grade above them, and GradeAllOK has SeverityNone.
+func (g Grade) Severity() Severity {
+	switch {
+	case g <= GradeCritical:
+		return SeverityCritical
+	case g <= GradeHigh:
+		return SeverityHigh
+	case g <= GradeWarning:
+		return SeverityMedium
+	case g <= GradeAlmostOK:
+		return SeverityLow
+	case g < GradeAllOK:
+		return SeverityInfo
+	default:
+		return SeverityNone
+	}
+}
+
+// ParseGrade parses the name of a grade or a severity, as used in configuration files
+func ParseGrade(s string) (Grade, error) {
+	switch strings.ToLower(s) {
+	case "critical":
+		return GradeCritical, nil
+	case "high":
+		return GradeHigh, nil
+	case "warning", "medium":
+		return GradeWarning, nil
+	case "almost-ok", "low":
+		return GradeAlmostOK, nil
+	case "info":
+		return GradeInfo, nil
+	case "ok":
+		return GradeAllOK, nil
+	default:
+		return 0, fmt.Errorf("unknown grade %q, must be one of: critical, high, medium (warning), low (almost-ok), info, ok", s)
+	}
+}
+
+// Severity is the severity of the finding of a check, from SeverityInfo to SeverityCritical. Checks that pass
+// have SeverityNone.
+type Severity int
+
+const (
+	SeverityNone Severity = iota
+	SeverityInfo
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityCritical:
+		return "critical"
+	case SeverityHigh:
+		return "high"
+	case SeverityMedium:
+		return "medium"
+	case SeverityLow:
+		return "low"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "none"
+	}
+}
+
+// Grade returns the grade of the severity
+func (s Severity) Grade() Grade {
+	switch s {
+	case SeverityCritical:
+		return GradeCritical
+	case SeverityHigh:
+		return GradeHigh
+	case SeverityMedium:
+		return GradeWarning
+	case SeverityLow:
+		return GradeAlmostOK
+	case SeverityInfo:
+		return GradeInfo
+	default:
+		return GradeAllOK
+	}
+}
+
 type TestScoreComment struct {
 	Path             string
 	Summary          string
 	Description      string
 	DocumentationURL string
+
+	// FileLocation is the location of the field that the comment refers to. It's set when the score is added to
+	// a ScoredObject.
+	FileLocation ks.FileLocation
+
+	// Remediation is a machine readable fix of the issue, or nil if no fix is known
+	Remediation *Remediation
+}
+
+// Remediation is a suggested change to the object that fixes the issue of a comment.
+// Both patches describe the same change, and can be applied to the object as it's written in the file.
+type Remediation struct {
+	// JSONPatch is a RFC 6902 JSON Patch
+	JSONPatch []JSONPatchOperation
+
+	// StrategicMergePatch is a Kubernetes strategic merge patch, as accepted by "kubectl patch --type strategic"
+	StrategicMergePatch map[string]interface{}
+}
+
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
 }
 
 func (ts *TestScore) AddComment(path, summary, description string) {
@@ -146,6 +482,15 @@ func (ts *TestScore) AddComment(path, summary, description string) {
 	})
 }
 
+func (ts *TestScore) AddCommentWithRemediation(path, summary, description string, remediation *Remediation) {
+	ts.Comments = append(ts.Comments, TestScoreComment{
+		Path:        path,
+		Summary:     summary,
+		Description: description,
+		Remediation: remediation,
+	})
+}
+
 func (ts *TestScore) AddCommentWithURL(path, summary, description, documentationURL string) {
 	ts.Comments = append(ts.Comments, TestScoreComment{
 		Path:             path,
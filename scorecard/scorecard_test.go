@@ -0,0 +1,144 @@
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+func TestScorecardScore(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	assert.Equal(t, 10.0, s.Score())
+
+	a := &ScoredObject{Checks: []TestScore{
+		{Grade: GradeCritical},
+		{Grade: GradeAllOK},
+		{Grade: GradeCritical, Skipped: true},
+	}}
+	b := &ScoredObject{Checks: []TestScore{
+		{Grade: GradeWarning},
+		{Grade: GradeAlmostOK},
+		{Grade: GradeAllOK},
+	}}
+	c := &ScoredObject{}
+	s["a"], s["b"], s["c"] = a, b, c
+
+	assert.Equal(t, 5.5, a.Score())
+	assert.Equal(t, 7.3, b.Score())
+	assert.Equal(t, 10.0, c.Score())
+
+	// The scorecard is the mean of the unrounded object scores
+	assert.Equal(t, 7.6, s.Score())
+
+	// The totals of the objects are the same as of the scorecard
+	var totals Totals
+	assert.Equal(t, 10.0, totals.Score())
+	assert.False(t, totals.AnyBelowOrEqualToGrade(GradeCritical))
+	for _, o := range s {
+		totals.Add(o)
+	}
+	assert.Equal(t, s.Score(), totals.Score())
+	assert.True(t, totals.AnyBelowOrEqualToGrade(GradeCritical))
+	assert.False(t, totals.AnyBelowOrEqualToGrade(GradeCritical-1))
+}
+
+func TestScorecardFilter(t *testing.T) {
+	t.Parallel()
+
+	s := New()
+	s["a"] = &ScoredObject{Checks: []TestScore{
+		{Grade: GradeCritical},
+		{Grade: GradeAllOK},
+		{Grade: GradeCritical, Skipped: true},
+	}}
+	s["b"] = &ScoredObject{Checks: []TestScore{
+		{Grade: GradeAllOK},
+	}}
+
+	failing := s.Filter(Filter{})
+	assert.Equal(t, []TestScore{{Grade: GradeCritical}}, failing["a"].Checks)
+	assert.Empty(t, failing["b"].Checks)
+
+	// The scores are not changed by the filter
+	assert.Equal(t, 5.5, failing["a"].Score())
+	assert.Equal(t, 10.0, failing["b"].Score())
+	assert.Equal(t, s.Score(), failing.Score())
+
+	assert.Len(t, s.Filter(Filter{ShowPassed: true})["a"].Checks, 2)
+	assert.Len(t, s.Filter(Filter{ShowPassed: true, ShowSkipped: true})["a"].Checks, 3)
+
+	// The scorecard is not changed
+	assert.Len(t, s["a"].Checks, 3)
+}
+
+func TestGradeSeverity(t *testing.T) {
+	for _, s := range []Severity{SeverityNone, SeverityInfo, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical} {
+		assert.Equal(t, s, s.Grade().Severity())
+
+		grade, err := ParseGrade(s.String())
+		if s == SeverityNone {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, s.Grade(), grade)
+	}
+
+	// Grades between the named grades have the severity of the grade above them
+	assert.Equal(t, SeverityHigh, Grade(2).Severity())
+	assert.Equal(t, SeverityMedium, Grade(4).Severity())
+	assert.Equal(t, SeverityInfo, Grade(8).Severity())
+
+	// The names of the grades are still accepted
+	grade, err := ParseGrade("warning")
+	assert.NoError(t, err)
+	assert.Equal(t, GradeWarning, grade)
+	grade, err = ParseGrade("almost-ok")
+	assert.NoError(t, err)
+	assert.Equal(t, GradeAlmostOK, grade)
+
+	assert.Equal(t, SeverityNone, TestScore{Grade: GradeCritical, Skipped: true}.Severity())
+}
+
+func TestIgnoreAnnotationExpiry(t *testing.T) {
+	t.Parallel()
+
+	objectMeta := func(name string, annotations map[string]string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Annotations: annotations}
+	}
+	check := ks.Check{ID: "service-type"}
+	failing := TestScore{Grade: GradeWarning, Comments: []TestScoreComment{{Summary: "The service is of type NodePort"}}}
+
+	s := New()
+
+	active := s.NewObject(metav1.TypeMeta{}, objectMeta("active", map[string]string{
+		"kube-score/ignore":        "service-type",
+		"kube-score/ignore-until":  "2999-12-31",
+		"kube-score/ignore-reason": "Migrating to an Ingress",
+	}), true)
+	active.Add(failing, check, testLocation{})
+	assert.True(t, active.Checks[0].Skipped)
+	assert.Equal(t, "Skipped because service-type is ignored: Migrating to an Ingress", active.Checks[0].Comments[0].Summary)
+
+	for _, until := range []string{"2000-01-01", "next week"} {
+		expired := s.NewObject(metav1.TypeMeta{}, objectMeta("expired-"+until, map[string]string{
+			"kube-score/ignore":       "service-type",
+			"kube-score/ignore-until": until,
+		}), true)
+		expired.Add(failing, check, testLocation{})
+		assert.False(t, expired.Checks[0].Skipped, until)
+		assert.Equal(t, GradeWarning, expired.Checks[0].Grade, until)
+		assert.Equal(t, "The service is of type NodePort", expired.Checks[0].Comments[0].Summary, until)
+	}
+}
+
+type testLocation struct{}
+
+func (testLocation) FileLocation() ks.FileLocation {
+	return ks.FileLocation{}
+}
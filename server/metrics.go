@@ -0,0 +1,106 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// metrics are exported in the Prometheus text format
+type metrics struct {
+	mu sync.Mutex
+
+	// requests is the number of requests, by handler and status code
+	requests map[requestLabels]int
+
+	objectsScored int
+	findings      map[string]int
+
+	scoreDurationSum   float64
+	scoreDurationCount int
+}
+
+type requestLabels struct {
+	handler string
+	code    int
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		requests: make(map[requestLabels]int),
+		findings: map[string]int{"critical": 0, "warning": 0},
+	}
+}
+
+func (m *metrics) observeRequest(handler string, code int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[requestLabels{handler, code}]++
+}
+
+func (m *metrics) observeScore(card *scorecard.Scorecard, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.objectsScored += len(*card)
+	for _, o := range *card {
+		for _, c := range o.Checks {
+			if c.Skipped {
+				continue
+			}
+			switch {
+			case c.Grade <= scorecard.GradeCritical:
+				m.findings["critical"]++
+			case c.Grade <= scorecard.GradeHigh:
+				m.findings["high"]++
+			case c.Grade <= scorecard.GradeWarning:
+				m.findings["warning"]++
+			}
+		}
+	}
+	m.scoreDurationSum += duration.Seconds()
+	m.scoreDurationCount++
+}
+
+func (m *metrics) handle(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	var labels []requestLabels
+	for l := range m.requests {
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].handler != labels[j].handler {
+			return labels[i].handler < labels[j].handler
+		}
+		return labels[i].code < labels[j].code
+	})
+
+	fmt.Fprintln(w, "# HELP kube_score_http_requests_total Number of HTTP requests, by handler and status code.")
+	fmt.Fprintln(w, "# TYPE kube_score_http_requests_total counter")
+	for _, l := range labels {
+		fmt.Fprintf(w, "kube_score_http_requests_total{handler=%q,code=\"%d\"} %d\n", l.handler, l.code, m.requests[l])
+	}
+
+	fmt.Fprintln(w, "# HELP kube_score_objects_scored_total Number of scored objects.")
+	fmt.Fprintln(w, "# TYPE kube_score_objects_scored_total counter")
+	fmt.Fprintf(w, "kube_score_objects_scored_total %d\n", m.objectsScored)
+
+	fmt.Fprintln(w, "# HELP kube_score_findings_total Number of failed checks, by grade.")
+	fmt.Fprintln(w, "# TYPE kube_score_findings_total counter")
+	fmt.Fprintf(w, "kube_score_findings_total{grade=\"critical\"} %d\n", m.findings["critical"])
+	fmt.Fprintf(w, "kube_score_findings_total{grade=\"high\"} %d\n", m.findings["high"])
+	fmt.Fprintf(w, "kube_score_findings_total{grade=\"warning\"} %d\n", m.findings["warning"])
+
+	fmt.Fprintln(w, "# HELP kube_score_score_duration_seconds Time spent parsing and scoring the objects of a request.")
+	fmt.Fprintln(w, "# TYPE kube_score_score_duration_seconds summary")
+	fmt.Fprintf(w, "kube_score_score_duration_seconds_sum %g\n", m.scoreDurationSum)
+	fmt.Fprintf(w, "kube_score_score_duration_seconds_count %d\n", m.scoreDurationCount)
+}
@@ -0,0 +1,157 @@
+// Package server implements the HTTP API of "kube-score serve", that scores the objects in the requests.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/internal/logger"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/renderer/json_v2"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// MaxRequestSize is the largest request body that is accepted by the score endpoint
+const MaxRequestSize = 10 << 20
+
+// Server scores the objects in the requests with a fixed configuration
+type Server struct {
+	// Timeout is the longest time that the scoring of a request may take, or zero for no timeout
+	Timeout time.Duration
+
+	cnf     config.Configuration
+	metrics *metrics
+}
+
+// New creates a server that runs the checks with the configuration. The files in the configuration are not used.
+func New(cnf config.Configuration) *Server {
+	return &Server{cnf: cnf, metrics: newMetrics()}
+}
+
+// Handler returns the handler of all endpoints:
+//
+//	POST /v1/score   Scores the objects in the request body (multi-document YAML or JSON), and returns the json v2 output
+//	GET  /healthz    Returns 200 OK while the server is running
+//	GET  /metrics    Metrics in the Prometheus text format
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/v1/score", s.instrument("score", http.HandlerFunc(s.handleScore)))
+	mux.Handle("/healthz", s.instrument("healthz", http.HandlerFunc(handleHealthz)))
+	mux.Handle("/metrics", http.HandlerFunc(s.metrics.handle))
+	return mux
+}
+
+// Score parses and scores the objects in r. The scoring is cancelled when ctx is done, or when it takes longer than
+// the timeout of the server.
+func (s *Server) Score(ctx context.Context, name string, r io.Reader) (*scorecard.Scorecard, error) {
+	start := time.Now()
+
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	cnf := s.cnf
+	cnf.AllFiles = []ks.NamedReader{namedReader{Reader: r, name: name}}
+
+	parsed, err := parser.ParseFiles(ctx, cnf)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, &requestError{err}
+	}
+
+	card, err := score.Score(ctx, parsed, cnf)
+	if err != nil {
+		return nil, err
+	}
+
+	s.metrics.observeScore(card, time.Since(start))
+	return card, nil
+}
+
+func (s *Server) handleScore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed, use POST", r.Method))
+		return
+	}
+
+	card, err := s.Score(r.Context(), "request", http.MaxBytesReader(w, r.Body, MaxRequestSize))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*requestError); ok {
+			status = http.StatusBadRequest
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			status = http.StatusServiceUnavailable
+		}
+		logger.Warn("Failed to score the request", "status", status, "error", err)
+		writeError(w, status, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = io.Copy(w, json_v2.Output(card))
+}
+
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = io.WriteString(w, "ok\n")
+}
+
+// instrument counts the requests to the handler by status code
+func (s *Server) instrument(handler string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+		s.metrics.observeRequest(handler, rec.status)
+		logger.Debug("Request", "handler", handler, "method", r.Method, "status", rec.status, "duration", time.Since(start))
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// requestError is an error caused by the content of the request
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+type namedReader struct {
+	io.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/renderer/json_v2"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+const pods = `apiVersion: v1
+kind: Pod
+metadata:
+  name: foo
+spec:
+  containers:
+  - name: foo
+    image: foo:latest
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: bar
+spec:
+  containers:
+  - name: bar
+    image: bar:latest
+`
+
+func newTestServer() *httptest.Server {
+	return httptest.NewServer(New(config.Configuration{
+		KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+		IgnoredTests:      map[string]struct{}{"container-resources": {}},
+	}).Handler())
+}
+
+func TestScore(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer()
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/score", "application/yaml", strings.NewReader(pods))
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var objects []json_v2.ScoredObject
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&objects))
+	assert.Len(t, objects, 2)
+
+	for _, o := range objects {
+		assert.Equal(t, "request", o.FileName)
+		var found bool
+		for _, c := range o.Checks {
+			assert.NotEqual(t, "container-resources", c.Check.ID)
+			if c.Check.ID == "container-image-tag" {
+				found = true
+				assert.Equal(t, scorecard.GradeCritical, c.Grade)
+			}
+		}
+		assert.True(t, found)
+	}
+}
+
+func TestScoreInvalid(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer()
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/score", "application/yaml", strings.NewReader("apiVersion: v1\nkind: Pod\nmetadata: [\n"))
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var body map[string]string
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.NotEmpty(t, body["error"])
+
+	resp, err = http.Get(srv.URL + "/v1/score")
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	assert.Equal(t, "POST", resp.Header.Get("Allow"))
+}
+
+func TestScoreCancelled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := New(config.Configuration{KubernetesVersion: config.Semver{Major: 1, Minor: 18}})
+	_, err := s.Score(ctx, "request", strings.NewReader(pods))
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestHealthz(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/healthz")
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer()
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/score", "application/yaml", strings.NewReader(pods))
+	assert.Nil(t, err)
+	resp.Body.Close()
+	resp, err = http.Get(srv.URL + "/v1/score")
+	assert.Nil(t, err)
+	resp.Body.Close()
+
+	resp, err = http.Get(srv.URL + "/metrics")
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.Nil(t, err)
+
+	assert.Contains(t, string(body), `kube_score_http_requests_total{handler="score",code="200"} 1`)
+	assert.Contains(t, string(body), `kube_score_http_requests_total{handler="score",code="405"} 1`)
+	assert.Contains(t, string(body), "kube_score_objects_scored_total 2\n")
+	assert.Contains(t, string(body), "kube_score_score_duration_seconds_count 1\n")
+	assert.NotContains(t, string(body), `kube_score_findings_total{grade="critical"} 0`)
+}
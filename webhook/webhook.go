@@ -0,0 +1,176 @@
+// Package webhook implements a Kubernetes validating admission webhook, that scores the objects that are created
+// or updated in the cluster, and denies or warns about the objects that fail the checks.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/zegl/kube-score/internal/logger"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// MaxRequestSize is the largest AdmissionReview that is accepted, the API server limits objects to 3 MiB
+const MaxRequestSize = 4 << 20
+
+// Scorer scores the objects in r, it's implemented by server.Server
+type Scorer interface {
+	Score(ctx context.Context, name string, r io.Reader) (*scorecard.Scorecard, error)
+}
+
+// Webhook handles AdmissionReview requests
+type Webhook struct {
+	scorer Scorer
+
+	// DenyGrade is the grade at or below which objects are denied
+	DenyGrade scorecard.Grade
+
+	// WarnGrade is the grade at or below which objects are admitted with a warning. If zero, no warnings are
+	// returned.
+	WarnGrade scorecard.Grade
+
+	// FailOpen admits the objects that can't be scored, such as when the scoring times out, with a warning. By
+	// default they are denied.
+	FailOpen bool
+}
+
+// New creates a webhook that denies objects with critical findings, and warns about objects with warnings
+func New(scorer Scorer) *Webhook {
+	return &Webhook{
+		scorer:    scorer,
+		DenyGrade: scorecard.GradeCritical,
+		WarnGrade: scorecard.GradeWarning,
+	}
+}
+
+func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, fmt.Sprintf("method %s is not allowed, use POST", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var review admissionv1.AdmissionReview
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestSize)).Decode(&review); err != nil {
+		http.Error(w, fmt.Sprintf("invalid AdmissionReview: %s", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "invalid AdmissionReview: request is missing", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = wh.Review(r.Context(), review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(review)
+}
+
+// Review scores the object in the request, and decides if it's admitted. The scoring is cancelled when ctx is done.
+func (wh *Webhook) Review(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	// Objects that are deleted, and subresources such as the status, are not scored
+	if (req.Operation != admissionv1.Create && req.Operation != admissionv1.Update) || req.SubResource != "" || len(req.Object.Raw) == 0 {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	name := fmt.Sprintf("%s/%s", req.Namespace, req.Name)
+	card, err := wh.scorer.Score(ctx, name, bytes.NewReader(req.Object.Raw))
+	if err != nil {
+		logger.Warn("Failed to score the object", "object", name, "kind", req.Kind.Kind, "error", err, "fail-open", wh.FailOpen)
+		return wh.failed(err)
+	}
+
+	denied, warnings := findings(card, wh.DenyGrade, wh.WarnGrade)
+	if len(denied) == 0 {
+		logger.Debug("Admitted object", "object", name, "kind", req.Kind.Kind, "warnings", warnings)
+		return &admissionv1.AdmissionResponse{Allowed: true, Warnings: warnings}
+	}
+
+	logger.Info("Denied object", "object", name, "kind", req.Kind.Kind, "denied", denied)
+
+	return &admissionv1.AdmissionResponse{
+		Allowed:  false,
+		Warnings: warnings,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonForbidden,
+			Code:    http.StatusForbidden,
+			Message: fmt.Sprintf("kube-score denied the object: %s", strings.Join(denied, ", ")),
+		},
+	}
+}
+
+// failed returns the response to an object that could not be scored. It's denied, unless FailOpen is set.
+func (wh *Webhook) failed(err error) *admissionv1.AdmissionResponse {
+	message := fmt.Sprintf("kube-score: failed to score the object: %s", err)
+	if wh.FailOpen {
+		return &admissionv1.AdmissionResponse{Allowed: true, Warnings: []string{message}}
+	}
+
+	status := &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Reason:  metav1.StatusReasonInternalError,
+		Code:    http.StatusInternalServerError,
+		Message: message,
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		status.Reason = metav1.StatusReasonTimeout
+		status.Code = http.StatusGatewayTimeout
+	}
+	return &admissionv1.AdmissionResponse{Allowed: false, Result: status}
+}
+
+// findings returns the failed checks at or below the deny grade, and the failed checks at or below the warn grade
+// that are not denied
+func findings(card *scorecard.Scorecard, denyGrade, warnGrade scorecard.Grade) (denied, warnings []string) {
+	var keys []string
+	for k := range *card {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, c := range (*card)[k].Checks {
+			if c.Skipped {
+				continue
+			}
+
+			var list *[]string
+			switch {
+			case c.Grade <= denyGrade:
+				list = &denied
+			case c.Grade <= warnGrade:
+				list = &warnings
+			default:
+				continue
+			}
+
+			if len(c.Comments) == 0 {
+				*list = append(*list, fmt.Sprintf("[%s] %s", c.Grade, c.Check.Name))
+			}
+			for _, comment := range c.Comments {
+				*list = append(*list, formatComment(c, comment))
+			}
+		}
+	}
+	return denied, warnings
+}
+
+func formatComment(c scorecard.TestScore, comment scorecard.TestScoreComment) string {
+	if comment.Path != "" {
+		return fmt.Sprintf("[%s] %s: (%s) %s", c.Grade, c.Check.Name, comment.Path, comment.Summary)
+	}
+	return fmt.Sprintf("[%s] %s: %s", c.Grade, c.Check.Name, comment.Summary)
+}
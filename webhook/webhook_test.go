@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/scorecard"
+	"github.com/zegl/kube-score/server"
+)
+
+const latestTagPod = `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "foo"},
+	"spec": {"containers": [{"name": "app", "image": "foo:latest"}]}}`
+
+const pinnedTagPod = `{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "foo"},
+	"spec": {"containers": [{"name": "app", "image": "foo:1.0", "imagePullPolicy": "IfNotPresent"}]}}`
+
+func newWebhook(enabled ...string) *Webhook {
+	return New(server.New(config.Configuration{
+		KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+		// Only run the checks that are enabled
+		IgnoredTests: ignoreAllExcept(enabled...),
+	}))
+}
+
+func ignoreAllExcept(enabled ...string) map[string]struct{} {
+	ignored := make(map[string]struct{})
+	for _, c := range score.RegisterAllChecks(parser.Empty(), config.Configuration{}).All() {
+		ignored[c.ID] = struct{}{}
+	}
+	for _, id := range enabled {
+		delete(ignored, id)
+	}
+	return ignored
+}
+
+func request(operation admissionv1.Operation, object string) *admissionv1.AdmissionRequest {
+	return &admissionv1.AdmissionRequest{
+		UID:       "705ab4f5-6393-11e8-b7cc-42010a800002",
+		Name:      "foo",
+		Namespace: "default",
+		Operation: operation,
+		Object:    runtime.RawExtension{Raw: []byte(object)},
+	}
+}
+
+func TestReviewDenied(t *testing.T) {
+	t.Parallel()
+	resp := newWebhook("container-image-tag").Review(context.Background(), request(admissionv1.Create, latestTagPod))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, int32(http.StatusForbidden), resp.Result.Code)
+	assert.Equal(t, "kube-score denied the object: [CRITICAL] Container Image Tag: (app) Image with latest tag", resp.Result.Message)
+}
+
+func TestReviewAllowed(t *testing.T) {
+	t.Parallel()
+	resp := newWebhook("container-image-tag").Review(context.Background(), request(admissionv1.Update, pinnedTagPod))
+	assert.True(t, resp.Allowed)
+	assert.Nil(t, resp.Result)
+	assert.Empty(t, resp.Warnings)
+
+	// Deleted objects are not scored
+	resp = newWebhook("container-image-tag").Review(context.Background(), request(admissionv1.Delete, latestTagPod))
+	assert.True(t, resp.Allowed)
+}
+
+func TestReviewThresholds(t *testing.T) {
+	t.Parallel()
+
+	// Warn instead of denying
+	wh := newWebhook("container-image-tag")
+	wh.DenyGrade = 0
+	wh.WarnGrade = scorecard.GradeCritical
+	resp := wh.Review(context.Background(), request(admissionv1.Create, latestTagPod))
+	assert.True(t, resp.Allowed)
+	assert.Equal(t, []string{"[CRITICAL] Container Image Tag: (app) Image with latest tag"}, resp.Warnings)
+
+	// No warnings
+	wh.WarnGrade = 0
+	resp = wh.Review(context.Background(), request(admissionv1.Create, latestTagPod))
+	assert.True(t, resp.Allowed)
+	assert.Empty(t, resp.Warnings)
+}
+
+func TestServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(newWebhook("container-image-tag"))
+	defer srv.Close()
+
+	body, err := json.Marshal(admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  request(admissionv1.Create, latestTagPod),
+	})
+	assert.Nil(t, err)
+
+	resp, err := http.Post(srv.URL, "application/json", bytes.NewReader(body))
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var review admissionv1.AdmissionReview
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&review))
+	assert.Equal(t, "admission.k8s.io/v1", review.APIVersion)
+	assert.Equal(t, "AdmissionReview", review.Kind)
+	assert.Nil(t, review.Request)
+	assert.Equal(t, "705ab4f5-6393-11e8-b7cc-42010a800002", string(review.Response.UID))
+	assert.False(t, review.Response.Allowed)
+
+	resp, err = http.Post(srv.URL, "application/json", strings.NewReader("{}"))
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+type failingScorer struct {
+	err error
+}
+
+func (f failingScorer) Score(ctx context.Context, name string, r io.Reader) (*scorecard.Scorecard, error) {
+	return nil, f.err
+}
+
+func TestReviewFailClosed(t *testing.T) {
+	t.Parallel()
+
+	// Objects that can't be scored are denied by default
+	resp := New(failingScorer{errors.New("out of memory")}).Review(context.Background(), request(admissionv1.Create, latestTagPod))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, metav1.StatusReasonInternalError, resp.Result.Reason)
+	assert.Equal(t, int32(http.StatusInternalServerError), resp.Result.Code)
+	assert.Equal(t, "kube-score: failed to score the object: out of memory", resp.Result.Message)
+
+	timeout := fmt.Errorf("scoring timed out: %w", context.DeadlineExceeded)
+	resp = New(failingScorer{timeout}).Review(context.Background(), request(admissionv1.Create, latestTagPod))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, metav1.StatusReasonTimeout, resp.Result.Reason)
+	assert.Equal(t, int32(http.StatusGatewayTimeout), resp.Result.Code)
+}
+
+func TestReviewFailOpen(t *testing.T) {
+	t.Parallel()
+
+	wh := New(failingScorer{errors.New("out of memory")})
+	wh.FailOpen = true
+	resp := wh.Review(context.Background(), request(admissionv1.Create, latestTagPod))
+	assert.True(t, resp.Allowed)
+	assert.Nil(t, resp.Result)
+	assert.Equal(t, []string{"kube-score: failed to score the object: out of memory"}, resp.Warnings)
+}